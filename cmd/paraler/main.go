@@ -8,6 +8,7 @@ import (
 	"github.com/paralerdev/paraler/internal/app"
 	"github.com/paralerdev/paraler/internal/config"
 	"github.com/paralerdev/paraler/internal/discovery"
+	"github.com/paralerdev/paraler/internal/log"
 )
 
 var (
@@ -31,6 +32,10 @@ func main() {
 	// Flags for main command
 	configPath := flag.String("config", "", "Path to config file")
 	showVersion := flag.Bool("version", false, "Show version")
+	exportFormat := flag.String("export-format", "plain", "Format of exported logs: plain, json or ndjson")
+	inline := flag.Bool("inline", false, "Run below the shell prompt instead of in the alternate screen")
+	inlineHeight := flag.Int("inline-height", 15, "Rows used with --inline")
+	accessible := flag.Bool("accessible", false, "Spell out states instead of showing them by color or symbol, without animations (implied by NO_COLOR)")
 	flag.Parse()
 
 	if *showVersion {
@@ -38,12 +43,29 @@ func main() {
 		os.Exit(0)
 	}
 
+	format, err := log.ParseExportFormat(*exportFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *inline && *inlineHeight < app.MinInlineHeight {
+		fmt.Fprintf(os.Stderr, "Error: --inline-height must be at least %d\n", app.MinInlineHeight)
+		os.Exit(1)
+	}
+
 	// Create and run the app
 	application, err := app.New(*configPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	application.SetExportFormat(format)
+	if *inline {
+		application.SetInline(*inlineHeight)
+	}
+	if *accessible {
+		application.SetAccessible()
+	}
 
 	if err := application.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -7,16 +7,24 @@ import (
 	"syscall"
 
 	"github.com/paralerdev/paraler/internal/config"
+	"github.com/paralerdev/paraler/internal/log"
+	"github.com/paralerdev/paraler/internal/process"
 	"github.com/paralerdev/paraler/internal/ui"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// MinInlineHeight is the fewest rows the UI can run inline in
+const MinInlineHeight = 8
+
 // App is the main application
 type App struct {
-	config     *config.Config
-	configPath string
-	model      *ui.Model
-	program    *tea.Program
+	config       *config.Config
+	configPath   string
+	exportFormat log.ExportFormat
+	inlineHeight int // Rows used below the prompt, 0 for the alternate screen
+	accessible   bool
+	model        *ui.Model
+	program      *tea.Program
 }
 
 // New creates a new application
@@ -37,28 +45,75 @@ func New(configPath string) (*App, error) {
 	}
 
 	return &App{
-		config:     cfg,
-		configPath: path,
+		config:       cfg,
+		configPath:   path,
+		exportFormat: log.ExportPlain,
 	}, nil
 }
 
+// SetExportFormat sets the format logs are exported in
+func (a *App) SetExportFormat(format log.ExportFormat) {
+	a.exportFormat = format
+}
+
+// SetInline runs the UI in height rows below the shell prompt instead of
+// in the alternate screen, keeping the scrollback visible
+func (a *App) SetInline(height int) {
+	a.inlineHeight = height
+}
+
+// SetAccessible spells out states instead of showing them by color or
+// symbol alone, and stops spinners
+func (a *App) SetAccessible() {
+	a.accessible = true
+}
+
 // Run starts the application
 func (a *App) Run() error {
 	// Create the UI model
 	a.model = ui.NewModel(a.config, a.configPath)
+	a.model.SetExportFormat(a.exportFormat)
+	a.model.SetInline(a.inlineHeight)
+	if a.accessible {
+		a.model.SetAccessible()
+	}
 
-	// Create the Bubble Tea program
-	a.program = tea.NewProgram(
-		a.model,
-		tea.WithAltScreen(),
-	)
+	// Take over the services of the paraler process this one replaced
+	if path := os.Getenv(process.UpgradeStateEnv); path != "" {
+		os.Unsetenv(process.UpgradeStateEnv)
+		if err := a.model.Manager().ResumeUpgrade(path); err != nil {
+			a.model.UpgradeFailed(err)
+		}
+	}
 
 	// Handle signals for graceful shutdown
 	go a.handleSignals()
 
-	// Run the program
-	_, err := a.program.Run()
-	return err
+	for {
+		// Create the Bubble Tea program. Inline, the mouse is left to the
+		// terminal so its scrollback can be scrolled.
+		var opts []tea.ProgramOption
+		if a.inlineHeight == 0 {
+			opts = append(opts, tea.WithAltScreen(), tea.WithMouseCellMotion())
+		}
+		a.program = tea.NewProgram(a.model, opts...)
+
+		// Run the program
+		_, err := a.program.Run()
+		a.model.Close()
+		if err != nil {
+			return err
+		}
+		if !a.model.UpgradeRequested() {
+			return nil
+		}
+
+		// Upgrade only returns if the new binary couldn't be started, in
+		// which case the UI comes back with the error
+		err = a.model.Manager().Upgrade()
+		a.model.Reopen()
+		a.model.UpgradeFailed(err)
+	}
 }
 
 // handleSignals handles OS signals
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// healthCommand is the mapping form of health
+type healthCommand struct {
+	Cmd string `yaml:"cmd"`
+}
+
+// UnmarshalYAML accepts cmd either as a shell command line or as a list of
+// arguments. A list is stored in Argv and also joined into Cmd for display.
+// health is either a URL or a {cmd: ...} mapping, stored in HealthCmd.
+func (s *Service) UnmarshalYAML(value *yaml.Node) error {
+	type plain Service
+
+	var argv []string
+	var healthCmd string
+	if value.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			key, val := value.Content[i], value.Content[i+1]
+			switch {
+			case key.Value == "cmd" && val.Kind == yaml.SequenceNode:
+				if err := val.Decode(&argv); err != nil {
+					return err
+				}
+				value.Content[i+1] = &yaml.Node{
+					Kind:  yaml.ScalarNode,
+					Tag:   "!!str",
+					Value: strings.Join(argv, " "),
+				}
+			case key.Value == "health" && val.Kind == yaml.MappingNode:
+				var health healthCommand
+				if err := val.Decode(&health); err != nil {
+					return err
+				}
+				if health.Cmd == "" {
+					return fmt.Errorf("line %d: health: cmd is required", val.Line)
+				}
+				healthCmd = health.Cmd
+				value.Content[i+1] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str"}
+			}
+		}
+	}
+
+	if err := value.Decode((*plain)(s)); err != nil {
+		return err
+	}
+	s.Argv = argv
+	s.HealthCmd = healthCmd
+	return nil
+}
+
+// MarshalYAML writes cmd back as a list and health as a {cmd: ...} mapping
+// for services configured with them
+func (s Service) MarshalYAML() (interface{}, error) {
+	type plain Service
+
+	var node yaml.Node
+	if err := node.Encode(plain(s)); err != nil {
+		return nil, err
+	}
+
+	if len(s.Argv) > 0 {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value != "cmd" {
+				continue
+			}
+			var argv yaml.Node
+			if err := argv.Encode(s.Argv); err != nil {
+				return nil, err
+			}
+			argv.Style = yaml.FlowStyle
+			node.Content[i+1] = &argv
+		}
+	}
+
+	if s.HealthCmd != "" {
+		var health yaml.Node
+		if err := health.Encode(healthCommand{Cmd: s.HealthCmd}); err != nil {
+			return nil, err
+		}
+		health.Style = yaml.FlowStyle
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "health"},
+			&health,
+		)
+	}
+	return &node, nil
+}
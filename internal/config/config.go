@@ -1,38 +1,305 @@
 package config
 
-import "time"
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
 
 // Config represents the root configuration structure
 type Config struct {
 	Projects map[string]Project `yaml:"projects"`
+	Logs     Logs               `yaml:"logs,omitempty"`
+	Sinks    []Sink             `yaml:"sinks,omitempty"`
+	Theme    Theme              `yaml:"theme,omitempty"`
+
+	// ProjectOrder lists the projects in the order of the config file
+	ProjectOrder []string `yaml:"-"`
+}
+
+// Sink types
+const (
+	SinkFile   = "file"   // append lines to a file
+	SinkSyslog = "syslog" // send lines to the local or a remote syslog
+	SinkLoki   = "loki"   // push to a Loki push API endpoint
+	SinkOTLP   = "otlp"   // export as OTLP logs over HTTP/JSON
+)
+
+// Sink forwards all captured output to an external destination
+type Sink struct {
+	Type string `yaml:"type"`
+
+	// Path of the file sink
+	Path string `yaml:"path,omitempty"`
+
+	// Address of a remote syslog, e.g. udp://host:514 (default: local)
+	Address string `yaml:"address,omitempty"`
+
+	// URL of the Loki push API (.../loki/api/v1/push) or the OTLP logs
+	// endpoint (.../v1/logs)
+	URL string `yaml:"url,omitempty"`
+
+	// Labels are added to Loki streams and OTLP resource attributes, next
+	// to the project and service
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+// Timestamp formats of log lines
+const (
+	TimestampsTime     = "time"     // 15:04:05 (default)
+	TimestampsMillis   = "ms"       // 15:04:05.000
+	TimestampsDate     = "date"     // 2006-01-02 15:04:05
+	TimestampsRelative = "relative" // 3s ago
+	TimestampsNone     = "none"     // no timestamps
+)
+
+// TimestampFormats lists the timestamp formats in the order they're cycled
+var TimestampFormats = []string{TimestampsTime, TimestampsMillis, TimestampsDate, TimestampsRelative, TimestampsNone}
+
+// Logs configures how service output is kept and shown: optionally written
+// to rotating files on disk, so logs survive restarts and aren't limited to
+// the in-memory buffer
+type Logs struct {
+	Persist bool `yaml:"persist,omitempty"`
+
+	// Dir overrides the directory log files are written to
+	Dir string `yaml:"dir,omitempty"`
+
+	// A service's log file is rotated once it exceeds MaxSizeMB or is
+	// older than MaxAge; rotated files are gzipped and the MaxFiles most
+	// recent are kept
+	MaxSizeMB int           `yaml:"max_size_mb,omitempty"`
+	MaxAge    time.Duration `yaml:"max_age,omitempty"`
+	MaxFiles  int           `yaml:"max_files,omitempty"`
+
+	// History is the number of older log lines of each service kept on
+	// disk while paraler runs, once they no longer fit in memory
+	History int `yaml:"history,omitempty"`
+
+	// StripColors shows lines in the colors of their level instead of the
+	// colors services print them in
+	StripColors bool `yaml:"strip_colors,omitempty"`
+
+	// Timestamps is the format of the timestamps log lines start with,
+	// one of TimestampFormats
+	Timestamps string `yaml:"timestamps,omitempty"`
+
+	// TimeLayout is the Go time layout of the "time" timestamps and other
+	// times of day shown, "15:04:05" by default
+	TimeLayout string `yaml:"time_layout,omitempty"`
+
+	// Timezone is the zone times are shown, exported and stored in:
+	// "local" (the default), "UTC" or an IANA name such as "Europe/Paris"
+	Timezone string `yaml:"timezone,omitempty"`
+}
+
+// DefaultTimeLayout is the layout times of day are shown in by default
+const DefaultTimeLayout = "15:04:05"
+
+// Layout returns the layout times of day are shown in
+func (l Logs) Layout() string {
+	if l.TimeLayout == "" {
+		return DefaultTimeLayout
+	}
+	return l.TimeLayout
+}
+
+// Location returns the time zone times are shown, exported and stored in
+func (l Logs) Location() *time.Location {
+	if l.Timezone == "" || strings.EqualFold(l.Timezone, "local") {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(l.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
 }
 
 // Project represents a development project with multiple services
 type Project struct {
 	Path     string             `yaml:"path"`
 	Services map[string]Service `yaml:"services"`
+
+	// ServiceOrder lists the services in the order of the config file
+	ServiceOrder []string `yaml:"-"`
 }
 
+// Service types
+const (
+	ServiceTypeTask   = "task"   // runs to completion instead of staying up
+	ServiceTypeDocker = "docker" // runs cmd's image (or container:<name>) in Docker
+)
+
 // Service represents a single service within a project
 type Service struct {
+	Type        string        `yaml:"type,omitempty"`
 	Cmd         string        `yaml:"cmd"`
+	Argv        []string      `yaml:"-"` // Set when cmd is a list, run without a shell
+	Shell       string        `yaml:"shell,omitempty"`
 	Cwd         string        `yaml:"cwd,omitempty"`
 	Port        int           `yaml:"port,omitempty"`
 	Health      string        `yaml:"health,omitempty"`
+	HealthCmd   string        `yaml:"-"` // Set when health is {cmd: ...}, healthy while it exits 0
 	Env         []string      `yaml:"env,omitempty"`
 	AutoRestart bool          `yaml:"auto_restart,omitempty"`
 	Delay       time.Duration `yaml:"delay,omitempty"`
 	DependsOn   []string      `yaml:"depends_on,omitempty"`
 	Color       string        `yaml:"color,omitempty"`
+
+	// LogFields lists the fields of JSON log lines shown inline next to
+	// the message; by default all fields are shown
+	LogFields []string `yaml:"log_fields,omitempty"`
+
+	// LogHistory is the number of older log lines kept on disk beyond those
+	// in memory, overriding logs.history
+	LogHistory int `yaml:"log_history,omitempty"`
+
+	// LogLevels sets the level (and color) of lines, instead of guessing
+	// it from words like "error" in the line; the first matching rule wins
+	LogLevels []LevelRule `yaml:"log_levels,omitempty"`
+
+	// ClearLogsOnRestart clears the service's logs when it restarts on a
+	// file change, a trigger or auto-restart, like a manual restart does
+	ClearLogsOnRestart bool `yaml:"clear_logs_on_restart,omitempty"`
+
+	// AutoPort starts the service on the next free port (passed via PORT and
+	// {{port}}) when its port is taken
+	AutoPort bool `yaml:"auto_port,omitempty"`
+
+	// HealthInterval is how often the health check runs once the service
+	// is up; it is checked every second right after it starts
+	HealthInterval time.Duration `yaml:"health_interval,omitempty"`
+
+	// Ready is a regexp matched against output; dependents wait for a
+	// matching line before they start
+	Ready string `yaml:"ready,omitempty"`
+
+	// WaitFor lists URLs and TCP addresses that must be reachable before
+	// the service starts
+	WaitFor        []string      `yaml:"wait_for,omitempty"`
+	WaitForTimeout time.Duration `yaml:"wait_for_timeout,omitempty"`
+
+	// StartTimeout fails and kills the service if it isn't ready in time
+	StartTimeout time.Duration `yaml:"start_timeout,omitempty"`
+
+	// Triggers run actions when the service prints matching lines
+	Triggers []Trigger `yaml:"triggers,omitempty"`
+
+	// PriorityNice is the CPU niceness (-20..19) of the service's processes;
+	// on Linux it also sets their IO priority
+	PriorityNice int `yaml:"priority_nice,omitempty"`
+
+	// Watch lists glob patterns (relative to cwd) that trigger a restart on change
+	Watch         []string      `yaml:"watch,omitempty"`
+	WatchDebounce time.Duration `yaml:"watch_debounce,omitempty"`
+
+	// Auto-restart stops once the service fails CrashLoopFailures times
+	// within CrashLoopWindow
+	CrashLoopFailures int           `yaml:"crash_loop_failures,omitempty"`
+	CrashLoopWindow   time.Duration `yaml:"crash_loop_window,omitempty"`
+
+	// The auto-restart counter resets once the service has run this long
+	StableAfter time.Duration `yaml:"stable_after,omitempty"`
+
+	// Replicas runs this many copies of the service, each with its own
+	// {{instance}} number and the port offset by instance-1
+	Replicas int `yaml:"replicas,omitempty"`
+
+	// URL is where the service is opened in a browser, when it isn't
+	// localhost on its port
+	URL string `yaml:"url,omitempty"`
+}
+
+// Levels of a LevelRule
+const (
+	LevelError = "error"
+	LevelWarn  = "warn"
+	LevelInfo  = "info"
+	LevelDebug = "debug"
+)
+
+// LevelRule sets the level, the color, or both of lines matching Match
+type LevelRule struct {
+	Match string `yaml:"match"`
+	Level string `yaml:"level,omitempty"`
+	Color string `yaml:"color,omitempty"` // hex, e.g. "#10B981"
 }
 
-// ServiceID uniquely identifies a service within a project
+// Trigger actions
+const (
+	TriggerRestart = "restart" // restart the service
+	TriggerRun     = "run"     // run a command in the service's cwd
+	TriggerNotify  = "notify"  // raise a desktop notification
+)
+
+// Trigger runs an action when a service prints a line matching Match
+type Trigger struct {
+	Match  string `yaml:"match"`
+	Action string `yaml:"action"`
+	Run    string `yaml:"run,omitempty"` // Command for the run action
+}
+
+// IsTask returns true if the service is a one-off task
+func (s Service) IsTask() bool {
+	return s.Type == ServiceTypeTask
+}
+
+// IsDocker returns true if the service runs in a Docker container
+func (s Service) IsDocker() bool {
+	return s.Type == ServiceTypeDocker
+}
+
+// IsReplicated returns true if the service runs more than one replica
+func (s Service) IsReplicated() bool {
+	return s.Replicas > 1
+}
+
+// BrowserURL returns where to open the service in a browser: url if set,
+// else localhost on its port, else the origin of its HTTP health check. It
+// is empty if the service has none of them.
+func (s Service) BrowserURL() string {
+	if s.URL != "" {
+		return s.URL
+	}
+	if s.Port > 0 {
+		return fmt.Sprintf("http://localhost:%d", s.Port)
+	}
+	if u, err := url.Parse(s.Health); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+		return u.Scheme + "://" + u.Host
+	}
+	return ""
+}
+
+// Replica returns the config of a replica (numbered from 1), with the port
+// offset by instance-1
+func (s Service) Replica(instance int) Service {
+	if s.Port > 0 {
+		s.Port += instance - 1
+	}
+	return s
+}
+
+// ServiceID uniquely identifies a service within a project. Instance
+// numbers the replicas of a replicated service, starting at 1; it is 0
+// otherwise.
 type ServiceID struct {
-	Project string
-	Service string
+	Project  string
+	Service  string
+	Instance int
 }
 
 // String returns a human-readable representation of ServiceID
 func (s ServiceID) String() string {
+	if s.Instance > 0 {
+		return fmt.Sprintf("%s/%s#%d", s.Project, s.Service, s.Instance)
+	}
 	return s.Project + "/" + s.Service
 }
+
+// Base returns the ID of the service a replica belongs to
+func (s ServiceID) Base() ServiceID {
+	s.Instance = 0
+	return s
+}
@@ -25,6 +25,11 @@ func TestServiceID_String(t *testing.T) {
 			id:       ServiceID{Project: "myproject", Service: ""},
 			expected: "myproject/",
 		},
+		{
+			name:     "replica",
+			id:       ServiceID{Project: "myproject", Service: "worker", Instance: 2},
+			expected: "myproject/worker#2",
+		},
 	}
 
 	for _, tt := range tests {
@@ -110,3 +115,84 @@ func TestConfig_RemoveProject(t *testing.T) {
 		t.Error("project2 should still exist")
 	}
 }
+
+func TestExpandTemplate(t *testing.T) {
+	vars := map[string]string{"port": "3000", "service": "api"}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"no placeholders", "npm run dev", "npm run dev"},
+		{"single", "vite --port {{port}}", "vite --port 3000"},
+		{"spaces inside braces", "vite --port {{ port }}", "vite --port 3000"},
+		{"multiple", "run {{service}} on {{port}}", "run api on 3000"},
+		{"missing value kept", "cd {{cwd}}", "cd {{cwd}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExpandTemplate(tt.input, vars)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseWaitTarget(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  WaitTarget
+		expectErr bool
+	}{
+		{input: "5432", expected: WaitTarget{Addr: "localhost:5432"}},
+		{input: "db.example.com:5432", expected: WaitTarget{Addr: "db.example.com:5432"}},
+		{input: "tcp://db.example.com:5432", expected: WaitTarget{Addr: "db.example.com:5432"}},
+		{input: "https://api.example.com/health", expected: WaitTarget{URL: "https://api.example.com/health"}},
+		{input: "db.example.com", expectErr: true},
+		{input: "ftp://example.com:21", expectErr: true},
+		{input: "70000", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			target, err := ParseWaitTarget(tt.input)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got %+v", target)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if target != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, target)
+			}
+		})
+	}
+}
+
+func TestService_BrowserURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		service  Service
+		expected string
+	}{
+		{name: "url", service: Service{URL: "https://app.localhost/admin", Port: 3000}, expected: "https://app.localhost/admin"},
+		{name: "port", service: Service{Port: 3000, Health: "http://localhost:8080/health"}, expected: "http://localhost:3000"},
+		{name: "health", service: Service{Health: "https://localhost:8443/health"}, expected: "https://localhost:8443"},
+		{name: "tcp health", service: Service{Health: "tcp://localhost:5432"}, expected: ""},
+		{name: "none", service: Service{}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.service.BrowserURL(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
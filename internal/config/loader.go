@@ -1,9 +1,16 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -70,15 +77,198 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("project %q: no services defined", name)
 		}
 		for svcName, svc := range project.Services {
-			if svc.Cmd == "" {
+			if strings.TrimSpace(svc.Cmd) == "" {
 				return fmt.Errorf("project %q, service %q: cmd is required", name, svcName)
 			}
+			switch svc.Type {
+			case "", ServiceTypeTask, ServiceTypeDocker:
+			default:
+				return fmt.Errorf("project %q, service %q: unknown type %q", name, svcName, svc.Type)
+			}
+			if svc.IsDocker() && strings.TrimSpace(strings.TrimPrefix(svc.Cmd, "container:")) == "" {
+				return fmt.Errorf("project %q, service %q: cmd: container name is required", name, svcName)
+			}
+			if err := validateTemplate(svc.Cmd); err != nil {
+				return fmt.Errorf("project %q, service %q: cmd: %w", name, svcName, err)
+			}
+			if len(svc.Argv) > 0 && svc.Argv[0] == "" {
+				return fmt.Errorf("project %q, service %q: cmd: program is required", name, svcName)
+			}
+			if err := validateTemplate(svc.Health); err != nil {
+				return fmt.Errorf("project %q, service %q: health: %w", name, svcName, err)
+			}
+			if err := validateHealthProbe(svc.Health); err != nil {
+				return fmt.Errorf("project %q, service %q: health: %w", name, svcName, err)
+			}
+			if err := validateTemplate(svc.HealthCmd); err != nil {
+				return fmt.Errorf("project %q, service %q: health: cmd: %w", name, svcName, err)
+			}
+			if err := validateTemplate(svc.URL); err != nil {
+				return fmt.Errorf("project %q, service %q: url: %w", name, svcName, err)
+			}
+			for i, trigger := range svc.Triggers {
+				if err := trigger.validate(); err != nil {
+					return fmt.Errorf("project %q, service %q: trigger %d: %w", name, svcName, i+1, err)
+				}
+			}
+			for i, rule := range svc.LogLevels {
+				if err := rule.validate(); err != nil {
+					return fmt.Errorf("project %q, service %q: log level rule %d: %w", name, svcName, i+1, err)
+				}
+			}
+			for _, target := range svc.WaitFor {
+				if _, err := ParseWaitTarget(target); err != nil {
+					return fmt.Errorf("project %q, service %q: wait_for: %w", name, svcName, err)
+				}
+			}
+			if svc.Replicas < 0 {
+				return fmt.Errorf("project %q, service %q: replicas must not be negative", name, svcName)
+			}
+			if svc.LogHistory < 0 {
+				return fmt.Errorf("project %q, service %q: log_history must not be negative", name, svcName)
+			}
+			if svc.PriorityNice < -20 || svc.PriorityNice > 19 {
+				return fmt.Errorf("project %q, service %q: priority_nice must be between -20 and 19", name, svcName)
+			}
+			if svc.Ready != "" {
+				if _, err := regexp.Compile(svc.Ready); err != nil {
+					return fmt.Errorf("project %q, service %q: invalid ready pattern: %w", name, svcName, err)
+				}
+			}
+		}
+	}
+
+	for i, sink := range c.Sinks {
+		if err := sink.validate(); err != nil {
+			return fmt.Errorf("sink %d: %w", i+1, err)
+		}
+	}
+
+	if c.Logs.MaxSizeMB < 0 || c.Logs.MaxFiles < 0 || c.Logs.MaxAge < 0 {
+		return fmt.Errorf("logs: max_size_mb, max_age and max_files must not be negative")
+	}
+	if c.Logs.History < 0 {
+		return fmt.Errorf("logs: history must not be negative")
+	}
+	if c.Logs.Timestamps != "" && !slices.Contains(TimestampFormats, c.Logs.Timestamps) {
+		return fmt.Errorf("logs: unknown timestamps format %q (use %s)", c.Logs.Timestamps, strings.Join(TimestampFormats, ", "))
+	}
+	if c.Logs.Timezone != "" && !strings.EqualFold(c.Logs.Timezone, "local") {
+		if _, err := time.LoadLocation(c.Logs.Timezone); err != nil {
+			return fmt.Errorf("logs: unknown timezone %q (use local, UTC or a name like Europe/Paris)", c.Logs.Timezone)
+		}
+	}
+	if c.Logs.TimeLayout != "" && !strings.ContainsAny(c.Logs.TimeLayout, "0123456789") {
+		return fmt.Errorf("logs: time_layout %q has no time fields (use a Go layout like 15:04:05)", c.Logs.TimeLayout)
+	}
+	if err := c.Theme.validate(); err != nil {
+		return fmt.Errorf("theme: %w", err)
+	}
+
+	return nil
+}
+
+// validateHealthProbe checks that a health URL uses a supported probe type
+func validateHealthProbe(health string) error {
+	scheme, _, ok := strings.Cut(health, "://")
+	if !ok {
+		return nil
+	}
+	switch scheme {
+	case "http", "https", "tcp", "grpc":
+		return nil
+	default:
+		return fmt.Errorf("unsupported probe type %q (use http, https, tcp or grpc)", scheme)
+	}
+}
+
+// validate checks a trigger's pattern and action
+func (t Trigger) validate() error {
+	if t.Match == "" {
+		return fmt.Errorf("match is required")
+	}
+	if _, err := regexp.Compile(t.Match); err != nil {
+		return fmt.Errorf("invalid match pattern: %w", err)
+	}
+
+	switch t.Action {
+	case TriggerRestart, TriggerNotify:
+	case TriggerRun:
+		if t.Run == "" {
+			return fmt.Errorf("run is required for the run action")
 		}
+	default:
+		return fmt.Errorf("unknown action %q", t.Action)
 	}
+	return nil
+}
+
+// hexColor matches colors like #10B981
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
 
+// validate checks that a level rule has a valid pattern and sets a known
+// level or a color
+func (r LevelRule) validate() error {
+	if r.Match == "" {
+		return fmt.Errorf("match is required")
+	}
+	if _, err := regexp.Compile(r.Match); err != nil {
+		return fmt.Errorf("invalid match pattern: %w", err)
+	}
+
+	switch r.Level {
+	case LevelError, LevelWarn, LevelInfo, LevelDebug:
+	case "":
+		if r.Color == "" {
+			return fmt.Errorf("level or color is required")
+		}
+	default:
+		return fmt.Errorf("unknown level %q", r.Level)
+	}
+	if r.Color != "" && !hexColor.MatchString(r.Color) {
+		return fmt.Errorf("invalid color %q (use hex like #10B981)", r.Color)
+	}
 	return nil
 }
 
+// validate checks that a sink has what its type needs
+func (s Sink) validate() error {
+	switch s.Type {
+	case SinkFile:
+		if s.Path == "" {
+			return fmt.Errorf("path is required for the file sink")
+		}
+	case SinkSyslog:
+		if s.Address != "" {
+			if _, _, err := s.SyslogAddress(); err != nil {
+				return err
+			}
+		}
+	case SinkLoki, SinkOTLP:
+		u, err := url.Parse(s.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("url must be an http(s) URL for the %s sink", s.Type)
+		}
+	default:
+		return fmt.Errorf("unknown type %q", s.Type)
+	}
+	return nil
+}
+
+// SyslogAddress returns the network and address of a remote syslog sink
+func (s Sink) SyslogAddress() (network, addr string, err error) {
+	u, err := url.Parse(s.Address)
+	if err != nil || u.Host == "" {
+		return "", "", fmt.Errorf("invalid syslog address %q (must be udp://host:port or tcp://host:port)", s.Address)
+	}
+	switch u.Scheme {
+	case "udp", "tcp":
+		return u.Scheme, u.Host, nil
+	default:
+		return "", "", fmt.Errorf("invalid syslog address %q (must be udp://host:port or tcp://host:port)", s.Address)
+	}
+}
+
 // expandPaths expands ~ to home directory in all paths
 func (c *Config) expandPaths() {
 	home, _ := os.UserHomeDir()
@@ -146,6 +336,25 @@ func (c *Config) AllServices() []ServiceID {
 	return services
 }
 
+// StateDir returns the directory paraler keeps session state in
+func StateDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "paraler")
+}
+
+// StateKey returns a short key identifying a config file, so each config
+// gets its own state and separate sessions don't clobber each other
+func StateKey(configPath string) string {
+	if abs, err := filepath.Abs(configPath); err == nil {
+		configPath = abs
+	}
+	sum := sha256.Sum256([]byte(configPath))
+	return hex.EncodeToString(sum[:8])
+}
+
 // Save writes the configuration to a file
 func (c *Config) Save(path string) error {
 	// Ensure directory exists
@@ -294,6 +503,7 @@ func (c *Config) RenameProject(oldName, newName string) error {
 	// Add with new name and remove old
 	c.Projects[newName] = project
 	delete(c.Projects, oldName)
+	c.ProjectOrder = renameInOrder(c.ProjectOrder, oldName, newName)
 
 	return nil
 }
@@ -325,7 +535,75 @@ func (c *Config) RenameService(projectName, oldName, newName string) error {
 	// Add with new name and remove old
 	project.Services[newName] = service
 	delete(project.Services, oldName)
+	project.ServiceOrder = renameInOrder(project.ServiceOrder, oldName, newName)
 	c.Projects[projectName] = project
 
 	return nil
 }
+
+// UpdateService replaces the config of a service, keeping the old one if
+// the new config is invalid
+func (c *Config) UpdateService(projectName, serviceName string, service Service) error {
+	project, ok := c.Projects[projectName]
+	if !ok {
+		return fmt.Errorf("project %q not found", projectName)
+	}
+	old, ok := project.Services[serviceName]
+	if !ok {
+		return fmt.Errorf("service %q not found in project %q", serviceName, projectName)
+	}
+
+	for _, dep := range service.DependsOn {
+		if dep == serviceName {
+			return fmt.Errorf("depends_on: service cannot depend on itself")
+		}
+		if _, ok := project.Services[dep]; !ok {
+			return fmt.Errorf("depends_on: service %q not found in project %q", dep, projectName)
+		}
+	}
+
+	project.Services[serviceName] = service
+	if err := c.Validate(); err != nil {
+		project.Services[serviceName] = old
+		return err
+	}
+	return nil
+}
+
+// AddService adds a service to a project, leaving the config unchanged if
+// the service is invalid
+func (c *Config) AddService(projectName, serviceName string, service Service) error {
+	project, ok := c.Projects[projectName]
+	if !ok {
+		return fmt.Errorf("project %q not found", projectName)
+	}
+	if serviceName == "" {
+		return fmt.Errorf("service name cannot be empty")
+	}
+	if strings.ContainsAny(serviceName, "/# \t") {
+		return fmt.Errorf("service name %q must not contain '/', '#' or spaces", serviceName)
+	}
+	if _, exists := project.Services[serviceName]; exists {
+		return fmt.Errorf("service %q already exists in project %q", serviceName, projectName)
+	}
+	for _, dep := range service.DependsOn {
+		if _, ok := project.Services[dep]; !ok {
+			return fmt.Errorf("depends_on: service %q not found in project %q", dep, projectName)
+		}
+	}
+
+	if project.Services == nil {
+		project.Services = make(map[string]Service)
+	}
+	project.Services[serviceName] = service
+	c.Projects[projectName] = project
+	if err := c.Validate(); err != nil {
+		delete(project.Services, serviceName)
+		return err
+	}
+	if len(project.ServiceOrder) > 0 {
+		project.ServiceOrder = append(project.ServiceOrder, serviceName)
+		c.Projects[projectName] = project
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -106,6 +107,211 @@ func TestLoadAndSave(t *testing.T) {
 	}
 }
 
+func TestLoadArgvCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	data := `projects:
+  app:
+    path: /srv/app
+    services:
+      api:
+        cmd: ["./bin/server", "--name", "my api"]
+      web:
+        cmd: npm run dev
+`
+	if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	api := cfg.Projects["app"].Services["api"]
+	if len(api.Argv) != 3 || api.Argv[2] != "my api" {
+		t.Errorf("expected argv to be kept, got %q", api.Argv)
+	}
+	if api.Cmd != "./bin/server --name my api" {
+		t.Errorf("expected joined cmd, got %q", api.Cmd)
+	}
+	if web := cfg.Projects["app"].Services["web"]; web.Argv != nil || web.Cmd != "npm run dev" {
+		t.Errorf("expected shell cmd, got %q / %q", web.Cmd, web.Argv)
+	}
+
+	// Saving keeps the list form
+	if err := cfg.Save(configPath); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+	reloaded, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to reload config: %v", err)
+	}
+	if argv := reloaded.Projects["app"].Services["api"].Argv; len(argv) != 3 || argv[2] != "my api" {
+		t.Errorf("expected argv to survive save, got %q", argv)
+	}
+}
+
+func TestLoadOrder(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	data := `projects:
+  web:
+    path: /srv/web
+    services:
+      ui:
+        cmd: npm run dev
+      api:
+        cmd: go run .
+  db:
+    path: /srv/db
+    services:
+      postgres:
+        cmd: postgres
+`
+	if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if got := strings.Join(cfg.OrderedProjectNames(), ","); got != "web,db" {
+		t.Errorf("expected projects in file order, got %s", got)
+	}
+	if got := strings.Join(cfg.Projects["web"].OrderedServiceNames(), ","); got != "ui,api" {
+		t.Errorf("expected services in file order, got %s", got)
+	}
+
+	// Renamed services keep their place, added ones come last
+	if err := cfg.RenameService("web", "ui", "frontend"); err != nil {
+		t.Fatalf("failed to rename service: %v", err)
+	}
+	cfg.AddProject("cache", Project{Path: "/srv/cache", Services: map[string]Service{"redis": {Cmd: "redis-server"}}})
+
+	// Saving keeps the order
+	if err := cfg.Save(configPath); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+	reloaded, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to reload config: %v", err)
+	}
+	if got := strings.Join(reloaded.ProjectOrder, ","); got != "web,db,cache" {
+		t.Errorf("expected project order to survive save, got %s", got)
+	}
+	if got := strings.Join(reloaded.Projects["web"].ServiceOrder, ","); got != "frontend,api" {
+		t.Errorf("expected service order to survive save, got %s", got)
+	}
+}
+
+func TestLoadHealthCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	data := `projects:
+  app:
+    path: /srv/app
+    services:
+      db:
+        cmd: postgres
+        health: { cmd: "pg_isready -h localhost" }
+      api:
+        cmd: ./server
+        health: http://localhost:8080/health
+`
+	if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	db := cfg.Projects["app"].Services["db"]
+	if db.HealthCmd != "pg_isready -h localhost" || db.Health != "" {
+		t.Errorf("expected health command, got %q / %q", db.HealthCmd, db.Health)
+	}
+	if api := cfg.Projects["app"].Services["api"]; api.HealthCmd != "" || api.Health != "http://localhost:8080/health" {
+		t.Errorf("expected health URL, got %q / %q", api.HealthCmd, api.Health)
+	}
+
+	// Saving keeps the mapping form
+	if err := cfg.Save(configPath); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+	reloaded, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to reload config: %v", err)
+	}
+	if cmd := reloaded.Projects["app"].Services["db"].HealthCmd; cmd != "pg_isready -h localhost" {
+		t.Errorf("expected health command to survive save, got %q", cmd)
+	}
+
+	// The mapping needs a command
+	data = strings.Replace(data, `{ cmd: "pg_isready -h localhost" }`, `{ url: "x" }`, 1)
+	if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if _, err := Load(configPath); err == nil {
+		t.Error("expected error for health without cmd")
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	data := `theme: light
+projects:
+  app:
+    path: /srv/app
+    services:
+      api:
+        cmd: ./server
+`
+	if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.Theme.Preset != ThemeLight || cfg.Theme.Colors != nil {
+		t.Errorf("expected light preset, got %+v", cfg.Theme)
+	}
+
+	// Saving keeps the name form
+	if err := cfg.Save(configPath); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+	saved, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	if !strings.Contains(string(saved), "theme: light") {
+		t.Errorf("expected theme name to survive save, got:\n%s", saved)
+	}
+
+	// The mapping form overrides colors
+	data = strings.Replace(data, "theme: light", "theme:\n  preset: high-contrast\n  colors:\n    primary: \"#FF79C6\"", 1)
+	if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	cfg, err = Load(configPath)
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.Theme.Preset != ThemeHighContrast || cfg.Theme.Colors["primary"] != "#FF79C6" {
+		t.Errorf("expected theme mapping, got %+v", cfg.Theme)
+	}
+}
+
 func TestValidate(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -159,6 +365,353 @@ func TestValidate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "docker service with blank cmd",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"svc": {Cmd: "   ", Type: ServiceTypeDocker},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "task service",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"migrate": {Type: ServiceTypeTask, Cmd: "npm run migrate"},
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "unknown service type",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"svc": {Type: "daemon", Cmd: "npm run dev"},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "unknown cmd placeholder",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"svc": {Cmd: "vite --port {{prot}}"},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "unknown trigger action",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"svc": {Cmd: "npm run dev", Triggers: []Trigger{{Match: "OOM", Action: "reboot"}}},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid ready pattern",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"svc": {Cmd: "npm run dev", Ready: "listening on (port"},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "docker service",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"db": {Type: ServiceTypeDocker, Cmd: "postgres:16", Port: 5432},
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "docker service without container name",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"db": {Type: ServiceTypeDocker, Cmd: "container:"},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "grpc health probe",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"api": {Cmd: "./server", Health: "grpc://localhost:{{port}}/api.v1.Users"},
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "unsupported health probe",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"api": {Cmd: "./server", Health: "udp://localhost:9000"},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "valid log level rules",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"api": {Cmd: "./server", LogLevels: []LevelRule{
+								{Match: `\b0 failed`, Level: LevelInfo},
+								{Match: `^webpack`, Color: "#10B981"},
+							}},
+						},
+					},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "log level rule with unknown level",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"api": {Cmd: "./server", LogLevels: []LevelRule{
+								{Match: "failed", Level: "fatal"},
+							}},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "log level rule with invalid color",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"api": {Cmd: "./server", LogLevels: []LevelRule{
+								{Match: "failed", Color: "green"},
+							}},
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "valid sinks",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"api": {Cmd: "./server"},
+						},
+					},
+				},
+				Sinks: []Sink{
+					{Type: SinkFile, Path: "~/paraler.log"},
+					{Type: SinkSyslog, Address: "udp://localhost:514"},
+					{Type: SinkLoki, URL: "http://localhost:3100/loki/api/v1/push"},
+				},
+			},
+			expectErr: false,
+		},
+		{
+			name: "loki sink without url",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"api": {Cmd: "./server"},
+						},
+					},
+				},
+				Sinks: []Sink{
+					{Type: SinkLoki},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "unknown sink type",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"api": {Cmd: "./server"},
+						},
+					},
+				},
+				Sinks: []Sink{
+					{Type: "kafka"},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name: "unknown timestamps format",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"api": {Cmd: "./server"},
+						},
+					},
+				},
+				Logs: Logs{Timestamps: "iso"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "unknown timezone",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"api": {Cmd: "./server"},
+						},
+					},
+				},
+				Logs: Logs{Timezone: "Mars/Olympus"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "timezone and time layout",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"api": {Cmd: "./server"},
+						},
+					},
+				},
+				Logs: Logs{Timezone: "UTC", TimeLayout: "15:04:05.000"},
+			},
+			expectErr: false,
+		},
+		{
+			name: "time layout without fields",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"api": {Cmd: "./server"},
+						},
+					},
+				},
+				Logs: Logs{TimeLayout: "time"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "theme with colors",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"api": {Cmd: "./server"},
+						},
+					},
+				},
+				Theme: Theme{Preset: ThemeLight, Colors: map[string]string{"primary": "#f0a", "border": "240"}},
+			},
+			expectErr: false,
+		},
+		{
+			name: "unknown theme",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"api": {Cmd: "./server"},
+						},
+					},
+				},
+				Theme: Theme{Preset: "solarized"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "invalid theme color",
+			config: &Config{
+				Projects: map[string]Project{
+					"test": {
+						Path: "/test",
+						Services: map[string]Service{
+							"api": {Cmd: "./server"},
+						},
+					},
+				},
+				Theme: Theme{Colors: map[string]string{"primary": "purple"}},
+			},
+			expectErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -173,3 +726,87 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateService(t *testing.T) {
+	cfg := &Config{
+		Projects: map[string]Project{
+			"app": {
+				Path: "/app",
+				Services: map[string]Service{
+					"api": {Cmd: "./server", Port: 3000},
+					"db":  {Cmd: "postgres"},
+				},
+			},
+		},
+	}
+
+	updated := Service{Cmd: "./server --debug", Port: 3001, DependsOn: []string{"db"}}
+	if err := cfg.UpdateService("app", "api", updated); err != nil {
+		t.Fatalf("UpdateService: %v", err)
+	}
+	if got := cfg.Projects["app"].Services["api"]; got.Cmd != updated.Cmd || got.Port != 3001 {
+		t.Errorf("expected the updated service, got %+v", got)
+	}
+
+	invalid := []Service{
+		{Cmd: ""},
+		{Cmd: "./server", Health: "ftp://localhost"},
+		{Cmd: "./server", DependsOn: []string{"cache"}},
+		{Cmd: "./server", DependsOn: []string{"api"}},
+	}
+	for _, svc := range invalid {
+		if err := cfg.UpdateService("app", "api", svc); err == nil {
+			t.Errorf("expected an error for %+v", svc)
+		}
+		if got := cfg.Projects["app"].Services["api"]; got.Cmd != updated.Cmd {
+			t.Errorf("expected the service to be kept after an error, got %+v", got)
+		}
+	}
+
+	if err := cfg.UpdateService("app", "web", updated); err == nil {
+		t.Error("expected an error for an unknown service")
+	}
+}
+
+func TestAddService(t *testing.T) {
+	cfg := &Config{
+		Projects: map[string]Project{
+			"app": {
+				Path:         "/app",
+				Services:     map[string]Service{"db": {Cmd: "postgres"}, "api": {Cmd: "./server"}},
+				ServiceOrder: []string{"db", "api"},
+			},
+		},
+	}
+
+	added := Service{Cmd: "stripe listen", Env: []string{"STRIPE_KEY=sk"}, DependsOn: []string{"api"}}
+	if err := cfg.AddService("app", "stripe", added); err != nil {
+		t.Fatalf("AddService: %v", err)
+	}
+	if got := cfg.Projects["app"].Services["stripe"]; got.Cmd != added.Cmd {
+		t.Errorf("expected the added service, got %+v", got)
+	}
+	if got, want := strings.Join(cfg.Projects["app"].OrderedServiceNames(), ","), "db,api,stripe"; got != want {
+		t.Errorf("expected order %s, got %s", want, got)
+	}
+
+	invalid := []struct {
+		project, name string
+		service       Service
+	}{
+		{"app", "", Service{Cmd: "redis-server"}},
+		{"app", "my cache", Service{Cmd: "redis-server"}},
+		{"app", "db", Service{Cmd: "redis-server"}},
+		{"app", "cache", Service{Cmd: ""}},
+		{"app", "cache", Service{Cmd: "redis-server", DependsOn: []string{"queue"}}},
+		{"web", "cache", Service{Cmd: "redis-server"}},
+	}
+	for _, tt := range invalid {
+		if err := cfg.AddService(tt.project, tt.name, tt.service); err == nil {
+			t.Errorf("expected an error adding %q to %q", tt.name, tt.project)
+		}
+	}
+	if got := len(cfg.Projects["app"].Services); got != 3 {
+		t.Errorf("expected 3 services after the errors, got %d", got)
+	}
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"slices"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnmarshalYAML records the order the projects are listed in
+func (c *Config) UnmarshalYAML(value *yaml.Node) error {
+	type plain Config
+	if err := value.Decode((*plain)(c)); err != nil {
+		return err
+	}
+	c.ProjectOrder = mappingKeys(value, "projects")
+	return nil
+}
+
+// MarshalYAML writes the projects in the order they were listed in
+func (c Config) MarshalYAML() (interface{}, error) {
+	type plain Config
+
+	var node yaml.Node
+	if err := node.Encode(plain(c)); err != nil {
+		return nil, err
+	}
+	reorderMapping(&node, "projects", c.OrderedProjectNames())
+	return &node, nil
+}
+
+// UnmarshalYAML records the order the services are listed in
+func (p *Project) UnmarshalYAML(value *yaml.Node) error {
+	type plain Project
+	if err := value.Decode((*plain)(p)); err != nil {
+		return err
+	}
+	p.ServiceOrder = mappingKeys(value, "services")
+	return nil
+}
+
+// MarshalYAML writes the services in the order they were listed in
+func (p Project) MarshalYAML() (interface{}, error) {
+	type plain Project
+
+	var node yaml.Node
+	if err := node.Encode(plain(p)); err != nil {
+		return nil, err
+	}
+	reorderMapping(&node, "services", p.OrderedServiceNames())
+	return &node, nil
+}
+
+// OrderedProjectNames returns the project names in the order of the config
+// file, followed by projects added since, sorted
+func (c *Config) OrderedProjectNames() []string {
+	return orderedKeys(c.Projects, c.ProjectOrder)
+}
+
+// OrderedServiceNames returns the service names in the order of the config
+// file, followed by services added since, sorted
+func (p Project) OrderedServiceNames() []string {
+	return orderedKeys(p.Services, p.ServiceOrder)
+}
+
+// orderedKeys returns the keys of m listed in order, in that order,
+// followed by the others sorted
+func orderedKeys[V any](m map[string]V, order []string) []string {
+	keys := make([]string, 0, len(m))
+	for _, key := range order {
+		if _, ok := m[key]; ok && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	var rest []string
+	for key := range m {
+		if !slices.Contains(keys, key) {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+// renameInOrder replaces a name in an order list
+func renameInOrder(order []string, oldName, newName string) []string {
+	order = slices.Clone(order)
+	if i := slices.Index(order, oldName); i >= 0 {
+		order[i] = newName
+	}
+	return order
+}
+
+// mappingKeys returns the keys of the mapping under key in a mapping node,
+// in the order they are listed
+func mappingKeys(node *yaml.Node, key string) []string {
+	value := mappingValue(node, key)
+	if value == nil || value.Kind != yaml.MappingNode {
+		return nil
+	}
+	keys := make([]string, 0, len(value.Content)/2)
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		keys = append(keys, value.Content[i].Value)
+	}
+	return keys
+}
+
+// reorderMapping sorts the entries of the mapping under key in a mapping
+// node in the given order
+func reorderMapping(node *yaml.Node, key string, order []string) {
+	value := mappingValue(node, key)
+	if value == nil || value.Kind != yaml.MappingNode {
+		return
+	}
+	type entry struct{ key, value *yaml.Node }
+	entries := make([]entry, 0, len(value.Content)/2)
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		entries = append(entries, entry{value.Content[i], value.Content[i+1]})
+	}
+	sort.SliceStable(entries, func(a, b int) bool {
+		return slices.Index(order, entries[a].key.Value) < slices.Index(order, entries[b].key.Value)
+	})
+	value.Content = value.Content[:0]
+	for _, e := range entries {
+		value.Content = append(value.Content, e.key, e.value)
+	}
+}
+
+// mappingValue returns the value under key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
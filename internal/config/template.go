@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// TemplateVars lists the placeholders usable in cmd and health, resolved
+// when the service starts
+var TemplateVars = []string{"port", "project", "project_path", "service", "cwd", "instance"}
+
+var templatePattern = regexp.MustCompile(`\{\{\s*(\w+)\s*\}\}`)
+
+// ExpandTemplate replaces {{name}} placeholders with their values.
+// Placeholders without a value are left as-is.
+func ExpandTemplate(s string, vars map[string]string) string {
+	return templatePattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := templatePattern.FindStringSubmatch(match)[1]
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// UsesTemplateVar returns true if s contains the {{name}} placeholder
+func UsesTemplateVar(s, name string) bool {
+	for _, m := range templatePattern.FindAllStringSubmatch(s, -1) {
+		if m[1] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTemplate checks that s only uses known placeholders
+func validateTemplate(s string) error {
+	for _, m := range templatePattern.FindAllStringSubmatch(s, -1) {
+		known := false
+		for _, name := range TemplateVars {
+			if m[1] == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown placeholder %s", m[0])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Built-in themes
+const (
+	ThemeDark         = "dark" // default
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+)
+
+// ThemePresets lists the built-in themes
+var ThemePresets = []string{ThemeDark, ThemeLight, ThemeHighContrast}
+
+// ThemeColors lists the colors of a theme that can be overridden
+var ThemeColors = []string{
+	"primary",   // focused borders and titles, keys, selection markers
+	"accent",    // modal borders and titles
+	"text",      // log lines and list items
+	"subtle",    // secondary text and values
+	"muted",     // unfocused titles, timestamps and hints
+	"dimmed",    // lines dimmed by a highlight filter
+	"border",    // unfocused borders and the copy mode cursor
+	"surface",   // background of selected list items
+	"selection", // background of lines selected in copy mode
+	"success",   // running and healthy
+	"warning",   // starting, warnings and filter matches
+	"error",     // failed, errors and stderr
+	"info",      // paused and debug lines
+	"contrast",  // text on filter matches
+}
+
+// Theme selects the colors of the UI: a built-in preset, with some of its
+// colors overridden by hex codes ("#8B5CF6") or ANSI color numbers ("13").
+// It is configured either as the name of the preset or as a mapping.
+type Theme struct {
+	Preset string            `yaml:"preset,omitempty"`
+	Colors map[string]string `yaml:"colors,omitempty"`
+}
+
+// UnmarshalYAML accepts theme either as the name of a preset or as a
+// {preset: ..., colors: ...} mapping
+func (t *Theme) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		t.Preset = value.Value
+		return nil
+	}
+	type plain Theme
+	return value.Decode((*plain)(t))
+}
+
+// MarshalYAML writes a theme without color overrides as its preset name
+func (t Theme) MarshalYAML() (interface{}, error) {
+	if len(t.Colors) == 0 {
+		return t.Preset, nil
+	}
+	type plain Theme
+	return plain(t), nil
+}
+
+// IsZero reports whether the theme is unset, so it's omitted when saving
+func (t Theme) IsZero() bool {
+	return t.Preset == "" && len(t.Colors) == 0
+}
+
+// validate checks the preset and the colors of a theme
+func (t Theme) validate() error {
+	if t.Preset != "" && !slices.Contains(ThemePresets, t.Preset) {
+		return fmt.Errorf("unknown preset %q (use %s)", t.Preset, strings.Join(ThemePresets, ", "))
+	}
+	for name, color := range t.Colors {
+		if !slices.Contains(ThemeColors, name) {
+			return fmt.Errorf("unknown color %q (use %s)", name, strings.Join(ThemeColors, ", "))
+		}
+		if !validColor(color) {
+			return fmt.Errorf("color %s: %q is no hex code (#RGB or #RRGGBB) or ANSI color number (0-255)", name, color)
+		}
+	}
+	return nil
+}
+
+// validColor returns true if color is a hex code or an ANSI color number
+func validColor(color string) bool {
+	if hex, ok := strings.CutPrefix(color, "#"); ok {
+		if len(hex) != 3 && len(hex) != 6 {
+			return false
+		}
+		_, err := strconv.ParseUint(hex, 16, 32)
+		return err == nil
+	}
+	n, err := strconv.Atoi(color)
+	return err == nil && n >= 0 && n <= 255
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+)
+
+// WaitTarget is a parsed wait_for entry: either an http(s) URL that must
+// answer, or a TCP address that must accept connections
+type WaitTarget struct {
+	URL  string
+	Addr string
+}
+
+// ParseWaitTarget parses a wait_for entry: an http(s) URL, tcp://host:port,
+// host:port or a bare port on localhost
+func ParseWaitTarget(s string) (WaitTarget, error) {
+	if port, err := strconv.Atoi(s); err == nil {
+		if port < 1 || port > 65535 {
+			return WaitTarget{}, fmt.Errorf("invalid port %d", port)
+		}
+		return WaitTarget{Addr: net.JoinHostPort("localhost", s)}, nil
+	}
+
+	u, err := url.Parse(s)
+	if err == nil && u.Host != "" {
+		switch u.Scheme {
+		case "http", "https":
+			return WaitTarget{URL: s}, nil
+		case "tcp":
+			s = u.Host
+		default:
+			return WaitTarget{}, fmt.Errorf("unsupported scheme %q", u.Scheme)
+		}
+	}
+
+	host, port, err := net.SplitHostPort(s)
+	if err != nil || host == "" || port == "" {
+		return WaitTarget{}, fmt.Errorf("%q is not a URL or host:port", s)
+	}
+	return WaitTarget{Addr: s}, nil
+}
+
+// String returns the URL or address of the target
+func (t WaitTarget) String() string {
+	if t.URL != "" {
+		return t.URL
+	}
+	return t.Addr
+}
@@ -66,14 +66,29 @@ func (p *DetectedProject) MergeIntoConfig(cfg *config.Config) {
 // DefaultPorts returns default ports for known frameworks
 func DefaultPorts() map[Framework]int {
 	return map[Framework]int{
-		FrameworkNestJS:  3000,
-		FrameworkExpress: 3000,
-		FrameworkFastify: 3000,
-		FrameworkReact:   3000, // CRA default
-		FrameworkVue:     8080,
-		FrameworkSvelte:  5173, // Vite default
-		FrameworkNext:    3000,
-		FrameworkNuxt:    3000,
+		FrameworkNestJS:     3000,
+		FrameworkExpress:    3000,
+		FrameworkFastify:    3000,
+		FrameworkReact:      3000, // CRA default
+		FrameworkVue:        8080,
+		FrameworkSvelte:     5173, // Vite default
+		FrameworkNext:       3000,
+		FrameworkNuxt:       3000,
+		FrameworkAngular:    4200,
+		FrameworkVite:       5173,
+		FrameworkAstro:      4321,
+		FrameworkRemix:      3000,
+		FrameworkSvelteKit:  5173,
+		FrameworkSolidStart: 3000,
+		FrameworkDeno:       8000,
+		FrameworkDjango:     8000,
+		FrameworkFastAPI:    8000,
+		FrameworkFlask:      5000,
+		FrameworkRails:      3000,
+		FrameworkRuby:       9292,
+		FrameworkLaravel:    8000,
+		FrameworkPHP:        8000,
+		FrameworkSpringBoot: 8080,
 	}
 }
 
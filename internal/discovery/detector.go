@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"gopkg.in/yaml.v3"
 )
@@ -26,19 +27,36 @@ const (
 type Framework string
 
 const (
-	FrameworkNestJS    Framework = "nestjs"
-	FrameworkExpress   Framework = "express"
-	FrameworkFastify   Framework = "fastify"
-	FrameworkReact     Framework = "react"
-	FrameworkVue       Framework = "vue"
-	FrameworkSvelte    Framework = "svelte"
-	FrameworkNext      Framework = "next"
-	FrameworkNuxt      Framework = "nuxt"
-	FrameworkGo        Framework = "go"
-	FrameworkRust      Framework = "rust"
-	FrameworkPython    Framework = "python"
-	FrameworkFlutter   Framework = "flutter"
-	FrameworkUnknown   Framework = "unknown"
+	FrameworkNestJS     Framework = "nestjs"
+	FrameworkExpress    Framework = "express"
+	FrameworkFastify    Framework = "fastify"
+	FrameworkReact      Framework = "react"
+	FrameworkVue        Framework = "vue"
+	FrameworkSvelte     Framework = "svelte"
+	FrameworkNext       Framework = "next"
+	FrameworkNuxt       Framework = "nuxt"
+	FrameworkAngular    Framework = "angular"
+	FrameworkVite       Framework = "vite"
+	FrameworkAstro      Framework = "astro"
+	FrameworkRemix      Framework = "remix"
+	FrameworkSvelteKit  Framework = "sveltekit"
+	FrameworkSolidStart Framework = "solid-start"
+	FrameworkDeno       Framework = "deno"
+	FrameworkNx         Framework = "nx"
+	FrameworkGo         Framework = "go"
+	FrameworkRust       Framework = "rust"
+	FrameworkPython     Framework = "python"
+	FrameworkDjango     Framework = "django"
+	FrameworkFastAPI    Framework = "fastapi"
+	FrameworkFlask      Framework = "flask"
+	FrameworkRails      Framework = "rails"
+	FrameworkRuby       Framework = "ruby"
+	FrameworkLaravel    Framework = "laravel"
+	FrameworkPHP        Framework = "php"
+	FrameworkSpringBoot Framework = "spring-boot"
+	FrameworkJVM        Framework = "jvm"
+	FrameworkFlutter    Framework = "flutter"
+	FrameworkUnknown    Framework = "unknown"
 )
 
 // DetectedService represents a discovered service
@@ -56,10 +74,11 @@ type DetectedService struct {
 
 // PackageJSON represents parsed package.json
 type PackageJSON struct {
-	Name         string            `json:"name"`
-	Scripts      map[string]string `json:"scripts"`
-	Dependencies map[string]string `json:"dependencies"`
-	DevDeps      map[string]string `json:"devDependencies"`
+	Name           string            `json:"name"`
+	Scripts        map[string]string `json:"scripts"`
+	Dependencies   map[string]string `json:"dependencies"`
+	DevDeps        map[string]string `json:"devDependencies"`
+	PackageManager string            `json:"packageManager"`
 }
 
 // DetectedProject represents a discovered project
@@ -115,7 +134,63 @@ func (d *Detector) Detect(projectPath string) (*DetectedProject, error) {
 
 	// Scan root directory
 	rootServices := d.scanDirectory(absPath, "")
-	project.Services = append(project.Services, rootServices...)
+
+	// Directories scanned as monorepo projects, which the scans below skip
+	scanned := make(map[string]bool)
+	monorepo := false
+
+	// Scan Nx projects, run through nx from the root
+	nxServices, nxDirs := d.detectNxWorkspace(absPath)
+	for _, dir := range nxDirs {
+		scanned[dir] = true
+	}
+	project.Services = append(project.Services, nxServices...)
+	monorepo = len(nxServices) > 0
+
+	// Scan workspace packages, run from the root through the workspace's
+	// tool
+	if ws := readWorkspace(absPath); ws != nil {
+		for _, dir := range ws.dirs {
+			if scanned[dir] {
+				continue
+			}
+			scanned[dir] = true
+			for _, svc := range d.scanDirectory(filepath.Join(absPath, dir), dir) {
+				if svc.PackageJSON != nil {
+					if script := d.findNodeDevScript(svc.PackageJSON); script != "" {
+						svc.DevCommand = ws.runCommand(svc.PackageJSON, dir, script)
+						svc.Command = svc.DevCommand
+						svc.Path = ""
+					}
+				}
+				project.Services = append(project.Services, svc)
+				monorepo = true
+			}
+		}
+	}
+	// Scan the modules of a Go workspace
+	var moduleServices []DetectedService
+	for _, dir := range readGoWork(absPath) {
+		if scanned[dir] {
+			continue
+		}
+		scanned[dir] = true
+		moduleServices = append(moduleServices, d.scanDirectory(filepath.Join(absPath, dir), dir)...)
+	}
+	project.Services = append(project.Services, prefixModuleNames(moduleServices)...)
+
+	// The root's own scripts usually start every project at once, so
+	// they're left out of a monorepo
+	if monorepo {
+		var kept []DetectedService
+		for _, svc := range rootServices {
+			if svc.PackageJSON == nil {
+				kept = append(kept, svc)
+			}
+		}
+		rootServices = kept
+	}
+	project.Services = append(rootServices, project.Services...)
 
 	// Scan common subdirectories
 	subdirs := []string{
@@ -126,6 +201,9 @@ func (d *Detector) Detect(projectPath string) (*DetectedProject, error) {
 
 	for _, subdir := range subdirs {
 		subPath := filepath.Join(absPath, subdir)
+		if scanned[subdir] {
+			continue
+		}
 		if info, err := os.Stat(subPath); err == nil && info.IsDir() {
 			services := d.scanDirectory(subPath, subdir)
 			project.Services = append(project.Services, services...)
@@ -137,9 +215,9 @@ func (d *Detector) Detect(projectPath string) (*DetectedProject, error) {
 		packagesPath := filepath.Join(absPath, monorepoDir)
 		if entries, err := os.ReadDir(packagesPath); err == nil {
 			for _, entry := range entries {
-				if entry.IsDir() {
+				relPath := filepath.Join(monorepoDir, entry.Name())
+				if entry.IsDir() && !scanned[relPath] {
 					pkgPath := filepath.Join(packagesPath, entry.Name())
-					relPath := filepath.Join(monorepoDir, entry.Name())
 					services := d.scanDirectory(pkgPath, relPath)
 					project.Services = append(project.Services, services...)
 				}
@@ -167,19 +245,62 @@ func (d *Detector) Detect(projectPath string) (*DetectedProject, error) {
 	return project, nil
 }
 
+// Markers are the files a service is detected by
+var Markers = []string{
+	"package.json",
+	"deno.json",
+	"deno.jsonc",
+	"go.mod",
+	"go.work",
+	"Cargo.toml",
+	"requirements.txt",
+	"pyproject.toml",
+	"setup.py",
+	"Pipfile",
+	"Gemfile",
+	"config.ru",
+	"composer.json",
+	"build.gradle",
+	"build.gradle.kts",
+	"pom.xml",
+}
+
+// FindMarkers returns the markers present in a directory, in the order of
+// Markers
+func FindMarkers(dirPath string) []string {
+	var found []string
+	for _, marker := range Markers {
+		if info, err := os.Stat(filepath.Join(dirPath, marker)); err == nil && !info.IsDir() {
+			found = append(found, marker)
+		}
+	}
+	return found
+}
+
 // scanDirectory scans a single directory for services
 func (d *Detector) scanDirectory(dirPath, relPath string) []DetectedService {
 	var services []DetectedService
 
+	// Check for composer.json (PHP). Laravel proposes its Vite dev server
+	// itself, so its package.json isn't detected again below
+	phpServices := d.detectPHPProject(dirPath, relPath)
+	laravel := len(phpServices) > 0 && phpServices[0].Framework == FrameworkLaravel
+
+	// Check for deno.json (Deno), whose tasks take over from a package.json
+	deno := d.detectDenoProject(dirPath, relPath)
+	if deno != nil {
+		services = append(services, *deno)
+	}
+
 	// Check for package.json (Node.js)
-	if svc := d.detectNodeProject(dirPath, relPath); svc != nil {
-		services = append(services, *svc)
+	if !laravel && deno == nil {
+		if svc := d.detectNodeProject(dirPath, relPath); svc != nil {
+			services = append(services, *svc)
+		}
 	}
 
 	// Check for go.mod (Go)
-	if svc := d.detectGoProject(dirPath, relPath); svc != nil {
-		services = append(services, *svc)
-	}
+	services = append(services, d.detectGoProject(dirPath, relPath)...)
 
 	// Check for Cargo.toml (Rust)
 	if svc := d.detectRustProject(dirPath, relPath); svc != nil {
@@ -191,6 +312,16 @@ func (d *Detector) scanDirectory(dirPath, relPath string) []DetectedService {
 		services = append(services, *svc)
 	}
 
+	// Check for Gemfile or config.ru (Ruby)
+	services = append(services, d.detectRubyProject(dirPath, relPath)...)
+
+	services = append(services, phpServices...)
+
+	// Check for build.gradle(.kts) or pom.xml (JVM)
+	if svc := d.detectJVMProject(dirPath, relPath); svc != nil {
+		services = append(services, *svc)
+	}
+
 	// Flutter disabled - requires interactive device selection
 	// User can manually add with specific device:
 	//   flutter run -d iPhone
@@ -212,6 +343,13 @@ func (d *Detector) detectNodeProject(dirPath, relPath string) *DetectedService {
 	if err := json.Unmarshal(data, &pkg); err != nil {
 		return nil
 	}
+	if pkg.PackageManager == "" {
+		for _, lockfile := range []string{"bun.lockb", "bun.lock"} {
+			if _, err := os.Stat(filepath.Join(dirPath, lockfile)); err == nil {
+				pkg.PackageManager = "bun"
+			}
+		}
+	}
 
 	svc := &DetectedService{
 		Name:        d.generateServiceName(relPath, pkg.Name),
@@ -221,13 +359,39 @@ func (d *Detector) detectNodeProject(dirPath, relPath string) *DetectedService {
 
 	// Detect framework and type
 	svc.Framework, svc.Type = d.detectNodeFramework(&pkg)
+	if _, err := os.Stat(filepath.Join(dirPath, "angular.json")); err == nil {
+		svc.Framework, svc.Type = FrameworkAngular, ServiceTypeFrontend
+	}
 
 	// Find dev command
 	svc.DevCommand = d.findNodeDevCommand(&pkg)
+	if svc.DevCommand == "" {
+		switch svc.Framework {
+		case FrameworkAngular:
+			svc.DevCommand = "npx ng serve"
+		case FrameworkVite, FrameworkSvelteKit:
+			svc.DevCommand = "npx vite"
+		case FrameworkAstro:
+			svc.DevCommand = "npx astro dev"
+		}
+	}
 	svc.Command = svc.DevCommand
 
-	// Detect port from scripts
+	// Detect port from scripts, then from the dev server's own config
 	svc.Port = d.detectPortFromScripts(&pkg)
+	if svc.Port == 0 {
+		switch {
+		case svc.Framework == FrameworkAngular:
+			svc.Port = angularPort(dirPath)
+		case svc.Framework == FrameworkAstro:
+			svc.Port = configPort(dirPath, []string{"astro.config.mjs", "astro.config.ts", "astro.config.js"}, 4321)
+		case pkg.Dependencies["vite"] != "" || pkg.DevDeps["vite"] != "":
+			svc.Port = configPort(dirPath, []string{"vite.config.ts", "vite.config.js", "vite.config.mts", "vite.config.mjs"}, 5173)
+		case svc.Framework == FrameworkRemix, svc.Framework == FrameworkSolidStart:
+			// Remix's own dev server and Vinxi
+			svc.Port = 3000
+		}
+	}
 
 	// Generate health URL if port found
 	if svc.Port > 0 && svc.Type == ServiceTypeBackend {
@@ -237,6 +401,55 @@ func (d *Detector) detectNodeProject(dirPath, relPath string) *DetectedService {
 	return svc
 }
 
+// angularPort returns the port ng serve listens on, set in angular.json or
+// 4200 by default
+func angularPort(dirPath string) int {
+	data, err := os.ReadFile(filepath.Join(dirPath, "angular.json"))
+	if err != nil {
+		return 4200
+	}
+	var workspace struct {
+		Projects map[string]struct {
+			Architect struct {
+				Serve struct {
+					Options struct {
+						Port int `json:"port"`
+					} `json:"options"`
+				} `json:"serve"`
+			} `json:"architect"`
+		} `json:"projects"`
+	}
+	if err := json.Unmarshal(data, &workspace); err == nil {
+		for _, project := range workspace.Projects {
+			if port := project.Architect.Serve.Options.Port; port > 0 {
+				return port
+			}
+		}
+	}
+	return 4200
+}
+
+// configPortPattern matches the server port in a Vite or Astro config
+var configPortPattern = regexp.MustCompile(`\bport\s*:\s*(\d{2,5})`)
+
+// configPort returns the port a dev server listens on, set in the first of
+// its config files that exists or fallback by default
+func configPort(dirPath string, names []string, fallback int) int {
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dirPath, name))
+		if err != nil {
+			continue
+		}
+		if m := configPortPattern.FindSubmatch(data); m != nil {
+			if port, err := strconv.Atoi(string(m[1])); err == nil {
+				return port
+			}
+		}
+		break
+	}
+	return fallback
+}
+
 // detectNodeFramework detects Node.js framework
 func (d *Detector) detectNodeFramework(pkg *PackageJSON) (Framework, ServiceType) {
 	allDeps := make(map[string]bool)
@@ -265,8 +478,23 @@ func (d *Detector) detectNodeFramework(pkg *PackageJSON) (Framework, ServiceType
 	if allDeps["nuxt"] {
 		return FrameworkNuxt, ServiceTypeFullstack
 	}
+	if allDeps["@remix-run/dev"] || allDeps["@remix-run/react"] {
+		return FrameworkRemix, ServiceTypeFullstack
+	}
+	if allDeps["@sveltejs/kit"] {
+		return FrameworkSvelteKit, ServiceTypeFullstack
+	}
+	if allDeps["@solidjs/start"] || allDeps["solid-start"] {
+		return FrameworkSolidStart, ServiceTypeFullstack
+	}
+	if allDeps["astro"] {
+		return FrameworkAstro, ServiceTypeFrontend
+	}
 
 	// Frontend frameworks
+	if allDeps["@angular/core"] {
+		return FrameworkAngular, ServiceTypeFrontend
+	}
 	if allDeps["react"] || allDeps["react-dom"] {
 		return FrameworkReact, ServiceTypeFrontend
 	}
@@ -277,11 +505,24 @@ func (d *Detector) detectNodeFramework(pkg *PackageJSON) (Framework, ServiceType
 		return FrameworkSvelte, ServiceTypeFrontend
 	}
 
+	// Vite without a framework it's known for
+	if allDeps["vite"] {
+		return FrameworkVite, ServiceTypeFrontend
+	}
+
 	return FrameworkUnknown, ServiceTypeUnknown
 }
 
 // findNodeDevCommand finds the dev command from scripts
 func (d *Detector) findNodeDevCommand(pkg *PackageJSON) string {
+	if script := d.findNodeDevScript(pkg); script != "" {
+		return d.detectPackageManager(pkg) + " run " + script
+	}
+	return ""
+}
+
+// findNodeDevScript finds the script to run in development
+func (d *Detector) findNodeDevScript(pkg *PackageJSON) string {
 	// Priority order for dev commands
 	devCommands := []string{
 		"start:dev",  // NestJS
@@ -292,21 +533,32 @@ func (d *Detector) findNodeDevCommand(pkg *PackageJSON) string {
 		"watch",      // Generic watch
 	}
 
-	pm := d.detectPackageManager(pkg)
-
 	for _, cmd := range devCommands {
 		if _, ok := pkg.Scripts[cmd]; ok {
-			return pm + " run " + cmd
+			return cmd
 		}
 	}
 
 	return ""
 }
 
-// detectPackageManager detects npm/yarn/pnpm
+// detectPackageManager detects npm or bun, by the packageManager field
+// (filled in from bun's lockfile when missing), bun's types or scripts run
+// with bun
 func (d *Detector) detectPackageManager(pkg *PackageJSON) string {
-	// Check for package manager field or lock files would be better
-	// For now, default to npm
+	if strings.HasPrefix(pkg.PackageManager, "bun") {
+		return "bun"
+	}
+	if pkg.PackageManager == "" {
+		if pkg.DevDeps["@types/bun"] != "" || pkg.DevDeps["bun-types"] != "" {
+			return "bun"
+		}
+		for _, script := range pkg.Scripts {
+			if strings.HasPrefix(script, "bun ") || strings.Contains(script, " bun ") {
+				return "bun"
+			}
+		}
+	}
 	return "npm"
 }
 
@@ -331,44 +583,247 @@ func (d *Detector) detectPortFromScripts(pkg *PackageJSON) int {
 	return 0
 }
 
-// detectGoProject detects Go projects
-func (d *Detector) detectGoProject(dirPath, relPath string) *DetectedService {
-	modPath := filepath.Join(dirPath, "go.mod")
-	if _, err := os.Stat(modPath); err != nil {
+// DenoJSON represents parsed deno.json
+type DenoJSON struct {
+	Tasks map[string]any `json:"tasks"`
+}
+
+// detectDenoProject detects Deno projects, run through their tasks
+func (d *Detector) detectDenoProject(dirPath, relPath string) *DetectedService {
+	var data []byte
+	for _, name := range []string{"deno.json", "deno.jsonc"} {
+		var err error
+		if data, err = os.ReadFile(filepath.Join(dirPath, name)); err == nil {
+			break
+		}
+	}
+	if data == nil {
+		return nil
+	}
+
+	var cfg DenoJSON
+	if err := json.Unmarshal(stripJSONC(data), &cfg); err != nil {
 		return nil
 	}
 
 	svc := &DetectedService{
 		Name:      d.generateServiceName(relPath, filepath.Base(dirPath)),
 		Path:      relPath,
-		Framework: FrameworkGo,
 		Type:      ServiceTypeBackend,
+		Framework: FrameworkDeno,
+	}
+
+	for _, task := range []string{"dev", "start", "serve"} {
+		definition, ok := cfg.Tasks[task]
+		if !ok {
+			continue
+		}
+		svc.DevCommand = "deno task " + task
+		// A task is a command, or an object with one since Deno 2.1
+		command, _ := definition.(string)
+		if object, ok := definition.(map[string]any); ok {
+			command, _ = object["command"].(string)
+		}
+		for _, pattern := range d.portPatterns {
+			if matches := pattern.FindStringSubmatch(command); len(matches) > 1 {
+				svc.Port, _ = strconv.Atoi(matches[1])
+				break
+			}
+		}
+		break
+	}
+	if svc.DevCommand == "" {
+		for _, entry := range []string{"main.ts", "main.js"} {
+			if _, err := os.Stat(filepath.Join(dirPath, entry)); err == nil {
+				svc.DevCommand = "deno run -A --watch " + entry
+				break
+			}
+		}
+	}
+	svc.Command = svc.DevCommand
+	// Deno.serve and Fresh listen on 8000 unless told otherwise
+	if svc.Port == 0 {
+		svc.Port = 8000
+	}
+
+	return svc
+}
+
+// stripJSONC removes the comments and trailing commas JSONC allows from
+// data, leaving JSON
+func stripJSONC(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		switch {
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			i--
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := strings.Index(string(data[i+2:]), "*/")
+			if end < 0 {
+				return out
+			}
+			i += end + 3
+		case c == '}' || c == ']':
+			// Drop a trailing comma before the closing bracket
+			j := len(out) - 1
+			for j >= 0 && (out[j] == ' ' || out[j] == '\t' || out[j] == '\n' || out[j] == '\r') {
+				j--
+			}
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// goPortPatterns match a port in a main.go: a flag's default, a listen
+// address or a PORT environment variable's fallback
+var goPortPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`flag\.\w+\(\s*"(?:port|addr|address|listen|http)[\w.-]*"\s*,\s*"?[\w.]*:?(\d{2,5})"?`),
+	regexp.MustCompile(`Listen\w*\(\s*(?:"tcp"\s*,\s*)?"[\w.]*:(\d{2,5})"`),
+	regexp.MustCompile(`(?s)Getenv\("PORT"\).{0,80}?"(\d{2,5})"`),
+}
+
+// detectGoProject detects Go projects, with a service for each binary in
+// cmd/
+func (d *Detector) detectGoProject(dirPath, relPath string) []DetectedService {
+	modPath := filepath.Join(dirPath, "go.mod")
+	if _, err := os.Stat(modPath); err != nil {
+		return nil
+	}
+
+	name := d.generateServiceName(relPath, filepath.Base(dirPath))
+	newService := func(command, mainPath string) DetectedService {
+		return DetectedService{
+			Name:       name,
+			Path:       relPath,
+			Framework:  FrameworkGo,
+			Type:       ServiceTypeBackend,
+			Command:    command,
+			DevCommand: command,
+			Port:       goMainPort(mainPath),
+		}
 	}
 
 	// Check for main.go in cmd/
+	var services []DetectedService
 	cmdDir := filepath.Join(dirPath, "cmd")
 	if entries, err := os.ReadDir(cmdDir); err == nil {
 		for _, entry := range entries {
 			if entry.IsDir() {
 				mainPath := filepath.Join(cmdDir, entry.Name(), "main.go")
 				if _, err := os.Stat(mainPath); err == nil {
-					svc.Command = "go run ./cmd/" + entry.Name()
-					svc.DevCommand = svc.Command
-					break
+					svc := newService("go run ./cmd/"+entry.Name(), mainPath)
+					svc.Name = entry.Name()
+					services = append(services, svc)
 				}
 			}
 		}
 	}
+	// A single binary keeps the module's name
+	if len(services) == 1 {
+		services[0].Name = name
+	}
 
 	// Fallback to main.go in root
-	if svc.Command == "" {
+	if len(services) == 0 {
 		if _, err := os.Stat(filepath.Join(dirPath, "main.go")); err == nil {
-			svc.Command = "go run ."
-			svc.DevCommand = svc.Command
+			services = append(services, newService("go run .", filepath.Join(dirPath, "main.go")))
 		}
 	}
 
-	return svc
+	return services
+}
+
+// goMainPort returns the port a main.go listens on by default, or 0 if
+// it's none of the usual patterns
+func goMainPort(mainPath string) int {
+	data, err := os.ReadFile(mainPath)
+	if err != nil {
+		return 0
+	}
+	for _, pattern := range goPortPatterns {
+		if m := pattern.FindSubmatch(data); m != nil {
+			if port, err := strconv.Atoi(string(m[1])); err == nil {
+				return port
+			}
+		}
+	}
+	return 0
+}
+
+// prefixModuleNames prefixes the Go binaries that share a name across the
+// modules of a workspace, e.g. two cmd/api, with their module's directory
+func prefixModuleNames(services []DetectedService) []DetectedService {
+	count := make(map[string]int)
+	for _, svc := range services {
+		count[svc.Name]++
+	}
+	for i, svc := range services {
+		if count[svc.Name] < 2 || svc.Framework != FrameworkGo {
+			continue
+		}
+		if module := filepath.Base(svc.Path); module != svc.Name {
+			services[i].Name = module + "-" + svc.Name
+		}
+	}
+	return services
+}
+
+// readGoWork returns the module directories a go.work uses, relative to
+// the root
+func readGoWork(rootPath string) []string {
+	data, err := os.ReadFile(filepath.Join(rootPath, "go.work"))
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	inBlock := false
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		var dir string
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock:
+			dir = line
+		case line == "use (":
+			inBlock = true
+		case strings.HasPrefix(line, "use "):
+			dir = strings.TrimSpace(strings.TrimPrefix(line, "use "))
+		}
+		dir = filepath.Clean(filepath.FromSlash(strings.Trim(dir, `"`)))
+		if dir != "." && !filepath.IsAbs(dir) && !strings.HasPrefix(dir, "..") {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
 }
 
 // detectRustProject detects Rust projects
@@ -417,14 +872,59 @@ func (d *Detector) detectPythonProject(dirPath, relPath string) *DetectedService
 		Type:      ServiceTypeBackend,
 	}
 
-	// Check for common entry points
-	if _, err := os.Stat(filepath.Join(dirPath, "manage.py")); err == nil {
+	deps := pythonDependencies(dirPath)
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dirPath, name))
+		return err == nil
+	}
+
+	// Web frameworks, by their own dev servers
+	switch {
+	case exists("manage.py"):
+		svc.Framework = FrameworkDjango
 		svc.Command = "python manage.py runserver"
 		svc.DevCommand = svc.Command
-	} else if _, err := os.Stat(filepath.Join(dirPath, "app.py")); err == nil {
+		svc.Port = 8000
+		// startproject enables the admin, so its login page answers in
+		// every new project
+		svc.HealthURL = "http://localhost:8000/admin/login/"
+		return svc
+	case deps["fastapi"] || deps["uvicorn"]:
+		module := pythonASGIModule(dirPath)
+		if module == "" {
+			break
+		}
+		if deps["fastapi"] {
+			svc.Framework = FrameworkFastAPI
+			svc.HealthURL = "http://localhost:8000/docs"
+		}
+		svc.Command = "uvicorn " + module + ":app"
+		svc.DevCommand = svc.Command + " --reload"
+		svc.Port = 8000
+		return svc
+	case deps["flask"]:
+		// flask finds app.py, wsgi.py and an app package by itself
+		var command string
+		if exists("app.py") || exists("wsgi.py") || exists(filepath.Join("app", "__init__.py")) {
+			command = "flask run"
+		} else if exists("main.py") {
+			command = "flask --app main run"
+		} else {
+			break
+		}
+		// Flask apps have no route in common, so there's no health URL
+		svc.Framework = FrameworkFlask
+		svc.Command = command
+		svc.DevCommand = command + " --debug"
+		svc.Port = 5000
+		return svc
+	}
+
+	// Check for common entry points
+	if exists("app.py") {
 		svc.Command = "python app.py"
 		svc.DevCommand = svc.Command
-	} else if _, err := os.Stat(filepath.Join(dirPath, "main.py")); err == nil {
+	} else if exists("main.py") {
 		svc.Command = "python main.py"
 		svc.DevCommand = svc.Command
 	}
@@ -432,6 +932,278 @@ func (d *Detector) detectPythonProject(dirPath, relPath string) *DetectedService
 	return svc
 }
 
+// pythonWebPackages are the packages pythonDependencies looks for
+var pythonWebPackages = map[string]bool{
+	"django":  true,
+	"fastapi": true,
+	"flask":   true,
+	"uvicorn": true,
+}
+
+// pythonDependencies returns the web framework packages a Python project
+// depends on, lowercased. Lines are split into package-name-like words, so
+// it reads requirements lines, pyproject.toml and Pipfile tables and
+// setup.py lists alike, and flask-cors doesn't count as flask
+func pythonDependencies(dirPath string) map[string]bool {
+	deps := make(map[string]bool)
+	for _, name := range []string{"requirements.txt", "pyproject.toml", "Pipfile", "setup.py"} {
+		data, err := os.ReadFile(filepath.Join(dirPath, name))
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if i := strings.Index(line, "#"); i >= 0 {
+				line = line[:i]
+			}
+			words := strings.FieldsFunc(line, func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' && r != '.'
+			})
+			for _, word := range words {
+				if word = strings.ToLower(word); pythonWebPackages[word] {
+					deps[word] = true
+				}
+			}
+		}
+	}
+	return deps
+}
+
+// pythonASGIModule returns the module uvicorn imports the app from, or ""
+// if there's none of the usual entry points
+func pythonASGIModule(dirPath string) string {
+	for _, path := range []string{"app/main.py", "main.py", "app.py", "src/main.py"} {
+		if _, err := os.Stat(filepath.Join(dirPath, filepath.FromSlash(path))); err == nil {
+			return strings.ReplaceAll(strings.TrimSuffix(path, ".py"), "/", ".")
+		}
+	}
+	return ""
+}
+
+// gemPattern matches a gem declaration in a Gemfile
+var gemPattern = regexp.MustCompile(`^\s*gem\s*\(?\s*["']([^"']+)["']`)
+
+// gemfileGems returns the gems a Gemfile declares
+func gemfileGems(dirPath string) map[string]bool {
+	gems := make(map[string]bool)
+	data, err := os.ReadFile(filepath.Join(dirPath, "Gemfile"))
+	if err != nil {
+		return gems
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if m := gemPattern.FindStringSubmatch(line); m != nil {
+			gems[m[1]] = true
+		}
+	}
+	return gems
+}
+
+// detectRubyProject detects Rails and Rack apps, and a Sidekiq worker
+// alongside them
+func (d *Detector) detectRubyProject(dirPath, relPath string) []DetectedService {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dirPath, name))
+		return err == nil
+	}
+	if !exists("Gemfile") && !exists("config.ru") {
+		return nil
+	}
+
+	gems := gemfileGems(dirPath)
+	name := d.generateServiceName(relPath, filepath.Base(dirPath))
+	web := DetectedService{
+		Name:      name,
+		Path:      relPath,
+		Type:      ServiceTypeBackend,
+		Framework: FrameworkRuby,
+	}
+
+	if gems["rails"] || exists(filepath.Join("config", "application.rb")) {
+		web.Framework = FrameworkRails
+		web.Command = "bin/rails server"
+		web.DevCommand = web.Command
+		// bin/dev also builds the assets, through Procfile.dev
+		if exists(filepath.Join("bin", "dev")) {
+			web.DevCommand = "bin/dev"
+			web.Type = ServiceTypeFullstack
+		}
+		web.Port = 3000
+		// Rails 7.1 and later route /up to their health check
+		if routes, err := os.ReadFile(filepath.Join(dirPath, "config", "routes.rb")); err == nil &&
+			strings.Contains(string(routes), "rails/health") {
+			web.HealthURL = "http://localhost:3000/up"
+		}
+	} else if exists("config.ru") {
+		web.Command = "bundle exec rackup"
+		web.DevCommand = web.Command
+		web.Port = 9292
+	}
+
+	var services []DetectedService
+	if web.Command != "" {
+		services = append(services, web)
+	}
+
+	if gems["sidekiq"] {
+		// Procfile.dev may start Sidekiq with the server already
+		procfile, _ := os.ReadFile(filepath.Join(dirPath, "Procfile.dev"))
+		if web.DevCommand != "bin/dev" || !strings.Contains(string(procfile), "sidekiq") {
+			services = append(services, DetectedService{
+				Name:       name + "-sidekiq",
+				Path:       relPath,
+				Type:       ServiceTypeWorker,
+				Framework:  web.Framework,
+				Command:    "bundle exec sidekiq",
+				DevCommand: "bundle exec sidekiq",
+			})
+		}
+	}
+
+	return services
+}
+
+// ComposerJSON represents parsed composer.json
+type ComposerJSON struct {
+	Name       string            `json:"name"`
+	Require    map[string]string `json:"require"`
+	RequireDev map[string]string `json:"require-dev"`
+}
+
+// detectPHPProject detects Laravel apps, with their Vite dev server as a
+// separate service, and plain PHP apps served from public/
+func (d *Detector) detectPHPProject(dirPath, relPath string) []DetectedService {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dirPath, name))
+		return err == nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(dirPath, "composer.json"))
+	if err != nil {
+		return nil
+	}
+	var composer ComposerJSON
+	if err := json.Unmarshal(data, &composer); err != nil {
+		return nil
+	}
+
+	name := d.generateServiceName(relPath, filepath.Base(dirPath))
+
+	if _, ok := composer.Require["laravel/framework"]; !ok && !exists("artisan") {
+		if !exists(filepath.Join("public", "index.php")) {
+			return nil
+		}
+		return []DetectedService{{
+			Name:       name,
+			Path:       relPath,
+			Type:       ServiceTypeBackend,
+			Framework:  FrameworkPHP,
+			Command:    "php -S localhost:8000 -t public",
+			DevCommand: "php -S localhost:8000 -t public",
+			Port:       8000,
+		}}
+	}
+
+	app := DetectedService{
+		Name:       name,
+		Path:       relPath,
+		Type:       ServiceTypeBackend,
+		Framework:  FrameworkLaravel,
+		Command:    "php artisan serve",
+		DevCommand: "php artisan serve",
+		Port:       8000,
+	}
+	// Laravel 11 and later route /up to their health check
+	if bootstrap, err := os.ReadFile(filepath.Join(dirPath, "bootstrap", "app.php")); err == nil &&
+		strings.Contains(string(bootstrap), "health:") {
+		app.HealthURL = "http://localhost:8000/up"
+	}
+	services := []DetectedService{app}
+
+	// Vite serves the assets on its own port while php artisan serve runs
+	if pkgData, err := os.ReadFile(filepath.Join(dirPath, "package.json")); err == nil {
+		var pkg PackageJSON
+		if json.Unmarshal(pkgData, &pkg) == nil {
+			if command := d.findNodeDevCommand(&pkg); command != "" {
+				services = append(services, DetectedService{
+					Name:        name + "-vite",
+					Path:        relPath,
+					Type:        ServiceTypeFrontend,
+					Framework:   FrameworkLaravel,
+					Command:     command,
+					DevCommand:  command,
+					Port:        5173,
+					PackageJSON: &pkg,
+				})
+			}
+		}
+	}
+
+	return services
+}
+
+// gradleApplicationPattern matches Gradle's application plugin, applied
+// by id or as the Kotlin DSL's accessor
+var gradleApplicationPattern = regexp.MustCompile(`(?m)["']application["']|^\s*application\s*$`)
+
+// detectJVMProject detects Gradle and Maven projects, run through their
+// wrapper scripts when they have one
+func (d *Detector) detectJVMProject(dirPath, relPath string) *DetectedService {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dirPath, name))
+		return err == nil
+	}
+
+	var build, tool string
+	for _, name := range []string{"build.gradle.kts", "build.gradle", "pom.xml"} {
+		if data, err := os.ReadFile(filepath.Join(dirPath, name)); err == nil {
+			build = string(data)
+			tool = "gradle"
+			if name == "pom.xml" {
+				tool = "mvn"
+			}
+			break
+		}
+	}
+	if tool == "" {
+		return nil
+	}
+
+	svc := &DetectedService{
+		Name:      d.generateServiceName(relPath, filepath.Base(dirPath)),
+		Path:      relPath,
+		Type:      ServiceTypeBackend,
+		Framework: FrameworkJVM,
+	}
+
+	runner := tool
+	if tool == "gradle" && exists("gradlew") {
+		runner = "./gradlew"
+	} else if tool == "mvn" && exists("mvnw") {
+		runner = "./mvnw"
+	}
+
+	switch {
+	case strings.Contains(build, "org.springframework.boot") || strings.Contains(build, "spring-boot-starter"):
+		svc.Framework = FrameworkSpringBoot
+		svc.Command = runner + " bootRun"
+		if tool == "mvn" {
+			svc.Command = runner + " spring-boot:run"
+		}
+		svc.Port = 8080
+		// The health endpoint comes with Actuator only
+		if strings.Contains(build, "spring-boot-starter-actuator") {
+			svc.HealthURL = "http://localhost:8080/actuator/health"
+		}
+	case tool == "gradle" && gradleApplicationPattern.MatchString(build):
+		svc.Command = runner + " run"
+	default:
+		// Maven has no standard way to run a project
+		return nil
+	}
+	svc.DevCommand = svc.Command
+
+	return svc
+}
+
 // PubspecYAML represents parsed pubspec.yaml
 type PubspecYAML struct {
 	Name         string            `yaml:"name"`
@@ -50,6 +50,42 @@ func TestDetector_DetectFramework(t *testing.T) {
 			expectedFW:   FrameworkNext,
 			expectedType: ServiceTypeFullstack,
 		},
+		{
+			name:         "angular",
+			deps:         map[string]string{"@angular/core": "^18.0.0"},
+			expectedFW:   FrameworkAngular,
+			expectedType: ServiceTypeFrontend,
+		},
+		{
+			name:         "vite",
+			deps:         map[string]string{"vite": "^5.0.0", "lit": "^3.0.0"},
+			expectedFW:   FrameworkVite,
+			expectedType: ServiceTypeFrontend,
+		},
+		{
+			name:         "astro",
+			deps:         map[string]string{"astro": "^4.0.0", "react": "^18.0.0"},
+			expectedFW:   FrameworkAstro,
+			expectedType: ServiceTypeFrontend,
+		},
+		{
+			name:         "remix",
+			deps:         map[string]string{"@remix-run/react": "^2.0.0", "react": "^18.0.0", "express": "^4.18.0"},
+			expectedFW:   FrameworkRemix,
+			expectedType: ServiceTypeFullstack,
+		},
+		{
+			name:         "sveltekit",
+			deps:         map[string]string{"@sveltejs/kit": "^2.0.0", "svelte": "^4.0.0", "vite": "^5.0.0"},
+			expectedFW:   FrameworkSvelteKit,
+			expectedType: ServiceTypeFullstack,
+		},
+		{
+			name:         "solid start",
+			deps:         map[string]string{"@solidjs/start": "^1.0.0", "solid-js": "^1.8.0"},
+			expectedFW:   FrameworkSolidStart,
+			expectedType: ServiceTypeFullstack,
+		},
 		{
 			name:         "unknown",
 			deps:         map[string]string{"some-package": "^1.0.0"},
@@ -299,3 +335,612 @@ func TestDetector_DetectMonorepo(t *testing.T) {
 		t.Error("frontend service not found")
 	}
 }
+
+func TestFindMarkers(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{"go.mod", "package.json", "README.md"} {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), nil, 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	// A directory named like a marker doesn't count
+	if err := os.Mkdir(filepath.Join(tmpDir, "Cargo.toml"), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+
+	markers := FindMarkers(tmpDir)
+	if len(markers) != 2 || markers[0] != "package.json" || markers[1] != "go.mod" {
+		t.Errorf("expected [package.json go.mod], got %v", markers)
+	}
+
+	if markers := FindMarkers(filepath.Join(tmpDir, "missing")); len(markers) != 0 {
+		t.Errorf("expected no markers in a missing directory, got %v", markers)
+	}
+}
+
+func TestDetector_DetectPythonFramework(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		framework  Framework
+		devCommand string
+		port       int
+		healthURL  string
+	}{
+		{
+			name:       "django",
+			files:      map[string]string{"requirements.txt": "Django>=4.2\n", "manage.py": ""},
+			framework:  FrameworkDjango,
+			devCommand: "python manage.py runserver",
+			port:       8000,
+			healthURL:  "http://localhost:8000/admin/login/",
+		},
+		{
+			name:       "fastapi in a package",
+			files:      map[string]string{"requirements.txt": "fastapi==0.110\nuvicorn[standard]\n", "app/main.py": ""},
+			framework:  FrameworkFastAPI,
+			devCommand: "uvicorn app.main:app --reload",
+			port:       8000,
+			healthURL:  "http://localhost:8000/docs",
+		},
+		{
+			name:       "fastapi from pyproject",
+			files:      map[string]string{"pyproject.toml": "[tool.poetry.dependencies]\npython = \"^3.12\"\nfastapi = \"^0.110\"\n", "main.py": ""},
+			framework:  FrameworkFastAPI,
+			devCommand: "uvicorn main:app --reload",
+			port:       8000,
+			healthURL:  "http://localhost:8000/docs",
+		},
+		{
+			name:       "flask",
+			files:      map[string]string{"requirements.txt": "flask\nflask-cors\n", "app.py": ""},
+			framework:  FrameworkFlask,
+			devCommand: "flask run --debug",
+			port:       5000,
+		},
+		{
+			name:       "flask extension only",
+			files:      map[string]string{"requirements.txt": "flask-cors  # not flask itself\n", "app.py": ""},
+			framework:  FrameworkPython,
+			devCommand: "python app.py",
+		},
+		{
+			name:       "plain script",
+			files:      map[string]string{"requirements.txt": "requests\n", "main.py": ""},
+			framework:  FrameworkPython,
+			devCommand: "python main.py",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeFiles(t, tmpDir, tt.files)
+
+			svc := NewDetector().detectPythonProject(tmpDir, "")
+			if svc == nil {
+				t.Fatal("expected a service")
+			}
+			if svc.Framework != tt.framework {
+				t.Errorf("expected framework %s, got %s", tt.framework, svc.Framework)
+			}
+			if svc.DevCommand != tt.devCommand {
+				t.Errorf("expected dev command %q, got %q", tt.devCommand, svc.DevCommand)
+			}
+			if svc.Port != tt.port {
+				t.Errorf("expected port %d, got %d", tt.port, svc.Port)
+			}
+			if svc.HealthURL != tt.healthURL {
+				t.Errorf("expected health URL %q, got %q", tt.healthURL, svc.HealthURL)
+			}
+		})
+	}
+}
+
+func TestDetector_DetectRubyProject(t *testing.T) {
+
+	t.Run("rails with sidekiq", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeFiles(t, tmpDir, map[string]string{
+			"Gemfile":          "source \"https://rubygems.org\"\ngem \"rails\", \"~> 7.1\"\ngem 'sidekiq'\n",
+			"config.ru":        "",
+			"config/routes.rb": "get \"up\" => \"rails/health#show\", as: :rails_health_check\n",
+		})
+
+		services := NewDetector().detectRubyProject(tmpDir, "api")
+		if len(services) != 2 {
+			t.Fatalf("expected 2 services, got %d", len(services))
+		}
+		web, worker := services[0], services[1]
+		if web.Framework != FrameworkRails || web.DevCommand != "bin/rails server" || web.Port != 3000 {
+			t.Errorf("unexpected web service %+v", web)
+		}
+		if web.HealthURL != "http://localhost:3000/up" {
+			t.Errorf("expected health URL /up, got %q", web.HealthURL)
+		}
+		if worker.Name != "api-sidekiq" || worker.Type != ServiceTypeWorker || worker.DevCommand != "bundle exec sidekiq" {
+			t.Errorf("unexpected worker %+v", worker)
+		}
+	})
+
+	t.Run("bin/dev starting sidekiq", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeFiles(t, tmpDir, map[string]string{
+			"Gemfile":      "gem \"rails\"\ngem \"sidekiq\"\n",
+			"bin/dev":      "",
+			"Procfile.dev": "web: bin/rails server\nworker: bundle exec sidekiq\n",
+		})
+
+		services := NewDetector().detectRubyProject(tmpDir, "")
+		if len(services) != 1 {
+			t.Fatalf("expected 1 service, got %d", len(services))
+		}
+		if services[0].DevCommand != "bin/dev" || services[0].HealthURL != "" {
+			t.Errorf("unexpected service %+v", services[0])
+		}
+	})
+
+	t.Run("rack", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeFiles(t, tmpDir, map[string]string{"Gemfile": "gem \"sinatra\"\n", "config.ru": ""})
+
+		services := NewDetector().detectRubyProject(tmpDir, "")
+		if len(services) != 1 || services[0].Framework != FrameworkRuby || services[0].Port != 9292 {
+			t.Errorf("unexpected services %+v", services)
+		}
+	})
+}
+
+func TestDetector_DetectLaravel(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"composer.json":     `{"name": "laravel/laravel", "require": {"php": "^8.2", "laravel/framework": "^11.0"}}`,
+		"artisan":           "",
+		"package.json":      `{"private": true, "scripts": {"build": "vite build", "dev": "vite"}, "devDependencies": {"laravel-vite-plugin": "^1.0", "vite": "^5.0"}}`,
+		"bootstrap/app.php": "->withRouting(\n    web: __DIR__.'/../routes/web.php',\n    health: '/up',\n)",
+	}
+	writeFiles(t, tmpDir, files)
+
+	services := NewDetector().scanDirectory(tmpDir, "shop")
+	if len(services) != 2 {
+		t.Fatalf("expected 2 services, got %+v", services)
+	}
+	app, vite := services[0], services[1]
+	if app.Name != "shop" || app.DevCommand != "php artisan serve" || app.Port != 8000 {
+		t.Errorf("unexpected app service %+v", app)
+	}
+	if app.HealthURL != "http://localhost:8000/up" {
+		t.Errorf("expected health URL /up, got %q", app.HealthURL)
+	}
+	if vite.Name != "shop-vite" || vite.DevCommand != "npm run dev" || vite.Port != 5173 {
+		t.Errorf("unexpected vite service %+v", vite)
+	}
+}
+
+func TestDetector_DetectJVMProject(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		framework  Framework
+		devCommand string
+		port       int
+		healthURL  string
+	}{
+		{
+			name: "spring boot with gradle wrapper",
+			files: map[string]string{
+				"build.gradle.kts": "plugins {\n    id(\"org.springframework.boot\") version \"3.3.0\"\n}\ndependencies {\n    implementation(\"org.springframework.boot:spring-boot-starter-actuator\")\n}\n",
+				"gradlew":          "",
+			},
+			framework:  FrameworkSpringBoot,
+			devCommand: "./gradlew bootRun",
+			port:       8080,
+			healthURL:  "http://localhost:8080/actuator/health",
+		},
+		{
+			name: "spring boot with maven wrapper",
+			files: map[string]string{
+				"pom.xml": "<parent><artifactId>spring-boot-starter-parent</artifactId></parent>",
+				"mvnw":    "",
+			},
+			framework:  FrameworkSpringBoot,
+			devCommand: "./mvnw spring-boot:run",
+			port:       8080,
+		},
+		{
+			name:       "gradle application",
+			files:      map[string]string{"build.gradle": "plugins {\n    id 'application'\n}\n"},
+			framework:  FrameworkJVM,
+			devCommand: "gradle run",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeFiles(t, tmpDir, tt.files)
+
+			svc := NewDetector().detectJVMProject(tmpDir, "")
+			if svc == nil {
+				t.Fatal("expected a service")
+			}
+			if svc.Framework != tt.framework {
+				t.Errorf("expected framework %s, got %s", tt.framework, svc.Framework)
+			}
+			if svc.DevCommand != tt.devCommand {
+				t.Errorf("expected dev command %q, got %q", tt.devCommand, svc.DevCommand)
+			}
+			if svc.Port != tt.port {
+				t.Errorf("expected port %d, got %d", tt.port, svc.Port)
+			}
+			if svc.HealthURL != tt.healthURL {
+				t.Errorf("expected health URL %q, got %q", tt.healthURL, svc.HealthURL)
+			}
+		})
+	}
+
+	// A Maven library has nothing to run
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "pom.xml"), []byte("<project></project>"), 0644); err != nil {
+		t.Fatalf("failed to write pom.xml: %v", err)
+	}
+	if svc := NewDetector().detectJVMProject(tmpDir, ""); svc != nil {
+		t.Errorf("expected no service, got %+v", svc)
+	}
+}
+
+func TestDetector_DevServerPort(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		framework  Framework
+		devCommand string
+		port       int
+	}{
+		{
+			name: "angular default",
+			files: map[string]string{
+				"package.json": `{"scripts": {"start": "ng serve"}, "dependencies": {"@angular/core": "^18.0.0"}}`,
+				"angular.json": `{"projects": {"web": {"architect": {"build": {}}}}}`,
+			},
+			framework:  FrameworkAngular,
+			devCommand: "npm run start",
+			port:       4200,
+		},
+		{
+			name: "angular port from angular.json",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"@angular/core": "^18.0.0"}}`,
+				"angular.json": `{"projects": {"web": {"architect": {"serve": {"options": {"port": 4300}}}}}}`,
+			},
+			framework:  FrameworkAngular,
+			devCommand: "npx ng serve",
+			port:       4300,
+		},
+		{
+			name: "vite port from config",
+			files: map[string]string{
+				"package.json":   `{"scripts": {"dev": "vite"}, "devDependencies": {"vite": "^5.0.0"}}`,
+				"vite.config.ts": "export default defineConfig({\n  server: {\n    port: 3100,\n  },\n})\n",
+			},
+			framework:  FrameworkVite,
+			devCommand: "npm run dev",
+			port:       3100,
+		},
+		{
+			name: "astro port from config",
+			files: map[string]string{
+				"package.json":     `{"scripts": {"dev": "astro dev"}, "dependencies": {"astro": "^4.0.0"}}`,
+				"astro.config.mjs": "export default defineConfig({ server: { port: 4000 } })\n",
+			},
+			framework:  FrameworkAstro,
+			devCommand: "npm run dev",
+			port:       4000,
+		},
+		{
+			name: "solid start",
+			files: map[string]string{
+				"package.json": `{"scripts": {"dev": "vinxi dev"}, "dependencies": {"@solidjs/start": "^1.0.0"}}`,
+			},
+			framework:  FrameworkSolidStart,
+			devCommand: "npm run dev",
+			port:       3000,
+		},
+		{
+			name: "vite default",
+			files: map[string]string{
+				"package.json": `{"devDependencies": {"vite": "^5.0.0"}}`,
+			},
+			framework:  FrameworkVite,
+			devCommand: "npx vite",
+			port:       5173,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			writeFiles(t, tmpDir, tt.files)
+
+			svc := NewDetector().detectNodeProject(tmpDir, "web")
+			if svc == nil {
+				t.Fatal("expected a service")
+			}
+			if svc.Framework != tt.framework {
+				t.Errorf("expected framework %s, got %s", tt.framework, svc.Framework)
+			}
+			if svc.DevCommand != tt.devCommand {
+				t.Errorf("expected dev command %q, got %q", tt.devCommand, svc.DevCommand)
+			}
+			if svc.Port != tt.port {
+				t.Errorf("expected port %d, got %d", tt.port, svc.Port)
+			}
+		})
+	}
+}
+
+func TestDetector_DetectDenoProject(t *testing.T) {
+	tmpDir := t.TempDir()
+	denoJSONC := `{
+  // Dev server
+  "tasks": {
+    "dev": { "command": "deno run --watch -A main.ts --port 8100", "description": "https://example.com/*" },
+    "test": "deno test",
+  },
+}`
+	writeFiles(t, tmpDir, map[string]string{"deno.jsonc": denoJSONC, "package.json": `{"scripts": {"dev": "node index.js"}}`})
+
+	services := NewDetector().scanDirectory(tmpDir, "api")
+	if len(services) != 1 {
+		t.Fatalf("expected 1 service, got %+v", services)
+	}
+	svc := services[0]
+	if svc.Framework != FrameworkDeno || svc.DevCommand != "deno task dev" || svc.Port != 8100 {
+		t.Errorf("unexpected service %+v", svc)
+	}
+}
+
+func TestDetector_DetectBun(t *testing.T) {
+	tests := []struct {
+		name     string
+		pkg      string
+		lockfile string
+		expected string
+	}{
+		{"lockfile", `{"scripts": {"dev": "vite"}}`, "bun.lockb", "bun run dev"},
+		{"text lockfile", `{"scripts": {"dev": "vite"}}`, "bun.lock", "bun run dev"},
+		{"packageManager", `{"packageManager": "bun@1.1.0", "scripts": {"dev": "vite"}}`, "", "bun run dev"},
+		{"script", `{"scripts": {"dev": "bun --watch src/index.ts"}}`, "", "bun run dev"},
+		{"npm", `{"scripts": {"dev": "vite"}}`, "package-lock.json", "npm run dev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(tt.pkg), 0644); err != nil {
+				t.Fatalf("failed to write package.json: %v", err)
+			}
+			if tt.lockfile != "" {
+				if err := os.WriteFile(filepath.Join(tmpDir, tt.lockfile), nil, 0644); err != nil {
+					t.Fatalf("failed to write %s: %v", tt.lockfile, err)
+				}
+			}
+
+			svc := NewDetector().detectNodeProject(tmpDir, "web")
+			if svc == nil || svc.DevCommand != tt.expected {
+				t.Errorf("expected %q, got %+v", tt.expected, svc)
+			}
+		})
+	}
+}
+
+func TestDetector_DetectWorkspace(t *testing.T) {
+	commands := func(project *DetectedProject) map[string]string {
+		result := make(map[string]string)
+		for _, svc := range project.Services {
+			result[svc.Name] = svc.DevCommand
+		}
+		return result
+	}
+
+	t.Run("pnpm", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeFiles(t, tmpDir, map[string]string{
+			"package.json":                `{"name": "root", "scripts": {"dev": "pnpm -r dev"}}`,
+			"pnpm-workspace.yaml":         "packages:\n  - 'tools/*'\n  - 'sites/**'\n  - '!sites/legacy'\n",
+			"tools/worker/package.json":   `{"name": "@acme/worker", "scripts": {"start": "node index.js"}}`,
+			"sites/shop/web/package.json": `{"scripts": {"dev": "vite"}, "devDependencies": {"vite": "^5.0.0"}}`,
+			"sites/legacy/package.json":   `{"name": "legacy", "scripts": {"dev": "gulp"}}`,
+		})
+
+		detected, err := NewDetector().Detect(tmpDir)
+		if err != nil {
+			t.Fatalf("detect failed: %v", err)
+		}
+		got := commands(detected)
+		want := map[string]string{
+			"worker": "pnpm --filter @acme/worker run start",
+			"web":    "pnpm --filter ./sites/shop/web run dev",
+		}
+		if len(got) != len(want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+		for name, command := range want {
+			if got[name] != command {
+				t.Errorf("expected %s to run %q, got %q", name, command, got[name])
+			}
+		}
+	})
+
+	t.Run("npm workspaces with turbo", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeFiles(t, tmpDir, map[string]string{
+			"package.json":          `{"name": "root", "workspaces": ["apps/*"], "scripts": {"dev": "turbo run dev"}}`,
+			"turbo.json":            `{"tasks": {"dev": {"cache": false}}}`,
+			"apps/api/package.json": `{"name": "api", "scripts": {"dev": "nest start --watch"}}`,
+		})
+
+		detected, err := NewDetector().Detect(tmpDir)
+		if err != nil {
+			t.Fatalf("detect failed: %v", err)
+		}
+		if len(detected.Services) != 1 {
+			t.Fatalf("expected 1 service, got %+v", detected.Services)
+		}
+		svc := detected.Services[0]
+		if svc.DevCommand != "npx turbo run dev --filter=api" || svc.Path != "" {
+			t.Errorf("unexpected service %+v", svc)
+		}
+	})
+
+	t.Run("npm workspaces", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		writeFiles(t, tmpDir, map[string]string{
+			"package.json":          `{"workspaces": {"packages": ["apps/*"]}}`,
+			"apps/api/package.json": `{"name": "api", "scripts": {"dev": "nest start --watch"}}`,
+		})
+
+		detected, err := NewDetector().Detect(tmpDir)
+		if err != nil {
+			t.Fatalf("detect failed: %v", err)
+		}
+		if got := commands(detected); got["api"] != "npm run dev -w apps/api" {
+			t.Errorf("unexpected services %v", got)
+		}
+	})
+}
+
+func TestDetector_DetectNx(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"nx.json":                 `{"plugins": [{"plugin": "@nx/vite/plugin", "options": {"serveTargetName": "dev"}}, "@nx/eslint/plugin"]}`,
+		"package.json":            `{"name": "acme", "workspaces": ["apps/*", "libs/*"], "scripts": {"start": "nx run-many -t serve"}}`,
+		"apps/api/project.json":   `{"name": "api", "projectType": "application", "targets": {"serve": {"executor": "@nx/js:node", "options": {"buildTarget": "api:build", "port": 3333}}}}`,
+		"apps/api/package.json":   `{"name": "@acme/api", "scripts": {"dev": "node main.js"}}`,
+		"apps/web/project.json":   `{"name": "web", "projectType": "application"}`,
+		"apps/web/vite.config.ts": "export default defineConfig({ server: { port: 4200 } })\n",
+		"libs/ui/project.json":    `{"name": "ui", "projectType": "library", "targets": {"serve": {}}}`,
+		"apps/docs/package.json":  `{"name": "docs", "scripts": {"dev": "astro dev"}, "dependencies": {"astro": "^4.0.0"}}`,
+	}
+	writeFiles(t, tmpDir, files)
+
+	detected, err := NewDetector().Detect(tmpDir)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	services := make(map[string]DetectedService)
+	for _, svc := range detected.Services {
+		services[svc.Name] = svc
+	}
+	if len(services) != 3 {
+		t.Fatalf("expected api, web and docs, got %+v", detected.Services)
+	}
+	if api := services["api"]; api.DevCommand != "npx nx serve api" || api.Port != 3333 || api.Type != ServiceTypeBackend {
+		t.Errorf("unexpected api service %+v", api)
+	}
+	if web := services["web"]; web.DevCommand != "npx nx dev web" || web.Port != 4200 || web.Type != ServiceTypeFrontend {
+		t.Errorf("unexpected web service %+v", web)
+	}
+	// Packages without a project.json are left to the workspace
+	if docs := services["docs"]; docs.DevCommand != "npm run dev -w apps/docs" {
+		t.Errorf("unexpected docs service %+v", docs)
+	}
+}
+
+func TestDetector_DetectGoWork(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.work":                             "go 1.22\n\nuse (\n\t./services/billing // payments\n\t./tools/gen\n)\nuse ./edge\n",
+		"services/billing/go.mod":             "module example.com/billing\n",
+		"services/billing/cmd/api/main.go":    "func main() {\n\taddr := flag.String(\"addr\", \":8081\", \"listen address\")\n}\n",
+		"services/billing/cmd/worker/main.go": "func main() {}\n",
+		"tools/gen/go.mod":                    "module example.com/gen\n",
+		"tools/gen/main.go":                   "func main() {\n\tport := os.Getenv(\"PORT\")\n\tif port == \"\" {\n\t\tport = \"9090\"\n\t}\n}\n",
+		"edge/go.mod":                         "module example.com/edge\n",
+		"edge/cmd/proxy/main.go":              "func main() {\n\thttp.ListenAndServe(\"localhost:7000\", nil)\n}\n",
+	}
+	writeFiles(t, tmpDir, files)
+
+	detected, err := NewDetector().Detect(tmpDir)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	want := map[string]DetectedService{
+		"api":    {Path: filepath.Join("services", "billing"), DevCommand: "go run ./cmd/api", Port: 8081},
+		"worker": {Path: filepath.Join("services", "billing"), DevCommand: "go run ./cmd/worker"},
+		"gen":    {Path: filepath.Join("tools", "gen"), DevCommand: "go run .", Port: 9090},
+		"edge":   {Path: "edge", DevCommand: "go run ./cmd/proxy", Port: 7000},
+	}
+	if len(detected.Services) != len(want) {
+		t.Fatalf("expected %d services, got %+v", len(want), detected.Services)
+	}
+	for _, svc := range detected.Services {
+		expected, ok := want[svc.Name]
+		if !ok {
+			t.Errorf("unexpected service %+v", svc)
+			continue
+		}
+		if svc.Path != expected.Path || svc.DevCommand != expected.DevCommand || svc.Port != expected.Port {
+			t.Errorf("expected %s to be %+v, got %+v", svc.Name, expected, svc)
+		}
+	}
+}
+
+func TestDetector_DetectGoWorkSharedBinaryNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"go.work":                     "go 1.22\n\nuse (\n\t./billing\n\t./orders\n\t./api\n)\n",
+		"billing/go.mod":              "module example.com/billing\n",
+		"billing/cmd/api/main.go":     "func main() {}\n",
+		"billing/cmd/migrate/main.go": "func main() {}\n",
+		"orders/go.mod":               "module example.com/orders\n",
+		"orders/cmd/api/main.go":      "func main() {}\n",
+		"orders/cmd/worker/main.go":   "func main() {}\n",
+		"api/go.mod":                  "module example.com/api\n",
+		"api/main.go":                 "func main() {}\n",
+	}
+	writeFiles(t, tmpDir, files)
+
+	detected, err := NewDetector().Detect(tmpDir)
+	if err != nil {
+		t.Fatalf("detect failed: %v", err)
+	}
+
+	want := map[string]DetectedService{
+		"billing-api": {Path: "billing", DevCommand: "go run ./cmd/api"},
+		"migrate":     {Path: "billing", DevCommand: "go run ./cmd/migrate"},
+		"orders-api":  {Path: "orders", DevCommand: "go run ./cmd/api"},
+		"worker":      {Path: "orders", DevCommand: "go run ./cmd/worker"},
+		"api":         {Path: "api", DevCommand: "go run ."},
+	}
+	if len(detected.Services) != len(want) {
+		t.Fatalf("expected %d services, got %+v", len(want), detected.Services)
+	}
+	for _, svc := range detected.Services {
+		expected, ok := want[svc.Name]
+		if !ok {
+			t.Errorf("unexpected service %+v", svc)
+			continue
+		}
+		if svc.Path != expected.Path || svc.DevCommand != expected.DevCommand {
+			t.Errorf("expected %s to be %+v, got %+v", svc.Name, expected, svc)
+		}
+	}
+}
+
+// writeFiles writes files, given by slash-separated paths relative to dir,
+// creating their directories
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+}
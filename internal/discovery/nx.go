@@ -0,0 +1,157 @@
+package discovery
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// nxServeTargets are the targets that run a project in development, in
+// order of preference
+var nxServeTargets = []string{"serve", "dev", "start"}
+
+// nxPlugin is an Nx plugin that infers a serve target for projects with
+// one of its config files
+type nxPlugin struct {
+	plugin  string
+	configs []string
+	// The plugin option naming the target, and its default
+	option string
+	target string
+	// The port the dev server listens on when its config doesn't say
+	port    int
+	svcType ServiceType
+}
+
+var nxPlugins = []nxPlugin{
+	{"@nx/vite/plugin", []string{"vite.config.ts", "vite.config.js", "vite.config.mts"}, "serveTargetName", "serve", 5173, ServiceTypeFrontend},
+	{"@nx/next/plugin", []string{"next.config.js", "next.config.mjs", "next.config.ts"}, "devTargetName", "dev", 3000, ServiceTypeFullstack},
+	{"@nx/webpack/plugin", []string{"webpack.config.js", "webpack.config.ts"}, "serveTargetName", "serve", 0, ServiceTypeFrontend},
+}
+
+// NxJSON represents parsed nx.json
+type NxJSON struct {
+	Plugins []json.RawMessage `json:"plugins"`
+}
+
+// NxProject represents a parsed project.json
+type NxProject struct {
+	Name        string `json:"name"`
+	ProjectType string `json:"projectType"`
+	Targets     map[string]struct {
+		Executor string `json:"executor"`
+		Options  struct {
+			Port int `json:"port"`
+		} `json:"options"`
+	} `json:"targets"`
+}
+
+// detectNxWorkspace detects the applications of an Nx workspace, run with
+// nx from the root, and returns them with the directories they're in
+func (d *Detector) detectNxWorkspace(rootPath string) ([]DetectedService, []string) {
+	data, err := os.ReadFile(filepath.Join(rootPath, "nx.json"))
+	if err != nil {
+		return nil, nil
+	}
+	var nx NxJSON
+	if err := json.Unmarshal(data, &nx); err != nil {
+		return nil, nil
+	}
+
+	// Plugins inferring targets, with the target names they use
+	var plugins []nxPlugin
+	for _, raw := range nx.Plugins {
+		var name string
+		var plugin struct {
+			Plugin  string         `json:"plugin"`
+			Options map[string]any `json:"options"`
+		}
+		if json.Unmarshal(raw, &name) != nil {
+			json.Unmarshal(raw, &plugin)
+			name = plugin.Plugin
+		}
+		for _, p := range nxPlugins {
+			if p.plugin != name {
+				continue
+			}
+			if option, ok := plugin.Options[p.option].(string); ok && option != "" {
+				p.target = option
+			}
+			plugins = append(plugins, p)
+		}
+	}
+
+	var services []DetectedService
+	var dirs []string
+	filepath.WalkDir(rootPath, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			if err == nil && path != rootPath && (entry.Name() == "node_modules" || entry.Name() == "dist" || strings.HasPrefix(entry.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.Name() != "project.json" {
+			return nil
+		}
+
+		dir := filepath.Dir(path)
+		rel, err := filepath.Rel(rootPath, dir)
+		if err != nil || rel == "." {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		var project NxProject
+		if json.Unmarshal(data, &project) != nil || project.ProjectType == "library" {
+			return nil
+		}
+		if project.Name == "" {
+			project.Name = filepath.Base(dir)
+		}
+
+		svc := DetectedService{
+			Name:      project.Name,
+			Type:      ServiceTypeFrontend,
+			Framework: FrameworkNx,
+		}
+		for _, target := range nxServeTargets {
+			if t, ok := project.Targets[target]; ok {
+				svc.Command = "npx nx " + target + " " + project.Name
+				svc.Port = t.Options.Port
+				switch {
+				case strings.Contains(t.Executor, ":node"):
+					svc.Type = ServiceTypeBackend
+				case strings.HasPrefix(t.Executor, "@nx/next"):
+					svc.Type = ServiceTypeFullstack
+				}
+				break
+			}
+		}
+	plugins:
+		for _, p := range plugins {
+			if svc.Command != "" {
+				break
+			}
+			for _, config := range p.configs {
+				if _, err := os.Stat(filepath.Join(dir, config)); err == nil {
+					svc.Command = "npx nx " + p.target + " " + project.Name
+					svc.Type = p.svcType
+					svc.Port = configPort(dir, p.configs, p.port)
+					continue plugins
+				}
+			}
+		}
+		if svc.Command == "" {
+			return nil
+		}
+		svc.DevCommand = svc.Command
+		services = append(services, svc)
+		dirs = append(dirs, rel)
+		return nil
+	})
+
+	return services, dirs
+}
@@ -0,0 +1,159 @@
+package discovery
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Workspace tools, in the order readWorkspace prefers them
+const (
+	workspaceTurbo = "turbo"
+	workspacePnpm  = "pnpm"
+	workspaceBun   = "bun"
+	workspaceYarn  = "yarn"
+	workspaceNpm   = "npm"
+)
+
+// workspace describes a JavaScript monorepo: its packages and the tool that
+// runs their scripts from the root
+type workspace struct {
+	tool string
+	// Package directories, relative to the root
+	dirs []string
+}
+
+// readWorkspace reads the package globs from package.json workspaces or
+// pnpm-workspace.yaml and expands them, or returns nil if the directory
+// isn't a workspace root
+func readWorkspace(rootPath string) *workspace {
+	var patterns []string
+	tool := workspaceNpm
+
+	if data, err := os.ReadFile(filepath.Join(rootPath, "pnpm-workspace.yaml")); err == nil {
+		var pnpm struct {
+			Packages []string `yaml:"packages"`
+		}
+		if yaml.Unmarshal(data, &pnpm) == nil {
+			patterns = pnpm.Packages
+			tool = workspacePnpm
+		}
+	}
+	if patterns == nil {
+		if data, err := os.ReadFile(filepath.Join(rootPath, "package.json")); err == nil {
+			var pkg struct {
+				Workspaces json.RawMessage `json:"workspaces"`
+			}
+			if json.Unmarshal(data, &pkg) == nil && len(pkg.Workspaces) > 0 {
+				// A list, or an object with one as yarn classic allows
+				if json.Unmarshal(pkg.Workspaces, &patterns) != nil {
+					var object struct {
+						Packages []string `json:"packages"`
+					}
+					json.Unmarshal(pkg.Workspaces, &object)
+					patterns = object.Packages
+				}
+			}
+		}
+		exists := func(name string) bool {
+			_, err := os.Stat(filepath.Join(rootPath, name))
+			return err == nil
+		}
+		if exists("bun.lockb") || exists("bun.lock") {
+			tool = workspaceBun
+		} else if exists("yarn.lock") {
+			tool = workspaceYarn
+		}
+	}
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	if _, err := os.Stat(filepath.Join(rootPath, "turbo.json")); err == nil {
+		tool = workspaceTurbo
+	}
+
+	ws := &workspace{tool: tool}
+	excluded := make(map[string]bool)
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		if rest, ok := strings.CutPrefix(pattern, "!"); ok {
+			for _, dir := range expandWorkspacePattern(rootPath, rest) {
+				excluded[dir] = true
+			}
+		}
+	}
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			continue
+		}
+		for _, dir := range expandWorkspacePattern(rootPath, pattern) {
+			if !excluded[dir] && !seen[dir] {
+				seen[dir] = true
+				ws.dirs = append(ws.dirs, dir)
+			}
+		}
+	}
+	return ws
+}
+
+// expandWorkspacePattern returns the package directories a workspace glob
+// matches, relative to the root. A trailing /** matches packages at any
+// depth below, outside node_modules
+func expandWorkspacePattern(rootPath, pattern string) []string {
+	pattern = strings.TrimSuffix(strings.TrimPrefix(pattern, "./"), "/")
+
+	var matches []string
+	if base, ok := strings.CutSuffix(pattern, "/**"); ok {
+		filepath.WalkDir(filepath.Join(rootPath, filepath.FromSlash(base)), func(path string, entry os.DirEntry, err error) error {
+			if err != nil || !entry.IsDir() {
+				return nil
+			}
+			if entry.Name() == "node_modules" || strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			matches = append(matches, path)
+			return nil
+		})
+	} else {
+		matches, _ = filepath.Glob(filepath.Join(rootPath, filepath.FromSlash(pattern)))
+	}
+
+	var dirs []string
+	for _, match := range matches {
+		if _, err := os.Stat(filepath.Join(match, "package.json")); err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(rootPath, match); err == nil && rel != "." {
+			dirs = append(dirs, rel)
+		}
+	}
+	return dirs
+}
+
+// runCommand returns the command running a package's script from the
+// workspace root, selecting the package by name or, without one, by
+// directory
+func (ws *workspace) runCommand(pkg *PackageJSON, dir, script string) string {
+	selector := pkg.Name
+	if selector == "" {
+		selector = "./" + filepath.ToSlash(dir)
+	}
+
+	switch ws.tool {
+	case workspaceTurbo:
+		return "npx turbo run " + script + " --filter=" + selector
+	case workspacePnpm:
+		return "pnpm --filter " + selector + " run " + script
+	case workspaceBun:
+		return "bun run --filter " + selector + " " + script
+	case workspaceYarn:
+		if pkg.Name != "" {
+			return "yarn workspace " + pkg.Name + " run " + script
+		}
+	}
+	return "npm run " + script + " -w " + filepath.ToSlash(dir)
+}
@@ -1,8 +1,10 @@
 package log
 
 import (
-	"strings"
+	"regexp"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/paralerdev/paraler/internal/config"
 )
@@ -12,11 +14,28 @@ const (
 	DefaultBufferSize = 1000
 )
 
-// Buffer is a ring buffer for storing log entries per service
+// Buffer is a ring buffer for storing log entries per service. With
+// history enabled, entries trimmed from it are kept on disk.
 type Buffer struct {
 	mu      sync.RWMutex
 	entries map[string][]Entry // key: ServiceID.String()
 	maxSize int
+	history *history
+	rates   map[string]*lineRate // key: ServiceID.String()
+	unseen  map[string]int       // stderr entries since AcknowledgeErrors
+
+	// Entries ever added per service, and a generation that changes when
+	// entries change otherwise, for GetSince
+	added map[string]uint64
+	gen   uint64
+}
+
+// Version identifies the entries of a service at some point: Added counts
+// the entries ever added to it, and Gen changes whenever entries were
+// removed or inserted instead (cleared, or history loaded or dropped)
+type Version struct {
+	Gen   uint64
+	Added uint64
 }
 
 // NewBuffer creates a new log buffer
@@ -27,6 +46,9 @@ func NewBuffer(maxSize int) *Buffer {
 	return &Buffer{
 		entries: make(map[string][]Entry),
 		maxSize: maxSize,
+		rates:   make(map[string]*lineRate),
+		unseen:  make(map[string]int),
+		added:   make(map[string]uint64),
 	}
 }
 
@@ -38,26 +60,85 @@ func (b *Buffer) Add(entry Entry) {
 	key := entry.ServiceID.String()
 	entries := b.entries[key]
 
+	rate, ok := b.rates[key]
+	if !ok {
+		rate = &lineRate{}
+		b.rates[key] = rate
+	}
+	rate.add(entry.Timestamp)
+	if entry.IsStderr {
+		b.unseen[key]++
+	}
+	b.added[key]++
+
 	// Add entry
 	entries = append(entries, entry)
 
 	// Trim if over capacity
 	if len(entries) > b.maxSize {
+		trimmed := entries[:len(entries)-b.maxSize]
+		if b.history != nil {
+			if err := b.history.add(entry.ServiceID, trimmed); err != nil {
+				b.history.close()
+				b.history = nil
+				b.gen++
+			}
+		}
 		entries = entries[len(entries)-b.maxSize:]
 	}
 
 	b.entries[key] = entries
 }
 
-// Get returns all entries for a service
+// Get returns all entries for a service, preceded by those loaded back by
+// LoadHistory
 func (b *Buffer) Get(id config.ServiceID) []Entry {
+	entries, _ := b.GetVersion(id)
+	return entries
+}
+
+// GetVersion returns all entries for a service like Get, and their version
+func (b *Buffer) GetVersion(id config.ServiceID) ([]Entry, Version) {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
 	entries := b.entries[id.String()]
-	result := make([]Entry, len(entries))
-	copy(result, entries)
-	return result
+	var loaded []Entry
+	if b.history != nil {
+		loaded = b.history.loaded[id.String()]
+	}
+	result := make([]Entry, 0, len(loaded)+len(entries))
+	result = append(result, loaded...)
+	result = append(result, entries...)
+	return result, Version{Gen: b.gen, Added: b.added[id.String()]}
+}
+
+// GetSince returns the entries added to a service after version since,
+// the number of the oldest entry Get would return, and the current
+// version. Entries are numbered from 1 in the order they were added, so
+// the last of those Get returns is numbered Version.Added. ok is false if
+// entries changed otherwise since then, and must be got again.
+func (b *Buffer) GetSince(id config.ServiceID, since Version) (added []Entry, oldest uint64, now Version, ok bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	key := id.String()
+	now = Version{Gen: b.gen, Added: b.added[key]}
+	if now.Gen != since.Gen || now.Added < since.Added {
+		return nil, 0, now, false
+	}
+
+	entries := b.entries[key]
+	total := len(entries)
+	if b.history != nil {
+		total += len(b.history.loaded[key])
+	}
+	oldest = now.Added - min(uint64(total), now.Added) + 1
+
+	n := int(min(now.Added-since.Added, uint64(len(entries))))
+	added = make([]Entry, n)
+	copy(added, entries[len(entries)-n:])
+	return added, oldest, now, true
 }
 
 // GetAll returns all entries across all services
@@ -72,36 +153,72 @@ func (b *Buffer) GetAll() []Entry {
 	return all
 }
 
-// GetFiltered returns entries matching a filter string
+// GetFiltered returns entries matching a filter expression (see
+// ParseFilter). An invalid regexp is matched as a plain substring.
 func (b *Buffer) GetFiltered(id config.ServiceID, filter string) []Entry {
+	f, err := ParseFilter(filter)
+	if err != nil {
+		f, _ = ParseFilter(regexp.QuoteMeta(filter))
+	}
+	return b.GetMatching(id, f)
+}
+
+// GetMatching returns entries kept by a filter
+func (b *Buffer) GetMatching(id config.ServiceID, filter *Filter) []Entry {
 	entries := b.Get(id)
 
-	if filter == "" {
+	if filter == nil {
 		return entries
 	}
 
-	filter = strings.ToLower(filter)
 	var filtered []Entry
 	for _, entry := range entries {
-		if strings.Contains(strings.ToLower(entry.Line), filter) {
+		if filter.Match(entry.Line) {
 			filtered = append(filtered, entry)
 		}
 	}
 	return filtered
 }
 
-// Clear removes all entries for a service
+// GetMerged returns the entries of several services kept by a filter,
+// interleaved in chronological order
+func (b *Buffer) GetMerged(ids []config.ServiceID, filter *Filter) []Entry {
+	var merged []Entry
+	for _, id := range ids {
+		merged = append(merged, b.GetMatching(id, filter)...)
+	}
+
+	// Stable, so lines of a service printed at the same time keep their order
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Timestamp.Before(merged[j].Timestamp)
+	})
+	return merged
+}
+
+// Clear removes all entries for a service, including its history
 func (b *Buffer) Clear(id config.ServiceID) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	delete(b.entries, id.String())
+	delete(b.rates, id.String())
+	delete(b.unseen, id.String())
+	b.gen++
+	if b.history != nil {
+		b.history.clear(id)
+	}
 }
 
 // ClearAll removes all entries
 func (b *Buffer) ClearAll() {
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	if b.history != nil {
+		b.history.clearAll()
+	}
 	b.entries = make(map[string][]Entry)
+	b.rates = make(map[string]*lineRate)
+	b.unseen = make(map[string]int)
+	b.gen++
 }
 
 // Count returns the number of entries for a service
@@ -130,7 +247,7 @@ func (b *Buffer) GetLines(id config.ServiceID, filter string, showTimestamp bool
 
 	for i, entry := range entries {
 		if showTimestamp {
-			lines[i] = entry.Timestamp.Format("15:04:05") + " " + entry.Line
+			lines[i] = entry.Timestamp.Format(config.DefaultTimeLayout) + " " + entry.Line
 		} else {
 			lines[i] = entry.Line
 		}
@@ -139,6 +256,26 @@ func (b *Buffer) GetLines(id config.ServiceID, filter string, showTimestamp bool
 	return lines
 }
 
+// LastErrors returns up to n of the most recent stderr lines for a service
+func (b *Buffer) LastErrors(id config.ServiceID, n int) []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	entries := b.entries[id.String()]
+	var lines []string
+	for i := len(entries) - 1; i >= 0 && len(lines) < n; i-- {
+		if entries[i].IsStderr {
+			lines = append(lines, entries[i].Line)
+		}
+	}
+
+	// Restore chronological order
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
+
 // ErrorCount returns the number of stderr entries for a service
 func (b *Buffer) ErrorCount(id config.ServiceID) int {
 	b.mu.RLock()
@@ -153,3 +290,36 @@ func (b *Buffer) ErrorCount(id config.ServiceID) int {
 	}
 	return count
 }
+
+// RecentErrorCount returns the number of stderr entries for a service
+// logged after since
+func (b *Buffer) RecentErrorCount(id config.ServiceID, since time.Time) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	entries := b.entries[id.String()]
+	count := 0
+	// Entries are in order, so the recent ones are at the end
+	for i := len(entries) - 1; i >= 0 && entries[i].Timestamp.After(since); i-- {
+		if entries[i].IsStderr {
+			count++
+		}
+	}
+	return count
+}
+
+// NewErrorCount returns the number of stderr entries added for a service
+// since its errors were last acknowledged
+func (b *Buffer) NewErrorCount(id config.ServiceID) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.unseen[id.String()]
+}
+
+// AcknowledgeErrors marks the errors of a service as seen, resetting its
+// NewErrorCount
+func (b *Buffer) AcknowledgeErrors(id config.ServiceID) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.unseen, id.String())
+}
@@ -1,6 +1,7 @@
 package log
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -96,6 +97,105 @@ func TestBuffer_ErrorCount(t *testing.T) {
 	}
 }
 
+func TestBuffer_RecentErrorCount(t *testing.T) {
+	buf := NewBuffer(100)
+
+	id := config.ServiceID{Project: "test", Service: "backend"}
+	now := time.Now()
+
+	buf.Add(Entry{ServiceID: id, Line: "old stderr", IsStderr: true, Timestamp: now.Add(-10 * time.Minute)})
+	buf.Add(Entry{ServiceID: id, Line: "stderr line", IsStderr: true, Timestamp: now.Add(-time.Minute)})
+	buf.Add(Entry{ServiceID: id, Line: "stdout line", IsStderr: false, Timestamp: now})
+	buf.Add(Entry{ServiceID: id, Line: "another stderr", IsStderr: true, Timestamp: now})
+
+	if count := buf.RecentErrorCount(id, now.Add(-5*time.Minute)); count != 2 {
+		t.Errorf("expected 2 recent errors, got %d", count)
+	}
+}
+
+func TestBuffer_GetSince(t *testing.T) {
+	buf := NewBuffer(3)
+
+	id := config.ServiceID{Project: "test", Service: "backend"}
+	for i := 0; i < 2; i++ {
+		buf.Add(Entry{ServiceID: id, Line: fmt.Sprintf("line %d", i), Timestamp: time.Now()})
+	}
+	entries, version := buf.GetVersion(id)
+	if len(entries) != 2 || version.Added != 2 {
+		t.Fatalf("expected 2 entries at version 2, got %d at %d", len(entries), version.Added)
+	}
+
+	// Two more entries push the first out of the buffer
+	for i := 2; i < 4; i++ {
+		buf.Add(Entry{ServiceID: id, Line: fmt.Sprintf("line %d", i), Timestamp: time.Now()})
+	}
+	added, oldest, now, ok := buf.GetSince(id, version)
+	if !ok {
+		t.Fatal("expected entries since version")
+	}
+	if len(added) != 2 || added[0].Line != "line 2" || added[1].Line != "line 3" {
+		t.Errorf("expected lines 2 and 3, got %v", added)
+	}
+	if oldest != 2 {
+		t.Errorf("expected oldest entry 2, got %d", oldest)
+	}
+
+	if added, _, _, _ := buf.GetSince(id, now); len(added) != 0 {
+		t.Errorf("expected no new entries, got %v", added)
+	}
+
+	// Clearing invalidates versions
+	buf.Clear(id)
+	if _, _, _, ok := buf.GetSince(id, now); ok {
+		t.Error("expected version to be invalid after clear")
+	}
+}
+
+func TestBuffer_AcknowledgeErrors(t *testing.T) {
+	buf := NewBuffer(100)
+
+	id := config.ServiceID{Project: "test", Service: "backend"}
+
+	buf.Add(Entry{ServiceID: id, Line: "stderr line", IsStderr: true, Timestamp: time.Now()})
+	buf.Add(Entry{ServiceID: id, Line: "stdout line", IsStderr: false, Timestamp: time.Now()})
+	if count := buf.NewErrorCount(id); count != 1 {
+		t.Errorf("expected 1 new error, got %d", count)
+	}
+
+	buf.AcknowledgeErrors(id)
+	if count := buf.NewErrorCount(id); count != 0 {
+		t.Errorf("expected no new errors after acknowledging, got %d", count)
+	}
+
+	buf.Add(Entry{ServiceID: id, Line: "another stderr", IsStderr: true, Timestamp: time.Now()})
+	if count := buf.NewErrorCount(id); count != 1 {
+		t.Errorf("expected 1 new error, got %d", count)
+	}
+	if count := buf.ErrorCount(id); count != 2 {
+		t.Errorf("expected 2 errors in total, got %d", count)
+	}
+}
+
+func TestBuffer_LastErrors(t *testing.T) {
+	buf := NewBuffer(100)
+
+	id := config.ServiceID{Project: "test", Service: "backend"}
+
+	buf.Add(Entry{ServiceID: id, Line: "error 1", IsStderr: true, Timestamp: time.Now()})
+	buf.Add(Entry{ServiceID: id, Line: "stdout line", IsStderr: false, Timestamp: time.Now()})
+	buf.Add(Entry{ServiceID: id, Line: "error 2", IsStderr: true, Timestamp: time.Now()})
+	buf.Add(Entry{ServiceID: id, Line: "error 3", IsStderr: true, Timestamp: time.Now()})
+
+	lines := buf.LastErrors(id, 2)
+	if len(lines) != 2 || lines[0] != "error 2" || lines[1] != "error 3" {
+		t.Errorf("expected [error 2 error 3], got %v", lines)
+	}
+
+	if lines := buf.LastErrors(config.ServiceID{Project: "test", Service: "other"}, 2); len(lines) != 0 {
+		t.Errorf("expected no lines for unknown service, got %v", lines)
+	}
+}
+
 func TestBuffer_Clear(t *testing.T) {
 	buf := NewBuffer(100)
 
@@ -145,3 +245,176 @@ func TestBuffer_Count(t *testing.T) {
 		t.Errorf("expected count 5, got %d", buf.Count(id))
 	}
 }
+
+func TestBuffer_GetMatching(t *testing.T) {
+	buf := NewBuffer(100)
+
+	id := config.ServiceID{Project: "test", Service: "backend"}
+
+	buf.Add(Entry{ServiceID: id, Line: "GET /health 200", Timestamp: time.Now()})
+	buf.Add(Entry{ServiceID: id, Line: "GET /users 500", Timestamp: time.Now()})
+	buf.Add(Entry{ServiceID: id, Line: "POST /users 201", Timestamp: time.Now()})
+	buf.Add(Entry{ServiceID: id, Line: "! deprecated flag", Timestamp: time.Now()})
+
+	tests := []struct {
+		filter string
+		want   int
+	}{
+		{"", 4},
+		{"users", 2},
+		{"^get .* [45]\\d\\d$", 1},
+		{"!health", 3},
+		{"!/users [25]", 2},
+		{"\\!", 1},
+	}
+
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.filter)
+		if err != nil {
+			t.Fatalf("ParseFilter(%q) error = %v", tt.filter, err)
+		}
+		if got := len(buf.GetMatching(id, f)); got != tt.want {
+			t.Errorf("filter %q: expected %d entries, got %d", tt.filter, tt.want, got)
+		}
+	}
+
+	if _, err := ParseFilter("users("); err == nil {
+		t.Error("expected error for invalid regexp")
+	}
+
+	// GetFiltered falls back to a substring match
+	buf.Add(Entry{ServiceID: id, Line: "call users(1)", Timestamp: time.Now()})
+	if got := len(buf.GetFiltered(id, "users(")); got != 1 {
+		t.Errorf("expected 1 entry for invalid regexp, got %d", got)
+	}
+}
+
+func TestFilter_FindAll(t *testing.T) {
+	f, err := ParseFilter("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches := f.FindAll("User created for users")
+	if len(matches) != 2 || matches[0][0] != 0 || matches[1][0] != 17 {
+		t.Errorf("expected matches at 0 and 17, got %v", matches)
+	}
+
+	// Empty matches aren't highlighted
+	f, _ = ParseFilter("x*")
+	if matches := f.FindAll("abc"); len(matches) != 0 {
+		t.Errorf("expected no matches, got %v", matches)
+	}
+}
+
+func TestBuffer_GetMerged(t *testing.T) {
+	buf := NewBuffer(100)
+
+	api := config.ServiceID{Project: "test", Service: "api"}
+	web := config.ServiceID{Project: "test", Service: "web"}
+	start := time.Now()
+
+	buf.Add(Entry{ServiceID: web, Line: "web request", Timestamp: start})
+	buf.Add(Entry{ServiceID: web, Line: "web response", Timestamp: start.Add(3 * time.Millisecond)})
+	buf.Add(Entry{ServiceID: api, Line: "api handle", Timestamp: start.Add(time.Millisecond)})
+	buf.Add(Entry{ServiceID: api, Line: "api done", Timestamp: start.Add(2 * time.Millisecond)})
+
+	merged := buf.GetMerged([]config.ServiceID{api, web}, nil)
+	want := []string{"web request", "api handle", "api done", "web response"}
+	if len(merged) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(merged))
+	}
+	for i, line := range want {
+		if merged[i].Line != line {
+			t.Errorf("entry %d: expected %q, got %q", i, line, merged[i].Line)
+		}
+	}
+
+	f, _ := ParseFilter("done|request")
+	if got := len(buf.GetMerged([]config.ServiceID{api, web}, f)); got != 2 {
+		t.Errorf("expected 2 filtered entries, got %d", got)
+	}
+}
+
+func TestBuffer_History(t *testing.T) {
+	buf := NewBuffer(5)
+	if err := buf.EnableHistory(func(config.ServiceID) int { return 20 }); err != nil {
+		t.Fatalf("EnableHistory() error = %v", err)
+	}
+	defer buf.Close()
+
+	id := config.ServiceID{Project: "test", Service: "backend"}
+	for i := 0; i < 30; i++ {
+		buf.Add(NewEntry(id, fmt.Sprintf("line %d", i), false))
+	}
+
+	lines := func() []string {
+		var lines []string
+		for _, entry := range buf.Get(id) {
+			lines = append(lines, entry.Line)
+		}
+		return lines
+	}
+
+	// Lines 0-9 were dropped to stay within the retention
+	if n := buf.LoadHistory(id, 10); n != 10 {
+		t.Fatalf("expected 10 entries loaded, got %d", n)
+	}
+	if got := lines(); len(got) != 15 || got[0] != "line 15" || got[14] != "line 29" {
+		t.Fatalf("expected lines 15-29, got %v", got)
+	}
+
+	// Entries trimmed meanwhile stay contiguous with those loaded
+	buf.Add(NewEntry(id, "line 30", false))
+	if n := buf.LoadHistory(id, 100); n != 5 {
+		t.Fatalf("expected 5 entries loaded, got %d", n)
+	}
+	if got := lines(); len(got) != 21 || got[0] != "line 10" || got[15] != "line 25" || got[20] != "line 30" {
+		t.Fatalf("expected lines 10-30, got %v", got)
+	}
+	if n := buf.LoadHistory(id, 100); n != 0 {
+		t.Errorf("expected no more history, got %d entries", n)
+	}
+
+	buf.DropHistory(id)
+	if got := lines(); len(got) != 5 || got[0] != "line 26" {
+		t.Errorf("expected lines 26-30 after DropHistory, got %v", got)
+	}
+
+	buf.Clear(id)
+	if n := buf.LoadHistory(id, 100); n != 0 {
+		t.Errorf("expected Clear to remove the history, got %d entries", n)
+	}
+}
+
+func TestLineRate(t *testing.T) {
+	var rate lineRate
+	now := time.Unix(1000, 0)
+
+	// 3 lines two seconds ago, 1 a second ago, and 5 in the current second
+	for i := 0; i < 3; i++ {
+		rate.add(now.Add(-2 * time.Second))
+	}
+	rate.add(now.Add(-time.Second))
+	for i := 0; i < 5; i++ {
+		rate.add(now)
+	}
+
+	got := fmt.Sprint(rate.perSecond(now, 4))
+	if got != "[0 0 3 1]" {
+		t.Errorf("expected [0 0 3 1], got %s", got)
+	}
+
+	// After a quiet minute the old counts are gone
+	rate.add(now.Add(time.Minute))
+	got = fmt.Sprint(rate.perSecond(now.Add(time.Minute+time.Second), 3))
+	if got != "[0 0 1]" {
+		t.Errorf("expected [0 0 1], got %s", got)
+	}
+
+	// Lines older than the window are not counted
+	rate.add(now)
+	got = fmt.Sprint(rate.perSecond(now.Add(time.Minute+time.Second), 60))
+	if got != fmt.Sprint(append(make([]int, 59), 1)) {
+		t.Errorf("expected only the latest line counted, got %s", got)
+	}
+}
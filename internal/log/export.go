@@ -0,0 +1,102 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// ExportFormat is a file format logs are exported in
+type ExportFormat string
+
+const (
+	ExportPlain  ExportFormat = "plain"  // "<time> <service> <stream> <line>" per line
+	ExportJSON   ExportFormat = "json"   // a JSON array of records
+	ExportNDJSON ExportFormat = "ndjson" // one JSON record per line
+)
+
+// ParseExportFormat parses an export format name
+func ParseExportFormat(name string) (ExportFormat, error) {
+	switch format := ExportFormat(name); format {
+	case ExportPlain, ExportJSON, ExportNDJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown export format %q (must be plain, json or ndjson)", name)
+	}
+}
+
+// Extension returns the file extension of exported files
+func (f ExportFormat) Extension() string {
+	switch f {
+	case ExportJSON:
+		return ".json"
+	case ExportNDJSON:
+		return ".ndjson"
+	default:
+		return ".log"
+	}
+}
+
+// exportRecord is an exported entry in the JSON formats
+type exportRecord struct {
+	Time    time.Time `json:"time"`
+	Service string    `json:"service"`
+	Stderr  bool      `json:"stderr"`
+	Line    string    `json:"line"`
+}
+
+// Export writes entries to w in a format, with timestamps in loc (or as
+// they were recorded if loc is nil)
+func Export(w io.Writer, entries []Entry, format ExportFormat, loc *time.Location) error {
+	if loc != nil {
+		entries = slices.Clone(entries)
+		for i := range entries {
+			entries[i].Timestamp = entries[i].Timestamp.In(loc)
+		}
+	}
+
+	switch format {
+	case ExportJSON:
+		records := make([]exportRecord, len(entries))
+		for i, entry := range entries {
+			records[i] = newExportRecord(entry)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+
+	case ExportNDJSON:
+		enc := json.NewEncoder(w)
+		for _, entry := range entries {
+			if err := enc.Encode(newExportRecord(entry)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		for _, entry := range entries {
+			stream := "stdout"
+			if entry.IsStderr {
+				stream = "stderr"
+			}
+			if _, err := fmt.Fprintf(w, "%s %s %s %s\n",
+				entry.Timestamp.Format(time.RFC3339Nano), entry.ServiceID, stream, entry.Line); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// newExportRecord creates the JSON record of an entry
+func newExportRecord(entry Entry) exportRecord {
+	return exportRecord{
+		Time:    entry.Timestamp,
+		Service: entry.ServiceID.String(),
+		Stderr:  entry.IsStderr,
+		Line:    entry.Line,
+	}
+}
@@ -0,0 +1,74 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+func TestExport(t *testing.T) {
+	id := config.ServiceID{Project: "test", Service: "backend"}
+	ts := time.Date(2024, 5, 1, 12, 30, 0, 500, time.UTC)
+	entries := []Entry{
+		{ServiceID: id, Line: "listening", Timestamp: ts},
+		{ServiceID: id, Line: "oops", IsStderr: true, Timestamp: ts.Add(time.Second)},
+	}
+
+	var plain bytes.Buffer
+	if err := Export(&plain, entries, ExportPlain, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := "2024-05-01T12:30:00.0000005Z test/backend stdout listening\n" +
+		"2024-05-01T12:30:01.0000005Z test/backend stderr oops\n"
+	if plain.String() != want {
+		t.Errorf("plain export = %q, want %q", plain.String(), want)
+	}
+
+	var ndjson bytes.Buffer
+	if err := Export(&ndjson, entries, ExportNDJSON, nil); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(ndjson.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 ndjson lines, got %d", len(lines))
+	}
+	var record exportRecord
+	if err := json.Unmarshal([]byte(lines[1]), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Service != "test/backend" || !record.Stderr || record.Line != "oops" || !record.Time.Equal(ts.Add(time.Second)) {
+		t.Errorf("unexpected ndjson record %+v", record)
+	}
+
+	var array bytes.Buffer
+	if err := Export(&array, entries, ExportJSON, nil); err != nil {
+		t.Fatal(err)
+	}
+	var records []exportRecord
+	if err := json.Unmarshal(array.Bytes(), &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Line != "listening" || records[0].Stderr {
+		t.Errorf("unexpected json records %+v", records)
+	}
+
+	// Timestamps are written in the zone asked for
+	var local bytes.Buffer
+	if err := Export(&local, entries[:1], ExportPlain, time.FixedZone("CEST", 2*60*60)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(local.String(), "2024-05-01T14:30:00.0000005+02:00 ") {
+		t.Errorf("plain export in CEST = %q", local.String())
+	}
+	if !entries[0].Timestamp.Equal(ts) || entries[0].Timestamp.Location() != time.UTC {
+		t.Error("exporting changed the entries")
+	}
+
+	if _, err := ParseExportFormat("xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
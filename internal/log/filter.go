@@ -0,0 +1,82 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// Filter selects log lines. Its expression is a case-insensitive regexp;
+// a leading ! keeps the lines that don't match instead (\! matches a
+// literal !).
+type Filter struct {
+	expr   string
+	re     *regexp.Regexp
+	negate bool
+}
+
+// ParseFilter parses a filter expression. An empty expression returns a
+// nil filter, which matches every line.
+func ParseFilter(expr string) (*Filter, error) {
+	if expr == "" {
+		return nil, nil
+	}
+
+	f := &Filter{expr: expr}
+	pattern := expr
+	if strings.HasPrefix(pattern, "!") {
+		f.negate = true
+		pattern = pattern[1:]
+	}
+
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		// Drop the "error parsing regexp" prefix, which repeats the
+		// (?i) added above
+		var syntaxErr *syntax.Error
+		if errors.As(err, &syntaxErr) {
+			return nil, fmt.Errorf("invalid regexp: %s: `%s`", syntaxErr.Code, syntaxErr.Expr)
+		}
+		return nil, fmt.Errorf("invalid regexp: %w", err)
+	}
+	f.re = re
+	return f, nil
+}
+
+// Match returns true if the filter keeps a line
+func (f *Filter) Match(line string) bool {
+	if f == nil {
+		return true
+	}
+	return f.re.MatchString(line) != f.negate
+}
+
+// Negated returns true if the filter keeps the lines that don't match
+func (f *Filter) Negated() bool {
+	return f != nil && f.negate
+}
+
+// FindAll returns the start and end of each non-empty match of the
+// pattern in a line
+func (f *Filter) FindAll(line string) [][]int {
+	if f == nil {
+		return nil
+	}
+	var matches [][]int
+	for _, loc := range f.re.FindAllStringIndex(line, -1) {
+		if loc[1] > loc[0] {
+			matches = append(matches, loc)
+		}
+	}
+	return matches
+}
+
+// String returns the filter expression
+func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.expr
+}
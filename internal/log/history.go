@@ -0,0 +1,231 @@
+package log
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+// DefaultHistoryLines is the number of older lines of a service kept on
+// disk by default
+const DefaultHistoryLines = 100000
+
+// history keeps the entries trimmed from a Buffer in files, so older output
+// can be loaded back when scrolling up. Each service has a current file and
+// the previous one; once the current file holds half the retention it
+// replaces the previous one, so about the retention is kept on disk.
+type history struct {
+	dir       string
+	retention func(config.ServiceID) int
+	files     map[string]*historyFile // key: ServiceID.String()
+	loaded    map[string][]Entry      // entries loaded back, oldest first
+}
+
+// historyFile is the current history file of a service
+type historyFile struct {
+	f     *os.File
+	w     *bufio.Writer
+	lines int
+}
+
+// EnableHistory makes the buffer write entries it trims to a temporary
+// directory instead of dropping them. retention returns the number of
+// lines kept for a service, 0 for DefaultHistoryLines. If writing fails,
+// history is disabled again and entries are dropped as before.
+func (b *Buffer) EnableHistory(retention func(config.ServiceID) int) error {
+	dir, err := os.MkdirTemp("", "paraler-history-")
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.history = &history{
+		dir:       dir,
+		retention: retention,
+		files:     make(map[string]*historyFile),
+		loaded:    make(map[string][]Entry),
+	}
+	return nil
+}
+
+// LoadHistory loads up to n entries of a service older than those returned
+// by Get, returning the number loaded. They stay in memory until
+// DropHistory is called.
+func (b *Buffer) LoadHistory(id config.ServiceID, n int) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.history == nil {
+		return 0
+	}
+
+	key := id.String()
+	if hf, ok := b.history.files[key]; ok {
+		hf.w.Flush()
+	}
+	path := servicePath(b.history.dir, id)
+	var stored []Entry
+	for _, p := range []string{path + ".1", path} {
+		entries, err := readStoredEntries(p, id)
+		if err != nil {
+			return 0
+		}
+		stored = append(stored, entries...)
+	}
+
+	// Entries loaded before are the most recent of those stored
+	loaded := b.history.loaded[key]
+	end := len(stored) - len(loaded)
+	if end <= 0 {
+		return 0
+	}
+	start := max(0, end-n)
+	b.history.loaded[key] = append(stored[start:end:end], loaded...)
+	b.gen++
+	return end - start
+}
+
+// DropHistory releases the entries of a service loaded by LoadHistory
+func (b *Buffer) DropHistory(id config.ServiceID) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.history == nil {
+		return
+	}
+	if _, ok := b.history.loaded[id.String()]; ok {
+		delete(b.history.loaded, id.String())
+		b.gen++
+	}
+}
+
+// Close removes the history files
+func (b *Buffer) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.history != nil {
+		b.history.close()
+		b.history = nil
+	}
+}
+
+// add writes entries trimmed from a service's buffer to its history file
+func (h *history) add(id config.ServiceID, entries []Entry) error {
+	key := id.String()
+	if loaded, ok := h.loaded[key]; ok {
+		// Keep what is loaded contiguous with the buffer
+		h.loaded[key] = append(loaded, entries...)
+	}
+
+	hf, err := h.file(id)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if _, err := hf.w.WriteString(formatStoredLine(entry)); err != nil {
+			return err
+		}
+		hf.lines++
+	}
+
+	retention := h.retention(id)
+	if retention <= 0 {
+		retention = DefaultHistoryLines
+	}
+	if hf.lines >= max(1, retention/2) {
+		// Start a new file, replacing the previous one
+		delete(h.files, key)
+		if err := hf.close(); err != nil {
+			return err
+		}
+		path := servicePath(h.dir, id)
+		return os.Rename(path, path+".1")
+	}
+	return nil
+}
+
+// file returns the open history file of a service, creating it if needed
+func (h *history) file(id config.ServiceID) (*historyFile, error) {
+	key := id.String()
+	if hf, ok := h.files[key]; ok {
+		return hf, nil
+	}
+
+	path := servicePath(h.dir, id)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, err
+	}
+	hf := &historyFile{f: f, w: bufio.NewWriter(f)}
+	h.files[key] = hf
+	return hf, nil
+}
+
+// clear removes the history of a service
+func (h *history) clear(id config.ServiceID) {
+	key := id.String()
+	delete(h.loaded, key)
+	if hf, ok := h.files[key]; ok {
+		hf.close()
+		delete(h.files, key)
+	}
+	path := servicePath(h.dir, id)
+	os.Remove(path)
+	os.Remove(path + ".1")
+}
+
+// clearAll removes the history of all services
+func (h *history) clearAll() {
+	for _, hf := range h.files {
+		hf.close()
+	}
+	h.files = make(map[string]*historyFile)
+	h.loaded = make(map[string][]Entry)
+	dirs, _ := os.ReadDir(h.dir)
+	for _, d := range dirs {
+		os.RemoveAll(filepath.Join(h.dir, d.Name()))
+	}
+}
+
+// close closes the history files and removes them
+func (h *history) close() {
+	for _, hf := range h.files {
+		hf.close()
+	}
+	os.RemoveAll(h.dir)
+}
+
+func (hf *historyFile) close() error {
+	if err := hf.w.Flush(); err != nil {
+		hf.f.Close()
+		return err
+	}
+	return hf.f.Close()
+}
+
+// readStoredEntries reads the entries of a file written with
+// formatStoredLine. A missing file has no entries.
+func readStoredEntries(path string, id config.ServiceID) ([]Entry, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if entry, ok := parseStoredLine(id, scanner.Text()); ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
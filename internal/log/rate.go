@@ -0,0 +1,62 @@
+package log
+
+import (
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+// rateWindow is the number of seconds line counts are kept for
+const rateWindow = 60
+
+// lineRate counts the lines of a service per second, over the last
+// rateWindow seconds
+type lineRate struct {
+	counts [rateWindow]int // by unix second modulo rateWindow
+	last   int64           // unix second of the latest line
+}
+
+// add counts a line printed at t
+func (r *lineRate) add(t time.Time) {
+	sec := t.Unix()
+	switch {
+	case sec > r.last:
+		// Reset the slots of the seconds without lines since the latest
+		for s := max(r.last+1, sec-rateWindow+1); s <= sec; s++ {
+			r.counts[s%rateWindow] = 0
+		}
+		r.last = sec
+	case sec <= r.last-rateWindow:
+		return
+	}
+	r.counts[sec%rateWindow]++
+}
+
+// perSecond returns the line counts of the n whole seconds before now,
+// oldest first
+func (r *lineRate) perSecond(now time.Time, n int) []int {
+	counts := make([]int, n)
+	end := now.Unix()
+	for i := range counts {
+		sec := end - int64(n-i)
+		if sec > r.last || sec <= r.last-rateWindow || sec < 0 {
+			continue
+		}
+		counts[i] = r.counts[sec%rateWindow]
+	}
+	return counts
+}
+
+// LinesPerSecond returns the number of lines a service printed in each of
+// the last n whole seconds (up to a minute), oldest first
+func (b *Buffer) LinesPerSecond(id config.ServiceID, n int) []int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	n = min(n, rateWindow)
+	rate, ok := b.rates[id.String()]
+	if !ok {
+		return make([]int, n)
+	}
+	return rate.perSecond(time.Now(), n)
+}
@@ -0,0 +1,344 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+// Batching of the HTTP sinks
+const (
+	sinkBatchSize     = 500
+	sinkFlushInterval = time.Second
+	sinkQueueSize     = 10000
+)
+
+// Sink forwards entries of all services to an external destination
+type Sink interface {
+	// Write forwards an entry. HTTP sinks queue it and send it in the
+	// background, dropping entries if the endpoint can't keep up.
+	Write(entry Entry) error
+
+	// Close flushes queued entries and releases the sink
+	Close() error
+}
+
+// NewSink creates the sink configured by cfg
+func NewSink(cfg config.Sink) (Sink, error) {
+	switch cfg.Type {
+	case config.SinkFile:
+		return newFileSink(config.ExpandPath(cfg.Path))
+	case config.SinkSyslog:
+		return newSyslogSink(cfg)
+	case config.SinkLoki:
+		return newHTTPSink(cfg.URL, func(entries []Entry) ([]byte, error) {
+			return encodeLoki(entries, cfg.Labels)
+		}), nil
+	case config.SinkOTLP:
+		return newHTTPSink(cfg.URL, func(entries []Entry) ([]byte, error) {
+			return encodeOTLP(entries, cfg.Labels)
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown sink type %q", cfg.Type)
+	}
+}
+
+// fileSink appends entries to a file in the plain export format
+type fileSink struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+func newFileSink(path string) (*fileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create sink directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sink file: %w", err)
+	}
+	return &fileSink{f: f}, nil
+}
+
+func (s *fileSink) Write(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Export(s.f, []Entry{entry}, ExportPlain, nil)
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
+
+// httpSink sends batches of entries to an HTTP endpoint in the background
+type httpSink struct {
+	url    string
+	encode func([]Entry) ([]byte, error)
+	client *http.Client
+	queue  chan Entry
+	done   chan struct{}
+
+	mu      sync.Mutex
+	lastErr error
+	dropped int
+}
+
+func newHTTPSink(url string, encode func([]Entry) ([]byte, error)) *httpSink {
+	s := &httpSink{
+		url:    url,
+		encode: encode,
+		client: &http.Client{Timeout: 10 * time.Second},
+		queue:  make(chan Entry, sinkQueueSize),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Write queues an entry. It reports the last failure to send a batch, so
+// an unreachable endpoint shows up without blocking on the network.
+func (s *httpSink) Write(entry Entry) error {
+	select {
+	case s.queue <- entry:
+	default:
+		s.mu.Lock()
+		s.dropped++
+		s.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.lastErr
+	s.lastErr = nil
+	return err
+}
+
+func (s *httpSink) Close() error {
+	close(s.queue)
+	<-s.done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastErr
+}
+
+// run sends queued entries once a batch is full or every flush interval
+func (s *httpSink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(sinkFlushInterval)
+	defer ticker.Stop()
+
+	var batch []Entry
+	flush := func() {
+		if len(batch) > 0 {
+			s.send(batch)
+			batch = nil
+		}
+	}
+
+	for {
+		select {
+		case entry, ok := <-s.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) >= sinkBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// send posts a batch, recording the error if it fails
+func (s *httpSink) send(batch []Entry) {
+	err := s.post(batch)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil && s.dropped > 0 {
+		err = fmt.Errorf("%d lines dropped, %s isn't keeping up", s.dropped, s.url)
+		s.dropped = 0
+	}
+	if err != nil {
+		s.lastErr = err
+	}
+}
+
+func (s *httpSink) post(batch []Entry) error {
+	body, err := s.encode(batch)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %s", s.url, resp.Status)
+	}
+	return nil
+}
+
+// lokiPush is the body of a Loki push API request
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"` // [unix nanoseconds, line]
+}
+
+// encodeLoki encodes entries as Loki streams, one per service and output
+// stream
+func encodeLoki(entries []Entry, labels map[string]string) ([]byte, error) {
+	var push lokiPush
+	streams := make(map[string]int)
+	for _, entry := range entries {
+		stream := streamName(entry)
+		key := entry.ServiceID.String() + " " + stream
+		i, ok := streams[key]
+		if !ok {
+			streamLabels := map[string]string{
+				"job":     "paraler",
+				"project": entry.ServiceID.Project,
+				"service": entry.ServiceID.Service,
+				"stream":  stream,
+			}
+			if entry.ServiceID.Instance > 0 {
+				streamLabels["instance"] = strconv.Itoa(entry.ServiceID.Instance)
+			}
+			for k, v := range labels {
+				streamLabels[k] = v
+			}
+			i = len(push.Streams)
+			streams[key] = i
+			push.Streams = append(push.Streams, lokiStream{Stream: streamLabels})
+		}
+		push.Streams[i].Values = append(push.Streams[i].Values,
+			[2]string{strconv.FormatInt(entry.Timestamp.UnixNano(), 10), entry.Line})
+	}
+	return json.Marshal(push)
+}
+
+// OTLP/HTTP JSON logs request, trimmed to the fields used
+type otlpLogs struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano   string          `json:"timeUnixNano"`
+	SeverityNumber int             `json:"severityNumber"`
+	SeverityText   string          `json:"severityText"`
+	Body           otlpValue       `json:"body"`
+	Attributes     []otlpAttribute `json:"attributes"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// OTLP severity numbers of stdout and stderr lines
+const (
+	otlpSeverityInfo  = 9
+	otlpSeverityError = 17
+)
+
+// encodeOTLP encodes entries as OTLP logs, with a resource per service
+func encodeOTLP(entries []Entry, labels map[string]string) ([]byte, error) {
+	var req otlpLogs
+	resources := make(map[config.ServiceID]int)
+	for _, entry := range entries {
+		i, ok := resources[entry.ServiceID]
+		if !ok {
+			attrs := []otlpAttribute{
+				{Key: "service.name", Value: otlpValue{entry.ServiceID.Service}},
+				{Key: "service.namespace", Value: otlpValue{entry.ServiceID.Project}},
+			}
+			if entry.ServiceID.Instance > 0 {
+				attrs = append(attrs, otlpAttribute{Key: "service.instance.id", Value: otlpValue{strconv.Itoa(entry.ServiceID.Instance)}})
+			}
+			for k, v := range labels {
+				attrs = append(attrs, otlpAttribute{Key: k, Value: otlpValue{v}})
+			}
+			i = len(req.ResourceLogs)
+			resources[entry.ServiceID] = i
+			req.ResourceLogs = append(req.ResourceLogs, otlpResourceLogs{
+				Resource:  otlpResource{Attributes: attrs},
+				ScopeLogs: []otlpScopeLogs{{Scope: otlpScope{Name: "paraler"}}},
+			})
+		}
+
+		record := otlpLogRecord{
+			TimeUnixNano:   strconv.FormatInt(entry.Timestamp.UnixNano(), 10),
+			SeverityNumber: otlpSeverityInfo,
+			SeverityText:   "INFO",
+			Body:           otlpValue{entry.Line},
+			Attributes:     []otlpAttribute{{Key: "log.iostream", Value: otlpValue{streamName(entry)}}},
+		}
+		if entry.IsStderr {
+			record.SeverityNumber = otlpSeverityError
+			record.SeverityText = "ERROR"
+		}
+		scope := &req.ResourceLogs[i].ScopeLogs[0]
+		scope.LogRecords = append(scope.LogRecords, record)
+	}
+	return json.Marshal(req)
+}
+
+// streamName returns the output stream of an entry
+func streamName(entry Entry) string {
+	if entry.IsStderr {
+		return "stderr"
+	}
+	return "stdout"
+}
@@ -0,0 +1,116 @@
+package log
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+func TestSink_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "all.log")
+	sink, err := NewSink(config.Sink{Type: config.SinkFile, Path: path})
+	if err != nil {
+		t.Fatalf("NewSink() error = %v", err)
+	}
+
+	id := config.ServiceID{Project: "test", Service: "backend"}
+	sink.Write(NewEntry(id, "listening", false))
+	sink.Write(NewEntry(id, "oops", true))
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], " test/backend stdout listening") || !strings.HasSuffix(lines[1], " test/backend stderr oops") {
+		t.Errorf("unexpected sink file contents:\n%s", data)
+	}
+}
+
+func TestSink_Loki(t *testing.T) {
+	var mu sync.Mutex
+	var pushes []lokiPush
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var push lokiPush
+		if err := json.Unmarshal(body, &push); err != nil {
+			t.Errorf("invalid push body: %v", err)
+		}
+		mu.Lock()
+		pushes = append(pushes, push)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink, err := NewSink(config.Sink{Type: config.SinkLoki, URL: server.URL, Labels: map[string]string{"env": "dev"}})
+	if err != nil {
+		t.Fatalf("NewSink() error = %v", err)
+	}
+
+	api := config.ServiceID{Project: "shop", Service: "api"}
+	ts := time.Unix(1700000000, 42)
+	sink.Write(Entry{ServiceID: api, Line: "GET /", Timestamp: ts})
+	sink.Write(Entry{ServiceID: api, Line: "panic", IsStderr: true, Timestamp: ts})
+	sink.Write(Entry{ServiceID: api, Line: "GET /users", Timestamp: ts})
+
+	// Close flushes the pending batch
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(pushes) != 1 {
+		t.Fatalf("expected 1 push, got %d", len(pushes))
+	}
+	streams := pushes[0].Streams
+	if len(streams) != 2 {
+		t.Fatalf("expected stdout and stderr streams, got %+v", streams)
+	}
+	stdout := streams[0]
+	if stdout.Stream["service"] != "api" || stdout.Stream["project"] != "shop" || stdout.Stream["stream"] != "stdout" || stdout.Stream["env"] != "dev" {
+		t.Errorf("unexpected stream labels %v", stdout.Stream)
+	}
+	if len(stdout.Values) != 2 || stdout.Values[0] != [2]string{"1700000000000000042", "GET /"} {
+		t.Errorf("unexpected stream values %v", stdout.Values)
+	}
+}
+
+func TestEncodeOTLP(t *testing.T) {
+	api := config.ServiceID{Project: "shop", Service: "api"}
+	data, err := encodeOTLP([]Entry{
+		{ServiceID: api, Line: "started", Timestamp: time.Unix(1, 0)},
+		{ServiceID: api, Line: "failed", IsStderr: true, Timestamp: time.Unix(2, 0)},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var req otlpLogs
+	if err := json.Unmarshal(data, &req); err != nil {
+		t.Fatal(err)
+	}
+	if len(req.ResourceLogs) != 1 {
+		t.Fatalf("expected 1 resource, got %d", len(req.ResourceLogs))
+	}
+	if attr := req.ResourceLogs[0].Resource.Attributes[0]; attr.Key != "service.name" || attr.Value.StringValue != "api" {
+		t.Errorf("unexpected resource attribute %+v", attr)
+	}
+	records := req.ResourceLogs[0].ScopeLogs[0].LogRecords
+	if len(records) != 2 || records[1].SeverityText != "ERROR" || records[1].Body.StringValue != "failed" || records[0].TimeUnixNano != "1000000000" {
+		t.Errorf("unexpected log records %+v", records)
+	}
+}
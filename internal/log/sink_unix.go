@@ -0,0 +1,44 @@
+//go:build !windows
+
+package log
+
+import (
+	"fmt"
+	"log/syslog"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+// syslogSink sends entries to syslog, tagged paraler, with stderr lines at
+// error priority
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+func newSyslogSink(cfg config.Sink) (Sink, error) {
+	var network, addr string
+	if cfg.Address != "" {
+		var err error
+		if network, addr, err = cfg.SyslogAddress(); err != nil {
+			return nil, err
+		}
+	}
+
+	w, err := syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_USER, "paraler")
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	return &syslogSink{w: w}, nil
+}
+
+func (s *syslogSink) Write(entry Entry) error {
+	msg := entry.ServiceID.String() + ": " + entry.Line
+	if entry.IsStderr {
+		return s.w.Err(msg)
+	}
+	return s.w.Info(msg)
+}
+
+func (s *syslogSink) Close() error {
+	return s.w.Close()
+}
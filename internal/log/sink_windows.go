@@ -0,0 +1,14 @@
+//go:build windows
+
+package log
+
+import (
+	"fmt"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+// newSyslogSink fails, there is no syslog on Windows
+func newSyslogSink(cfg config.Sink) (Sink, error) {
+	return nil, fmt.Errorf("the syslog sink is not supported on Windows")
+}
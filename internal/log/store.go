@@ -0,0 +1,298 @@
+package log
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+const (
+	// DefaultMaxSizeMB is the size a log file is rotated at by default
+	DefaultMaxSizeMB = 10
+	// DefaultMaxAge is the age a log file is rotated at by default
+	DefaultMaxAge = 24 * time.Hour
+	// DefaultMaxFiles is the number of rotated files kept by default
+	DefaultMaxFiles = 5
+)
+
+// storeTimeLayout is the fixed-width timestamp each stored line starts with
+const storeTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// unsafeFileChars matches characters not used in log file names
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// Store writes log entries to a file per service, rotating files by size
+// and age. Rotated files are gzipped and only the most recent are kept.
+type Store struct {
+	mu       sync.Mutex
+	dir      string
+	maxSize  int64
+	maxAge   time.Duration
+	maxFiles int
+	loc      *time.Location // zone timestamps are written in
+	files    map[string]*storeFile // key: ServiceID.String()
+	rotated  sync.WaitGroup
+}
+
+// storeFile is the current log file of a service
+type storeFile struct {
+	f       *os.File
+	size    int64
+	created time.Time
+}
+
+// DefaultStoreDir returns the directory log files of a config are written to
+func DefaultStoreDir(configPath string) string {
+	return filepath.Join(config.StateDir(), "logs-"+config.StateKey(configPath))
+}
+
+// NewStore creates a store writing to dir, rotating files as configured
+func NewStore(dir string, cfg config.Logs) *Store {
+	s := &Store{
+		dir:      dir,
+		maxSize:  DefaultMaxSizeMB << 20,
+		maxAge:   DefaultMaxAge,
+		maxFiles: DefaultMaxFiles,
+		loc:      cfg.Location(),
+		files:    make(map[string]*storeFile),
+	}
+	if cfg.MaxSizeMB > 0 {
+		s.maxSize = int64(cfg.MaxSizeMB) << 20
+	}
+	if cfg.MaxAge > 0 {
+		s.maxAge = cfg.MaxAge
+	}
+	if cfg.MaxFiles > 0 {
+		s.maxFiles = cfg.MaxFiles
+	}
+	return s
+}
+
+// Dir returns the directory log files are written to
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Path returns the current log file of a service
+func (s *Store) Path(id config.ServiceID) string {
+	return servicePath(s.dir, id)
+}
+
+// servicePath returns the log file of a service in dir
+func servicePath(dir string, id config.ServiceID) string {
+	name := unsafeFileChars.ReplaceAllString(id.Service, "_")
+	if id.Instance > 0 {
+		name = fmt.Sprintf("%s-%d", name, id.Instance)
+	}
+	return filepath.Join(dir, unsafeFileChars.ReplaceAllString(id.Project, "_"), name+".log")
+}
+
+// Write appends an entry to the log file of its service
+func (s *Store) Write(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry.Timestamp = entry.Timestamp.In(s.loc)
+	line := formatStoredLine(entry)
+
+	key := entry.ServiceID.String()
+	sf, err := s.file(entry.ServiceID)
+	if err != nil {
+		return err
+	}
+
+	// Rotate before writing, so a file never grows past the limit
+	if sf.size > 0 && (sf.size+int64(len(line)) > s.maxSize || time.Since(sf.created) > s.maxAge) {
+		s.rotate(entry.ServiceID, sf)
+		delete(s.files, key)
+		if sf, err = s.file(entry.ServiceID); err != nil {
+			return err
+		}
+	}
+
+	n, err := sf.f.WriteString(line)
+	sf.size += int64(n)
+	return err
+}
+
+// file returns the open log file of a service, opening it if needed
+func (s *Store) file(id config.ServiceID) (*storeFile, error) {
+	key := id.String()
+	if sf, ok := s.files[key]; ok {
+		return sf, nil
+	}
+
+	path := s.Path(id)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	sf := &storeFile{f: f, created: time.Now()}
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		// A file left by an earlier session; its age is approximated by
+		// when it was last written
+		sf.size = info.Size()
+		sf.created = info.ModTime()
+	}
+	s.files[key] = sf
+	return sf, nil
+}
+
+// rotate moves the current log file of a service aside, then compresses it
+// and prunes old rotated files in the background
+func (s *Store) rotate(id config.ServiceID, sf *storeFile) {
+	sf.f.Close()
+
+	path := s.Path(id)
+	base := strings.TrimSuffix(path, ".log")
+	rotated := base + "-" + time.Now().Format("20060102-150405.000000") + ".log"
+	if err := os.Rename(path, rotated); err != nil {
+		return
+	}
+
+	s.rotated.Add(1)
+	go func() {
+		defer s.rotated.Done()
+		if err := compressFile(rotated); err == nil {
+			os.Remove(rotated)
+		}
+		s.prune(base)
+	}()
+}
+
+// prune removes the oldest rotated files of a service beyond the limit
+func (s *Store) prune(base string) {
+	// Glob also matches files of services named e.g. "api-v2" for "api",
+	// so only keep names with a rotation timestamp right after the base
+	matches, _ := filepath.Glob(base + "-*.log*")
+	var rotated []string
+	for _, m := range matches {
+		suffix := strings.TrimPrefix(m, base+"-")
+		if len(suffix) > 15 && suffix[8] == '-' && strings.Trim(suffix[:8], "0123456789") == "" {
+			rotated = append(rotated, m)
+		}
+	}
+
+	// Timestamps sort chronologically; an uncompressed file sorts before
+	// its compressed copy, so removing it is harmless
+	sort.Strings(rotated)
+	for len(rotated) > s.maxFiles {
+		os.Remove(rotated[0])
+		rotated = rotated[1:]
+	}
+}
+
+// compressFile writes a gzipped copy of a file next to it
+func compressFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(path + ".gz")
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(out)
+	_, err = io.Copy(zw, in)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(out.Name())
+	}
+	return err
+}
+
+// Tail returns up to n of the most recent entries of a service from its
+// current log file
+func (s *Store) Tail(id config.ServiceID, n int) ([]Entry, error) {
+	f, err := os.Open(s.Path(id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		entry, ok := parseStoredLine(id, scanner.Text())
+		if !ok {
+			continue
+		}
+		entries = append(entries, entry)
+		if len(entries) > 2*n {
+			entries = append(entries[:0], entries[len(entries)-n:]...)
+		}
+	}
+	if len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return entries, scanner.Err()
+}
+
+// formatStoredLine formats an entry as a line of a log file
+func formatStoredLine(entry Entry) string {
+	stream := "out"
+	if entry.IsStderr {
+		stream = "err"
+	}
+	return entry.Timestamp.Format(storeTimeLayout) + " " + stream + " " + entry.Line + "\n"
+}
+
+// parseStoredLine parses a line written by Write
+func parseStoredLine(id config.ServiceID, line string) (Entry, bool) {
+	parts := strings.SplitN(line, " ", 3)
+	if len(parts) < 2 {
+		return Entry{}, false
+	}
+	ts, err := time.Parse(storeTimeLayout, parts[0])
+	if err != nil {
+		return Entry{}, false
+	}
+
+	entry := Entry{
+		ServiceID: id,
+		IsStderr:  parts[1] == "err",
+		Timestamp: ts,
+	}
+	if len(parts) == 3 {
+		entry.Line = parts[2]
+	}
+	return entry, true
+}
+
+// Close closes all log files and waits for rotated files to be compressed
+func (s *Store) Close() {
+	s.mu.Lock()
+	for key, sf := range s.files {
+		sf.f.Close()
+		delete(s.files, key)
+	}
+	s.mu.Unlock()
+
+	s.rotated.Wait()
+}
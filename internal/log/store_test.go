@@ -0,0 +1,98 @@
+package log
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+func TestStore_WriteTail(t *testing.T) {
+	store := NewStore(t.TempDir(), config.Logs{})
+	id := config.ServiceID{Project: "test", Service: "backend"}
+
+	for _, line := range []string{"one", "two", "three"} {
+		if err := store.Write(NewEntry(id, line, line == "two")); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	store.Close()
+
+	// A new store picks up what an earlier session wrote
+	store = NewStore(store.Dir(), config.Logs{})
+	defer store.Close()
+
+	entries, err := store.Tail(id, 2)
+	if err != nil {
+		t.Fatalf("Tail() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Line != "two" || !entries[0].IsStderr {
+		t.Errorf("expected stderr entry 'two', got %+v", entries[0])
+	}
+	if entries[1].Line != "three" || entries[1].IsStderr {
+		t.Errorf("expected stdout entry 'three', got %+v", entries[1])
+	}
+
+	missing, err := store.Tail(config.ServiceID{Project: "test", Service: "other"}, 10)
+	if err != nil || len(missing) != 0 {
+		t.Errorf("expected no entries for a service without a log file, got %v, %v", missing, err)
+	}
+}
+
+func TestStore_Rotate(t *testing.T) {
+	store := NewStore(t.TempDir(), config.Logs{MaxSizeMB: 1, MaxFiles: 2})
+	id := config.ServiceID{Project: "test", Service: "backend"}
+
+	// Each rotation happens after roughly 1MB of lines
+	line := strings.Repeat("x", 64*1024)
+	for i := 0; i < 16*4; i++ {
+		if err := store.Write(NewEntry(id, line, false)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	store.Close()
+
+	info, err := os.Stat(store.Path(id))
+	if err != nil {
+		t.Fatalf("expected current log file: %v", err)
+	}
+	if info.Size() > 1<<20 {
+		t.Errorf("expected current log file under 1MB, got %d bytes", info.Size())
+	}
+
+	rotated, _ := filepath.Glob(filepath.Join(store.Dir(), "test", "backend-*.log*"))
+	if len(rotated) != 2 {
+		t.Fatalf("expected 2 rotated files to be kept, got %v", rotated)
+	}
+	for _, path := range rotated {
+		if !strings.HasSuffix(path, ".log.gz") {
+			t.Errorf("expected rotated file to be gzipped, got %s", path)
+			continue
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("invalid gzip file %s: %v", path, err)
+		}
+		data, err := io.ReadAll(zr)
+		f.Close()
+		if err != nil {
+			t.Fatalf("invalid gzip file %s: %v", path, err)
+		}
+		if !strings.Contains(string(data), " out "+line) {
+			t.Errorf("expected rotated file %s to hold log lines", path)
+		}
+	}
+}
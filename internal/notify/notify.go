@@ -0,0 +1,8 @@
+// Package notify sends desktop notifications
+package notify
+
+// Send shows a desktop notification. It returns an error if the platform
+// has no supported notification mechanism.
+func Send(title, body string) error {
+	return send(title, body)
+}
@@ -0,0 +1,16 @@
+//go:build darwin
+
+package notify
+
+import "os/exec"
+
+// send uses osascript; title and body are passed as arguments so they
+// don't need AppleScript escaping
+func send(title, body string) error {
+	return exec.Command("osascript",
+		"-e", "on run argv",
+		"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+		"-e", "end run",
+		title, body,
+	).Run()
+}
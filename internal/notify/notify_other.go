@@ -0,0 +1,17 @@
+//go:build !darwin && !windows
+
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// send uses notify-send (libnotify)
+func send(title, body string) error {
+	path, err := exec.LookPath("notify-send")
+	if err != nil {
+		return fmt.Errorf("notify-send not found: %w", err)
+	}
+	return exec.Command(path, "--app-name=paraler", title, body).Run()
+}
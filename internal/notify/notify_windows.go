@@ -0,0 +1,28 @@
+//go:build windows
+
+package notify
+
+import (
+	"os"
+	"os/exec"
+)
+
+// balloonScript shows a tray balloon; text is read from the environment to
+// avoid PowerShell quoting issues
+const balloonScript = `Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Warning
+$n.Visible = $true
+$n.ShowBalloonTip(10000, $env:PARALER_NOTIFY_TITLE, $env:PARALER_NOTIFY_BODY, 'Warning')
+Start-Sleep -Seconds 10
+$n.Dispose()`
+
+// send shows a notification through PowerShell
+func send(title, body string) error {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", balloonScript)
+	cmd.Env = append(os.Environ(),
+		"PARALER_NOTIFY_TITLE="+title,
+		"PARALER_NOTIFY_BODY="+body,
+	)
+	return cmd.Run()
+}
@@ -0,0 +1,263 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+// containerPrefix marks a docker service cmd that names an existing
+// container (e.g. one created by docker compose) instead of an image
+const containerPrefix = "container:"
+
+// containerNameChars matches characters not allowed in container names
+var containerNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// containerName returns the name of the container paraler creates for a service
+func containerName(id config.ServiceID) string {
+	name := "paraler-" + containerNameChars.ReplaceAllString(id.Project, "-") +
+		"-" + containerNameChars.ReplaceAllString(id.Service, "-")
+	if id.Instance > 0 {
+		name += "-" + strconv.Itoa(id.Instance)
+	}
+	return name
+}
+
+// startContainer runs a docker service: it creates the container (or uses
+// an existing one), starts it and follows its logs. The status must
+// already be StatusStarting.
+func (p *Process) startContainer() error {
+	p.mu.RLock()
+	cfg := p.resolved
+	p.mu.RUnlock()
+
+	client, err := newDockerClient()
+	if err != nil {
+		return p.failContainer(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.mu.Lock()
+	p.cancel = cancel
+	p.docker = client
+	p.mu.Unlock()
+
+	// Removing, pulling and creating can take a while; Stop cancels them
+	createCtx, cancelCreate := context.WithCancel(ctx)
+	p.mu.Lock()
+	p.startCancel = cancelCreate
+	if p.startStopped {
+		cancelCreate()
+	}
+	p.mu.Unlock()
+	id, managed, err := p.createContainer(createCtx, client, cfg)
+	p.mu.Lock()
+	p.startCancel = nil
+	p.mu.Unlock()
+	cancelCreate()
+	if err != nil {
+		return p.failContainer(err)
+	}
+
+	startedAt := time.Now()
+	if err := client.startContainer(ctx, id); err != nil {
+		return p.failContainer(err)
+	}
+
+	info, err := client.inspectContainer(ctx, id)
+	if err != nil {
+		return p.failContainer(err)
+	}
+
+	// Output of an existing container from before this start is skipped
+	var since time.Time
+	if !managed {
+		since = startedAt
+	}
+	logs, err := client.containerLogs(ctx, id, since)
+	if err != nil {
+		return p.failContainer(err)
+	}
+
+	p.attachContainer(client, id, managed, info, logs, startedAt)
+	p.emitSystemMessage(fmt.Sprintf("▶ Container started (%s)", shortID(id)))
+	return nil
+}
+
+// attachContainer marks the process running in a started container and
+// streams its logs until it stops
+func (p *Process) attachContainer(client *dockerClient, id string, managed bool, info containerInfo, logs io.ReadCloser, startedAt time.Time) {
+	p.mu.Lock()
+	p.docker = client
+	p.container = id
+	p.containerManaged = managed
+	p.containerPID = info.State.Pid
+	p.exited = make(chan struct{})
+	p.startedAt = startedAt
+	p.setStatusLocked(StatusRunning)
+	p.mu.Unlock()
+
+	// Containers without a TTY multiplex stdout and stderr into one stream
+	stdout, stdoutW := io.Pipe()
+	stderr, stderrW := io.Pipe()
+	go func() {
+		if info.Config.Tty {
+			io.Copy(stdoutW, logs)
+		} else {
+			demuxLogs(logs, stdoutW, stderrW)
+		}
+		logs.Close()
+		stdoutW.Close()
+		stderrW.Close()
+	}()
+
+	streamed := make(chan struct{}, 2)
+	go p.streamOutput(stdout, false, streamed)
+	go p.streamOutput(stderr, true, streamed)
+
+	go p.waitContainer(client, id, managed, streamed)
+}
+
+// createContainer creates the service's container from the image in cmd,
+// with the rest of cmd as its arguments. For container: references it
+// returns the named container instead. managed reports whether paraler
+// created the container and removes it once it exits.
+func (p *Process) createContainer(ctx context.Context, client *dockerClient, cfg config.Service) (id string, managed bool, err error) {
+	args := cfg.Argv
+	if len(args) == 0 {
+		args = strings.Fields(cfg.Cmd)
+	}
+	if len(args) == 0 {
+		return "", false, fmt.Errorf("no image in cmd")
+	}
+	image := args[0]
+	if name, ok := strings.CutPrefix(image, containerPrefix); ok {
+		return name, false, nil
+	}
+
+	// Remove a container left behind by a previous session
+	name := containerName(p.ID)
+	if err := client.removeContainer(ctx, name); err != nil && !isNotFound(err) {
+		return "", false, err
+	}
+
+	spec := containerConfig{
+		Image:  image,
+		Cmd:    args[1:],
+		Env:    cfg.Env,
+		Labels: map[string]string{"paraler.service": p.ID.String()},
+	}
+	if cfg.Port > 0 {
+		// Publish the port on the same host port
+		port := fmt.Sprintf("%d/tcp", cfg.Port)
+		spec.ExposedPorts = map[string]struct{}{port: {}}
+		spec.HostConfig.PortBindings = map[string][]portBinding{
+			port: {{HostPort: fmt.Sprint(cfg.Port)}},
+		}
+	}
+
+	id, err = client.createContainer(ctx, name, spec)
+	if isNotFound(err) {
+		p.emitSystemMessage(fmt.Sprintf("⇣ Pulling %s", image))
+		if err := client.pullImage(ctx, image); err != nil {
+			return "", false, err
+		}
+		id, err = client.createContainer(ctx, name, spec)
+	}
+	return id, true, err
+}
+
+// failContainer marks a docker service that couldn't be started as failed,
+// or as stopped if Stop cancelled the start
+func (p *Process) failContainer(err error) error {
+	p.mu.Lock()
+	cancel := p.cancel
+	p.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+
+	if p.stoppedWhileStarting() {
+		return fmt.Errorf("start cancelled")
+	}
+	p.setStatus(StatusFailed)
+	p.emitSystemMessage(fmt.Sprintf("✖ Failed to start: %v", err))
+	p.emitSystemMessage(fmt.Sprintf("  Image: %s", p.Resolved().Cmd))
+	return fmt.Errorf("failed to start container: %w", err)
+}
+
+// waitContainer waits for a container to stop and updates status. Managed
+// containers are removed afterwards.
+func (p *Process) waitContainer(client *dockerClient, id string, managed bool, streamed <-chan struct{}) {
+	p.mu.RLock()
+	exited := p.exited
+	p.mu.RUnlock()
+
+	exitCode, err := client.waitContainer(context.Background(), id)
+	defer close(exited)
+	if err == nil && exitCode != 0 {
+		err = fmt.Errorf("exit status %d", exitCode)
+	}
+
+	drainOutput(streamed)
+
+	if managed {
+		if err := client.removeContainer(context.Background(), id); err != nil && !isNotFound(err) {
+			p.emitSystemMessage(fmt.Sprintf("⚠ Failed to remove container: %v", err))
+		}
+	}
+
+	p.exit(exitCode, err)
+}
+
+// stopContainer stops a running container and waits for waitContainer to
+// record the exit
+func (p *Process) stopContainer(id string, done <-chan struct{}) {
+	if err := p.docker.stopContainer(context.Background(), id, StopGracePeriod); err != nil && !isNotFound(err) {
+		p.emitSystemMessage(fmt.Sprintf("⚠ Failed to stop container: %v", err))
+	}
+	<-done
+}
+
+// containerHealth returns the health reported by a running container's
+// HEALTHCHECK, or HealthUnknown if it has none
+func (p *Process) containerHealth() HealthStatus {
+	p.mu.RLock()
+	client := p.docker
+	id := p.container
+	p.mu.RUnlock()
+
+	if id == "" {
+		return HealthUnknown
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	info, err := client.inspectContainer(ctx, id)
+	if err != nil || info.State.Health == nil {
+		return HealthUnknown
+	}
+	switch info.State.Health.Status {
+	case "healthy":
+		return HealthHealthy
+	case "unhealthy":
+		return HealthUnhealthy
+	default:
+		return HealthUnknown
+	}
+}
+
+// shortID abbreviates a container ID like the docker CLI does
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,278 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// defaultDockerHost is used when DOCKER_HOST is not set
+const defaultDockerHost = "unix:///var/run/docker.sock"
+
+// dockerClient is a minimal client for the Docker Engine API
+type dockerClient struct {
+	http *http.Client
+	base string
+}
+
+// dockerError is an error response from the Docker API
+type dockerError struct {
+	Status  int
+	Message string
+}
+
+func (e *dockerError) Error() string {
+	return "docker: " + e.Message
+}
+
+// isNotFound returns true if the Docker API reported a missing image or container
+func isNotFound(err error) bool {
+	var derr *dockerError
+	return errors.As(err, &derr) && derr.Status == http.StatusNotFound
+}
+
+// newDockerClient creates a client for the daemon at DOCKER_HOST, which
+// may be a unix socket or a tcp address
+func newDockerClient() (*dockerClient, error) {
+	host := os.Getenv("DOCKER_HOST")
+	if host == "" {
+		host = defaultDockerHost
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DOCKER_HOST %q: %w", host, err)
+	}
+
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}
+		return &dockerClient{http: &http.Client{Transport: transport}, base: "http://docker"}, nil
+	case "tcp", "http":
+		return &dockerClient{http: &http.Client{}, base: "http://" + u.Host}, nil
+	default:
+		return nil, fmt.Errorf("unsupported DOCKER_HOST %q", host)
+	}
+}
+
+// do sends a request to the Docker API, turning error statuses into a
+// *dockerError. The caller closes the response body.
+func (c *dockerClient) do(ctx context.Context, method, path string, query url.Values, body any) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(data)
+	}
+
+	u := c.base + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("docker: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		var e struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&e)
+		if e.Message == "" {
+			e.Message = resp.Status
+		}
+		return nil, &dockerError{Status: resp.StatusCode, Message: e.Message}
+	}
+	return resp, nil
+}
+
+// call sends a request and decodes the JSON response into out, if non-nil
+func (c *dockerClient) call(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	resp, err := c.do(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// containerConfig is the body of a container create request
+type containerConfig struct {
+	Image        string              `json:"Image"`
+	Cmd          []string            `json:"Cmd,omitempty"`
+	Env          []string            `json:"Env,omitempty"`
+	Labels       map[string]string   `json:"Labels,omitempty"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts,omitempty"`
+	HostConfig   hostConfig          `json:"HostConfig"`
+}
+
+type hostConfig struct {
+	PortBindings map[string][]portBinding `json:"PortBindings,omitempty"`
+}
+
+type portBinding struct {
+	HostPort string `json:"HostPort"`
+}
+
+// containerInfo is the subset of a container inspect response paraler uses
+type containerInfo struct {
+	State struct {
+		Running bool
+		Pid     int
+		Health  *struct {
+			Status string
+		}
+	}
+	Config struct {
+		Tty bool
+	}
+}
+
+// createContainer creates a container and returns its ID
+func (c *dockerClient) createContainer(ctx context.Context, name string, cfg containerConfig) (string, error) {
+	var resp struct {
+		ID string `json:"Id"`
+	}
+	err := c.call(ctx, http.MethodPost, "/containers/create", url.Values{"name": {name}}, cfg, &resp)
+	return resp.ID, err
+}
+
+// pullImage pulls an image, waiting for the pull to complete
+func (c *dockerClient) pullImage(ctx context.Context, image string) error {
+	resp, err := c.do(ctx, http.MethodPost, "/images/create", url.Values{"fromImage": {image}}, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Progress is streamed as JSON messages; failures arrive as an error message
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var msg struct {
+			Error string `json:"error"`
+		}
+		if err := dec.Decode(&msg); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if msg.Error != "" {
+			return fmt.Errorf("docker: %s", msg.Error)
+		}
+	}
+}
+
+// startContainer starts a container; starting a running container is a no-op
+func (c *dockerClient) startContainer(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodPost, "/containers/"+id+"/start", nil, nil, nil)
+}
+
+// stopContainer stops a container, killing it after the timeout
+func (c *dockerClient) stopContainer(ctx context.Context, id string, timeout time.Duration) error {
+	query := url.Values{"t": {strconv.Itoa(int(timeout.Seconds()))}}
+	return c.call(ctx, http.MethodPost, "/containers/"+id+"/stop", query, nil, nil)
+}
+
+// killContainer sends a signal to a container's main process
+func (c *dockerClient) killContainer(ctx context.Context, id, signal string) error {
+	return c.call(ctx, http.MethodPost, "/containers/"+id+"/kill", url.Values{"signal": {signal}}, nil, nil)
+}
+
+// pauseContainer freezes a container's processes
+func (c *dockerClient) pauseContainer(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodPost, "/containers/"+id+"/pause", nil, nil, nil)
+}
+
+// unpauseContainer continues a paused container
+func (c *dockerClient) unpauseContainer(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodPost, "/containers/"+id+"/unpause", nil, nil, nil)
+}
+
+// removeContainer force-removes a container
+func (c *dockerClient) removeContainer(ctx context.Context, id string) error {
+	return c.call(ctx, http.MethodDelete, "/containers/"+id, url.Values{"force": {"1"}}, nil, nil)
+}
+
+// inspectContainer returns a container's state and config
+func (c *dockerClient) inspectContainer(ctx context.Context, id string) (containerInfo, error) {
+	var info containerInfo
+	err := c.call(ctx, http.MethodGet, "/containers/"+id+"/json", nil, nil, &info)
+	return info, err
+}
+
+// waitContainer blocks until a container stops and returns its exit code
+func (c *dockerClient) waitContainer(ctx context.Context, id string) (int, error) {
+	var resp struct {
+		StatusCode int
+	}
+	err := c.call(ctx, http.MethodPost, "/containers/"+id+"/wait", nil, nil, &resp)
+	return resp.StatusCode, err
+}
+
+// containerLogs follows a container's output from since (all output if zero)
+func (c *dockerClient) containerLogs(ctx context.Context, id string, since time.Time) (io.ReadCloser, error) {
+	query := url.Values{"follow": {"1"}, "stdout": {"1"}, "stderr": {"1"}}
+	if !since.IsZero() {
+		query.Set("since", strconv.FormatInt(since.Unix(), 10))
+	}
+	resp, err := c.do(ctx, http.MethodGet, "/containers/"+id+"/logs", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// demuxLogs splits the multiplexed log stream of a container without a TTY
+// into stdout and stderr. Each frame has an 8-byte header holding the
+// stream type and the payload size.
+func demuxLogs(r io.Reader, stdout, stderr io.Writer) error {
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		w := stdout
+		if header[0] == 2 {
+			w = stderr
+		}
+		size := int64(binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.CopyN(w, r, size); err != nil {
+			return err
+		}
+	}
+}
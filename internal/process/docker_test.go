@@ -0,0 +1,49 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+func TestDemuxLogs(t *testing.T) {
+	frame := func(stream byte, payload string) []byte {
+		header := make([]byte, 8)
+		header[0] = stream
+		binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+		return append(header, payload...)
+	}
+
+	var stream bytes.Buffer
+	stream.Write(frame(1, "listening on :5432\n"))
+	stream.Write(frame(2, "warning: no password\n"))
+	stream.Write(frame(1, "ready\n"))
+
+	var stdout, stderr bytes.Buffer
+	if err := demuxLogs(&stream, &stdout, &stderr); err != nil {
+		t.Fatalf("demuxLogs: %v", err)
+	}
+	if got, want := stdout.String(), "listening on :5432\nready\n"; got != want {
+		t.Errorf("expected stdout %q, got %q", want, got)
+	}
+	if got, want := stderr.String(), "warning: no password\n"; got != want {
+		t.Errorf("expected stderr %q, got %q", want, got)
+	}
+}
+
+func TestContainerName(t *testing.T) {
+	id := config.ServiceID{Project: "my app", Service: "db/primary"}
+	if got, want := containerName(id), "paraler-my-app-db-primary"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestCreateContainerWithoutImage(t *testing.T) {
+	p := NewProcess(config.ServiceID{Project: "app", Service: "db"}, config.Service{Cmd: "   ", Type: config.ServiceTypeDocker}, "/tmp", make(chan OutputLine, 10))
+	if _, _, err := p.createContainer(context.Background(), nil, p.Resolved()); err == nil {
+		t.Error("expected an error for a cmd without an image")
+	}
+}
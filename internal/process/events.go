@@ -0,0 +1,69 @@
+package process
+
+import (
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+// Event is published on the Manager's event channel when something about a
+// service changes
+type Event interface {
+	event()
+}
+
+// StatusChanged is published when a service's status changes
+type StatusChanged struct {
+	ID  config.ServiceID
+	Old Status
+	New Status
+}
+
+// HealthChanged is published when a health check result differs from the
+// previous one
+type HealthChanged struct {
+	ID  config.ServiceID
+	Old HealthStatus
+	New HealthStatus
+}
+
+// RestartScheduled is published when auto-restart is about to restart a
+// failed service
+type RestartScheduled struct {
+	ID      config.ServiceID
+	Attempt int
+	Delay   time.Duration
+}
+
+// CrashLoopDetected is published when auto-restart is disabled for a
+// service that failed too often
+type CrashLoopDetected struct {
+	ID config.ServiceID
+}
+
+// OutputDropped is published when output lines of a service were dropped
+// because the output channel was full. Use Process.TakeDroppedLines for the
+// count; it is published again once more lines are dropped after that.
+type OutputDropped struct {
+	ID config.ServiceID
+}
+
+// StatsSampled is published after CPU and memory usage were sampled
+type StatsSampled struct{}
+
+// PortReassigned is published when a service with auto_port, or started by
+// StartOnFreePort, starts on another port because its configured one was
+// taken
+type PortReassigned struct {
+	ID   config.ServiceID
+	From int
+	To   int
+}
+
+func (StatusChanged) event()     {}
+func (HealthChanged) event()     {}
+func (RestartScheduled) event()  {}
+func (CrashLoopDetected) event() {}
+func (OutputDropped) event()     {}
+func (StatsSampled) event()      {}
+func (PortReassigned) event()    {}
@@ -0,0 +1,127 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// gRPC health protocol (grpc.health.v1) serving statuses
+const (
+	grpcHealthUnknown = 0
+	grpcHealthServing = 1
+)
+
+// newGRPCClient creates a client that speaks HTTP/2 without TLS, as gRPC
+// servers in development usually do
+func newGRPCClient() *http.Client {
+	var protocols http.Protocols
+	protocols.SetUnencryptedHTTP2(true)
+	return &http.Client{
+		Transport: &http.Transport{
+			Protocols:         &protocols,
+			DisableKeepAlives: true,
+		},
+	}
+}
+
+// grpcHealthCheck calls grpc.health.v1.Health/Check on addr for a service
+// ("" for the server as a whole) and returns the serving status
+func grpcHealthCheck(ctx context.Context, client *http.Client, addr, service string) (int, error) {
+	// HealthCheckRequest has the service name as field 1
+	var msg []byte
+	if service != "" {
+		msg = append([]byte{0x0a}, binary.AppendUvarint(nil, uint64(len(service)))...)
+		msg = append(msg, service...)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		"http://"+addr+"/grpc.health.v1.Health/Check", bytes.NewReader(grpcFrame(msg)))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("grpc: HTTP status %d", resp.StatusCode)
+	}
+
+	// Errors without a message come as a trailers-only response
+	code := resp.Trailer.Get("Grpc-Status")
+	if code == "" {
+		code = resp.Header.Get("Grpc-Status")
+	}
+	if code != "0" {
+		msg := resp.Trailer.Get("Grpc-Message")
+		if msg == "" {
+			msg = resp.Header.Get("Grpc-Message")
+		}
+		return 0, fmt.Errorf("grpc: status %s %s", code, msg)
+	}
+
+	if len(body) < 5 {
+		return 0, errors.New("grpc: empty response")
+	}
+	size := binary.BigEndian.Uint32(body[1:5])
+	if uint32(len(body)-5) < size {
+		return 0, errors.New("grpc: truncated response")
+	}
+	return parseHealthCheckResponse(body[5 : 5+size])
+}
+
+// grpcFrame prefixes a message with the gRPC length-prefixed framing:
+// an uncompressed flag and the message size
+func grpcFrame(msg []byte) []byte {
+	frame := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+	return append(frame, msg...)
+}
+
+// parseHealthCheckResponse returns the status (field 1) of a
+// HealthCheckResponse, skipping unknown fields
+func parseHealthCheckResponse(msg []byte) (int, error) {
+	status := grpcHealthUnknown
+	for len(msg) > 0 {
+		key, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return 0, errors.New("grpc: malformed response")
+		}
+		msg = msg[n:]
+
+		switch key & 7 {
+		case 0: // varint
+			v, n := binary.Uvarint(msg)
+			if n <= 0 {
+				return 0, errors.New("grpc: malformed response")
+			}
+			if key>>3 == 1 {
+				status = int(v)
+			}
+			msg = msg[n:]
+		case 2: // length-delimited
+			l, n := binary.Uvarint(msg)
+			if n <= 0 || uint64(len(msg)-n) < l {
+				return 0, errors.New("grpc: malformed response")
+			}
+			msg = msg[n+int(l):]
+		default:
+			return 0, errors.New("grpc: malformed response")
+		}
+	}
+	return status, nil
+}
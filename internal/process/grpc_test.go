@@ -0,0 +1,82 @@
+package process
+
+import (
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+func TestHealthChecker_GRPC(t *testing.T) {
+	// Serves grpc.health.v1: "api" is SERVING, "worker" is NOT_SERVING and
+	// anything else is unknown
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/grpc.health.v1.Health/Check" || r.ProtoMajor != 2 {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var service string
+		if len(body) > 7 {
+			service = string(body[7:])
+		}
+
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Trailer", "Grpc-Status")
+		switch service {
+		case "api":
+			w.Write(grpcFrame([]byte{0x08, 0x01}))
+		case "worker":
+			w.Write(grpcFrame([]byte{0x08, 0x02}))
+		default:
+			w.Header().Set("Grpc-Status", "5") // NOT_FOUND
+			return
+		}
+		w.Header().Set("Grpc-Status", "0")
+	}))
+	srv.Config.Protocols = new(http.Protocols)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+	defer srv.Close()
+
+	addr := strings.TrimPrefix(srv.URL, "http://")
+	h := NewHealthChecker()
+
+	tests := []struct {
+		probe string
+		want  HealthStatus
+	}{
+		{"grpc://" + addr + "/api", HealthHealthy},
+		{"grpc://" + addr + "/worker", HealthUnhealthy},
+		{"grpc://" + addr + "/missing", HealthUnhealthy},
+		{"tcp://" + addr, HealthHealthy},
+		{"tcp://localhost:1", HealthUnhealthy},
+	}
+	for _, tt := range tests {
+		if got, _ := h.CheckHealth(config.Service{Health: tt.probe}, ""); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.probe, tt.want, got)
+		}
+	}
+}
+
+func TestParseHealthCheckResponse(t *testing.T) {
+	// An unknown length-delimited field before the status is skipped
+	msg := append([]byte{0x12, 0x02, 'h', 'i'}, 0x08)
+	msg = binary.AppendUvarint(msg, grpcHealthServing)
+
+	status, err := parseHealthCheckResponse(msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status != grpcHealthServing {
+		t.Errorf("expected SERVING, got %d", status)
+	}
+
+	if _, err := parseHealthCheckResponse([]byte{0x08}); err == nil {
+		t.Error("expected error for truncated message")
+	}
+}
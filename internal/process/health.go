@@ -2,9 +2,13 @@ package process
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/paralerdev/paraler/internal/config"
@@ -33,6 +37,7 @@ func (h HealthStatus) String() string {
 // HealthChecker performs health checks on services
 type HealthChecker struct {
 	client *http.Client
+	grpc   *http.Client
 }
 
 // NewHealthChecker creates a new health checker
@@ -44,51 +49,132 @@ func NewHealthChecker() *HealthChecker {
 				DisableKeepAlives: true,
 			},
 		},
+		grpc: newGRPCClient(),
 	}
 }
 
-// CheckHealth performs a health check on a service
-func (h *HealthChecker) CheckHealth(cfg config.Service) HealthStatus {
-	if cfg.Health != "" {
-		return h.checkHTTP(cfg.Health)
+// CheckHealth performs a health check on a service whose commands run in
+// dir. The error says why an unhealthy service failed it.
+func (h *HealthChecker) CheckHealth(cfg config.Service, dir string) (HealthStatus, error) {
+	var err error
+	switch {
+	case cfg.HealthCmd != "":
+		err = h.checkCommand(cfg, dir)
+	case cfg.Health != "":
+		err = h.checkProbe(cfg.Health)
+	case cfg.Port > 0:
+		err = h.checkPort(cfg.Port)
+	default:
+		return HealthUnknown, nil
 	}
-	if cfg.Port > 0 {
-		return h.checkPort(cfg.Port)
+	if err != nil {
+		return HealthUnhealthy, err
+	}
+	return HealthHealthy, nil
+}
+
+// checkProbe checks a health URL: tcp://host:port must accept connections,
+// grpc://host:port[/service] must report SERVING via the gRPC health
+// protocol, anything else is checked over HTTP
+func (h *HealthChecker) checkProbe(probe string) error {
+	switch {
+	case strings.HasPrefix(probe, "tcp://"):
+		u, err := url.Parse(probe)
+		if err != nil {
+			return err
+		}
+		return h.checkAddr(u.Host)
+	case strings.HasPrefix(probe, "grpc://"):
+		u, err := url.Parse(probe)
+		if err != nil {
+			return err
+		}
+		return h.checkGRPC(u.Host, strings.Trim(u.Path, "/"))
+	default:
+		return h.checkHTTP(probe)
 	}
-	return HealthUnknown
 }
 
 // checkHTTP performs an HTTP health check
-func (h *HealthChecker) checkHTTP(url string) HealthStatus {
+func (h *HealthChecker) checkHTTP(probe string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probe, nil)
 	if err != nil {
-		return HealthUnhealthy
+		return err
 	}
 
 	resp, err := h.client.Do(req)
 	if err != nil {
-		return HealthUnhealthy
+		// Leave out the method and URL, which the service's config shows
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 200 && resp.StatusCode < 400 {
-		return HealthHealthy
+		return nil
 	}
-	return HealthUnhealthy
+	return fmt.Errorf("HTTP %s", resp.Status)
+}
+
+// checkCommand runs a health check command, healthy if it exits 0. A
+// failure is reported with the last line the command printed.
+func (h *HealthChecker) checkCommand(cfg config.Service, dir string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	args := shellArgs(cfg.Shell, cfg.HealthCmd)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	setShellCmdLine(cmd)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Environ(), cfg.Env...)
+	// Don't hang on descendants that keep the command's pipes open
+	cmd.WaitDelay = time.Second
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+		if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+			return fmt.Errorf("health_cmd %w: %s", err, last)
+		}
+		return fmt.Errorf("health_cmd %w", err)
+	}
+	return nil
+}
+
+// checkGRPC calls the gRPC health service of a server
+func (h *HealthChecker) checkGRPC(addr, service string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	status, err := grpcHealthCheck(ctx, h.grpc, addr, service)
+	if err != nil {
+		return err
+	}
+	if status != grpcHealthServing {
+		return fmt.Errorf("grpc: not serving (status %d)", status)
+	}
+	return nil
 }
 
 // checkPort checks if a port is listening
-func (h *HealthChecker) checkPort(port int) HealthStatus {
-	addr := fmt.Sprintf("localhost:%d", port)
+func (h *HealthChecker) checkPort(port int) error {
+	return h.checkAddr(fmt.Sprintf("localhost:%d", port))
+}
+
+// checkAddr checks if a TCP address accepts connections
+func (h *HealthChecker) checkAddr(addr string) error {
 	conn, err := net.DialTimeout("tcp", addr, 2*time.Second)
 	if err != nil {
-		return HealthUnhealthy
+		return err
 	}
 	conn.Close()
-	return HealthHealthy
+	return nil
 }
 
 // CheckPort checks if a specific port is available
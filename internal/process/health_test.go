@@ -0,0 +1,58 @@
+package process
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+func TestHealthChecker_Command(t *testing.T) {
+	h := NewHealthChecker()
+	dir := t.TempDir()
+
+	if got, _ := h.CheckHealth(config.Service{HealthCmd: "exit 0"}, dir); got != HealthHealthy {
+		t.Errorf("expected healthy for exit 0, got %s", got)
+	}
+	if got, _ := h.CheckHealth(config.Service{HealthCmd: "exit 1"}, dir); got != HealthUnhealthy {
+		t.Errorf("expected unhealthy for exit 1, got %s", got)
+	}
+	// The command takes precedence over the port check
+	if got, _ := h.CheckHealth(config.Service{HealthCmd: "exit 1", Port: 1}, dir); got != HealthUnhealthy {
+		t.Errorf("expected unhealthy from command, got %s", got)
+	}
+}
+
+func TestHealthChecker_Reason(t *testing.T) {
+	h := NewHealthChecker()
+	dir := t.TempDir()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		cfg  config.Service
+		want string
+	}{
+		{config.Service{Health: srv.URL}, "HTTP 503 Service Unavailable"},
+		{config.Service{HealthCmd: "echo db down; exit 2"}, "health_cmd exit status 2: db down"},
+		{config.Service{Health: "tcp://localhost:1"}, "connection refused"},
+	}
+	for _, tt := range tests {
+		health, err := h.CheckHealth(tt.cfg, dir)
+		if health != HealthUnhealthy {
+			t.Errorf("%+v: expected unhealthy, got %s", tt.cfg, health)
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%+v: expected reason containing %q, got %v", tt.cfg, tt.want, err)
+		}
+	}
+
+	if _, err := h.CheckHealth(config.Service{HealthCmd: "exit 0"}, dir); err != nil {
+		t.Errorf("expected no reason when healthy, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build linux
+
+package process
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// ioprio_set constants from linux/ioprio.h
+const (
+	ioprioWhoPgrp    = 2
+	ioprioClassBE    = 2
+	ioprioClassShift = 13
+)
+
+// setIOPriority sets the best-effort IO priority of a process group, derived
+// from its niceness the same way the kernel does by default
+func setIOPriority(pgid, nice int) error {
+	level := (nice + 20) / 5
+	prio := ioprioClassBE<<ioprioClassShift | level
+
+	_, _, errno := unix.Syscall(unix.SYS_IOPRIO_SET, ioprioWhoPgrp, uintptr(pgid), uintptr(prio))
+	if errno != 0 {
+		return fmt.Errorf("ioprio_set: %w", errno)
+	}
+	return nil
+}
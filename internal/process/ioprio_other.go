@@ -0,0 +1,8 @@
+//go:build !linux && !windows
+
+package process
+
+// setIOPriority is a no-op: IO priorities are only supported on Linux
+func setIOPriority(pgid, nice int) error {
+	return nil
+}
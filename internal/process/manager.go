@@ -1,22 +1,71 @@
 package process
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/paralerdev/paraler/internal/config"
 )
 
-const maxAutoRestarts = 5 // Maximum auto-restarts before giving up
+// Crash-loop defaults: auto-restart stops after this many failures within
+// the window
+const (
+	DefaultCrashLoopFailures = 5
+	DefaultCrashLoopWindow   = 2 * time.Minute
+)
+
+// DefaultStableAfter is how long a service must run before its auto-restart
+// counter is reset
+const DefaultStableAfter = 5 * time.Minute
+
+// DefaultReadyTimeout is how long dependents wait for a dependency without a
+// start_timeout to become ready before starting anyway
+const DefaultReadyTimeout = 30 * time.Second
+
+// MonitorInterval is how often the monitor samples resource usage and
+// auto-restarts failed services
+const MonitorInterval = 2 * time.Second
+
+// DefaultHealthInterval is how often a service without a health_interval is
+// health checked once it is up
+const DefaultHealthInterval = 10 * time.Second
+
+// A freshly started service is checked every healthStartupInterval until it
+// first reports healthy or healthStartupPeriod has passed
+const (
+	healthStartupInterval = time.Second
+	healthStartupPeriod   = 30 * time.Second
+)
 
 // Manager handles multiple processes
 type Manager struct {
 	mu            sync.RWMutex
 	processes     map[string]*Process // key: ServiceID.String()
+	watchers      map[string]*Watcher // key: ServiceID.String()
 	outputCh      chan OutputLine
+	events        chan Event
+	done          chan struct{} // closed by Close
+	closeOnce     sync.Once
+	monitorStop   chan struct{} // closed by stopMonitor
+	monitorOnce   sync.Once
+	monitor       sync.WaitGroup
 	healthChecker *HealthChecker
+	stats         *StatsCollector
 	config        *config.Config
+
+	// clearLogs clears a service's logs before it restarts, for services
+	// with clear_logs_on_restart
+	clearLogs func(config.ServiceID)
+
+	// State file tracking running PIDs across sessions
+	stateMu   sync.Mutex
+	statePath string
+	orphans   []ProcessRecord // unresolved orphans, kept in the state file
 }
 
 // NewManager creates a new process manager
@@ -24,8 +73,13 @@ func NewManager(cfg *config.Config) *Manager {
 	outputCh := make(chan OutputLine, 1000)
 	m := &Manager{
 		processes:     make(map[string]*Process),
+		watchers:      make(map[string]*Watcher),
 		outputCh:      outputCh,
+		events:        make(chan Event, 1000),
+		done:          make(chan struct{}),
+		monitorStop:   make(chan struct{}),
 		healthChecker: NewHealthChecker(),
+		stats:         NewStatsCollector(),
 		config:        cfg,
 	}
 
@@ -37,19 +91,102 @@ func NewManager(cfg *config.Config) *Manager {
 				Service: serviceName,
 			}
 			cwd := cfg.GetServiceCwd(projectName, serviceName)
-			proc := NewProcess(id, service, cwd, outputCh)
-			m.processes[id.String()] = proc
+			if !service.IsReplicated() {
+				m.addProcess(id, service, cwd)
+				continue
+			}
+			for i := 1; i <= service.Replicas; i++ {
+				id.Instance = i
+				m.addProcess(id, service.Replica(i), cwd)
+			}
 		}
 	}
 
 	return m
 }
 
-// OutputChannel returns the channel for receiving process output
+// addProcess creates the process of a service (or one of its replicas)
+func (m *Manager) addProcess(id config.ServiceID, service config.Service, cwd string) {
+	proc := NewProcess(id, service, cwd, m.outputCh)
+	proc.events = m.events
+	proc.SetTriggerHandler(func(t config.Trigger, line string) {
+		m.handleTrigger(proc, t, line)
+	})
+	m.processes[id.String()] = proc
+}
+
+// OutputChannel returns the channel for receiving process output. Like
+// Events, it is never closed, as services can print until they exit; stop
+// reading once Done is closed.
 func (m *Manager) OutputChannel() <-chan OutputLine {
 	return m.outputCh
 }
 
+// Events returns the channel of service events. It is never closed; stop
+// reading once Done is closed.
+func (m *Manager) Events() <-chan Event {
+	return m.events
+}
+
+// Done returns a channel that is closed once the manager is closed
+func (m *Manager) Done() <-chan struct{} {
+	return m.done
+}
+
+// publish sends an event without blocking
+func (m *Manager) publish(e Event) {
+	select {
+	case m.events <- e:
+	default:
+		// Channel full, drop event
+	}
+}
+
+// StartMonitor schedules health checks for each service, and samples
+// resource usage and auto-restarts failed services every MonitorInterval,
+// until the manager is closed
+func (m *Manager) StartMonitor() {
+	for _, p := range m.All() {
+		go m.monitorHealth(p)
+	}
+
+	m.monitor.Add(1)
+	go func() {
+		defer m.monitor.Done()
+		ticker := time.NewTicker(MonitorInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-m.done:
+				return
+			case <-m.monitorStop:
+				return
+			case <-ticker.C:
+				m.CollectStats()
+				m.CheckAutoRestart()
+			}
+		}
+	}()
+}
+
+// stopMonitor stops the monitor and waits for it to exit, so it no longer
+// auto-restarts services
+func (m *Manager) stopMonitor() {
+	m.monitorOnce.Do(func() {
+		close(m.monitorStop)
+	})
+	m.monitor.Wait()
+}
+
+// Close stops the monitor and marks the manager done, without stopping
+// services
+func (m *Manager) Close() {
+	m.closeOnce.Do(func() {
+		close(m.done)
+	})
+}
+
 // Get returns a process by its ID
 func (m *Manager) Get(id config.ServiceID) *Process {
 	m.mu.RLock()
@@ -57,6 +194,31 @@ func (m *Manager) Get(id config.ServiceID) *Process {
 	return m.processes[id.String()]
 }
 
+// Instances returns the processes of a service: the process with the given
+// ID, or every replica, ordered by instance, if id names a replicated service
+func (m *Manager) Instances(id config.ServiceID) []*Process {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if p, ok := m.processes[id.String()]; ok {
+		return []*Process{p}
+	}
+	if id.Instance != 0 {
+		return nil
+	}
+
+	var procs []*Process
+	for _, p := range m.processes {
+		if p.ID.Base() == id {
+			procs = append(procs, p)
+		}
+	}
+	sort.Slice(procs, func(i, j int) bool {
+		return procs[i].ID.Instance < procs[j].ID.Instance
+	})
+	return procs
+}
+
 // All returns all processes
 func (m *Manager) All() []*Process {
 	m.mu.RLock()
@@ -76,26 +238,209 @@ func (m *Manager) Start(id config.ServiceID) error {
 		return nil
 	}
 
-	// Check for port conflicts with running services
-	if hasConflict, conflictID := m.CheckPortConflict(id); hasConflict {
+	// Check for port conflicts with running services; auto_port services
+	// move to another port instead
+	if hasConflict, conflictID := m.CheckPortConflict(id); hasConflict && !proc.movesPort() {
 		// Send warning to output channel
-		m.sendWarning(id, fmt.Sprintf("Port %d is already in use by %s", proc.Config.Port, conflictID.String()))
+		m.sendWarning(id, fmt.Sprintf("Port %d is already in use by %s", proc.Resolved().Port, conflictID.String()))
 	}
 
-	// Start dependencies first
+	// Start dependencies first, including every replica of a replicated one
 	for _, dep := range proc.Config.DependsOn {
-		depID := config.ServiceID{Project: id.Project, Service: dep}
-		depProc := m.Get(depID)
-		if depProc != nil && depProc.Status() != StatusRunning {
-			if err := depProc.Start(); err != nil {
+		for _, depProc := range m.Instances(config.ServiceID{Project: id.Project, Service: dep}) {
+			if err := m.startDependency(proc, depProc); err != nil {
 				return err
 			}
-			// Wait for dependency to be ready
-			m.waitForReady(depID, 10*time.Second)
 		}
 	}
 
-	return proc.Start()
+	return m.startProcess(proc)
+}
+
+// StartOnFreePort starts a service like Start, moving it to the next free
+// port if its port is taken, as if it had auto_port set
+func (m *Manager) StartOnFreePort(id config.ServiceID) error {
+	proc := m.Get(id)
+	if proc == nil {
+		return nil
+	}
+	proc.setMovePort()
+	return m.Start(id)
+}
+
+// startDependency starts a dependency of proc unless it is already running,
+// and waits until it is ready (or, for tasks, completed)
+func (m *Manager) startDependency(proc, depProc *Process) error {
+	depID := depProc.ID
+
+	// Tasks must run to completion before dependents start
+	if depProc.Config.IsTask() {
+		if depProc.Status() == StatusSucceeded {
+			return nil
+		}
+		if !depProc.IsRunning() {
+			if err := m.startProcess(depProc); err != nil {
+				return err
+			}
+		}
+		if status := m.waitForTask(depID); status != StatusSucceeded {
+			proc.emitSystemMessage("✖ Not started: " + taskFailure(depID, status))
+			return fmt.Errorf("dependency %s did not complete successfully", depID)
+		}
+		return nil
+	}
+
+	if !depProc.Status().alive() {
+		if err := m.startProcess(depProc); err != nil {
+			return err
+		}
+		// Wait for dependency to be ready
+		m.waitForReady(depID)
+	} else if depProc.HasReadyPattern() && !depProc.IsReady() {
+		// Started elsewhere but hasn't printed its ready line yet
+		m.waitForReady(depID)
+	}
+	return nil
+}
+
+// startProcess starts a process and its file watcher (if configured)
+func (m *Manager) startProcess(proc *Process) error {
+	proc.ClearCrashLoop()
+	proc.applyPendingConfig()
+	if err := m.resolveTemplates(proc); err != nil {
+		proc.emitSystemMessage(fmt.Sprintf("✖ Failed to start: %v", err))
+		return err
+	}
+	if err := proc.Start(); err != nil {
+		return err
+	}
+	m.saveState()
+	m.watchStartTimeout(proc)
+	m.startWatcher(proc)
+	return nil
+}
+
+// resolveTemplates fills in the {{...}} placeholders of a service's cmd,
+// health check and url. A service that uses {{port}} without a configured port gets a
+// free port, which it keeps across restarts. With auto_port, a service whose
+// port is taken gets the next free one, also passed in the PORT variable;
+// so does a service started by StartOnFreePort.
+func (m *Manager) resolveTemplates(proc *Process) error {
+	resolved := proc.Config
+
+	port := proc.Config.Port
+	usesPort := config.UsesTemplateVar(proc.Config.Cmd, "port") ||
+		config.UsesTemplateVar(proc.Config.Health, "port") ||
+		config.UsesTemplateVar(proc.Config.HealthCmd, "port")
+	if port == 0 && usesPort {
+		port = proc.Resolved().Port
+		if port == 0 {
+			var err error
+			if port, err = FreePort(); err != nil {
+				return err
+			}
+			proc.emitSystemMessage(fmt.Sprintf("⚙ Assigned port %d", port))
+		}
+	}
+	if proc.takeMovePort() && port > 0 && !proc.IsRunning() && m.portTaken(proc, port) {
+		alt, err := m.alternatePort(proc, port)
+		if err != nil {
+			return err
+		}
+		proc.emitSystemMessage(fmt.Sprintf("⚠ Port %d is in use, using port %d instead", port, alt))
+		m.publish(PortReassigned{ID: proc.ID, From: port, To: alt})
+		resolved.Env = append(append([]string(nil), proc.Config.Env...), "PORT="+strconv.Itoa(alt))
+		port = alt
+	}
+	resolved.Port = port
+
+	vars := map[string]string{
+		"project":      proc.ID.Project,
+		"service":      proc.ID.Service,
+		"project_path": m.config.Projects[proc.ID.Project].Path,
+		"cwd":          proc.Cwd,
+		"instance":     strconv.Itoa(max(proc.ID.Instance, 1)),
+	}
+	if port > 0 {
+		vars["port"] = strconv.Itoa(port)
+	}
+	resolved.Cmd = config.ExpandTemplate(proc.Config.Cmd, vars)
+	if len(proc.Config.Argv) > 0 {
+		resolved.Argv = make([]string, len(proc.Config.Argv))
+		for i, arg := range proc.Config.Argv {
+			resolved.Argv[i] = config.ExpandTemplate(arg, vars)
+		}
+	}
+	resolved.Health = config.ExpandTemplate(proc.Config.Health, vars)
+	resolved.HealthCmd = config.ExpandTemplate(proc.Config.HealthCmd, vars)
+	resolved.URL = config.ExpandTemplate(proc.Config.URL, vars)
+
+	proc.SetResolved(resolved)
+	return nil
+}
+
+// portTaken returns true if another running service or an external
+// process uses the port
+func (m *Manager) portTaken(proc *Process, port int) bool {
+	for _, other := range m.All() {
+		if other != proc && other.IsRunning() && other.Resolved().Port == port {
+			return true
+		}
+	}
+	return portInUse(port)
+}
+
+// alternatePort returns the first free port above a taken one
+func (m *Manager) alternatePort(proc *Process, port int) (int, error) {
+	for p := port + 1; p <= port+maxPortSearch && p <= 65535; p++ {
+		if !m.portTaken(proc, p) {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("no free port found after %d", port)
+}
+
+// startWatcher starts watching a service's files if it has watch patterns.
+// The watcher keeps running while the service is failed so a fix restarts it.
+func (m *Manager) startWatcher(proc *Process) {
+	if len(proc.Config.Watch) == 0 {
+		return
+	}
+
+	key := proc.ID.String()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.watchers[key]; ok {
+		return
+	}
+
+	w, err := NewWatcher(proc.Cwd, proc.Config.Watch, proc.Config.WatchDebounce, func(path string) {
+		proc.emitSystemMessage(fmt.Sprintf("↻ Change detected in %s, restarting", path))
+		// Restart stops this watcher when a pending config is applied, and
+		// starts one for the new config; this one is left alone otherwise
+		m.Restart(proc.ID)
+	})
+	if err != nil {
+		proc.emitSystemMessage(fmt.Sprintf("⚠ Failed to watch files: %v", err))
+		return
+	}
+	m.watchers[key] = w
+}
+
+// stopWatcher stops watching a service's files
+func (m *Manager) stopWatcher(id config.ServiceID) {
+	key := id.String()
+
+	m.mu.Lock()
+	w, ok := m.watchers[key]
+	delete(m.watchers, key)
+	m.mu.Unlock()
+
+	if ok {
+		w.Close()
+	}
 }
 
 // sendWarning sends a warning message to the output channel
@@ -112,32 +457,200 @@ func (m *Manager) sendWarning(id config.ServiceID, message string) {
 	}
 }
 
-// waitForReady waits for a service to be ready (running and healthy)
-func (m *Manager) waitForReady(id config.ServiceID, timeout time.Duration) {
+// waitForReady waits for a service to be ready so its dependents can start,
+// warning if it isn't ready in time
+func (m *Manager) waitForReady(id config.ServiceID) {
+	proc := m.Get(id)
+	if proc == nil {
+		return
+	}
+
+	timeout := readyTimeout(proc.Config)
+	if !m.awaitReady(proc, timeout) && proc.IsRunning() {
+		proc.emitSystemMessage(fmt.Sprintf("⚠ Not ready after %s, starting dependents anyway", timeout))
+	}
+}
+
+// awaitReady waits until a process prints a line matching its ready pattern
+// if it has one, otherwise until its health check passes. It returns false
+// if the process exited or the timeout expired.
+func (m *Manager) awaitReady(proc *Process, timeout time.Duration) bool {
 	deadline := time.Now().Add(timeout)
 	for time.Now().Before(deadline) {
-		proc := m.Get(id)
-		if proc == nil {
-			return
+		if proc.IsDone() {
+			return false
 		}
 		if proc.Status() == StatusRunning {
-			// Check health if configured
-			health := m.healthChecker.CheckHealth(proc.Config)
-			if health == HealthHealthy || health == HealthUnknown {
-				return
+			if proc.HasReadyPattern() {
+				if proc.IsReady() {
+					return true
+				}
+			} else {
+				// Check health if configured
+				health, _ := m.healthChecker.CheckHealth(proc.Resolved(), proc.Cwd)
+				if health == HealthHealthy || health == HealthUnknown {
+					return true
+				}
 			}
 		}
-		time.Sleep(500 * time.Millisecond)
+		time.Sleep(200 * time.Millisecond)
+	}
+	return false
+}
+
+// watchStartTimeout fails and kills a freshly started service if it doesn't
+// become ready within its start_timeout
+func (m *Manager) watchStartTimeout(proc *Process) {
+	timeout := proc.Config.StartTimeout
+	if timeout <= 0 || proc.Config.IsTask() {
+		return
+	}
+
+	startedAt := proc.StartedAt()
+	go func() {
+		if m.awaitReady(proc, timeout) {
+			return
+		}
+		proc.AbortStart(startedAt)
+		m.saveState()
+	}()
+}
+
+// readyTimeout returns how long dependents wait for a service to be ready
+func readyTimeout(svc config.Service) time.Duration {
+	if svc.StartTimeout > 0 {
+		return svc.StartTimeout
+	}
+	return DefaultReadyTimeout
+}
+
+// waitForTask waits for a task to finish and returns its final status. It
+// gives up once the manager is closed, returning StatusStopped, or after
+// the task's start_timeout, returning its status then.
+func (m *Manager) waitForTask(id config.ServiceID) Status {
+	var deadline <-chan time.Time
+	if proc := m.Get(id); proc != nil && proc.Config.StartTimeout > 0 {
+		deadline = time.After(proc.Config.StartTimeout)
+	}
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		proc := m.Get(id)
+		if proc == nil {
+			return StatusStopped
+		}
+		if proc.IsDone() {
+			return proc.Status()
+		}
+		select {
+		case <-m.done:
+			return StatusStopped
+		case <-deadline:
+			return proc.Status()
+		case <-ticker.C:
+		}
 	}
 }
 
-// Stop stops a specific service
+// taskFailure describes a task that didn't succeed, for its dependents
+func taskFailure(id config.ServiceID, status Status) string {
+	if status == StatusRunning {
+		return fmt.Sprintf("task %s still running after its start timeout", id.Service)
+	}
+	return fmt.Sprintf("task %s %s", id.Service, status)
+}
+
+// Stop stops a specific service, or every replica of a replicated one
 func (m *Manager) Stop(id config.ServiceID) error {
+	procs := m.Instances(id)
+	if len(procs) == 0 {
+		return nil
+	}
+
+	var err error
+	for _, proc := range procs {
+		m.stopWatcher(proc.ID)
+		if stopErr := proc.Stop(); stopErr != nil {
+			err = stopErr
+		}
+	}
+	m.saveState()
+	return err
+}
+
+// Signal sends a signal, given by name, to a specific service
+func (m *Manager) Signal(id config.ServiceID, name string) error {
 	proc := m.Get(id)
 	if proc == nil {
 		return nil
 	}
-	return proc.Stop()
+	return proc.Signal(name)
+}
+
+// Pause freezes a service, or all replicas of a replicated service
+func (m *Manager) Pause(id config.ServiceID) error {
+	var err error
+	for _, proc := range m.Instances(id) {
+		if e := proc.Pause(); e != nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// Resume continues a paused service, or all paused replicas
+func (m *Manager) Resume(id config.ServiceID) error {
+	var err error
+	for _, proc := range m.Instances(id) {
+		if proc.Status() != StatusPaused {
+			continue
+		}
+		if e := proc.Resume(); e != nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// UpdateService applies the config of a service changed in the manager's
+// config to its processes (every replica). Running processes keep their
+// settings until they are restarted.
+func (m *Manager) UpdateService(id config.ServiceID) {
+	service, ok := m.config.Projects[id.Project].Services[id.Service]
+	if !ok {
+		return
+	}
+	cwd := m.config.GetServiceCwd(id.Project, id.Service)
+	for _, proc := range m.Instances(id.Base()) {
+		cfg := service
+		if proc.ID.Instance > 0 {
+			cfg = service.Replica(proc.ID.Instance)
+		}
+		proc.Reconfigure(cfg, cwd)
+	}
+}
+
+// AddService creates the processes of a service added to the manager's
+// config (one per replica), leaving the other services as they are
+func (m *Manager) AddService(id config.ServiceID) {
+	service, ok := m.config.Projects[id.Project].Services[id.Service]
+	if !ok || len(m.Instances(id.Base())) > 0 {
+		return
+	}
+	cwd := m.config.GetServiceCwd(id.Project, id.Service)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !service.IsReplicated() {
+		m.addProcess(id.Base(), service, cwd)
+		return
+	}
+	for i := 1; i <= service.Replicas; i++ {
+		replica := id.Base()
+		replica.Instance = i
+		m.addProcess(replica, service.Replica(i), cwd)
+	}
 }
 
 // Restart restarts a specific service
@@ -146,119 +659,251 @@ func (m *Manager) Restart(id config.ServiceID) error {
 	if proc == nil {
 		return nil
 	}
-	return proc.Restart()
+	proc.ClearCrashLoop()
+	if proc.HasPendingConfig() {
+		// Stop first, so the new config is resolved for the start
+		m.stopWatcher(proc.ID)
+		if err := proc.Stop(); err != nil {
+			return err
+		}
+		proc.applyPendingConfig()
+	}
+	if err := m.resolveTemplates(proc); err != nil {
+		proc.emitSystemMessage(fmt.Sprintf("✖ Failed to restart: %v", err))
+		return err
+	}
+	m.clearLogsOnRestart(proc)
+	if err := proc.Restart(); err != nil {
+		return err
+	}
+	m.saveState()
+	m.watchStartTimeout(proc)
+	m.startWatcher(proc)
+	return nil
 }
 
-// StartAll starts all services in dependency order
+// StartAll starts all services in dependency order. Services within a
+// topological layer start concurrently; the next layer starts once every
+// service in the current layer that something depends on is ready.
 func (m *Manager) StartAll() {
-	// Get services sorted by dependencies
-	order := m.getDependencyOrder()
+	m.startInOrder(func(*Process) bool { return true })
+}
 
-	for _, id := range order {
-		proc := m.Get(id)
-		if proc != nil && proc.Status() != StatusRunning {
-			proc.Start()
-			// Small delay between starts
-			if proc.Config.Delay > 0 {
-				time.Sleep(proc.Config.Delay)
-			} else {
-				time.Sleep(100 * time.Millisecond)
+// StartOrder returns every service in the order StartAll starts them:
+// layer by layer, alphabetically within a layer
+func (m *Manager) StartOrder() []config.ServiceID {
+	var order []config.ServiceID
+	for _, layer := range m.dependencyLayers() {
+		order = append(order, layer...)
+	}
+	return order
+}
+
+// startInOrder starts matching processes layer by layer. Like Start, it
+// doesn't start services whose dependencies failed to start, or depend on a
+// task that didn't succeed.
+func (m *Manager) startInOrder(match func(*Process) bool) {
+	// Why a service failed or wasn't started, key: service without instance
+	var failedMu sync.Mutex
+	failed := make(map[config.ServiceID]string)
+	fail := func(id config.ServiceID, reason string) {
+		failedMu.Lock()
+		failed[config.ServiceID{Project: id.Project, Service: id.Service}] = reason
+		failedMu.Unlock()
+	}
+	failedDependency := func(proc *Process) string {
+		failedMu.Lock()
+		defer failedMu.Unlock()
+		for _, dep := range proc.Config.DependsOn {
+			if reason, ok := failed[config.ServiceID{Project: proc.ID.Project, Service: dep}]; ok {
+				return reason
 			}
 		}
+		return ""
+	}
+
+	for _, layer := range m.dependencyLayers() {
+		var wg sync.WaitGroup
+		for _, id := range layer {
+			proc := m.Get(id)
+			if proc == nil || !match(proc) {
+				continue
+			}
+			// Skip running services and tasks that already completed
+			if proc.Status() == StatusRunning || proc.Status() == StatusSucceeded {
+				continue
+			}
+			if reason := failedDependency(proc); reason != "" {
+				proc.emitSystemMessage("✖ Not started: " + reason)
+				fail(id, fmt.Sprintf("%s not started", id.Service))
+				continue
+			}
+			wg.Add(1)
+			go func(proc *Process) {
+				defer wg.Done()
+				if reason := m.startAndWait(proc); reason != "" {
+					fail(proc.ID, reason)
+				}
+			}(proc)
+		}
+		wg.Wait()
 	}
 }
 
-// getDependencyOrder returns services sorted by dependencies (topological sort)
-func (m *Manager) getDependencyOrder() []config.ServiceID {
+// startAndWait starts a process and blocks until dependents may start. It
+// returns why dependents mustn't start, or "" if they may.
+func (m *Manager) startAndWait(proc *Process) string {
+	if err := m.startProcess(proc); err != nil {
+		return fmt.Sprintf("%s failed to start", proc.ID.Service)
+	}
+
+	// Tasks must finish before anything that depends on them starts
+	if proc.Config.IsTask() {
+		if status := m.waitForTask(proc.ID); status != StatusSucceeded {
+			return taskFailure(proc.ID, status)
+		}
+		return ""
+	}
+
+	if proc.Config.Delay > 0 {
+		time.Sleep(proc.Config.Delay)
+	}
+
+	if m.hasDependents(proc.ID) {
+		m.waitForReady(proc.ID)
+	}
+	return ""
+}
+
+// hasDependents returns true if any service depends on the given one
+func (m *Manager) hasDependents(id config.ServiceID) bool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	// Build dependency graph
-	var allIDs []config.ServiceID
-	deps := make(map[string][]string) // service -> dependencies
+	for _, p := range m.processes {
+		if p.ID.Project != id.Project {
+			continue
+		}
+		for _, dep := range p.Config.DependsOn {
+			if dep == id.Service {
+				return true
+			}
+		}
+	}
+	return false
+}
 
-	for _, proc := range m.processes {
-		allIDs = append(allIDs, proc.ID)
-		key := proc.ID.String()
-		deps[key] = nil
+// dependencyLayers groups services into topological layers: layer 0 has no
+// dependencies, and every service only depends on services in earlier layers.
+// Services caught in a dependency cycle are placed together in a final layer.
+func (m *Manager) dependencyLayers() [][]config.ServiceID {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// Build dependency graph
+	ids := make(map[string]config.ServiceID)
+	instances := make(map[string][]string)  // service -> its processes (replicas)
+	deps := make(map[string][]string)       // service -> dependencies
+	dependents := make(map[string][]string) // dependency -> services depending on it
+
+	for key, proc := range m.processes {
+		ids[key] = proc.ID
+		base := proc.ID.Base().String()
+		instances[base] = append(instances[base], key)
+	}
+	for key, proc := range m.processes {
 		for _, dep := range proc.Config.DependsOn {
+			// Unknown dependencies have no instances and are ignored
 			depID := config.ServiceID{Project: proc.ID.Project, Service: dep}
-			deps[key] = append(deps[key], depID.String())
+			for _, depKey := range instances[depID.String()] {
+				deps[key] = append(deps[key], depKey)
+				dependents[depKey] = append(dependents[depKey], key)
+			}
 		}
 	}
 
-	// Topological sort using Kahn's algorithm
+	// Kahn's algorithm, one layer at a time
 	inDegree := make(map[string]int)
-	for id := range deps {
-		inDegree[id] = 0
-	}
-	for _, dependencies := range deps {
-		for _, dep := range dependencies {
-			inDegree[dep]++
+	var current []string
+	for key := range ids {
+		inDegree[key] = len(deps[key])
+		if inDegree[key] == 0 {
+			current = append(current, key)
 		}
 	}
 
-	// Find all nodes with no incoming edges
-	var queue []string
-	for id, degree := range inDegree {
-		if degree == 0 {
-			queue = append(queue, id)
+	var layers [][]config.ServiceID
+	placed := 0
+	for len(current) > 0 {
+		sort.Strings(current)
+
+		layer := make([]config.ServiceID, 0, len(current))
+		var next []string
+		for _, key := range current {
+			layer = append(layer, ids[key])
+			for _, dependent := range dependents[key] {
+				inDegree[dependent]--
+				if inDegree[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
 		}
-	}
 
-	var result []config.ServiceID
-	for len(queue) > 0 {
-		// Dequeue
-		current := queue[0]
-		queue = queue[1:]
+		layers = append(layers, layer)
+		placed += len(layer)
+		current = next
+	}
 
-		// Find the ServiceID for this key
-		for _, proc := range m.processes {
-			if proc.ID.String() == current {
-				result = append(result, proc.ID)
-				break
+	// Anything left is part of a cycle
+	if placed < len(ids) {
+		var cyclic []string
+		for key, degree := range inDegree {
+			if degree > 0 {
+				cyclic = append(cyclic, key)
 			}
 		}
+		sort.Strings(cyclic)
 
-		// Reduce in-degree for dependents
-		for id, dependencies := range deps {
-			for _, dep := range dependencies {
-				if dep == current {
-					inDegree[id]--
-					if inDegree[id] == 0 {
-						queue = append(queue, id)
-					}
-				}
-			}
+		layer := make([]config.ServiceID, 0, len(cyclic))
+		for _, key := range cyclic {
+			layer = append(layer, ids[key])
 		}
+		layers = append(layers, layer)
 	}
 
-	// If result doesn't contain all services, there's a cycle - just return all
-	if len(result) != len(allIDs) {
-		return allIDs
-	}
-
-	return result
+	return layers
 }
 
-// StopAll stops all services
+// StopAll stops all services in reverse dependency order: dependents are
+// stopped (and waited for) before the services they depend on, so they can
+// still flush work to e.g. a database that is still up.
 func (m *Manager) StopAll() {
-	m.mu.RLock()
-	procs := make([]*Process, 0, len(m.processes))
-	for _, p := range m.processes {
-		procs = append(procs, p)
-	}
-	m.mu.RUnlock()
+	m.stopInReverseOrder(func(*Process) bool { return true })
+}
 
-	var wg sync.WaitGroup
-	for _, p := range procs {
-		wg.Add(1)
-		go func(proc *Process) {
-			defer wg.Done()
-			proc.Stop()
-		}(p)
+// stopInReverseOrder stops matching processes layer by layer, starting with
+// the services nothing else depends on
+func (m *Manager) stopInReverseOrder(match func(*Process) bool) {
+	layers := m.dependencyLayers()
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		var wg sync.WaitGroup
+		for _, id := range layers[i] {
+			proc := m.Get(id)
+			if proc == nil || !match(proc) {
+				continue
+			}
+			wg.Add(1)
+			go func(proc *Process) {
+				defer wg.Done()
+				m.stopWatcher(proc.ID)
+				proc.Stop()
+			}(proc)
+		}
+		wg.Wait()
 	}
-	wg.Wait()
+
+	m.saveState()
 }
 
 // RestartAll restarts all services
@@ -269,8 +914,11 @@ func (m *Manager) RestartAll() {
 
 // Shutdown gracefully shuts down all processes
 func (m *Manager) Shutdown() {
+	// Stop the monitor first, so it can't auto-restart a failed service
+	// during StopAll
+	m.stopMonitor()
 	m.StopAll()
-	close(m.outputCh)
+	m.Close()
 }
 
 // GetByProject returns all processes for a specific project
@@ -287,32 +935,24 @@ func (m *Manager) GetByProject(projectName string) []*Process {
 	return procs
 }
 
-// StartProject starts all services in a project
+// StartProject starts all services in a project in dependency order
 func (m *Manager) StartProject(projectName string) {
-	procs := m.GetByProject(projectName)
-	var wg sync.WaitGroup
-	for _, p := range procs {
-		wg.Add(1)
-		go func(proc *Process) {
-			defer wg.Done()
-			proc.Start()
-		}(p)
-	}
-	wg.Wait()
+	m.startInOrder(func(p *Process) bool {
+		return p.ID.Project == projectName
+	})
 }
 
-// StopProject stops all services in a project
+// StopProject stops all services in a project in reverse dependency order
 func (m *Manager) StopProject(projectName string) {
-	procs := m.GetByProject(projectName)
-	var wg sync.WaitGroup
-	for _, p := range procs {
-		wg.Add(1)
-		go func(proc *Process) {
-			defer wg.Done()
-			proc.Stop()
-		}(p)
-	}
-	wg.Wait()
+	m.stopInReverseOrder(func(p *Process) bool {
+		return p.ID.Project == projectName
+	})
+}
+
+// RestartProject restarts all services in a project
+func (m *Manager) RestartProject(projectName string) {
+	m.StopProject(projectName)
+	m.StartProject(projectName)
 }
 
 // RunningCount returns the number of running processes
@@ -346,17 +986,92 @@ func (m *Manager) CheckHealth() {
 	m.mu.RUnlock()
 
 	for _, p := range procs {
-		if p.Status() == StatusRunning {
-			health := m.healthChecker.CheckHealth(p.Config)
-			p.SetHealth(health)
+		// Tasks run to completion, so health checks don't apply
+		if p.Status() == StatusRunning && !p.Config.IsTask() {
+			p.SetHealth(m.checkHealth(p))
+		} else {
+			p.SetHealth(HealthUnknown, nil)
+		}
+	}
+}
+
+// checkHealth runs a service's health check, returning why it failed
+func (m *Manager) checkHealth(p *Process) (HealthStatus, error) {
+	health, err := m.healthChecker.CheckHealth(p.Resolved(), p.Cwd)
+	if health == HealthUnknown && p.Config.IsDocker() {
+		// Fall back to the container's own HEALTHCHECK
+		health = p.containerHealth()
+		if health == HealthUnhealthy {
+			err = errors.New("container HEALTHCHECK reports unhealthy")
+		}
+	}
+	return health, err
+}
+
+// monitorHealth health checks a service until the manager is closed. Each
+// run is checked every second until it first reports healthy (or for
+// healthStartupPeriod), then every health_interval.
+func (m *Manager) monitorHealth(p *Process) {
+	interval := p.Config.HealthInterval
+	if interval <= 0 {
+		interval = DefaultHealthInterval
+	}
+
+	timer := time.NewTimer(healthStartupInterval)
+	defer timer.Stop()
+
+	var run time.Time // start of the run being checked
+	var healthy bool  // whether the run has reported healthy yet
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-timer.C:
+		}
+
+		delay := healthStartupInterval
+		if p.Status() == StatusRunning && !p.Config.IsTask() {
+			if started := p.StartedAt(); !started.Equal(run) {
+				run = started
+				healthy = false
+			}
+			health, err := m.checkHealth(p)
+			p.SetHealth(health, err)
+			healthy = healthy || health == HealthHealthy
+			if healthy || time.Since(run) > healthStartupPeriod {
+				delay = interval
+			}
 		} else {
-			p.SetHealth(HealthUnknown)
+			p.SetHealth(HealthUnknown, nil)
 		}
+		timer.Reset(delay)
+	}
+}
+
+// CollectStats samples CPU and memory usage of all running processes
+func (m *Manager) CollectStats() {
+	procs := m.All()
+
+	pids := make([]int, 0, len(procs))
+	for _, p := range procs {
+		if pid := p.PID(); pid > 0 {
+			pids = append(pids, pid)
+		}
+	}
+
+	stats := m.stats.Collect(pids)
+	for _, p := range procs {
+		p.SetStats(stats[p.PID()])
 	}
+	m.publish(StatsSampled{})
 }
 
-// CheckAutoRestart checks for failed processes and restarts them if auto_restart is enabled
-func (m *Manager) CheckAutoRestart() {
+// CheckAutoRestart checks for failed processes and restarts them if
+// auto_restart is enabled, and resets the restart counter of services that
+// stayed up for their stable period. It returns services that were just
+// detected as crash-looping, for which auto-restart is now disabled; they
+// are also published as CrashLoopDetected events.
+func (m *Manager) CheckAutoRestart() []config.ServiceID {
 	m.mu.RLock()
 	procs := make([]*Process, 0, len(m.processes))
 	for _, p := range m.processes {
@@ -364,16 +1079,74 @@ func (m *Manager) CheckAutoRestart() {
 	}
 	m.mu.RUnlock()
 
+	var looping []config.ServiceID
 	for _, p := range procs {
-		if p.Status() == StatusFailed && p.Config.AutoRestart {
-			if p.RestartCount() < maxAutoRestarts {
-				p.IncrementRestartCount()
-				// Small delay before restart
-				time.Sleep(500 * time.Millisecond)
-				p.Start()
+		// Forget old crashes once the service has been up long enough
+		if p.RestartCount() > 0 && p.Uptime() >= stableAfter(p.Config) {
+			p.ResetRestartCount()
+		}
+
+		if p.Status() != StatusFailed || !p.Config.AutoRestart || p.IsCrashLooping() {
+			continue
+		}
+
+		maxFailures, window := crashLoopLimits(p.Config)
+		if failures := p.RecentFailures(window); failures >= maxFailures {
+			p.SetCrashLooping(true)
+			p.emitSystemMessage(fmt.Sprintf("✖ Crash loop: failed %d times in %s, auto-restart disabled", failures, window))
+			m.publish(CrashLoopDetected{ID: p.ID})
+			looping = append(looping, p.ID)
+			continue
+		}
+
+		p.IncrementRestartCount()
+		m.publish(RestartScheduled{ID: p.ID, Attempt: p.RestartCount(), Delay: autoRestartDelay})
+		// Small delay before restart, cut short by shutdown
+		select {
+		case <-time.After(autoRestartDelay):
+		case <-m.monitorStop:
+			return looping
+		case <-m.done:
+			return looping
+		}
+		m.clearLogsOnRestart(p)
+		if p.applyPendingConfig() {
+			if err := m.resolveTemplates(p); err != nil {
+				p.emitSystemMessage(fmt.Sprintf("✖ Failed to restart: %v", err))
+				continue
 			}
 		}
+		if p.Start() == nil {
+			m.saveState()
+			m.watchStartTimeout(p)
+		}
+	}
+	return looping
+}
+
+// autoRestartDelay is how long auto-restart waits before restarting a
+// failed service
+const autoRestartDelay = 500 * time.Millisecond
+
+// crashLoopLimits returns the crash-loop threshold for a service
+func crashLoopLimits(svc config.Service) (int, time.Duration) {
+	maxFailures := svc.CrashLoopFailures
+	if maxFailures <= 0 {
+		maxFailures = DefaultCrashLoopFailures
+	}
+	window := svc.CrashLoopWindow
+	if window <= 0 {
+		window = DefaultCrashLoopWindow
 	}
+	return maxFailures, window
+}
+
+// stableAfter returns how long a service must run to count as stable
+func stableAfter(svc config.Service) time.Duration {
+	if svc.StableAfter > 0 {
+		return svc.StableAfter
+	}
+	return DefaultStableAfter
 }
 
 // GetHealth returns the health status of a specific service
@@ -399,8 +1172,8 @@ func (m *Manager) GetPortConflicts() map[int][]config.ServiceID {
 	portUsage := make(map[int][]config.ServiceID)
 
 	for _, proc := range m.processes {
-		if proc.Config.Port > 0 {
-			portUsage[proc.Config.Port] = append(portUsage[proc.Config.Port], proc.ID)
+		if port := proc.Resolved().Port; port > 0 {
+			portUsage[port] = append(portUsage[port], proc.ID)
 		}
 	}
 
@@ -418,9 +1191,10 @@ func (m *Manager) GetPortConflicts() map[int][]config.ServiceID {
 // CheckPortConflict checks if starting this service would conflict with another running service
 func (m *Manager) CheckPortConflict(id config.ServiceID) (bool, config.ServiceID) {
 	proc := m.Get(id)
-	if proc == nil || proc.Config.Port == 0 {
+	if proc == nil || proc.Resolved().Port == 0 {
 		return false, config.ServiceID{}
 	}
+	port := proc.Resolved().Port
 
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -429,7 +1203,7 @@ func (m *Manager) CheckPortConflict(id config.ServiceID) (bool, config.ServiceID
 		if other.ID == id {
 			continue
 		}
-		if other.Config.Port == proc.Config.Port && other.Status() == StatusRunning {
+		if other.Resolved().Port == port && other.Status().alive() {
 			return true, other.ID
 		}
 	}
@@ -444,8 +1218,8 @@ func (m *Manager) GetRunningPorts() map[int]config.ServiceID {
 
 	ports := make(map[int]config.ServiceID)
 	for _, proc := range m.processes {
-		if proc.Config.Port > 0 && proc.Status() == StatusRunning {
-			ports[proc.Config.Port] = proc.ID
+		if port := proc.Resolved().Port; port > 0 && proc.Status().alive() {
+			ports[port] = proc.ID
 		}
 	}
 	return ports
@@ -465,11 +1239,14 @@ type PortConflictInfo struct {
 // Returns nil if port is available, or PortConflictInfo if there's a conflict
 func (m *Manager) CheckPortAvailability(id config.ServiceID) *PortConflictInfo {
 	proc := m.Get(id)
-	if proc == nil || proc.Config.Port == 0 {
+	if proc == nil || proc.Resolved().Port == 0 {
 		return nil // No port configured, no conflict possible
 	}
+	if proc.Config.AutoPort {
+		return nil // Moves to a free port on start
+	}
 
-	port := proc.Config.Port
+	port := proc.Resolved().Port
 
 	// First check if another paraler service is using this port
 	if hasConflict, conflictID := m.CheckPortConflict(id); hasConflict {
@@ -499,3 +1276,158 @@ func (m *Manager) CheckPortAvailability(id config.ServiceID) *PortConflictInfo {
 func (m *Manager) KillPortProcess(port int) error {
 	return KillProcessOnPort(port)
 }
+
+// SetLogClearer sets the function that clears a service's logs when it
+// restarts, if the service has clear_logs_on_restart. It must be set before
+// services start.
+func (m *Manager) SetLogClearer(clear func(config.ServiceID)) {
+	m.clearLogs = clear
+}
+
+// clearLogsOnRestart clears the logs of a service about to restart, if it
+// is configured to
+func (m *Manager) clearLogsOnRestart(proc *Process) {
+	if m.clearLogs != nil && proc.Config.ClearLogsOnRestart {
+		m.clearLogs(proc.ID)
+	}
+}
+
+// SetStatePath enables persisting the PIDs of started services to a state
+// file, so orphans can be found after a crash. Records already in the file
+// are kept until FindOrphans has checked them.
+func (m *Manager) SetStatePath(path string) {
+	records, _ := readStateFile(path)
+
+	var orphans []ProcessRecord
+	for _, r := range records {
+		if !ownedByOtherSession(r) {
+			orphans = append(orphans, r)
+		}
+	}
+
+	m.stateMu.Lock()
+	m.statePath = path
+	m.orphans = orphans
+	m.stateMu.Unlock()
+}
+
+// FindOrphans returns processes left running by a previous session that
+// exited without stopping its services. They stay in the state file until
+// adopted or killed.
+func (m *Manager) FindOrphans() []ProcessRecord {
+	m.stateMu.Lock()
+	path := m.statePath
+	m.stateMu.Unlock()
+
+	// Where services stop with paraler, a recorded PID that is alive
+	// belongs to some other process that reused it
+	if path == "" || !OutlivesParaler {
+		return nil
+	}
+
+	records, err := readStateFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var orphans []ProcessRecord
+	for _, r := range records {
+		// Processes handed over by an in-place upgrade are already managed
+		if isOrphan(r) && !m.managesPID(r.PID) {
+			orphans = append(orphans, r)
+		}
+	}
+
+	m.stateMu.Lock()
+	m.orphans = orphans
+	m.stateMu.Unlock()
+	m.saveState()
+
+	return orphans
+}
+
+// managesPID returns true if a running service has the given PID
+func (m *Manager) managesPID(pid int) bool {
+	for _, p := range m.All() {
+		if p.PID() == pid {
+			return true
+		}
+	}
+	return false
+}
+
+// AdoptOrphan takes over an orphaned process as its service's process
+func (m *Manager) AdoptOrphan(r ProcessRecord) error {
+	proc := m.Get(r.ServiceID())
+	if proc == nil {
+		return fmt.Errorf("service %s is no longer configured", r.ServiceID())
+	}
+	if err := proc.Adopt(r.PID, r.StartedAt); err != nil {
+		return err
+	}
+
+	m.forgetOrphan(r)
+	m.saveState()
+	return nil
+}
+
+// KillOrphan kills an orphaned process and everything it spawned
+func (m *Manager) KillOrphan(r ProcessRecord) error {
+	err := adoptProcessGroup(r.PID).Kill()
+	m.forgetOrphan(r)
+	m.saveState()
+	return err
+}
+
+// forgetOrphan removes an orphan from the unresolved list
+func (m *Manager) forgetOrphan(r ProcessRecord) {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+
+	for i, o := range m.orphans {
+		if o.PID == r.PID {
+			m.orphans = append(m.orphans[:i], m.orphans[i+1:]...)
+			return
+		}
+	}
+}
+
+// saveState writes the PIDs of running services (and unresolved orphans)
+// to the state file
+func (m *Manager) saveState() {
+	m.stateMu.Lock()
+	defer m.stateMu.Unlock()
+
+	if m.statePath == "" {
+		return
+	}
+
+	// Keep the records of other sessions running on the same config
+	var records []ProcessRecord
+	if stored, err := readStateFile(m.statePath); err == nil {
+		for _, r := range stored {
+			if ownedByOtherSession(r) {
+				records = append(records, r)
+			}
+		}
+	}
+	records = append(records, m.orphans...)
+	for _, p := range m.All() {
+		pid := p.PID()
+		if pid == 0 || p.Config.IsDocker() {
+			// Leftover containers are replaced by name on the next start
+			continue
+		}
+		records = append(records, ProcessRecord{
+			Project:   p.ID.Project,
+			Service:   p.ID.Service,
+			Instance:  p.ID.Instance,
+			PID:       pid,
+			Cmd:       p.Resolved().Cmd,
+			StartedAt: p.StartedAt(),
+			Owner:     os.Getpid(),
+		})
+	}
+
+	writeStateFile(m.statePath, records)
+}
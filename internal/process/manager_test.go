@@ -0,0 +1,647 @@
+package process
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+func TestManager_DependencyLayers(t *testing.T) {
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: "/tmp",
+				Services: map[string]config.Service{
+					"db":       {Cmd: "postgres"},
+					"cache":    {Cmd: "redis-server"},
+					"api":      {Cmd: "npm run dev", DependsOn: []string{"db", "cache"}},
+					"worker":   {Cmd: "npm run worker", DependsOn: []string{"db"}},
+					"frontend": {Cmd: "npm run dev", DependsOn: []string{"api", "missing"}},
+				},
+			},
+		},
+	}
+
+	m := NewManager(cfg)
+	layers := m.dependencyLayers()
+
+	expected := [][]string{
+		{"app/cache", "app/db"},
+		{"app/api", "app/worker"},
+		{"app/frontend"},
+	}
+
+	if len(layers) != len(expected) {
+		t.Fatalf("expected %d layers, got %d: %v", len(expected), len(layers), layers)
+	}
+	for i, layer := range layers {
+		if len(layer) != len(expected[i]) {
+			t.Fatalf("layer %d: expected %v, got %v", i, expected[i], layer)
+		}
+		for j, id := range layer {
+			if id.String() != expected[i][j] {
+				t.Errorf("layer %d: expected %v, got %v", i, expected[i], layer)
+			}
+		}
+	}
+}
+
+func TestManager_DependencyLayersCycle(t *testing.T) {
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: "/tmp",
+				Services: map[string]config.Service{
+					"base": {Cmd: "true"},
+					"a":    {Cmd: "true", DependsOn: []string{"b", "base"}},
+					"b":    {Cmd: "true", DependsOn: []string{"a"}},
+				},
+			},
+		},
+	}
+
+	m := NewManager(cfg)
+	layers := m.dependencyLayers()
+
+	total := 0
+	for _, layer := range layers {
+		total += len(layer)
+	}
+	if total != 3 {
+		t.Errorf("expected all 3 services to be placed, got %d", total)
+	}
+	if layers[0][0].Service != "base" {
+		t.Errorf("expected base in first layer, got %v", layers[0])
+	}
+}
+
+func TestManager_StartAllWaitsForReadyLayer(t *testing.T) {
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: "/tmp",
+				Services: map[string]config.Service{
+					"db":  {Cmd: "sleep 1; echo accepting connections; sleep 30", Ready: "accepting connections"},
+					"api": {Cmd: "sleep 30", DependsOn: []string{"db"}},
+				},
+			},
+		},
+	}
+
+	m := NewManager(cfg)
+	defer m.Shutdown()
+	db := m.Get(config.ServiceID{Project: "app", Service: "db"})
+	api := m.Get(config.ServiceID{Project: "app", Service: "api"})
+
+	done := make(chan struct{})
+	go func() {
+		m.StartAll()
+		close(done)
+	}()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for !db.IsReady() {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected db to become ready, got %s", db.Status())
+		}
+		if api.Status() != StatusStopped {
+			t.Fatalf("expected api to wait for db to be ready, got %s", api.Status())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected StartAll to return")
+	}
+	if api.Status() != StatusRunning {
+		t.Errorf("expected api to start once db was ready, got %s", api.Status())
+	}
+}
+
+func TestManager_StartAllSkipsDependentsOfFailedTask(t *testing.T) {
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: "/tmp",
+				Services: map[string]config.Service{
+					"migrate": {Cmd: "exit 1", Type: config.ServiceTypeTask},
+					"cache":   {Cmd: "sleep 30"},
+					"api":     {Cmd: "sleep 30", DependsOn: []string{"migrate", "cache"}},
+					"web":     {Cmd: "sleep 30", DependsOn: []string{"api"}},
+				},
+			},
+		},
+	}
+
+	m := NewManager(cfg)
+	defer m.Shutdown()
+	m.StartAll()
+
+	if status := m.Get(config.ServiceID{Project: "app", Service: "cache"}).Status(); status != StatusRunning {
+		t.Errorf("expected cache to start, got %s", status)
+	}
+	for _, name := range []string{"api", "web"} {
+		if status := m.Get(config.ServiceID{Project: "app", Service: name}).Status(); status != StatusStopped {
+			t.Errorf("expected %s not to start after the task failed, got %s", name, status)
+		}
+	}
+}
+
+func TestManager_WaitForTaskStopsOnClose(t *testing.T) {
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: "/tmp",
+				Services: map[string]config.Service{
+					"migrate": {Cmd: "sleep 30", Type: config.ServiceTypeTask},
+				},
+			},
+		},
+	}
+
+	m := NewManager(cfg)
+	defer m.Shutdown()
+	id := config.ServiceID{Project: "app", Service: "migrate"}
+	if err := m.Start(id); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	done := make(chan Status)
+	go func() { done <- m.waitForTask(id) }()
+	m.Close()
+	select {
+	case status := <-done:
+		if status != StatusStopped {
+			t.Errorf("expected StatusStopped, got %s", status)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Close to end the wait")
+	}
+}
+
+func TestManager_CheckAutoRestartResetsStableRestartCount(t *testing.T) {
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: "/tmp",
+				Services: map[string]config.Service{
+					"stable": {Cmd: "true", AutoRestart: true, StableAfter: time.Minute},
+					"flaky":  {Cmd: "true", AutoRestart: true, StableAfter: time.Minute},
+				},
+			},
+		},
+	}
+
+	m := NewManager(cfg)
+	for name, uptime := range map[string]time.Duration{"stable": 2 * time.Minute, "flaky": 10 * time.Second} {
+		p := m.Get(config.ServiceID{Project: "app", Service: name})
+		p.status = StatusRunning
+		p.startedAt = time.Now().Add(-uptime)
+		p.restartCount = 3
+	}
+
+	m.CheckAutoRestart()
+
+	if got := m.Get(config.ServiceID{Project: "app", Service: "stable"}).RestartCount(); got != 0 {
+		t.Errorf("expected stable service restart count to reset, got %d", got)
+	}
+	if got := m.Get(config.ServiceID{Project: "app", Service: "flaky"}).RestartCount(); got != 3 {
+		t.Errorf("expected flaky service restart count to stay 3, got %d", got)
+	}
+}
+
+func TestManager_ShutdownCancelsScheduledAutoRestart(t *testing.T) {
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: "/tmp",
+				Services: map[string]config.Service{
+					"api": {Cmd: "sleep 30", AutoRestart: true},
+				},
+			},
+		},
+	}
+
+	m := NewManager(cfg)
+	p := m.Get(config.ServiceID{Project: "app", Service: "api"})
+	p.status = StatusFailed
+
+	checked := make(chan struct{})
+	go func() {
+		m.CheckAutoRestart()
+		close(checked)
+	}()
+	for p.RestartCount() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	m.Shutdown()
+	<-checked
+
+	if p.IsRunning() {
+		p.Stop()
+		t.Errorf("expected no auto-restart after shutdown, got %s", p.Status())
+	}
+}
+
+func TestManager_ResolveTemplates(t *testing.T) {
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: "/srv/app",
+				Services: map[string]config.Service{
+					"api": {Cmd: "serve --name {{service}} --root {{project_path}} --port {{port}}", Port: 4000},
+					"web": {Cmd: "vite --port {{port}}", Health: "http://localhost:{{port}}/"},
+				},
+			},
+		},
+	}
+
+	m := NewManager(cfg)
+
+	api := m.Get(config.ServiceID{Project: "app", Service: "api"})
+	if err := m.resolveTemplates(api); err != nil {
+		t.Fatalf("resolveTemplates: %v", err)
+	}
+	if got, want := api.Resolved().Cmd, "serve --name api --root /srv/app --port 4000"; got != want {
+		t.Errorf("expected cmd %q, got %q", want, got)
+	}
+
+	web := m.Get(config.ServiceID{Project: "app", Service: "web"})
+	if err := m.resolveTemplates(web); err != nil {
+		t.Fatalf("resolveTemplates: %v", err)
+	}
+	port := web.Resolved().Port
+	if port == 0 {
+		t.Fatal("expected a port to be assigned")
+	}
+	if got, want := web.Resolved().Health, fmt.Sprintf("http://localhost:%d/", port); got != want {
+		t.Errorf("expected health %q, got %q", want, got)
+	}
+
+	// The assigned port is kept across restarts
+	if err := m.resolveTemplates(web); err != nil {
+		t.Fatalf("resolveTemplates: %v", err)
+	}
+	if web.Resolved().Port != port {
+		t.Errorf("expected port %d to be kept, got %d", port, web.Resolved().Port)
+	}
+}
+
+func TestManager_PublishesStatusChanged(t *testing.T) {
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: "/tmp",
+				Services: map[string]config.Service{
+					"api": {Cmd: "true"},
+				},
+			},
+		},
+	}
+
+	m := NewManager(cfg)
+	id := config.ServiceID{Project: "app", Service: "api"}
+	p := m.Get(id)
+
+	p.setStatus(StatusRunning)
+	p.setStatus(StatusRunning) // unchanged, not published
+
+	select {
+	case e := <-m.Events():
+		changed, ok := e.(StatusChanged)
+		if !ok {
+			t.Fatalf("expected StatusChanged, got %T", e)
+		}
+		if changed.ID != id || changed.Old != StatusStopped || changed.New != StatusRunning {
+			t.Errorf("unexpected event %+v", changed)
+		}
+	default:
+		t.Fatal("expected a StatusChanged event")
+	}
+
+	select {
+	case e := <-m.Events():
+		t.Errorf("expected no further events, got %+v", e)
+	default:
+	}
+}
+
+func TestManager_ClearLogsOnRestart(t *testing.T) {
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: "/tmp",
+				Services: map[string]config.Service{
+					"api":    {Cmd: "true", ClearLogsOnRestart: true},
+					"worker": {Cmd: "true"},
+				},
+			},
+		},
+	}
+
+	m := NewManager(cfg)
+	var cleared []config.ServiceID
+	m.SetLogClearer(func(id config.ServiceID) {
+		cleared = append(cleared, id)
+	})
+
+	api := config.ServiceID{Project: "app", Service: "api"}
+	m.clearLogsOnRestart(m.Get(api))
+	m.clearLogsOnRestart(m.Get(config.ServiceID{Project: "app", Service: "worker"}))
+
+	if len(cleared) != 1 || cleared[0] != api {
+		t.Errorf("expected only %s cleared, got %v", api, cleared)
+	}
+}
+
+func TestManager_Replicas(t *testing.T) {
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: "/tmp",
+				Services: map[string]config.Service{
+					"db":     {Cmd: "postgres"},
+					"worker": {Cmd: "worker --id {{instance}} --port {{port}}", Port: 9000, Replicas: 3, DependsOn: []string{"db"}},
+					"lb":     {Cmd: "nginx", DependsOn: []string{"worker"}},
+				},
+			},
+		},
+	}
+
+	m := NewManager(cfg)
+
+	workers := m.Instances(config.ServiceID{Project: "app", Service: "worker"})
+	if len(workers) != 3 {
+		t.Fatalf("expected 3 replicas, got %d", len(workers))
+	}
+	for i, w := range workers {
+		if err := m.resolveTemplates(w); err != nil {
+			t.Fatalf("resolveTemplates: %v", err)
+		}
+		want := fmt.Sprintf("worker --id %d --port %d", i+1, 9000+i)
+		if got := w.Resolved().Cmd; got != want {
+			t.Errorf("replica %d: expected cmd %q, got %q", i+1, want, got)
+		}
+	}
+
+	var got [][]string
+	for _, layer := range m.dependencyLayers() {
+		var keys []string
+		for _, id := range layer {
+			keys = append(keys, id.String())
+		}
+		got = append(got, keys)
+	}
+	want := fmt.Sprint([][]string{{"app/db"}, {"app/worker#1", "app/worker#2", "app/worker#3"}, {"app/lb"}})
+	if fmt.Sprint(got) != want {
+		t.Errorf("expected layers %s, got %v", want, got)
+	}
+}
+
+func TestManager_UpdateService(t *testing.T) {
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: "/tmp",
+				Services: map[string]config.Service{
+					"api":    {Cmd: "api", Port: 3000},
+					"worker": {Cmd: "worker", Port: 9000, Replicas: 2},
+				},
+			},
+		},
+	}
+
+	m := NewManager(cfg)
+	api := m.Get(config.ServiceID{Project: "app", Service: "api"})
+	api.setStatus(StatusRunning)
+
+	cfg.Projects["app"].Services["api"] = config.Service{Cmd: "api --debug", Port: 3001, Ready: "listening"}
+	cfg.Projects["app"].Services["worker"] = config.Service{Cmd: "worker -v", Port: 9100, Replicas: 2}
+	m.UpdateService(config.ServiceID{Project: "app", Service: "api"})
+	m.UpdateService(config.ServiceID{Project: "app", Service: "worker", Instance: 2})
+
+	// Running services keep their config until they start again
+	if api.Config.Cmd != "api" || !api.HasPendingConfig() {
+		t.Errorf("expected the running service to keep its config, got %q", api.Config.Cmd)
+	}
+	api.setStatus(StatusStopped)
+	if !api.applyPendingConfig() || api.Config.Cmd != "api --debug" || api.Config.Port != 3001 || !api.HasReadyPattern() {
+		t.Errorf("expected the new config once stopped, got %+v", api.Config)
+	}
+
+	for i, w := range m.Instances(config.ServiceID{Project: "app", Service: "worker"}) {
+		if w.Config.Cmd != "worker -v" || w.Config.Port != 9100+i || w.HasPendingConfig() {
+			t.Errorf("replica %d: expected the new config, got %+v", i+1, w.Config)
+		}
+	}
+}
+
+func TestManager_AutoPort(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	taken := ln.Addr().(*net.TCPAddr).Port
+
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: "/tmp",
+				Services: map[string]config.Service{
+					"web": {Cmd: "vite --port {{port}}", Port: taken, AutoPort: true, Env: []string{"NODE_ENV=development"}},
+				},
+			},
+		},
+	}
+
+	m := NewManager(cfg)
+	web := m.Get(config.ServiceID{Project: "app", Service: "web"})
+	if err := m.resolveTemplates(web); err != nil {
+		t.Fatalf("resolveTemplates: %v", err)
+	}
+
+	resolved := web.Resolved()
+	if resolved.Port <= taken {
+		t.Fatalf("expected a port above %d, got %d", taken, resolved.Port)
+	}
+	if got, want := resolved.Cmd, fmt.Sprintf("vite --port %d", resolved.Port); got != want {
+		t.Errorf("expected cmd %q, got %q", want, got)
+	}
+	if got, want := resolved.Env[len(resolved.Env)-1], fmt.Sprintf("PORT=%d", resolved.Port); got != want {
+		t.Errorf("expected env %q, got %q", want, got)
+	}
+	if len(web.Config.Env) != 1 {
+		t.Errorf("expected configured env to be left alone, got %v", web.Config.Env)
+	}
+
+	select {
+	case e := <-m.Events():
+		if r, ok := e.(PortReassigned); !ok || r.From != taken || r.To != resolved.Port {
+			t.Errorf("unexpected event %+v", e)
+		}
+	default:
+		t.Error("expected a PortReassigned event")
+	}
+}
+
+func TestManager_MovePort(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	taken := ln.Addr().(*net.TCPAddr).Port
+
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: "/tmp",
+				Services: map[string]config.Service{
+					"web": {Cmd: "vite --port {{port}}", Port: taken},
+				},
+			},
+		},
+	}
+
+	m := NewManager(cfg)
+	web := m.Get(config.ServiceID{Project: "app", Service: "web"})
+
+	// Moves once, as StartOnFreePort asks for
+	web.setMovePort()
+	if err := m.resolveTemplates(web); err != nil {
+		t.Fatalf("resolveTemplates: %v", err)
+	}
+	resolved := web.Resolved()
+	if resolved.Port <= taken {
+		t.Fatalf("expected a port above %d, got %d", taken, resolved.Port)
+	}
+	if got, want := resolved.Env[len(resolved.Env)-1], fmt.Sprintf("PORT=%d", resolved.Port); got != want {
+		t.Errorf("expected env %q, got %q", want, got)
+	}
+
+	// Later starts use the configured port again
+	if err := m.resolveTemplates(web); err != nil {
+		t.Fatalf("resolveTemplates: %v", err)
+	}
+	if got := web.Resolved().Port; got != taken {
+		t.Errorf("expected port %d on the next start, got %d", taken, got)
+	}
+}
+
+func TestManager_StartOrder(t *testing.T) {
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: "/tmp",
+				Services: map[string]config.Service{
+					"db":     {Cmd: "postgres"},
+					"api":    {Cmd: "npm run dev", DependsOn: []string{"db"}},
+					"web":    {Cmd: "npm run dev", DependsOn: []string{"api"}},
+					"worker": {Cmd: "npm run worker", DependsOn: []string{"db"}},
+				},
+			},
+		},
+	}
+
+	m := NewManager(cfg)
+	expected := []string{"app/db", "app/api", "app/worker", "app/web"}
+	order := m.StartOrder()
+	if len(order) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, order)
+	}
+	for i, id := range order {
+		if id.String() != expected[i] {
+			t.Errorf("expected %v, got %v", expected, order)
+			break
+		}
+	}
+}
+
+func TestManager_AddService(t *testing.T) {
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path:     "/tmp",
+				Services: map[string]config.Service{"api": {Cmd: "./server"}},
+			},
+		},
+	}
+	m := NewManager(cfg)
+	api := m.Get(config.ServiceID{Project: "app", Service: "api"})
+
+	if err := cfg.AddService("app", "workers", config.Service{Cmd: "./worker", Replicas: 2}); err != nil {
+		t.Fatalf("AddService: %v", err)
+	}
+	m.AddService(config.ServiceID{Project: "app", Service: "workers"})
+
+	if got := len(m.Instances(config.ServiceID{Project: "app", Service: "workers"})); got != 2 {
+		t.Errorf("expected 2 replicas of the added service, got %d", got)
+	}
+	if m.Get(config.ServiceID{Project: "app", Service: "api"}) != api {
+		t.Error("expected the other services to keep their processes")
+	}
+	if got := m.TotalCount(); got != 3 {
+		t.Errorf("expected 3 processes, got %d", got)
+	}
+}
+
+func TestManager_WatchRestartAppliesPendingConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: dir,
+				Services: map[string]config.Service{
+					"api": {Cmd: "sleep 30", Watch: []string{"*.go"}, WatchDebounce: 50 * time.Millisecond},
+				},
+			},
+		},
+	}
+
+	m := NewManager(cfg)
+	defer m.Shutdown()
+	id := config.ServiceID{Project: "app", Service: "api"}
+	if err := m.Start(id); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	api := m.Get(id)
+
+	cfg.Projects["app"].Services["api"] = config.Service{Cmd: "sleep 31", Watch: []string{"*.go"}, WatchDebounce: 50 * time.Millisecond}
+	m.UpdateService(id)
+	if !api.HasPendingConfig() {
+		t.Fatal("expected the running service to have a pending config")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for api.Resolved().Cmd != "sleep 31" || api.Status() != StatusRunning {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a restart with the pending config, got %q (%s)", api.Resolved().Cmd, api.Status())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	// The restart replaced the watcher, which still restarts the service
+	deadline = time.Now().Add(10 * time.Second)
+	pid := api.PID()
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for api.PID() == pid || api.Status() != StatusRunning {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the new watcher to restart the service")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
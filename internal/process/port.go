@@ -3,10 +3,6 @@ package process
 import (
 	"fmt"
 	"net"
-	"os/exec"
-	"regexp"
-	"strconv"
-	"strings"
 	"time"
 )
 
@@ -34,70 +30,12 @@ func GetPortStatus(port int) PortStatus {
 	conn.Close()
 	status.InUse = true
 
-	// Try to find what's using the port using lsof
+	// Try to find what's using the port
 	status.PID, status.Process, status.Command = getProcessOnPort(port)
 
 	return status
 }
 
-// getProcessOnPort uses lsof to find process using a port (macOS/Linux)
-func getProcessOnPort(port int) (pid int, name string, command string) {
-	// lsof -i :PORT -t gives PID
-	// lsof -i :PORT gives full info
-	cmd := exec.Command("lsof", "-i", fmt.Sprintf(":%d", port), "-P", "-n")
-	output, err := cmd.Output()
-	if err != nil {
-		return 0, "", ""
-	}
-
-	lines := strings.Split(string(output), "\n")
-	if len(lines) < 2 {
-		return 0, "", ""
-	}
-
-	// Parse lsof output (skip header)
-	// COMMAND  PID  USER   FD   TYPE  DEVICE  SIZE/OFF  NODE  NAME
-	for _, line := range lines[1:] {
-		if line == "" {
-			continue
-		}
-		// Only look for LISTEN state
-		if !strings.Contains(line, "LISTEN") {
-			continue
-		}
-
-		fields := regexp.MustCompile(`\s+`).Split(line, -1)
-		if len(fields) < 2 {
-			continue
-		}
-
-		name = fields[0]
-		if p, err := strconv.Atoi(fields[1]); err == nil {
-			pid = p
-		}
-
-		// Get full command line
-		if pid > 0 {
-			command = getCommandLine(pid)
-		}
-
-		return pid, name, command
-	}
-
-	return 0, "", ""
-}
-
-// getCommandLine gets the full command line for a process
-func getCommandLine(pid int) string {
-	// ps -p PID -o args=
-	cmd := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "args=")
-	output, err := cmd.Output()
-	if err != nil {
-		return ""
-	}
-	return strings.TrimSpace(string(output))
-}
-
 // KillProcessOnPort kills the process using a specific port
 func KillProcessOnPort(port int) error {
 	status := GetPortStatus(port)
@@ -110,8 +48,7 @@ func KillProcessOnPort(port int) error {
 	}
 
 	// Kill the process
-	cmd := exec.Command("kill", "-9", strconv.Itoa(status.PID))
-	if err := cmd.Run(); err != nil {
+	if err := killPID(status.PID); err != nil {
 		return fmt.Errorf("failed to kill process %d: %w", status.PID, err)
 	}
 
@@ -120,3 +57,32 @@ func KillProcessOnPort(port int) error {
 
 	return nil
 }
+
+// FreePort asks the OS for a port that is currently free
+func FreePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find a free port: %w", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// maxPortSearch is how many ports above a taken one are tried when looking
+// for an alternate port
+const maxPortSearch = 100
+
+// portInUse returns true if something accepts connections on the port or
+// it can't be bound
+func portInUse(port int) bool {
+	if conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), 500*time.Millisecond); err == nil {
+		conn.Close()
+		return true
+	}
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return true
+	}
+	l.Close()
+	return false
+}
@@ -0,0 +1,74 @@
+//go:build !windows
+
+package process
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// getProcessOnPort uses lsof to find process using a port (macOS/Linux)
+func getProcessOnPort(port int) (pid int, name string, command string) {
+	// lsof -i :PORT -t gives PID
+	// lsof -i :PORT gives full info
+	cmd := exec.Command("lsof", "-i", fmt.Sprintf(":%d", port), "-P", "-n")
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, "", ""
+	}
+
+	lines := strings.Split(string(output), "\n")
+	if len(lines) < 2 {
+		return 0, "", ""
+	}
+
+	// Parse lsof output (skip header)
+	// COMMAND  PID  USER   FD   TYPE  DEVICE  SIZE/OFF  NODE  NAME
+	for _, line := range lines[1:] {
+		if line == "" {
+			continue
+		}
+		// Only look for LISTEN state
+		if !strings.Contains(line, "LISTEN") {
+			continue
+		}
+
+		fields := regexp.MustCompile(`\s+`).Split(line, -1)
+		if len(fields) < 2 {
+			continue
+		}
+
+		name = fields[0]
+		if p, err := strconv.Atoi(fields[1]); err == nil {
+			pid = p
+		}
+
+		// Get full command line
+		if pid > 0 {
+			command = getCommandLine(pid)
+		}
+
+		return pid, name, command
+	}
+
+	return 0, "", ""
+}
+
+// getCommandLine gets the full command line for a process
+func getCommandLine(pid int) string {
+	// ps -p PID -o args=
+	cmd := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "args=")
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// killPID forcefully kills a single process
+func killPID(pid int) error {
+	return exec.Command("kill", "-9", strconv.Itoa(pid)).Run()
+}
@@ -0,0 +1,74 @@
+//go:build windows
+
+package process
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// getProcessOnPort uses netstat to find process using a port (Windows)
+func getProcessOnPort(port int) (pid int, name string, command string) {
+	// netstat -ano lists all sockets with the owning PID
+	cmd := exec.Command("netstat", "-ano", "-p", "TCP")
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, "", ""
+	}
+
+	// Proto  Local Address  Foreign Address  State  PID
+	suffix := fmt.Sprintf(":%d", port)
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 || fields[3] != "LISTENING" {
+			continue
+		}
+		if !strings.HasSuffix(fields[1], suffix) {
+			continue
+		}
+
+		p, err := strconv.Atoi(fields[4])
+		if err != nil {
+			continue
+		}
+		pid = p
+		name = getProcessName(pid)
+		command = getCommandLine(pid)
+		return pid, name, command
+	}
+
+	return 0, "", ""
+}
+
+// getProcessName gets the image name for a process
+func getProcessName(pid int) string {
+	// tasklist /FI "PID eq N" /FO CSV /NH -> "node.exe","1234",...
+	cmd := exec.Command("tasklist", "/FI", fmt.Sprintf("PID eq %d", pid), "/FO", "CSV", "/NH")
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	fields := strings.Split(strings.TrimSpace(string(output)), ",")
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.Trim(fields[0], `"`)
+}
+
+// getCommandLine gets the full command line for a process
+func getCommandLine(pid int) string {
+	cmd := exec.Command("powershell", "-NoProfile", "-Command",
+		fmt.Sprintf("(Get-CimInstance Win32_Process -Filter 'ProcessId=%d').CommandLine", pid))
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// killPID forcefully kills a single process
+func killPID(pid int) error {
+	return exec.Command("taskkill", "/F", "/PID", strconv.Itoa(pid)).Run()
+}
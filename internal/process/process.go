@@ -7,8 +7,11 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 
 	"github.com/paralerdev/paraler/internal/config"
@@ -23,6 +26,8 @@ const (
 	StatusRunning
 	StatusStopping
 	StatusFailed
+	StatusSucceeded // Task completed successfully
+	StatusPaused    // Frozen with SIGSTOP (or a paused container)
 )
 
 func (s Status) String() string {
@@ -37,30 +42,99 @@ func (s Status) String() string {
 		return "stopping"
 	case StatusFailed:
 		return "failed"
+	case StatusSucceeded:
+		return "succeeded"
+	case StatusPaused:
+		return "paused"
 	default:
 		return "unknown"
 	}
 }
 
+// alive returns true if the service has a live process, running or paused
+func (s Status) alive() bool {
+	return s == StatusRunning || s == StatusPaused
+}
+
 // Process wraps an exec.Cmd with additional functionality
 type Process struct {
 	ID     config.ServiceID
 	Config config.Service
 	Cwd    string
 
-	mu           sync.RWMutex
-	cmd          *exec.Cmd
-	cancel       context.CancelFunc
-	status       Status
-	health       HealthStatus
-	exitCode     int
-	exitErr      error
-	startedAt    time.Time
-	stoppedAt    time.Time
-	restartCount int
+	mu               sync.RWMutex
+	resolved         config.Service // Config with placeholders resolved
+	cmd              *exec.Cmd
+	exited           chan struct{} // closed once wait() has reaped cmd
+	stdout           *os.File      // read ends of the output pipes, handed over on upgrade
+	stderr           *os.File
+	group            *processGroup
+	docker           *dockerClient
+	container        string // ID of the running container of a docker service
+	containerPID     int
+	containerManaged bool // created by paraler, removed once it stops
+	cancel           context.CancelFunc
+	startCancel      context.CancelFunc // cancels waiting for wait_for targets or creating the container
+	starting         chan struct{}      // closed once Start returns
+	startStopped     bool               // Stop was called while starting
+	status           Status
+	health           HealthStatus
+	healthErr        error // why the last health check failed
+	exitCode         int
+	exitErr          error
+	startedAt        time.Time
+	stoppedAt        time.Time
+	stopRequestedAt  time.Time // when Stop asked the service to terminate
+	restartCount     int
+	stats            Stats
+
+	// Readiness: set once an output line matches readyPattern
+	readyPattern *regexp.Regexp
+	ready        bool
+	timedOut     bool // killed for not becoming ready within start_timeout
+
+	triggers  []*trigger
+	onTrigger TriggerHandler
+
+	// Crash-loop detection
+	failures     []time.Time // recent failure times
+	crashLooping bool
+
+	exits []ExitRecord // last ExitHistorySize exits, oldest first
+
+	// Config set by Reconfigure while running, used from the next start
+	pending *pendingConfig
+
+	// The next start moves to a free port if its port is taken, as with
+	// auto_port
+	movePort bool
 
 	// Output channels
 	outputCh chan OutputLine
+	events   chan<- Event // set by the Manager, nil for standalone processes
+	dropped  atomic.Int64 // output lines dropped since TakeDroppedLines
+}
+
+// StopGracePeriod is how long Stop waits for a service to exit after
+// SIGTERM before killing it
+const StopGracePeriod = 5 * time.Second
+
+// ExitHistorySize is how many exits are kept per service
+const ExitHistorySize = 10
+
+// ExitRecord describes how a run of a service ended
+type ExitRecord struct {
+	Time     time.Time
+	Status   Status // stopped, failed or succeeded
+	ExitCode int
+	Signal   string // Signal that killed the process, if any
+	Uptime   time.Duration
+}
+
+// pendingConfig is a service config waiting for the process to stop
+type pendingConfig struct {
+	service config.Service
+	cwd     string
 }
 
 // OutputLine represents a line of output from the process
@@ -73,13 +147,90 @@ type OutputLine struct {
 
 // NewProcess creates a new process wrapper
 func NewProcess(id config.ServiceID, cfg config.Service, cwd string, outputCh chan OutputLine) *Process {
-	return &Process{
+	p := &Process{
 		ID:       id,
 		Config:   cfg,
 		Cwd:      cwd,
+		resolved: cfg,
 		status:   StatusStopped,
 		outputCh: outputCh,
 	}
+	if cfg.Ready != "" {
+		// Patterns are validated when the config is loaded
+		p.readyPattern, _ = regexp.Compile(cfg.Ready)
+	}
+	p.triggers = compileTriggers(cfg.Triggers)
+	return p
+}
+
+// Reconfigure replaces the service config of the process. A running
+// process keeps its config until it is started again.
+func (p *Process) Reconfigure(cfg config.Service, cwd string) {
+	p.mu.Lock()
+	p.pending = &pendingConfig{service: cfg, cwd: cwd}
+	alive := p.status.alive()
+	p.mu.Unlock()
+
+	if !alive {
+		p.applyPendingConfig()
+	}
+}
+
+// HasPendingConfig returns true if a config set by Reconfigure waits for the
+// process to be started again
+func (p *Process) HasPendingConfig() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.pending != nil
+}
+
+// setMovePort makes the next start move to a free port if the service's
+// port is taken
+func (p *Process) setMovePort() {
+	p.mu.Lock()
+	p.movePort = true
+	p.mu.Unlock()
+}
+
+// movesPort returns true if the service moves to a free port when its port
+// is taken
+func (p *Process) movesPort() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.Config.AutoPort || p.movePort
+}
+
+// takeMovePort returns true if the service moves to a free port when its
+// port is taken on this start, clearing the one set by setMovePort
+func (p *Process) takeMovePort() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	move := p.Config.AutoPort || p.movePort
+	p.movePort = false
+	return move
+}
+
+// applyPendingConfig switches to the config set by Reconfigure, returning
+// false if there is none. The process must not be running.
+func (p *Process) applyPendingConfig() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pending == nil {
+		return false
+	}
+
+	cfg := p.pending.service
+	p.Config = cfg
+	p.Cwd = p.pending.cwd
+	p.resolved = cfg
+	p.readyPattern = nil
+	if cfg.Ready != "" {
+		// Patterns are validated when the config is saved
+		p.readyPattern, _ = regexp.Compile(cfg.Ready)
+	}
+	p.triggers = compileTriggers(cfg.Triggers)
+	p.pending = nil
+	return true
 }
 
 // Status returns the current process status
@@ -96,6 +247,48 @@ func (p *Process) StartedAt() time.Time {
 	return p.startedAt
 }
 
+// StopRequestedAt returns when Stop asked the service to terminate, or the
+// zero time if it isn't stopping
+func (p *Process) StopRequestedAt() time.Time {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.status != StatusStopping {
+		return time.Time{}
+	}
+	return p.stopRequestedAt
+}
+
+// Resolved returns the service config with cmd and health placeholders
+// resolved for the current run
+func (p *Process) Resolved() config.Service {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.resolved
+}
+
+// SetResolved sets the resolved service config used by the next start
+func (p *Process) SetResolved(cfg config.Service) {
+	p.mu.Lock()
+	p.resolved = cfg
+	p.mu.Unlock()
+}
+
+// stoppedWhileStarting reports whether Stop was called while the service
+// was starting, marking it stopped if so
+func (p *Process) stoppedWhileStarting() bool {
+	p.mu.Lock()
+	stopped := p.startStopped
+	if stopped {
+		p.setStatusLocked(StatusStopped)
+	}
+	p.mu.Unlock()
+
+	if stopped {
+		p.emitSystemMessage("■ Start cancelled")
+	}
+	return stopped
+}
+
 // ExitCode returns the exit code of the last run
 func (p *Process) ExitCode() int {
 	p.mu.RLock()
@@ -106,15 +299,42 @@ func (p *Process) ExitCode() int {
 // Start starts the process
 func (p *Process) Start() error {
 	p.mu.Lock()
-	if p.status == StatusRunning || p.status == StatusStarting {
+	if p.status.alive() || p.status == StatusStarting {
 		p.mu.Unlock()
 		return fmt.Errorf("process already running")
 	}
 
-	p.status = StatusStarting
+	p.setStatusLocked(StatusStarting)
 	p.exitErr = nil
 	p.exitCode = 0
+	p.ready = false
+	p.timedOut = false
+	p.startStopped = false
+	starting := make(chan struct{})
+	p.starting = starting
+	cmdline := p.resolved.Cmd
+	argv := p.resolved.Argv
+	env := p.resolved.Env
+	var waitCtx context.Context
+	if len(p.Config.WaitFor) > 0 {
+		// Registered before unlocking, so a Stop right away cancels the wait
+		waitCtx = p.startWaitLocked()
+	}
 	p.mu.Unlock()
+	defer close(starting)
+
+	if waitCtx != nil {
+		if err := p.waitForTargets(waitCtx); err != nil {
+			return err
+		}
+	}
+	if p.stoppedWhileStarting() {
+		return fmt.Errorf("start cancelled")
+	}
+
+	if p.Config.IsDocker() {
+		return p.startContainer()
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	p.mu.Lock()
@@ -123,104 +343,165 @@ func (p *Process) Start() error {
 
 	// Check if working directory exists
 	if _, err := os.Stat(p.Cwd); os.IsNotExist(err) {
+		cancel()
 		p.setStatus(StatusFailed)
 		p.emitSystemMessage(fmt.Sprintf("✖ Directory not found: %s", p.Cwd))
 		return fmt.Errorf("working directory does not exist: %s", p.Cwd)
 	}
 
-	// Create command with shell
-	cmd := exec.CommandContext(ctx, "sh", "-c", p.Config.Cmd)
+	// Run argv directly, or the command line through a shell
+	args := argv
+	if len(args) == 0 {
+		args = shellArgs(p.Config.Shell, cmdline)
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
 	cmd.Dir = p.Cwd
-	cmd.Env = append(cmd.Environ(), p.Config.Env...)
+	cmd.Env = append(cmd.Environ(), env...)
 
 	// Set process group for killing children
-	cmd.SysProcAttr = &syscall.SysProcAttr{
-		Setpgid: true,
-	}
+	setProcAttr(cmd)
+	setShellCmdLine(cmd)
 
-	// Get stdout and stderr pipes
-	stdout, err := cmd.StdoutPipe()
+	// Get stdout and stderr pipes. Unlike StdoutPipe, Wait doesn't close
+	// their read ends, so output of a process that exits right away is
+	// still read in full.
+	stdout, stdoutW, err := os.Pipe()
 	if err != nil {
+		cancel()
 		p.setStatus(StatusFailed)
 		p.emitSystemMessage(fmt.Sprintf("✖ Failed to start: %v", err))
 		return fmt.Errorf("failed to get stdout pipe: %w", err)
 	}
 
-	stderr, err := cmd.StderrPipe()
+	stderr, stderrW, err := os.Pipe()
 	if err != nil {
+		stdout.Close()
+		stdoutW.Close()
+		cancel()
 		p.setStatus(StatusFailed)
 		p.emitSystemMessage(fmt.Sprintf("✖ Failed to start: %v", err))
 		return fmt.Errorf("failed to get stderr pipe: %w", err)
 	}
+	cmd.Stdout = stdoutW
+	cmd.Stderr = stderrW
 
 	// Start the process
-	if err := cmd.Start(); err != nil {
+	err = cmd.Start()
+	// The child has its own copies of the write ends
+	stdoutW.Close()
+	stderrW.Close()
+	if err != nil {
+		stdout.Close()
+		stderr.Close()
+		cancel()
 		p.setStatus(StatusFailed)
 		p.emitSystemMessage(fmt.Sprintf("✖ Failed to start: %v", err))
-		p.emitSystemMessage(fmt.Sprintf("  Command: %s", p.Config.Cmd))
+		p.emitSystemMessage(fmt.Sprintf("  Command: %s", cmdline))
 		p.emitSystemMessage(fmt.Sprintf("  Directory: %s", p.Cwd))
 		return fmt.Errorf("failed to start process: %w", err)
 	}
 
+	group, err := newProcessGroup(cmd)
+	if err != nil {
+		p.emitSystemMessage(fmt.Sprintf("⚠ %v", err))
+	}
+	if p.Config.PriorityNice != 0 && group != nil {
+		if err := group.SetPriority(p.Config.PriorityNice); err != nil {
+			p.emitSystemMessage(fmt.Sprintf("⚠ Failed to set priority: %v", err))
+		}
+	}
+
 	p.mu.Lock()
 	p.cmd = cmd
+	p.group = group
+	p.exited = make(chan struct{})
+	p.stdout = stdout
+	p.stderr = stderr
 	p.startedAt = time.Now()
-	p.status = StatusRunning
+	p.setStatusLocked(StatusRunning)
 	p.mu.Unlock()
 
 	// Emit start message
 	p.emitSystemMessage("▶ Service started")
 
 	// Stream output in goroutines
-	go p.streamOutput(stdout, false)
-	go p.streamOutput(stderr, true)
+	streamed := make(chan struct{}, 2)
+	go p.streamOutput(stdout, false, streamed)
+	go p.streamOutput(stderr, true, streamed)
 
 	// Wait for process completion in background
-	go p.wait()
+	go p.wait(streamed)
 
 	return nil
 }
 
-// Stop stops the process gracefully
+// Stop stops the process gracefully. A service still starting has its
+// start cancelled, or is stopped once it is up.
 func (p *Process) Stop() error {
 	p.mu.Lock()
-	if p.status != StatusRunning {
+	if p.status == StatusStarting {
+		p.startStopped = true
+		cancel := p.startCancel
+		starting := p.starting
+		p.mu.Unlock()
+		if cancel != nil {
+			cancel()
+		}
+		<-starting
+		return p.Stop()
+	}
+	if !p.status.alive() {
 		p.mu.Unlock()
 		return nil
 	}
-	p.status = StatusStopping
-	cmd := p.cmd
+	paused := p.status == StatusPaused
+	p.setStatusLocked(StatusStopping)
+	p.stopRequestedAt = time.Now()
+	group := p.group
 	cancel := p.cancel
+	done := p.exited
+	container := p.container
 	p.mu.Unlock()
 
-	if cmd == nil || cmd.Process == nil {
+	if paused {
+		// Frozen processes can't handle the stop signal
+		p.unfreeze(group, container)
+	}
+
+	if container != "" {
+		p.stopContainer(container, done)
+		if cancel != nil {
+			cancel()
+		}
 		return nil
 	}
 
-	// Send SIGTERM to process group
-	pgid, err := syscall.Getpgid(cmd.Process.Pid)
-	if err == nil {
-		syscall.Kill(-pgid, syscall.SIGTERM)
+	if done == nil {
+		// Adopted processes can't be waited on
+		if group != nil {
+			return p.stopAdopted(group)
+		}
+		return nil
 	}
 
-	// Wait for graceful shutdown with timeout
-	done := make(chan struct{})
-	go func() {
-		cmd.Wait()
-		close(done)
-	}()
+	// Ask the process group to terminate
+	group.Terminate()
 
+	// Wait for graceful shutdown with timeout
 	select {
 	case <-done:
 		// Process exited gracefully
-	case <-time.After(5 * time.Second):
+	case <-time.After(StopGracePeriod):
 		// Force kill if still running
-		if pgid, err := syscall.Getpgid(cmd.Process.Pid); err == nil {
-			syscall.Kill(-pgid, syscall.SIGKILL)
-		}
+		group.Kill()
 		<-done
 	}
 
+	// Descendants that left the process group can outlive the root
+	if !waitExit(group, 2*time.Second) {
+		group.Kill()
+	}
+
 	if cancel != nil {
 		cancel()
 	}
@@ -228,6 +509,212 @@ func (p *Process) Stop() error {
 	return nil
 }
 
+// Signal sends a signal, given by name (e.g. "SIGHUP"), to the process group
+// or the container
+func (p *Process) Signal(name string) error {
+	p.mu.RLock()
+	group := p.group
+	container := p.container
+	running := p.status.alive()
+	p.mu.RUnlock()
+
+	switch {
+	case !running:
+		return fmt.Errorf("process is not running")
+	case container != "":
+		if err := p.docker.killContainer(context.Background(), container, name); err != nil {
+			return err
+		}
+	case group != nil:
+		if err := group.Signal(name); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("process is not running")
+	}
+
+	p.emitSystemMessage(fmt.Sprintf("⚡ Sent %s", name))
+	return nil
+}
+
+// Pause freezes a running service (SIGSTOP to its process group, or pausing
+// its container) without losing its state
+func (p *Process) Pause() error {
+	p.mu.RLock()
+	group := p.group
+	container := p.container
+	running := p.status == StatusRunning
+	p.mu.RUnlock()
+
+	var err error
+	switch {
+	case !running:
+		return fmt.Errorf("process is not running")
+	case container != "":
+		err = p.docker.pauseContainer(context.Background(), container)
+	case group != nil:
+		err = group.Pause()
+	default:
+		return fmt.Errorf("process is not running")
+	}
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	if p.status == StatusRunning {
+		p.setStatusLocked(StatusPaused)
+	}
+	p.mu.Unlock()
+
+	p.emitSystemMessage("⏸ Paused")
+	return nil
+}
+
+// Resume continues a paused service
+func (p *Process) Resume() error {
+	p.mu.RLock()
+	group := p.group
+	container := p.container
+	paused := p.status == StatusPaused
+	p.mu.RUnlock()
+
+	if !paused {
+		return fmt.Errorf("process is not paused")
+	}
+	if err := p.unfreeze(group, container); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	if p.status == StatusPaused {
+		p.setStatusLocked(StatusRunning)
+	}
+	p.mu.Unlock()
+
+	p.emitSystemMessage("▶ Resumed")
+	return nil
+}
+
+// unfreeze continues the processes of a paused service
+func (p *Process) unfreeze(group *processGroup, container string) error {
+	switch {
+	case container != "":
+		return p.docker.unpauseContainer(context.Background(), container)
+	case group != nil:
+		return group.Resume()
+	default:
+		return nil
+	}
+}
+
+// AbortStart kills a process that didn't become ready within its start
+// timeout and marks it failed. It does nothing if the run that started at
+// startedAt has already ended.
+func (p *Process) AbortStart(startedAt time.Time) {
+	p.mu.Lock()
+	if p.status != StatusRunning || !p.startedAt.Equal(startedAt) {
+		p.mu.Unlock()
+		return
+	}
+	p.timedOut = true
+	timeout := p.Config.StartTimeout
+	p.mu.Unlock()
+
+	p.emitSystemMessage(fmt.Sprintf("✖ Not ready within start timeout (%s), killing", timeout))
+	p.Stop()
+}
+
+// Adopt takes over a process left running by a previous paraler session.
+// Its output can't be reattached, but it is monitored and can be stopped.
+func (p *Process) Adopt(pid int, startedAt time.Time) error {
+	p.mu.Lock()
+	if p.status.alive() || p.status == StatusStarting {
+		p.mu.Unlock()
+		return fmt.Errorf("process already running")
+	}
+
+	group := adoptProcessGroup(pid)
+	if !group.Alive() {
+		p.mu.Unlock()
+		return fmt.Errorf("process %d is no longer running", pid)
+	}
+
+	p.cmd = nil
+	p.cancel = nil
+	p.group = group
+	p.exited = nil
+	p.startedAt = startedAt
+	p.exitErr = nil
+	p.exitCode = 0
+	p.setStatusLocked(StatusRunning)
+	p.ready = true // Its ready line was printed long ago
+	p.mu.Unlock()
+
+	p.emitSystemMessage(fmt.Sprintf("⇲ Adopted process from a previous session (PID %d)", pid))
+	p.emitSystemMessage("  Output from before the adoption is not available")
+
+	go p.monitorAdopted(group)
+
+	return nil
+}
+
+// monitorAdopted polls an adopted process group until it exits
+func (p *Process) monitorAdopted(group *processGroup) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if group.Alive() {
+			continue
+		}
+
+		p.mu.Lock()
+		if p.group != group || !p.status.alive() {
+			// Stop() owns the transition
+			p.mu.Unlock()
+			return
+		}
+		p.group = nil
+		p.stoppedAt = time.Now()
+		p.setStatusLocked(StatusStopped)
+		p.mu.Unlock()
+
+		p.emitSystemMessage("■ Adopted process exited")
+		return
+	}
+}
+
+// stopAdopted stops an adopted process group, which we can't Wait() on
+func (p *Process) stopAdopted(group *processGroup) error {
+	group.Terminate()
+	if !waitExit(group, StopGracePeriod) {
+		group.Kill()
+	}
+
+	p.mu.Lock()
+	p.group = nil
+	p.stoppedAt = time.Now()
+	p.setStatusLocked(StatusStopped)
+	p.mu.Unlock()
+
+	p.emitSystemMessage("■ Service stopped")
+	return nil
+}
+
+// waitExit polls until every process in the group exited, returning false
+// if some are still running after the timeout
+func waitExit(group *processGroup, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for group.Alive() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return true
+}
+
 // Restart restarts the process
 func (p *Process) Restart() error {
 	if err := p.Stop(); err != nil {
@@ -238,10 +725,16 @@ func (p *Process) Restart() error {
 	return p.Start()
 }
 
-// wait waits for the process to complete and updates status
-func (p *Process) wait() {
+// outputDrainTimeout bounds how long wait() lets output drain after the
+// process exits, since descendants may keep the pipes open
+const outputDrainTimeout = 500 * time.Millisecond
+
+// wait waits for the process to complete and updates status. streamed
+// receives a value as each output stream reaches EOF.
+func (p *Process) wait(streamed <-chan struct{}) {
 	p.mu.RLock()
 	cmd := p.cmd
+	exited := p.exited
 	p.mu.RUnlock()
 
 	if cmd == nil {
@@ -249,44 +742,130 @@ func (p *Process) wait() {
 	}
 
 	err := cmd.Wait()
+	defer close(exited)
+
+	var exitCode int
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		exitCode = exitErr.ExitCode()
+	}
+
+	drainOutput(streamed)
+	p.exit(exitCode, err)
+}
+
+// drainOutput lets the last output lines arrive before the exit message,
+// waiting for both output streams to reach EOF for a bounded time
+func drainOutput(streamed <-chan struct{}) {
+	drain := time.After(outputDrainTimeout)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-streamed:
+		case <-drain:
+			return
+		}
+	}
+}
 
+// exit records the end of a run, setting the final status from the exit
+// error, and emits the exit message
+func (p *Process) exit(exitCode int, err error) {
 	p.mu.Lock()
 	p.stoppedAt = time.Now()
 	p.exitErr = err
 
 	var newStatus Status
-	var exitCode int
 
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			exitCode = exitErr.ExitCode()
-		}
-		if p.status != StatusStopping {
+		if p.status != StatusStopping || p.timedOut {
 			newStatus = StatusFailed
 		} else {
 			newStatus = StatusStopped
 		}
 	} else {
-		exitCode = 0
-		newStatus = StatusStopped
+		if p.timedOut {
+			newStatus = StatusFailed
+		} else if p.Config.IsTask() && p.status != StatusStopping {
+			newStatus = StatusSucceeded
+		} else {
+			newStatus = StatusStopped
+		}
 	}
 
 	p.exitCode = exitCode
-	p.status = newStatus
+	p.setStatusLocked(newStatus)
+	if newStatus == StatusFailed {
+		p.failures = append(p.failures, p.stoppedAt)
+	}
+	runTime := p.stoppedAt.Sub(p.startedAt)
+	p.recordExitLocked(ExitRecord{
+		Time:     p.stoppedAt,
+		Status:   newStatus,
+		ExitCode: exitCode,
+		Signal:   exitSignal(err),
+		Uptime:   runTime,
+	})
+	cmdline := p.resolved.Cmd
+	group := p.group
+	p.group = nil
+	p.container = ""
+	p.containerPID = 0
 	p.mu.Unlock()
 
+	if group != nil {
+		group.Close()
+	}
+
 	// Emit stop message
 	if newStatus == StatusFailed {
 		p.emitSystemMessage(fmt.Sprintf("✖ Service failed (exit code: %d)", exitCode))
-		p.emitSystemMessage(fmt.Sprintf("  Command: %s", p.Config.Cmd))
+		p.emitSystemMessage(fmt.Sprintf("  Command: %s", cmdline))
 		p.emitSystemMessage(fmt.Sprintf("  Directory: %s", p.Cwd))
+	} else if newStatus == StatusSucceeded {
+		p.emitSystemMessage(fmt.Sprintf("✔ Task completed in %s", runTime.Round(time.Millisecond)))
 	} else {
 		p.emitSystemMessage("■ Service stopped")
 	}
 }
 
-// streamOutput reads from a reader and sends lines to the output channel
-func (p *Process) streamOutput(r io.Reader, isStderr bool) {
+// shellArgs returns the argv used to run a command line through a shell
+func shellArgs(shell, cmdline string) []string {
+	if shell == "" {
+		shell = defaultShell
+	}
+
+	switch shellName(shell) {
+	case "cmd":
+		return []string{shell, "/C", cmdline}
+	case "powershell", "pwsh":
+		return []string{shell, "-NoProfile", "-Command", cmdline}
+	default:
+		return []string{shell, "-c", cmdline}
+	}
+}
+
+// shellName returns the name of a shell without directory and .exe
+func shellName(shell string) string {
+	name := strings.ToLower(filepath.Base(shell))
+	return strings.TrimSuffix(name, ".exe")
+}
+
+// ShellCommand returns a command that runs a command line through the
+// default shell
+func ShellCommand(ctx context.Context, cmdline string) *exec.Cmd {
+	args := shellArgs("", cmdline)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	setShellCmdLine(cmd)
+	return cmd
+}
+
+// streamOutput reads from a pipe until EOF, sending lines to the output
+// channel, then closes it and signals done
+func (p *Process) streamOutput(r io.ReadCloser, isStderr bool, done chan<- struct{}) {
+	defer func() {
+		r.Close()
+		done <- struct{}{}
+	}()
+
 	scanner := bufio.NewScanner(r)
 	// Increase buffer size for long lines
 	buf := make([]byte, 0, 64*1024)
@@ -294,6 +873,8 @@ func (p *Process) streamOutput(r io.Reader, isStderr bool) {
 
 	for scanner.Scan() {
 		line := scanner.Text()
+		p.matchReady(line)
+		p.matchTriggers(line)
 		select {
 		case p.outputCh <- OutputLine{
 			ServiceID: p.ID,
@@ -303,17 +884,68 @@ func (p *Process) streamOutput(r io.Reader, isStderr bool) {
 		}:
 		default:
 			// Drop line if channel is full
+			p.dropLine()
 		}
 	}
 }
 
+// matchReady marks the process ready if the line matches its ready pattern
+func (p *Process) matchReady(line string) {
+	p.mu.Lock()
+	if p.readyPattern == nil || p.ready || !p.readyPattern.MatchString(line) {
+		p.mu.Unlock()
+		return
+	}
+	p.ready = true
+	p.mu.Unlock()
+
+	p.emitSystemMessage("✔ Service ready")
+}
+
 // setStatus sets the process status
 func (p *Process) setStatus(s Status) {
 	p.mu.Lock()
-	p.status = s
+	p.setStatusLocked(s)
 	p.mu.Unlock()
 }
 
+// setStatusLocked sets the process status and publishes the change;
+// p.mu must be held
+func (p *Process) setStatusLocked(s Status) {
+	if p.status == s {
+		return
+	}
+	old := p.status
+	p.status = s
+	p.publish(StatusChanged{ID: p.ID, Old: old, New: s})
+}
+
+// publish sends an event to the Manager without blocking
+func (p *Process) publish(e Event) {
+	if p.events == nil {
+		return
+	}
+	select {
+	case p.events <- e:
+	default:
+		// Drop if channel full
+	}
+}
+
+// dropLine counts a dropped output line, publishing OutputDropped for the
+// first one since the count was last taken
+func (p *Process) dropLine() {
+	if p.dropped.Add(1) == 1 {
+		p.publish(OutputDropped{ID: p.ID})
+	}
+}
+
+// TakeDroppedLines returns how many output lines were dropped since the
+// last call and resets the count
+func (p *Process) TakeDroppedLines() int {
+	return int(p.dropped.Swap(0))
+}
+
 // emitSystemMessage sends a system message to the output channel
 func (p *Process) emitSystemMessage(msg string) {
 	select {
@@ -325,14 +957,38 @@ func (p *Process) emitSystemMessage(msg string) {
 	}:
 	default:
 		// Drop if channel full
+		p.dropLine()
 	}
 }
 
+// IsDone returns true if the process has exited (stopped, failed or succeeded)
+func (p *Process) IsDone() bool {
+	switch p.Status() {
+	case StatusStopped, StatusFailed, StatusSucceeded:
+		return true
+	}
+	return false
+}
+
 // IsRunning returns true if the process is currently running
 func (p *Process) IsRunning() bool {
 	return p.Status() == StatusRunning
 }
 
+// HasReadyPattern returns true if readiness is signalled by a log line
+func (p *Process) HasReadyPattern() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.readyPattern != nil
+}
+
+// IsReady returns true once a running process printed its ready line
+func (p *Process) IsReady() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.status == StatusRunning && p.ready
+}
+
 // Health returns the current health status
 func (p *Process) Health() HealthStatus {
 	p.mu.RLock()
@@ -340,11 +996,26 @@ func (p *Process) Health() HealthStatus {
 	return p.health
 }
 
-// SetHealth sets the health status
-func (p *Process) SetHealth(h HealthStatus) {
+// HealthError returns why the last health check failed, or nil if the
+// service isn't unhealthy
+func (p *Process) HealthError() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.healthErr
+}
+
+// SetHealth sets the health status and why the check failed, publishing a
+// change
+func (p *Process) SetHealth(h HealthStatus, err error) {
 	p.mu.Lock()
+	old := p.health
 	p.health = h
+	p.healthErr = err
 	p.mu.Unlock()
+
+	if old != h {
+		p.publish(HealthChanged{ID: p.ID, Old: old, New: h})
+	}
 }
 
 // RestartCount returns how many times the process was auto-restarted
@@ -368,12 +1039,107 @@ func (p *Process) ResetRestartCount() {
 	p.mu.Unlock()
 }
 
+// recordExitLocked adds an exit to the history, dropping the oldest once
+// it is full; p.mu must be held
+func (p *Process) recordExitLocked(r ExitRecord) {
+	p.exits = append(p.exits, r)
+	if len(p.exits) > ExitHistorySize {
+		p.exits = p.exits[len(p.exits)-ExitHistorySize:]
+	}
+}
+
+// ExitHistory returns the recorded exits, most recent first
+func (p *Process) ExitHistory() []ExitRecord {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	history := make([]ExitRecord, len(p.exits))
+	for i, r := range p.exits {
+		history[len(p.exits)-1-i] = r
+	}
+	return history
+}
+
+// RecentFailures returns how many times the process failed within the window
+func (p *Process) RecentFailures(window time.Duration) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cutoff := time.Now().Add(-window)
+	recent := p.failures[:0]
+	for _, t := range p.failures {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	p.failures = recent
+	return len(recent)
+}
+
+// IsCrashLooping returns true if auto-restart was disabled for failing
+// too often
+func (p *Process) IsCrashLooping() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.crashLooping
+}
+
+// SetCrashLooping marks the process as crash-looping
+func (p *Process) SetCrashLooping(looping bool) {
+	p.mu.Lock()
+	p.crashLooping = looping
+	p.mu.Unlock()
+}
+
+// ClearCrashLoop forgets past failures and re-enables auto-restart
+func (p *Process) ClearCrashLoop() {
+	p.mu.Lock()
+	p.failures = nil
+	p.crashLooping = false
+	p.mu.Unlock()
+}
+
+// PID returns the PID of the running process (0 if not running)
+func (p *Process) PID() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.status.alive() {
+		return 0
+	}
+	if p.container != "" {
+		return p.containerPID
+	}
+	if p.cmd != nil && p.cmd.Process != nil {
+		return p.cmd.Process.Pid
+	}
+	if p.group != nil {
+		// Adopted process
+		return p.group.pid
+	}
+	return 0
+}
+
+// Stats returns the last sampled resource usage
+func (p *Process) Stats() Stats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.stats
+}
+
+// SetStats sets the sampled resource usage
+func (p *Process) SetStats(s Stats) {
+	p.mu.Lock()
+	p.stats = s
+	p.mu.Unlock()
+}
+
 // Uptime returns how long the process has been running
 func (p *Process) Uptime() time.Duration {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
-	if p.status != StatusRunning {
+	if !p.status.alive() {
 		return 0
 	}
 	return time.Since(p.startedAt)
@@ -0,0 +1,391 @@
+//go:build linux
+
+package process
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+func TestProcess_StopKillsDetachedDescendants(t *testing.T) {
+	if _, err := exec.LookPath("setsid"); err != nil {
+		t.Skip("setsid not available")
+	}
+
+	id := config.ServiceID{Project: "app", Service: "api"}
+	outputCh := make(chan OutputLine, 100)
+	p := NewProcess(id, config.Service{Cmd: "setsid sleep 30 & echo $!; wait"}, t.TempDir(), outputCh)
+
+	if err := p.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+
+	// The child's PID is printed once it's running in its own session
+	var pid int
+	timeout := time.After(5 * time.Second)
+	for pid == 0 {
+		select {
+		case line := <-outputCh:
+			if n, err := strconv.Atoi(strings.TrimSpace(line.Line)); err == nil {
+				pid = n
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for child PID")
+		}
+	}
+
+	if err := p.Stop(); err != nil {
+		t.Fatalf("failed to stop: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for syscall.Kill(pid, 0) == nil {
+		if time.Now().After(deadline) {
+			syscall.Kill(pid, syscall.SIGKILL)
+			t.Fatalf("detached child %d survived Stop", pid)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestProcess_PriorityNice(t *testing.T) {
+	id := config.ServiceID{Project: "app", Service: "watcher"}
+	outputCh := make(chan OutputLine, 100)
+	p := NewProcess(id, config.Service{Cmd: "sleep 30", PriorityNice: 10}, t.TempDir(), outputCh)
+
+	if err := p.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	defer p.Stop()
+
+	// The raw syscall returns 20 - nice
+	prio, err := syscall.Getpriority(syscall.PRIO_PROCESS, p.PID())
+	if err != nil {
+		t.Fatalf("getpriority: %v", err)
+	}
+	if nice := 20 - prio; nice != 10 {
+		t.Errorf("expected nice 10, got %d", nice)
+	}
+}
+
+func TestProcess_StartArgv(t *testing.T) {
+	id := config.ServiceID{Project: "app", Service: "api"}
+	outputCh := make(chan OutputLine, 100)
+	// Without a shell, the argument is passed through verbatim
+	p := NewProcess(id, config.Service{Cmd: "echo", Argv: []string{"echo", "$HOME  *"}}, t.TempDir(), outputCh)
+
+	if err := p.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case line := <-outputCh:
+			if line.Line == "$HOME  *" {
+				return
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for verbatim output")
+		}
+	}
+}
+
+func TestProcess_Signal(t *testing.T) {
+	id := config.ServiceID{Project: "app", Service: "api"}
+	outputCh := make(chan OutputLine, 100)
+	p := NewProcess(id, config.Service{Cmd: "trap 'echo reloaded' HUP; echo ready; while :; do sleep 0.1; done"}, t.TempDir(), outputCh)
+
+	if err := p.Signal("SIGHUP"); err == nil {
+		t.Error("expected error signalling a stopped process")
+	}
+
+	if err := p.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	defer p.Stop()
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case line := <-outputCh:
+			switch line.Line {
+			case "ready":
+				if err := p.Signal("SIGHUP"); err != nil {
+					t.Fatalf("failed to send signal: %v", err)
+				}
+			case "reloaded":
+				return
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for the signal to be handled")
+		}
+	}
+}
+
+func TestProcess_ExitHistory(t *testing.T) {
+	id := config.ServiceID{Project: "app", Service: "api"}
+	outputCh := make(chan OutputLine, 100)
+
+	waitDone := func(p *Process) {
+		deadline := time.Now().Add(5 * time.Second)
+		for !p.IsDone() && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	p := NewProcess(id, config.Service{Cmd: "exit 3"}, t.TempDir(), outputCh)
+	if err := p.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	waitDone(p)
+
+	p.Config.Cmd = "kill -KILL $$"
+	p.SetResolved(p.Config)
+	if err := p.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	waitDone(p)
+
+	history := p.ExitHistory()
+	if len(history) != 2 {
+		t.Fatalf("expected 2 exits, got %d", len(history))
+	}
+	// Most recent first
+	if history[0].Signal != "SIGKILL" || history[0].Status != StatusFailed {
+		t.Errorf("expected failed SIGKILL exit, got %+v", history[0])
+	}
+	if history[1].ExitCode != 3 || history[1].Signal != "" || history[1].Status != StatusFailed {
+		t.Errorf("expected failed exit 3, got %+v", history[1])
+	}
+}
+
+func TestManager_ResumeUpgrade(t *testing.T) {
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: "/tmp",
+				Services: map[string]config.Service{
+					"api": {Cmd: "sleep 30"},
+				},
+			},
+		},
+	}
+	m := NewManager(cfg)
+	id := config.ServiceID{Project: "app", Service: "api"}
+
+	// Stand in for a service started by the replaced paraler process
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("sh", "-c", "read line; echo $line; exec sleep 30")
+	cmd.Stdin = strings.NewReader("handed over\n")
+	cmd.Stdout = w
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	defer syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+
+	fd, err := syscall.Dup(int(r.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+
+	path := filepath.Join(t.TempDir(), "upgrade.json")
+	data, _ := json.Marshal([]handoffRecord{{
+		Project:   id.Project,
+		Service:   id.Service,
+		PID:       cmd.Process.Pid,
+		Stdout:    fd,
+		Cmd:       "sleep 30",
+		StartedAt: time.Now(),
+	}})
+	os.WriteFile(path, data, 0644)
+
+	if err := m.ResumeUpgrade(path); err != nil {
+		t.Fatalf("failed to resume: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected handoff file to be removed")
+	}
+
+	p := m.Get(id)
+	if p.Status() != StatusRunning || p.PID() != cmd.Process.Pid {
+		t.Fatalf("expected resumed process %d running, got %s with PID %d", cmd.Process.Pid, p.Status(), p.PID())
+	}
+
+	// Output keeps flowing from the inherited pipe
+	timeout := time.After(5 * time.Second)
+	for found := false; !found; {
+		select {
+		case line := <-m.OutputChannel():
+			found = line.Line == "handed over"
+		case <-timeout:
+			t.Fatal("timed out waiting for output from the resumed process")
+		}
+	}
+
+	if err := p.Stop(); err != nil {
+		t.Fatalf("failed to stop: %v", err)
+	}
+	if p.Status() != StatusStopped {
+		t.Errorf("expected stopped, got %s", p.Status())
+	}
+}
+
+func TestManager_HealthCheckedRightAfterStart(t *testing.T) {
+	cfg := &config.Config{
+		Projects: map[string]config.Project{
+			"app": {
+				Path: "/tmp",
+				Services: map[string]config.Service{
+					// The long interval only applies once the service is healthy
+					"api": {Cmd: "sleep 30", HealthCmd: "exit 0", HealthInterval: time.Hour},
+				},
+			},
+		},
+	}
+	m := NewManager(cfg)
+	m.StartMonitor()
+	defer m.Close()
+
+	p := m.Get(config.ServiceID{Project: "app", Service: "api"})
+	if err := p.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	defer p.Stop()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for p.Health() != HealthHealthy {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected healthy within 3s of starting, got %s", p.Health())
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestProcess_PauseResume(t *testing.T) {
+	id := config.ServiceID{Project: "app", Service: "watcher"}
+	outputCh := make(chan OutputLine, 100)
+	p := NewProcess(id, config.Service{Cmd: "exec sleep 30"}, t.TempDir(), outputCh)
+
+	if err := p.Resume(); err == nil {
+		t.Error("expected error resuming a process that isn't paused")
+	}
+	if err := p.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	defer p.Stop()
+
+	// state is the field after the parenthesized command name
+	state := func() string {
+		data, err := os.ReadFile("/proc/" + strconv.Itoa(p.PID()) + "/stat")
+		if err != nil {
+			t.Fatalf("failed to read process state: %v", err)
+		}
+		fields := strings.Fields(string(data[strings.LastIndexByte(string(data), ')')+1:]))
+		return fields[0]
+	}
+	waitState := func(want string) {
+		deadline := time.Now().Add(2 * time.Second)
+		for state() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected process state %s, got %s", want, state())
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	if err := p.Pause(); err != nil {
+		t.Fatalf("failed to pause: %v", err)
+	}
+	if p.Status() != StatusPaused {
+		t.Errorf("expected paused, got %s", p.Status())
+	}
+	waitState("T")
+
+	if err := p.Resume(); err != nil {
+		t.Fatalf("failed to resume: %v", err)
+	}
+	if p.Status() != StatusRunning {
+		t.Errorf("expected running, got %s", p.Status())
+	}
+	waitState("S")
+
+	// A paused service still stops gracefully, without waiting for the kill
+	if err := p.Pause(); err != nil {
+		t.Fatalf("failed to pause: %v", err)
+	}
+	started := time.Now()
+	if err := p.Stop(); err != nil {
+		t.Fatalf("failed to stop: %v", err)
+	}
+	if p.Status() != StatusStopped {
+		t.Errorf("expected stopped, got %s", p.Status())
+	}
+	if elapsed := time.Since(started); elapsed >= StopGracePeriod {
+		t.Errorf("expected stop without force kill, took %s", elapsed)
+	}
+}
+
+func TestManager_FindOrphansSkipsOtherSessions(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A process named like this binary stands in for another live session
+	// and its service, and a reaped one for a session that crashed
+	session := exec.Command("sh", "-c", "sleep 30; true", filepath.Base(exe))
+	session.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := session.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		session.Process.Kill()
+		session.Wait()
+	}()
+	crashed := exec.Command("true")
+	if err := crashed.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	pid := session.Process.Pid
+	records := []ProcessRecord{
+		{Project: "app", Service: "api", PID: pid, Cmd: "sleep 30", Owner: pid},
+		{Project: "app", Service: "web", PID: pid, Cmd: "sleep 30", Owner: crashed.Process.Pid},
+	}
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := writeStateFile(path, records); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(&config.Config{Projects: map[string]config.Project{}})
+	m.SetStatePath(path)
+	orphans := m.FindOrphans()
+	if len(orphans) != 1 || orphans[0].Service != "web" {
+		t.Errorf("expected only the crashed session's service to be an orphan, got %+v", orphans)
+	}
+
+	// Saving keeps the live session's record
+	stored, err := readStateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 2 {
+		t.Errorf("expected both records to be kept, got %+v", stored)
+	}
+}
@@ -0,0 +1,228 @@
+package process
+
+import (
+	"net"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+func TestProcess_MatchReady(t *testing.T) {
+	id := config.ServiceID{Project: "app", Service: "api"}
+	outputCh := make(chan OutputLine, 10)
+	p := NewProcess(id, config.Service{Cmd: "npm run dev", Ready: `listening on :\d+`}, "/tmp", outputCh)
+	p.setStatus(StatusRunning)
+
+	if !p.HasReadyPattern() {
+		t.Fatal("expected ready pattern to be compiled")
+	}
+
+	p.matchReady("compiling...")
+	if p.IsReady() {
+		t.Error("expected not ready before matching line")
+	}
+
+	p.matchReady("server listening on :3000")
+	if !p.IsReady() {
+		t.Error("expected ready after matching line")
+	}
+
+	p.setStatus(StatusStopped)
+	if p.IsReady() {
+		t.Error("expected stopped process not to be ready")
+	}
+}
+
+func TestProcess_AbortStart(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	id := config.ServiceID{Project: "app", Service: "api"}
+	outputCh := make(chan OutputLine, 100)
+	p := NewProcess(id, config.Service{Cmd: "sleep 10", StartTimeout: time.Second}, t.TempDir(), outputCh)
+
+	if err := p.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+
+	// A stale run must not be aborted
+	p.AbortStart(p.StartedAt().Add(-time.Minute))
+	if !p.IsRunning() {
+		t.Fatal("expected process to keep running after stale abort")
+	}
+
+	p.AbortStart(p.StartedAt())
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !p.IsDone() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if p.Status() != StatusFailed {
+		t.Errorf("expected status failed, got %s", p.Status())
+	}
+}
+
+func TestProcess_MatchTriggers(t *testing.T) {
+	id := config.ServiceID{Project: "app", Service: "web"}
+	outputCh := make(chan OutputLine, 10)
+	p := NewProcess(id, config.Service{
+		Cmd: "npm run dev",
+		Triggers: []config.Trigger{
+			{Match: "heap out of memory", Action: config.TriggerRestart},
+			{Match: `ERROR \d+`, Action: config.TriggerNotify},
+		},
+	}, "/tmp", outputCh)
+
+	var fired []string
+	p.SetTriggerHandler(func(tr config.Trigger, line string) {
+		fired = append(fired, tr.Action)
+	})
+
+	p.matchTriggers("compiled successfully")
+	p.matchTriggers("FATAL ERROR: JavaScript heap out of memory")
+	// Cooling down, so a repeated line doesn't fire again
+	p.matchTriggers("FATAL ERROR: JavaScript heap out of memory")
+	p.matchTriggers("ERROR 42")
+
+	expected := []string{config.TriggerRestart, config.TriggerNotify}
+	if len(fired) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, fired)
+	}
+	for i := range expected {
+		if fired[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, fired)
+		}
+	}
+}
+
+func TestProcess_ReconfigureWhileMatchingOutput(t *testing.T) {
+	id := config.ServiceID{Project: "app", Service: "web"}
+	outputCh := make(chan OutputLine, 10)
+	p := NewProcess(id, config.Service{Cmd: "npm run dev", Ready: "ready"}, "/tmp", outputCh)
+	p.SetTriggerHandler(func(config.Trigger, string) {})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			p.matchReady("not yet")
+			p.matchTriggers("ERROR 42")
+		}
+	}()
+
+	// Run with -race: a reload swaps the pattern and triggers under the
+	// lock while output is matched against them
+	for i := 0; i < 200; i++ {
+		p.Reconfigure(config.Service{
+			Cmd:      "npm run dev",
+			Ready:    "listening",
+			Triggers: []config.Trigger{{Match: `ERROR \d+`, Action: config.TriggerNotify}},
+		}, "/tmp")
+		p.applyPendingConfig()
+	}
+	<-done
+}
+
+func TestProcess_WaitForTargets(t *testing.T) {
+	// Reserve a port, then free it so nothing listens there yet
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	id := config.ServiceID{Project: "app", Service: "api"}
+	outputCh := make(chan OutputLine, 100)
+
+	p := NewProcess(id, config.Service{Cmd: "true", WaitFor: []string{addr}, WaitForTimeout: 300 * time.Millisecond}, "/tmp", outputCh)
+	if err := p.Start(); err == nil {
+		t.Fatal("expected timeout with nothing listening")
+	}
+	if p.Status() != StatusFailed {
+		t.Errorf("expected status failed, got %s", p.Status())
+	}
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		if ln, err := net.Listen("tcp", addr); err == nil {
+			defer ln.Close()
+			time.Sleep(3 * time.Second)
+		}
+	}()
+
+	p = NewProcess(id, config.Service{Cmd: "true", WaitFor: []string{addr}, WaitForTimeout: 5 * time.Second}, "/tmp", outputCh)
+	if err := p.Start(); err != nil {
+		t.Fatalf("expected %s to become reachable: %v", addr, err)
+	}
+}
+
+func TestProcess_StopWhileWaitingForTargets(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	id := config.ServiceID{Project: "app", Service: "api"}
+	outputCh := make(chan OutputLine, 100)
+	p := NewProcess(id, config.Service{Cmd: "sleep 30", WaitFor: []string{addr}, WaitForTimeout: 10 * time.Second}, "/tmp", outputCh)
+
+	started := make(chan error, 1)
+	go func() { started <- p.Start() }()
+	for p.Status() != StatusStarting {
+		time.Sleep(time.Millisecond)
+	}
+	// Stop as soon as the status is starting must cancel the wait
+	if err := p.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	select {
+	case err := <-started:
+		if err == nil {
+			t.Fatal("expected Start to be cancelled")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Stop to cancel the wait")
+	}
+	if p.Status() != StatusStopped {
+		t.Errorf("expected status stopped, got %s", p.Status())
+	}
+}
+
+func TestProcess_StopWhileStarting(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	id := config.ServiceID{Project: "app", Service: "api"}
+	outputCh := make(chan OutputLine, 1000)
+	for i := 0; i < 3; i++ {
+		p := NewProcess(id, config.Service{Cmd: "sleep 30", WaitFor: []string{ln.Addr().String()}}, "/tmp", outputCh)
+
+		started := make(chan struct{})
+		go func() {
+			p.Start()
+			close(started)
+		}()
+		for p.Status() == StatusStopped {
+			time.Sleep(time.Millisecond)
+		}
+		// Whatever the start got to, the service must not come up
+		if err := p.Stop(); err != nil {
+			t.Fatalf("Stop: %v", err)
+		}
+		<-started
+		if p.IsRunning() {
+			p.Stop()
+			t.Fatalf("expected the service to be stopped, got %s", p.Status())
+		}
+	}
+}
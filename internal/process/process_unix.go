@@ -0,0 +1,218 @@
+//go:build !windows
+
+package process
+
+import (
+	"fmt"
+	"os/exec"
+	"sync"
+	"syscall"
+)
+
+// defaultShell is the shell used to run service commands
+const defaultShell = "sh"
+
+// setProcAttr puts the command in its own process group so the whole tree
+// can be signalled at once
+func setProcAttr(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+	}
+}
+
+// setShellCmdLine does nothing: shells parse the arguments exec passes as is
+func setShellCmdLine(cmd *exec.Cmd) {}
+
+// OutlivesParaler is true where services keep running when paraler exits
+// without stopping them, to be adopted or handed over by an in-place upgrade
+const OutlivesParaler = true
+
+// Signals lists the signals that can be sent to a service by name
+var Signals = []string{"SIGHUP", "SIGUSR1", "SIGUSR2", "SIGINT", "SIGQUIT"}
+
+// signalsByName maps the names accepted by Signal to signals
+var signalsByName = map[string]syscall.Signal{
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGINT":  syscall.SIGINT,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGUSR1": syscall.SIGUSR1,
+	"SIGUSR2": syscall.SIGUSR2,
+	"SIGTERM": syscall.SIGTERM,
+	"SIGKILL": syscall.SIGKILL,
+}
+
+// exitSignal returns the name of the signal that killed a process, given
+// the error returned by cmd.Wait, or "" if it wasn't killed by a signal
+func exitSignal(err error) string {
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		return ""
+	}
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return ""
+	}
+
+	sig := status.Signal()
+	for name, s := range signalsByName {
+		if s == sig {
+			return name
+		}
+	}
+	return sig.String()
+}
+
+// processGroup tracks the process group of a started command, plus any
+// descendants that left the group (e.g. by calling setsid)
+type processGroup struct {
+	pid  int
+	pgid int
+
+	mu          sync.Mutex
+	descendants map[int]bool // seen when signalling, may have left the group
+}
+
+// newProcessGroup attaches to the process group of a started command.
+// Setpgid makes the command its own group leader, so pgid == pid.
+func newProcessGroup(cmd *exec.Cmd) (*processGroup, error) {
+	return adoptProcessGroup(cmd.Process.Pid), nil
+}
+
+// adoptProcessGroup attaches to the group of a process started by a
+// previous paraler session
+func adoptProcessGroup(pid int) *processGroup {
+	return &processGroup{pid: pid, pgid: pid, descendants: make(map[int]bool)}
+}
+
+// Terminate asks every process in the tree to exit (SIGTERM)
+func (g *processGroup) Terminate() error {
+	return g.signal(syscall.SIGTERM)
+}
+
+// Kill forcefully kills every process in the tree (SIGKILL)
+func (g *processGroup) Kill() error {
+	return g.signal(syscall.SIGKILL)
+}
+
+// Pause freezes every process in the tree (SIGSTOP)
+func (g *processGroup) Pause() error {
+	return g.signal(syscall.SIGSTOP)
+}
+
+// Resume continues every process in the tree (SIGCONT)
+func (g *processGroup) Resume() error {
+	return g.signal(syscall.SIGCONT)
+}
+
+// Signal sends a signal, given by name, to every process in the group
+func (g *processGroup) Signal(name string) error {
+	sig, ok := signalsByName[name]
+	if !ok {
+		return fmt.Errorf("unknown signal %s", name)
+	}
+	return syscall.Kill(-g.pgid, sig)
+}
+
+// SetPriority sets the niceness of every process in the group. Children
+// spawned later inherit it.
+func (g *processGroup) SetPriority(nice int) error {
+	if err := syscall.Setpriority(syscall.PRIO_PGRP, g.pgid, nice); err != nil {
+		return fmt.Errorf("setpriority: %w", err)
+	}
+	return setIOPriority(g.pgid, nice)
+}
+
+// Alive returns true if any process in the group, or any descendant seen
+// outside it, is still running
+func (g *processGroup) Alive() bool {
+	if alive(-g.pgid) {
+		return true
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for pid := range g.descendants {
+		if alive(pid) {
+			return true
+		}
+	}
+	return false
+}
+
+// Close releases resources held by the group
+func (g *processGroup) Close() {}
+
+// signal sends a signal to the whole process group and to every descendant,
+// so children that moved to their own session or group are reached too
+func (g *processGroup) signal(sig syscall.Signal) error {
+	pids := g.refreshDescendants()
+
+	err := syscall.Kill(-g.pgid, sig)
+	for _, pid := range pids {
+		if syscall.Kill(pid, sig) == nil && err == syscall.ESRCH {
+			// The group is gone but stray descendants were reached
+			err = nil
+		}
+	}
+	return err
+}
+
+// refreshDescendants walks the process table for descendants of the root
+// and of those seen earlier (whose children are reparented once they die),
+// and returns all that are still running.
+// Descendants are collected before signalling, since killing a parent
+// reparents its children and loses the link to the tree.
+func (g *processGroup) refreshDescendants() []int {
+	parents, err := listParents()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err != nil {
+		return g.knownDescendants()
+	}
+
+	children := make(map[int][]int)
+	for pid, ppid := range parents {
+		children[ppid] = append(children[ppid], pid)
+	}
+
+	// Forget descendants that exited, so reused PIDs aren't signalled
+	for pid := range g.descendants {
+		if _, ok := parents[pid]; !ok {
+			delete(g.descendants, pid)
+		}
+	}
+
+	queue := []int{g.pid}
+	for pid := range g.descendants {
+		queue = append(queue, pid)
+	}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		for _, child := range children[pid] {
+			if !g.descendants[child] {
+				g.descendants[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	return g.knownDescendants()
+}
+
+// knownDescendants returns the descendants seen so far; g.mu must be held
+func (g *processGroup) knownDescendants() []int {
+	pids := make([]int, 0, len(g.descendants))
+	for pid := range g.descendants {
+		pids = append(pids, pid)
+	}
+	return pids
+}
+
+// alive returns true if a process (or process group, for negative pid) exists
+func alive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
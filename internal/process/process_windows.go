@@ -0,0 +1,255 @@
+//go:build windows
+
+package process
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// defaultShell is the shell used to run service commands
+const defaultShell = "cmd.exe"
+
+// setProcAttr starts the command in a new process group so console
+// control events don't propagate to paraler itself. It starts suspended,
+// so newProcessGroup can put it in its Job Object before it runs.
+func setProcAttr(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: windows.CREATE_NEW_PROCESS_GROUP | windows.CREATE_SUSPENDED,
+	}
+}
+
+// setShellCmdLine passes the command line of a cmd.exe /C command through
+// verbatim: cmd.exe doesn't parse its arguments with the quoting rules
+// exec escapes them for, which breaks commands containing quotes
+func setShellCmdLine(cmd *exec.Cmd) {
+	if len(cmd.Args) != 3 || shellName(cmd.Args[0]) != "cmd" || !strings.EqualFold(cmd.Args[1], "/C") {
+		return
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	// With /S, cmd.exe strips the outer quotes and runs the rest as is
+	cmd.SysProcAttr.CmdLine = syscall.EscapeArg(cmd.Args[0]) + ` /S /C "` + cmd.Args[2] + `"`
+}
+
+// OutlivesParaler is false on Windows: every service runs in a Job Object
+// that kills it when paraler exits, so no service is ever left running to
+// be adopted or handed over by an in-place upgrade
+const OutlivesParaler = false
+
+// Signals lists the signals that can be sent to a service by name. Windows
+// has no equivalent of SIGHUP/SIGUSR1/SIGUSR2.
+var Signals []string
+
+// exitSignal returns "" since Windows processes aren't killed by signals
+func exitSignal(err error) string {
+	return ""
+}
+
+// processGroup tracks a started command and its descendants via a Job Object
+type processGroup struct {
+	pid int
+	job windows.Handle
+}
+
+// jobs maps the root PIDs of process groups to their Job Objects, for
+// reading their resource usage
+var jobs = struct {
+	sync.Mutex
+	byPID map[int]windows.Handle
+}{byPID: make(map[int]windows.Handle)}
+
+// newProcessGroup creates a Job Object, assigns the command started
+// suspended by setProcAttr to it and resumes it, so every child it spawns
+// inherits the job
+func newProcessGroup(cmd *exec.Cmd) (*processGroup, error) {
+	g := &processGroup{pid: cmd.Process.Pid}
+	err := g.assignJob()
+	if resumeErr := resumeProcess(g.pid); resumeErr != nil {
+		// A process that stays suspended would never exit
+		cmd.Process.Kill()
+		return g, fmt.Errorf("failed to resume process: %w", resumeErr)
+	}
+	return g, err
+}
+
+// assignJob creates the group's Job Object and assigns its process to it
+func (g *processGroup) assignJob() error {
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create job object: %w", err)
+	}
+
+	// Kill the whole tree if paraler itself goes away
+	info := windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION{
+		BasicLimitInformation: windows.JOBOBJECT_BASIC_LIMIT_INFORMATION{
+			LimitFlags: windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE,
+		},
+	}
+	if _, err := windows.SetInformationJobObject(
+		job,
+		windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&info)),
+		uint32(unsafe.Sizeof(info)),
+	); err != nil {
+		windows.CloseHandle(job)
+		return fmt.Errorf("failed to configure job object: %w", err)
+	}
+
+	proc, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(g.pid))
+	if err != nil {
+		windows.CloseHandle(job)
+		return fmt.Errorf("failed to open process: %w", err)
+	}
+	defer windows.CloseHandle(proc)
+
+	if err := windows.AssignProcessToJobObject(job, proc); err != nil {
+		windows.CloseHandle(job)
+		return fmt.Errorf("failed to assign process to job: %w", err)
+	}
+
+	g.job = job
+	jobs.Lock()
+	jobs.byPID[g.pid] = job
+	jobs.Unlock()
+	return nil
+}
+
+// resumeProcess resumes the threads of a process started suspended
+func resumeProcess(pid int) error {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPTHREAD, 0)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(snapshot)
+
+	resumed := false
+	entry := windows.ThreadEntry32{Size: uint32(unsafe.Sizeof(windows.ThreadEntry32{}))}
+	for err := windows.Thread32First(snapshot, &entry); err == nil; err = windows.Thread32Next(snapshot, &entry) {
+		if entry.OwnerProcessID != uint32(pid) {
+			continue
+		}
+		thread, err := windows.OpenThread(windows.THREAD_SUSPEND_RESUME, false, entry.ThreadID)
+		if err != nil {
+			return err
+		}
+		_, err = windows.ResumeThread(thread)
+		windows.CloseHandle(thread)
+		if err != nil {
+			return err
+		}
+		resumed = true
+	}
+	if !resumed {
+		return fmt.Errorf("no threads found")
+	}
+	return nil
+}
+
+// adoptProcessGroup attaches to a process started by a previous paraler
+// session. Its job handle is gone, so the tree is managed via taskkill.
+func adoptProcessGroup(pid int) *processGroup {
+	return &processGroup{pid: pid}
+}
+
+// Terminate asks the process tree to exit. Windows has no SIGTERM, so this
+// uses taskkill without /F, which sends a close request to the tree.
+func (g *processGroup) Terminate() error {
+	return exec.Command("taskkill", "/T", "/PID", strconv.Itoa(g.pid)).Run()
+}
+
+// Kill forcefully terminates every process in the job
+func (g *processGroup) Kill() error {
+	if g.job != 0 {
+		return windows.TerminateJobObject(g.job, 1)
+	}
+	return exec.Command("taskkill", "/F", "/T", "/PID", strconv.Itoa(g.pid)).Run()
+}
+
+// Signal is not supported on Windows
+func (g *processGroup) Signal(name string) error {
+	return fmt.Errorf("sending %s is not supported on Windows", name)
+}
+
+// Pause is not supported on Windows
+func (g *processGroup) Pause() error {
+	return fmt.Errorf("pausing is not supported on Windows")
+}
+
+// Resume is not supported on Windows
+func (g *processGroup) Resume() error {
+	return fmt.Errorf("pausing is not supported on Windows")
+}
+
+// SetPriority maps a Unix niceness to a Windows priority class and applies
+// it to the root process. Children inherit below-normal classes.
+func (g *processGroup) SetPriority(nice int) error {
+	proc, err := windows.OpenProcess(windows.PROCESS_SET_INFORMATION, false, uint32(g.pid))
+	if err != nil {
+		return fmt.Errorf("failed to open process: %w", err)
+	}
+	defer windows.CloseHandle(proc)
+
+	var class uint32
+	switch {
+	case nice >= 15:
+		class = windows.IDLE_PRIORITY_CLASS
+	case nice > 0:
+		class = windows.BELOW_NORMAL_PRIORITY_CLASS
+	case nice <= -15:
+		class = windows.HIGH_PRIORITY_CLASS
+	case nice < 0:
+		class = windows.ABOVE_NORMAL_PRIORITY_CLASS
+	default:
+		class = windows.NORMAL_PRIORITY_CLASS
+	}
+
+	if err := windows.SetPriorityClass(proc, class); err != nil {
+		return fmt.Errorf("failed to set priority class: %w", err)
+	}
+	return nil
+}
+
+// stillActive is the exit code GetExitCodeProcess reports for a live process
+const stillActive = 259
+
+// Alive returns true if the root process is still running
+func (g *processGroup) Alive() bool {
+	return alive(g.pid)
+}
+
+// alive returns true if a process exists and hasn't exited
+func alive(pid int) bool {
+	proc, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(proc)
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(proc, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
+
+// Close releases the job handle
+func (g *processGroup) Close() {
+	if g.job != 0 {
+		jobs.Lock()
+		if jobs.byPID[g.pid] == g.job {
+			delete(jobs.byPID, g.pid)
+		}
+		windows.CloseHandle(g.job)
+		jobs.Unlock()
+		g.job = 0
+	}
+}
@@ -0,0 +1,118 @@
+package process
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+// ProcessRecord describes a service process started by paraler. Records are
+// persisted so a later session can find processes left behind by a crash.
+type ProcessRecord struct {
+	Project   string    `json:"project"`
+	Service   string    `json:"service"`
+	Instance  int       `json:"instance,omitempty"`
+	PID       int       `json:"pid"`
+	Cmd       string    `json:"cmd"`
+	StartedAt time.Time `json:"started_at"`
+	// PID of the paraler session that started the process
+	Owner int `json:"owner,omitempty"`
+}
+
+// ServiceID returns the ID of the service the record belongs to
+func (r ProcessRecord) ServiceID() config.ServiceID {
+	return config.ServiceID{Project: r.Project, Service: r.Service, Instance: r.Instance}
+}
+
+// DefaultStatePath returns the state file used for a config file. Each
+// config gets its own file; sessions sharing a config keep each other's
+// records.
+func DefaultStatePath(configPath string) string {
+	return filepath.Join(config.StateDir(), "state-"+config.StateKey(configPath)+".json")
+}
+
+// readStateFile reads process records from a state file
+func readStateFile(path string) ([]ProcessRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var records []ProcessRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// writeStateFile writes process records to a state file, removing it when
+// there is nothing to track
+func writeStateFile(path string, records []ProcessRecord) error {
+	if len(records) == 0 {
+		err := os.Remove(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write atomically so a crash mid-write can't corrupt the file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// isOrphan returns true if the recorded process is still running and looks
+// like the command we started (guards against PID reuse)
+func isOrphan(r ProcessRecord) bool {
+	if r.PID <= 0 || r.PID == os.Getpid() {
+		return false
+	}
+	// Processes of another session still running are that session's
+	if ownedByOtherSession(r) {
+		return false
+	}
+	if !adoptProcessGroup(r.PID).Alive() {
+		return false
+	}
+
+	// The group leader may have exited while children live on, in which
+	// case there is no command line to compare against
+	if cmdline := getCommandLine(r.PID); cmdline != "" && !strings.Contains(cmdline, r.Cmd) {
+		return false
+	}
+	return true
+}
+
+// ownedByOtherSession returns true if the record was written by another
+// paraler session that is still running. Its command line must name the
+// same executable, in case the session's PID was reused.
+func ownedByOtherSession(r ProcessRecord) bool {
+	if r.Owner <= 0 || r.Owner == os.Getpid() || !alive(r.Owner) {
+		return false
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return true
+	}
+	cmdline := getCommandLine(r.Owner)
+	return cmdline == "" || strings.Contains(cmdline, filepath.Base(exe))
+}
@@ -0,0 +1,86 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "state.json")
+
+	records := []ProcessRecord{
+		{Project: "app", Service: "api", PID: 1234, Cmd: "npm run dev", StartedAt: time.Unix(1700000000, 0).UTC()},
+		{Project: "app", Service: "web", PID: 5678, Cmd: "npm start"},
+	}
+
+	if err := writeStateFile(path, records); err != nil {
+		t.Fatalf("failed to write state: %v", err)
+	}
+
+	loaded, err := readStateFile(path)
+	if err != nil {
+		t.Fatalf("failed to read state: %v", err)
+	}
+	if len(loaded) != len(records) {
+		t.Fatalf("expected %d records, got %d", len(records), len(loaded))
+	}
+	for i := range records {
+		if loaded[i] != records[i] {
+			t.Errorf("record %d: expected %+v, got %+v", i, records[i], loaded[i])
+		}
+	}
+
+	// Writing nothing removes the file
+	if err := writeStateFile(path, nil); err != nil {
+		t.Fatalf("failed to clear state: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected state file to be removed, got %v", err)
+	}
+
+	loaded, err = readStateFile(path)
+	if err != nil || loaded != nil {
+		t.Errorf("expected no records for missing file, got %v (%v)", loaded, err)
+	}
+}
+
+func TestDefaultStatePath(t *testing.T) {
+	a := DefaultStatePath("/a/config.yaml")
+	b := DefaultStatePath("/b/config.yaml")
+
+	if a == b {
+		t.Errorf("expected different state files per config, got %q for both", a)
+	}
+	if a != DefaultStatePath("/a/config.yaml") {
+		t.Error("expected state path to be stable")
+	}
+}
+
+func TestIsOrphan(t *testing.T) {
+	tests := []struct {
+		name     string
+		record   ProcessRecord
+		expected bool
+	}{
+		{
+			name:     "no pid",
+			record:   ProcessRecord{PID: 0},
+			expected: false,
+		},
+		{
+			name:     "own pid",
+			record:   ProcessRecord{PID: os.Getpid()},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isOrphan(tt.record); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
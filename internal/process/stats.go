@@ -0,0 +1,89 @@
+package process
+
+import (
+	"sync"
+	"time"
+)
+
+// Stats holds resource usage for a process tree
+type Stats struct {
+	CPU float64 // Percent of a single core
+	RSS uint64  // Resident memory in bytes
+}
+
+// cpuSample is a cumulative CPU time reading for a process tree
+type cpuSample struct {
+	cpuTime time.Duration
+	at      time.Time
+}
+
+// StatsCollector samples CPU and memory usage of process groups
+type StatsCollector struct {
+	mu   sync.Mutex
+	prev map[int]cpuSample // key: process group leader PID
+}
+
+// NewStatsCollector creates a new stats collector
+func NewStatsCollector() *StatsCollector {
+	return &StatsCollector{
+		prev: make(map[int]cpuSample),
+	}
+}
+
+// Collect samples usage for the given process group leaders.
+// CPU is computed from the delta since the previous sample, so the first
+// reading for a group reports 0%.
+func (c *StatsCollector) Collect(pids []int) map[int]Stats {
+	result := make(map[int]Stats, len(pids))
+	if len(pids) == 0 {
+		return result
+	}
+
+	usage, err := readGroupUsage(pids)
+	if err != nil {
+		return result
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	seen := make(map[int]bool, len(pids))
+	for _, pid := range pids {
+		u, ok := usage[pid]
+		if !ok {
+			continue
+		}
+		seen[pid] = true
+
+		stats := Stats{RSS: u.rss, CPU: u.cpuPercent}
+		if u.cpuTime > 0 {
+			if prev, ok := c.prev[pid]; ok {
+				elapsed := now.Sub(prev.at)
+				delta := u.cpuTime - prev.cpuTime
+				if elapsed > 0 && delta >= 0 {
+					stats.CPU = float64(delta) / float64(elapsed) * 100
+				}
+			}
+			c.prev[pid] = cpuSample{cpuTime: u.cpuTime, at: now}
+		}
+		result[pid] = stats
+	}
+
+	// Forget groups that are gone
+	for pid := range c.prev {
+		if !seen[pid] {
+			delete(c.prev, pid)
+		}
+	}
+
+	return result
+}
+
+// groupUsage is a raw usage reading for a process group.
+// Platforms report either cumulative cpuTime or an instantaneous cpuPercent.
+type groupUsage struct {
+	cpuTime    time.Duration
+	cpuPercent float64
+	rss        uint64
+}
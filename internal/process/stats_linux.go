@@ -0,0 +1,78 @@
+//go:build linux
+
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicks is the kernel USER_HZ used for /proc CPU times
+const clockTicks = 100
+
+// readGroupUsage sums CPU time and RSS of all processes in each group from /proc
+func readGroupUsage(pgids []int) (map[int]groupUsage, error) {
+	wanted := make(map[int]bool, len(pgids))
+	for _, pgid := range pgids {
+		wanted[pgid] = true
+	}
+
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := uint64(os.Getpagesize())
+	usage := make(map[int]groupUsage)
+
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+
+		pgrp, cpuTime, rssPages, ok := parseProcStat(string(data))
+		if !ok || !wanted[pgrp] {
+			continue
+		}
+
+		u := usage[pgrp]
+		u.cpuTime += cpuTime
+		u.rss += rssPages * pageSize
+		usage[pgrp] = u
+	}
+
+	return usage, nil
+}
+
+// parseProcStat reads the process group, CPU time (user and system) and
+// resident pages from the contents of /proc/<pid>/stat
+func parseProcStat(stat string) (pgrp int, cpuTime time.Duration, rssPages uint64, ok bool) {
+	// Fields after the command name, which may contain spaces: "pid (comm) state ppid pgrp ..."
+	idx := strings.LastIndexByte(stat, ')')
+	if idx < 0 {
+		return 0, 0, 0, false
+	}
+	fields := strings.Fields(stat[idx+1:])
+	if len(fields) < 22 {
+		return 0, 0, 0, false
+	}
+
+	pgrp, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return 0, 0, 0, false
+	}
+
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	rssPages, _ = strconv.ParseUint(fields[21], 10, 64)
+
+	return pgrp, time.Duration(utime+stime) * time.Second / clockTicks, rssPages, true
+}
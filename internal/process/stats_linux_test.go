@@ -0,0 +1,49 @@
+//go:build linux
+
+package process
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestParseProcStat(t *testing.T) {
+	// A command name with spaces and a parenthesis, as node workers have
+	stat := "4242 (node (worker) 1) S 4200 4100 4100 0 -1 4194560 2630 0 0 0 " +
+		"150 50 0 0 20 0 11 0 123456 1234567890 3072 18446744073709551615 1 1 0 0 0 0 0 4096 17410 0 0 0 17 3 0 0 0 0 0\n"
+
+	pgrp, cpuTime, rssPages, ok := parseProcStat(stat)
+	if !ok {
+		t.Fatal("expected the stat line to parse")
+	}
+	if pgrp != 4100 {
+		t.Errorf("expected process group 4100, got %d", pgrp)
+	}
+	if cpuTime != 2*time.Second {
+		t.Errorf("expected 2s of CPU time, got %s", cpuTime)
+	}
+	if rssPages != 3072 {
+		t.Errorf("expected 3072 resident pages, got %d", rssPages)
+	}
+
+	for _, bad := range []string{"", "4242 node S 4200", "4242 (node) S 4200 4100"} {
+		if _, _, _, ok := parseProcStat(bad); ok {
+			t.Errorf("expected %q not to parse", bad)
+		}
+	}
+}
+
+func TestStatsCollector_OwnGroup(t *testing.T) {
+	pgrp, err := syscall.Getpgid(os.Getpid())
+	if err != nil {
+		t.Fatalf("Getpgid: %v", err)
+	}
+
+	c := NewStatsCollector()
+	stats := c.Collect([]int{pgrp})
+	if stats[pgrp].RSS == 0 {
+		t.Errorf("expected the test's own process group to use memory, got %+v", stats)
+	}
+}
@@ -0,0 +1,46 @@
+//go:build !linux && !windows
+
+package process
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// readGroupUsage sums %CPU and RSS of all processes in each group using ps
+func readGroupUsage(pgids []int) (map[int]groupUsage, error) {
+	wanted := make(map[int]bool, len(pgids))
+	for _, pgid := range pgids {
+		wanted[pgid] = true
+	}
+
+	// ps -A -o pgid=,rss=,%cpu= (rss in KiB)
+	output, err := exec.Command("ps", "-A", "-o", "pgid=,rss=,%cpu=").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[int]groupUsage)
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+
+		pgid, err := strconv.Atoi(fields[0])
+		if err != nil || !wanted[pgid] {
+			continue
+		}
+
+		rss, _ := strconv.ParseUint(fields[1], 10, 64)
+		cpu, _ := strconv.ParseFloat(fields[2], 64)
+
+		u := usage[pgid]
+		u.rss += rss * 1024
+		u.cpuPercent += cpu
+		usage[pgid] = u
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,129 @@
+//go:build windows
+
+package process
+
+import (
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// procGetProcessMemoryInfo reads a process's working set
+var procGetProcessMemoryInfo = windows.NewLazySystemDLL("kernel32.dll").NewProc("K32GetProcessMemoryInfo")
+
+// processMemoryCounters is PROCESS_MEMORY_COUNTERS
+type processMemoryCounters struct {
+	cb                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
+}
+
+// jobAccounting is JOBOBJECT_BASIC_ACCOUNTING_INFORMATION
+type jobAccounting struct {
+	TotalUserTime             int64
+	TotalKernelTime           int64
+	ThisPeriodTotalUserTime   int64
+	ThisPeriodTotalKernelTime int64
+	TotalPageFaultCount       uint32
+	TotalProcesses            uint32
+	ActiveProcesses           uint32
+	TotalTerminatedProcesses  uint32
+}
+
+// maxJobProcesses bounds the processes of a job whose memory is summed
+const maxJobProcesses = 512
+
+// jobProcessList is JOBOBJECT_BASIC_PROCESS_ID_LIST with room for
+// maxJobProcesses processes
+type jobProcessList struct {
+	NumberOfAssignedProcesses uint32
+	NumberOfProcessIdsInList  uint32
+	ProcessIdList             [maxJobProcesses]uintptr
+}
+
+// readGroupUsage reads CPU time and working sets from each group's Job
+// Object. CPU time includes processes of the job that already exited, as
+// it only grows. Processes adopted from a previous session have no job,
+// so only their root process is counted.
+func readGroupUsage(pids []int) (map[int]groupUsage, error) {
+	usage := make(map[int]groupUsage, len(pids))
+
+	jobs.Lock()
+	defer jobs.Unlock()
+
+	for _, pid := range pids {
+		job, ok := jobs.byPID[pid]
+		if !ok {
+			if u, ok := processUsage(pid); ok {
+				usage[pid] = u
+			}
+			continue
+		}
+
+		var accounting jobAccounting
+		if err := windows.QueryInformationJobObject(job, windows.JobObjectBasicAccountingInformation,
+			uintptr(unsafe.Pointer(&accounting)), uint32(unsafe.Sizeof(accounting)), nil); err != nil {
+			continue
+		}
+		// Job times are in 100ns units
+		u := groupUsage{cpuTime: time.Duration(accounting.TotalUserTime+accounting.TotalKernelTime) * 100}
+
+		// A full list reports ERROR_MORE_DATA, with as many IDs as fit
+		var list jobProcessList
+		windows.QueryInformationJobObject(job, windows.JobObjectBasicProcessIdList,
+			uintptr(unsafe.Pointer(&list)), uint32(unsafe.Sizeof(list)), nil)
+		for _, member := range list.ProcessIdList[:min(list.NumberOfProcessIdsInList, maxJobProcesses)] {
+			if rss, ok := workingSet(int(member)); ok {
+				u.rss += rss
+			}
+		}
+		usage[pid] = u
+	}
+
+	return usage, nil
+}
+
+// processUsage reads the CPU time and working set of a single process
+func processUsage(pid int) (groupUsage, bool) {
+	proc, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return groupUsage{}, false
+	}
+	defer windows.CloseHandle(proc)
+
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(proc, &creation, &exit, &kernel, &user); err != nil {
+		return groupUsage{}, false
+	}
+	u := groupUsage{cpuTime: time.Duration(filetimeTicks(kernel)+filetimeTicks(user)) * 100}
+	u.rss, _ = workingSet(pid)
+	return u, true
+}
+
+// filetimeTicks returns a FILETIME duration in 100ns units
+func filetimeTicks(t windows.Filetime) int64 {
+	return int64(t.HighDateTime)<<32 | int64(t.LowDateTime)
+}
+
+// workingSet returns the resident memory of a process in bytes
+func workingSet(pid int) (uint64, bool) {
+	proc, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return 0, false
+	}
+	defer windows.CloseHandle(proc)
+
+	counters := processMemoryCounters{cb: uint32(unsafe.Sizeof(processMemoryCounters{}))}
+	if ok, _, _ := procGetProcessMemoryInfo.Call(uintptr(proc), uintptr(unsafe.Pointer(&counters)), uintptr(counters.cb)); ok == 0 {
+		return 0, false
+	}
+	return uint64(counters.WorkingSetSize), true
+}
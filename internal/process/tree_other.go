@@ -0,0 +1,37 @@
+//go:build !linux && !windows
+
+package process
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// listParents returns the parent PID of every process, read using ps
+func listParents() (map[int]int, error) {
+	output, err := exec.Command("ps", "-A", "-o", "pid=,ppid=").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	parents := make(map[int]int)
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		ppid, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		parents[pid] = ppid
+	}
+
+	return parents, nil
+}
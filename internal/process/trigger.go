@@ -0,0 +1,135 @@
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+	"github.com/paralerdev/paraler/internal/notify"
+)
+
+// TriggerCooldown is the minimum time between two firings of the same
+// trigger, so a burst of matching lines runs its action once
+const TriggerCooldown = 10 * time.Second
+
+// TriggerCommandTimeout is how long a trigger's run command may take before
+// it is killed, so a hung command can't pile up behind repeated firings
+const TriggerCommandTimeout = 5 * time.Minute
+
+// TriggerHandler is called when an output line matches a trigger
+type TriggerHandler func(t config.Trigger, line string)
+
+// trigger is a config.Trigger with its pattern compiled
+type trigger struct {
+	config.Trigger
+	pattern   *regexp.Regexp
+	lastFired time.Time
+}
+
+// compileTriggers compiles the patterns of a service's triggers.
+// Patterns are validated when the config is loaded.
+func compileTriggers(triggers []config.Trigger) []*trigger {
+	var compiled []*trigger
+	for _, t := range triggers {
+		pattern, err := regexp.Compile(t.Match)
+		if err != nil {
+			continue
+		}
+		compiled = append(compiled, &trigger{Trigger: t, pattern: pattern})
+	}
+	return compiled
+}
+
+// SetTriggerHandler sets the function called when output matches a trigger
+func (p *Process) SetTriggerHandler(h TriggerHandler) {
+	p.mu.Lock()
+	p.onTrigger = h
+	p.mu.Unlock()
+}
+
+// matchTriggers fires every trigger whose pattern matches the line and
+// isn't cooling down
+func (p *Process) matchTriggers(line string) {
+	var fired []config.Trigger
+
+	p.mu.Lock()
+	if len(p.triggers) == 0 {
+		p.mu.Unlock()
+		return
+	}
+	handler := p.onTrigger
+	now := time.Now()
+	for _, t := range p.triggers {
+		if now.Sub(t.lastFired) < TriggerCooldown || !t.pattern.MatchString(line) {
+			continue
+		}
+		t.lastFired = now
+		fired = append(fired, t.Trigger)
+	}
+	p.mu.Unlock()
+
+	if handler == nil {
+		return
+	}
+	for _, t := range fired {
+		handler(t, line)
+	}
+}
+
+// handleTrigger runs the action of a trigger that matched a service's output
+func (m *Manager) handleTrigger(proc *Process, t config.Trigger, line string) {
+	proc.emitSystemMessage(fmt.Sprintf("⚡ Trigger %q matched, running %s", t.Match, t.Action))
+
+	switch t.Action {
+	case config.TriggerRestart:
+		go m.Restart(proc.ID)
+	case config.TriggerRun:
+		go m.runTriggerCommand(proc, t.Run)
+	case config.TriggerNotify:
+		go notify.Send(proc.ID.String(), line)
+	}
+}
+
+// runTriggerCommand runs a trigger's command in the service's directory and
+// shows its output in the service's logs
+func (m *Manager) runTriggerCommand(proc *Process, cmdline string) {
+	ctx, cancel := context.WithTimeout(context.Background(), TriggerCommandTimeout)
+	defer cancel()
+	go func() {
+		select {
+		case <-m.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	args := shellArgs(proc.Config.Shell, cmdline)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	setShellCmdLine(cmd)
+	cmd.Dir = proc.Cwd
+	cmd.Env = append(cmd.Environ(), proc.Config.Env...)
+	cmd.WaitDelay = time.Second
+
+	output, err := cmd.CombinedOutput()
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		proc.emitSystemMessage("  " + scanner.Text())
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		proc.emitSystemMessage(fmt.Sprintf("✖ Trigger command timed out after %s", TriggerCommandTimeout))
+		return
+	}
+	if err != nil {
+		proc.emitSystemMessage(fmt.Sprintf("✖ Trigger command failed: %v", err))
+		return
+	}
+	proc.emitSystemMessage("✔ Trigger command finished")
+}
@@ -0,0 +1,263 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+// UpgradeStateEnv points a re-executed paraler at the handoff file written
+// by the process it replaced
+const UpgradeStateEnv = "PARALER_UPGRADE_STATE"
+
+// handoffRecord describes a running service handed over to the new paraler
+// process on an in-place upgrade
+type handoffRecord struct {
+	Project      string    `json:"project"`
+	Service      string    `json:"service"`
+	Instance     int       `json:"instance,omitempty"`
+	PID          int       `json:"pid,omitempty"`
+	Stdout       int       `json:"stdout,omitempty"` // inherited descriptors of the output pipes
+	Stderr       int       `json:"stderr,omitempty"`
+	Container    string    `json:"container,omitempty"`
+	Managed      bool      `json:"managed,omitempty"` // container created by paraler
+	Paused       bool      `json:"paused,omitempty"`
+	Cmd          string    `json:"cmd"`
+	Port         int       `json:"port,omitempty"`
+	StartedAt    time.Time `json:"started_at"`
+	RestartCount int       `json:"restart_count,omitempty"`
+}
+
+// Upgrade replaces the running paraler with the binary at its path (e.g.
+// after installing a new version) without stopping services. Running
+// services, including their output pipes, are handed over to the new
+// process, which picks them up with ResumeUpgrade. It only returns if the
+// new binary couldn't be executed.
+func (m *Manager) Upgrade() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find paraler binary: %w", err)
+	}
+
+	var records []handoffRecord
+	for _, p := range m.All() {
+		if r, ok := p.handoff(); ok {
+			records = append(records, r)
+		}
+	}
+
+	f, err := os.CreateTemp("", "paraler-upgrade-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write handoff state: %w", err)
+	}
+	err = json.NewEncoder(f).Encode(records)
+	f.Close()
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("failed to write handoff state: %w", err)
+	}
+
+	env := append(os.Environ(), UpgradeStateEnv+"="+f.Name())
+	if err := reexec(exe, os.Args, env); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("failed to re-execute paraler: %w", err)
+	}
+	return nil
+}
+
+// ResumeUpgrade takes over the services handed over by the paraler process
+// this one replaced, as listed in the handoff file at path
+func (m *Manager) ResumeUpgrade(path string) error {
+	data, err := os.ReadFile(path)
+	os.Remove(path)
+	if err != nil {
+		return fmt.Errorf("failed to read handoff state: %w", err)
+	}
+
+	var records []handoffRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return fmt.Errorf("failed to read handoff state: %w", err)
+	}
+
+	for _, r := range records {
+		// Services removed from the config are found as orphans instead
+		proc := m.Get(config.ServiceID{Project: r.Project, Service: r.Service, Instance: r.Instance})
+		if proc == nil {
+			continue
+		}
+		if err := proc.resume(r); err != nil {
+			proc.emitSystemMessage(fmt.Sprintf("⚠ Failed to resume after upgrade: %v", err))
+		}
+	}
+
+	m.saveState()
+	return nil
+}
+
+// handoff describes the process for the paraler process replacing this one,
+// returning false if it isn't running
+func (p *Process) handoff() (handoffRecord, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.status.alive() {
+		return handoffRecord{}, false
+	}
+
+	r := handoffRecord{
+		Project:      p.ID.Project,
+		Service:      p.ID.Service,
+		Instance:     p.ID.Instance,
+		Cmd:          p.resolved.Cmd,
+		Port:         p.resolved.Port,
+		StartedAt:    p.startedAt,
+		RestartCount: p.restartCount,
+		Paused:       p.status == StatusPaused,
+	}
+
+	switch {
+	case p.container != "":
+		r.Container = p.container
+		r.Managed = p.containerManaged
+	case p.group != nil:
+		r.PID = p.group.pid
+		// Adopted processes have no output pipes
+		if p.stdout != nil && p.stderr != nil {
+			r.Stdout = inheritFD(p.stdout)
+			r.Stderr = inheritFD(p.stderr)
+		}
+	default:
+		return handoffRecord{}, false
+	}
+	return r, true
+}
+
+// resume takes over a process handed over by the previous paraler process
+func (p *Process) resume(r handoffRecord) error {
+	if r.Container != "" {
+		return p.resumeContainer(r)
+	}
+
+	group := adoptProcessGroup(r.PID)
+	if !group.Alive() {
+		return fmt.Errorf("process %d is no longer running", r.PID)
+	}
+
+	exited := make(chan struct{})
+	p.mu.Lock()
+	p.cmd = nil
+	p.cancel = nil
+	p.group = group
+	p.exited = exited
+	p.startedAt = r.StartedAt
+	p.restartCount = r.RestartCount
+	p.resolved.Cmd = r.Cmd
+	p.resolved.Port = r.Port
+	p.ready = true
+	p.setStatusLocked(StatusRunning)
+	if r.Paused {
+		p.setStatusLocked(StatusPaused)
+	}
+	p.mu.Unlock()
+
+	// Keep reading output from the pipes inherited across the exec
+	streamed := make(chan struct{}, 2)
+	for _, pipe := range []struct {
+		fd       int
+		isStderr bool
+	}{{r.Stdout, false}, {r.Stderr, true}} {
+		if pipe.fd <= 0 {
+			streamed <- struct{}{}
+			continue
+		}
+		closeOnExec(pipe.fd)
+		f := os.NewFile(uintptr(pipe.fd), "output")
+		p.mu.Lock()
+		if pipe.isStderr {
+			p.stderr = f
+		} else {
+			p.stdout = f
+		}
+		p.mu.Unlock()
+		go p.streamOutput(f, pipe.isStderr, streamed)
+	}
+
+	p.emitSystemMessage(fmt.Sprintf("⇲ Resumed after paraler upgrade (PID %d)", r.PID))
+	go p.waitResumed(group, exited, streamed)
+	return nil
+}
+
+// waitResumed waits for a resumed process to exit and updates status. The
+// process is still a child of this (re-executed) paraler process, so its
+// exit status can be collected.
+func (p *Process) waitResumed(group *processGroup, exited chan struct{}, streamed <-chan struct{}) {
+	defer close(exited)
+
+	var exitCode int
+	var err error
+	proc, findErr := os.FindProcess(group.pid)
+	state, waitErr := (*os.ProcessState)(nil), findErr
+	if findErr == nil {
+		state, waitErr = proc.Wait()
+	}
+	if waitErr != nil {
+		// Not waitable, fall back to polling
+		for group.Alive() {
+			time.Sleep(time.Second)
+		}
+	} else {
+		exitCode = state.ExitCode()
+		if !state.Success() {
+			err = &exec.ExitError{ProcessState: state}
+		}
+	}
+
+	drainOutput(streamed)
+	p.exit(exitCode, err)
+}
+
+// resumeContainer reattaches to a container handed over by the previous
+// paraler process
+func (p *Process) resumeContainer(r handoffRecord) error {
+	client, err := newDockerClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	info, err := client.inspectContainer(ctx, r.Container)
+	if err != nil {
+		cancel()
+		return err
+	}
+	if !info.State.Running {
+		cancel()
+		return fmt.Errorf("container %s is no longer running", shortID(r.Container))
+	}
+
+	logs, err := client.containerLogs(ctx, r.Container, time.Now())
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	p.mu.Lock()
+	p.cancel = cancel
+	p.restartCount = r.RestartCount
+	p.resolved.Cmd = r.Cmd
+	p.resolved.Port = r.Port
+	p.ready = true
+	p.mu.Unlock()
+
+	p.attachContainer(client, r.Container, r.Managed, info, logs, r.StartedAt)
+	if r.Paused {
+		p.setStatus(StatusPaused)
+	}
+	p.emitSystemMessage(fmt.Sprintf("⇲ Resumed after paraler upgrade (container %s)", shortID(r.Container)))
+	return nil
+}
@@ -0,0 +1,37 @@
+//go:build !windows
+
+package process
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// inheritFD clears close-on-exec on a file so it survives the re-exec, and
+// returns its descriptor, or 0 if that fails
+func inheritFD(f *os.File) int {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return 0
+	}
+
+	fd := 0
+	conn.Control(func(raw uintptr) {
+		if _, err := unix.FcntlInt(raw, unix.F_SETFD, 0); err == nil {
+			fd = int(raw)
+		}
+	})
+	return fd
+}
+
+// closeOnExec sets close-on-exec again on an inherited descriptor
+func closeOnExec(fd int) {
+	syscall.CloseOnExec(fd)
+}
+
+// reexec replaces the current process image, keeping its PID and children
+func reexec(path string, args, env []string) error {
+	return syscall.Exec(path, args, env)
+}
@@ -0,0 +1,21 @@
+//go:build windows
+
+package process
+
+import (
+	"errors"
+	"os"
+)
+
+// inheritFD returns 0 since there is no re-exec on Windows
+func inheritFD(f *os.File) int {
+	return 0
+}
+
+// closeOnExec is a no-op on Windows
+func closeOnExec(fd int) {}
+
+// reexec fails on Windows, which can't replace a running process image
+func reexec(path string, args, env []string) error {
+	return errors.New("in-place upgrade is not supported on Windows")
+}
@@ -0,0 +1,128 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+// DefaultWaitForTimeout is how long a service waits for its wait_for
+// targets without a wait_for_timeout
+const DefaultWaitForTimeout = 60 * time.Second
+
+// waitForProgressInterval is how often a still-waiting message is logged
+const waitForProgressInterval = 5 * time.Second
+
+// waitForPollInterval is how often unreachable targets are retried
+const waitForPollInterval = time.Second
+
+// startWaitLocked returns the context bounding the wait for wait_for
+// targets and registers its cancel func, so Stop can cancel the wait as
+// soon as the status is StatusStarting. Called with p.mu held.
+func (p *Process) startWaitLocked() context.Context {
+	timeout := p.Config.WaitForTimeout
+	if timeout <= 0 {
+		timeout = DefaultWaitForTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	p.startCancel = cancel
+	return ctx
+}
+
+// waitForTargets blocks until every wait_for target is reachable. The
+// status must already be StatusStarting with ctx from startWaitLocked; it
+// is set to failed on timeout and to stopped if Stop cancels the wait.
+func (p *Process) waitForTargets(ctx context.Context) error {
+	defer func() {
+		p.mu.Lock()
+		if p.startCancel != nil {
+			p.startCancel()
+			p.startCancel = nil
+		}
+		p.mu.Unlock()
+	}()
+
+	started := time.Now()
+	for _, entry := range p.Config.WaitFor {
+		// Entries are validated when the config is loaded
+		target, _ := config.ParseWaitTarget(entry)
+		if reachable(ctx, target) {
+			continue
+		}
+
+		p.emitSystemMessage(fmt.Sprintf("⏳ Waiting for %s", target))
+		if err := p.awaitTarget(ctx, target, started); err != nil {
+			return err
+		}
+		p.emitSystemMessage(fmt.Sprintf("✔ %s is reachable", target))
+	}
+
+	// Stop may have cancelled the wait after the last target was reached
+	if ctx.Err() == context.Canceled {
+		p.setStatus(StatusStopped)
+		p.emitSystemMessage("■ Start cancelled")
+		return fmt.Errorf("start cancelled")
+	}
+	return nil
+}
+
+// awaitTarget polls a target until it is reachable, logging progress
+func (p *Process) awaitTarget(ctx context.Context, target config.WaitTarget, started time.Time) error {
+	poll := time.NewTicker(waitForPollInterval)
+	defer poll.Stop()
+	progress := time.NewTicker(waitForProgressInterval)
+	defer progress.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.Canceled {
+				p.setStatus(StatusStopped)
+				p.emitSystemMessage("■ Start cancelled")
+				return fmt.Errorf("start cancelled while waiting for %s", target)
+			}
+			p.setStatus(StatusFailed)
+			p.emitSystemMessage(fmt.Sprintf("✖ Timed out waiting for %s", target))
+			return fmt.Errorf("timed out waiting for %s", target)
+		case <-progress.C:
+			p.emitSystemMessage(fmt.Sprintf("⏳ Still waiting for %s (%s)", target, time.Since(started).Round(time.Second)))
+		case <-poll.C:
+			if reachable(ctx, target) {
+				return nil
+			}
+		}
+	}
+}
+
+// reachable returns true if a URL answers with a non-error status, or a
+// TCP address accepts connections
+func reachable(ctx context.Context, target config.WaitTarget) bool {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if target.URL == "" {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", target.Addr)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.URL, nil)
+	if err != nil {
+		return false
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode < 400
+}
@@ -0,0 +1,230 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce is how long to wait for changes to settle before restarting
+const DefaultWatchDebounce = 500 * time.Millisecond
+
+// ignoredWatchDirs are never watched, even if a pattern would match inside them
+var ignoredWatchDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"dist":         true,
+	"build":        true,
+	"__pycache__":  true,
+}
+
+// Watcher watches files matching glob patterns and fires a callback on change
+type Watcher struct {
+	root     string
+	patterns []string
+	debounce time.Duration
+	onChange func(path string)
+
+	watcher   *fsnotify.Watcher
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu    sync.Mutex
+	timer *time.Timer
+	last  string
+}
+
+// NewWatcher creates a watcher for patterns relative to root.
+// onChange is called with the last changed path once changes settle.
+func NewWatcher(root string, patterns []string, debounce time.Duration, onChange func(path string)) (*Watcher, error) {
+	if debounce <= 0 {
+		debounce = DefaultWatchDebounce
+	}
+
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		root:     root,
+		patterns: patterns,
+		debounce: debounce,
+		onChange: onChange,
+		watcher:  fw,
+		done:     make(chan struct{}),
+	}
+
+	if err := w.addTree(root); err != nil {
+		fw.Close()
+		return nil, err
+	}
+
+	go w.run()
+
+	return w, nil
+}
+
+// Close stops watching. It's safe to call more than once, concurrently.
+func (w *Watcher) Close() {
+	w.closeOnce.Do(func() {
+		close(w.done)
+		w.watcher.Close()
+
+		w.mu.Lock()
+		if w.timer != nil {
+			w.timer.Stop()
+		}
+		w.mu.Unlock()
+	})
+}
+
+// addTree adds a directory and all its non-ignored subdirectories
+func (w *Watcher) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			// Skip unreadable entries but keep walking
+			if path == dir {
+				return err
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != dir && (ignoredWatchDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+			return filepath.SkipDir
+		}
+		return w.watcher.Add(path)
+	})
+}
+
+// run processes file system events
+func (w *Watcher) run() {
+	for {
+		select {
+		case <-w.done:
+			return
+
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+
+			// Watch newly created directories
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					w.addTree(event.Name)
+					continue
+				}
+			}
+
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+				continue
+			}
+
+			rel, err := filepath.Rel(w.root, event.Name)
+			if err != nil {
+				continue
+			}
+			if w.matches(rel) {
+				w.schedule(rel)
+			}
+
+		case _, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// schedule (re)starts the debounce timer
+func (w *Watcher) schedule(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.last = path
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.timer = time.AfterFunc(w.debounce, w.fire)
+}
+
+// fire invokes the callback unless the watcher was closed
+func (w *Watcher) fire() {
+	select {
+	case <-w.done:
+		return
+	default:
+	}
+
+	w.mu.Lock()
+	path := w.last
+	w.mu.Unlock()
+
+	w.onChange(path)
+}
+
+// matches returns true if a path relative to root matches any pattern
+func (w *Watcher) matches(rel string) bool {
+	for _, pattern := range w.patterns {
+		if MatchGlob(pattern, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchGlob matches a slash-separated path against a glob pattern.
+// In addition to filepath.Match syntax, "**" matches any number of directories.
+// Patterns without a slash are matched against the file name only.
+func MatchGlob(pattern, path string) bool {
+	pattern = filepath.ToSlash(strings.TrimPrefix(pattern, "./"))
+	path = filepath.ToSlash(path)
+
+	if !strings.Contains(pattern, "/") {
+		ok, _ := filepath.Match(pattern, filepath.Base(path))
+		return ok
+	}
+
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(path, "/"))
+}
+
+// matchSegments matches pattern segments against path segments
+func matchSegments(pattern, path []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Collapse consecutive "**"
+			rest := pattern[1:]
+			for len(rest) > 0 && rest[0] == "**" {
+				rest = rest[1:]
+			}
+			if len(rest) == 0 {
+				return true
+			}
+			for i := 0; i <= len(path); i++ {
+				if matchSegments(rest, path[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(path) == 0 {
+			return false
+		}
+		if ok, _ := filepath.Match(pattern[0], path[0]); !ok {
+			return false
+		}
+		pattern = pattern[1:]
+		path = path[1:]
+	}
+	return len(path) == 0
+}
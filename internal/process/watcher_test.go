@@ -0,0 +1,51 @@
+package process
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"basename match", "*.go", "cmd/api/main.go", true},
+		{"basename mismatch", "*.go", "README.md", false},
+		{"single dir", "src/*.rs", "src/main.rs", true},
+		{"single dir no nesting", "src/*.rs", "src/bin/tool.rs", false},
+		{"double star", "src/**/*.rs", "src/bin/tool.rs", true},
+		{"double star zero dirs", "src/**/*.rs", "src/main.rs", true},
+		{"double star suffix", "config/**", "config/dev/app.yaml", true},
+		{"leading dot slash", "./internal/**/*.go", "internal/process/manager.go", true},
+		{"other dir", "internal/**/*.go", "cmd/main.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MatchGlob(tt.pattern, tt.path)
+			if result != tt.expected {
+				t.Errorf("MatchGlob(%q, %q) = %v, expected %v", tt.pattern, tt.path, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWatcher_ConcurrentClose(t *testing.T) {
+	w, err := NewWatcher(t.TempDir(), []string{"*.go"}, 0, func(string) {})
+	if err != nil {
+		t.Fatalf("NewWatcher: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.Close()
+		}()
+	}
+	wg.Wait()
+}
@@ -2,6 +2,9 @@ package components
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/paralerdev/paraler/internal/discovery"
@@ -14,12 +17,29 @@ type AddProjectState int
 
 const (
 	AddProjectStateInput AddProjectState = iota
+	AddProjectStateBrowse
 	AddProjectStateScanning
 	AddProjectStatePreview
+	AddProjectStateEdit // Editing a detected service in the preview
 	AddProjectStateError
 	AddProjectStateDone
 )
 
+// Fields of a detected service that can be edited in the preview
+const (
+	detectedName = iota
+	detectedCmd
+	detectedPort
+	detectedFieldCount
+)
+
+// detectedLabels are the config keys the fields become
+var detectedLabels = [detectedFieldCount]string{
+	detectedName: "name",
+	detectedCmd:  "cmd",
+	detectedPort: "port",
+}
+
 // AddProjectModal is a modal for adding new projects
 type AddProjectModal struct {
 	state           AddProjectState
@@ -27,9 +47,13 @@ type AddProjectModal struct {
 	pathCompleter   *PathCompleter
 	suggestions     []string
 	suggestionIndex int  // Currently selected suggestion (-1 = none)
+	tree            *DirTree
 	detected        *discovery.DetectedProject
 	selected        map[int]bool // Selected services
 	cursor          int
+	edits           [detectedFieldCount]textinput.Model // Fields of the service being edited
+	editField       int
+	editError       string
 	error           string
 	width           int
 	height          int
@@ -55,6 +79,8 @@ type AddProjectStyles struct {
 	ButtonActive  lipgloss.Style
 	Suggestion    lipgloss.Style
 	SuggestionSel lipgloss.Style
+	Field         lipgloss.Style
+	FieldSel      lipgloss.Style
 }
 
 // DefaultAddProjectStyles returns default styles
@@ -62,51 +88,58 @@ func DefaultAddProjectStyles() AddProjectStyles {
 	return AddProjectStyles{
 		Container: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#8B5CF6")).
+			BorderForeground(theme.Primary).
 			Padding(1, 2),
 		Title: lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#F9FAFB")).
+			Foreground(theme.Text).
 			MarginBottom(1),
 		Subtitle: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#9CA3AF")).
+			Foreground(theme.Subtle).
 			MarginBottom(1),
 		Input: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F9FAFB")),
+			Foreground(theme.Text),
 		Label: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#9CA3AF")),
+			Foreground(theme.Subtle),
 		Service: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F9FAFB")),
-		ServiceSel: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F9FAFB")).
-			Background(lipgloss.Color("#1F2937")),
+			Foreground(theme.Text),
+		ServiceSel: selectionStyle(theme.Surface).
+			Foreground(theme.Text),
 		Checkbox: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")),
+			Foreground(theme.Muted),
 		CheckboxSel: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#10B981")),
+			Foreground(theme.Success),
 		Framework: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#8B5CF6")),
+			Foreground(theme.Primary),
 		Command: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
+			Foreground(theme.Muted).
 			Italic(true),
 		Error: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#EF4444")),
+			Foreground(theme.Error),
 		Help: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
+			Foreground(theme.Muted).
 			MarginTop(1),
 		Button: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#9CA3AF")).
+			Foreground(theme.Subtle).
 			Padding(0, 2),
-		ButtonActive: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F9FAFB")).
-			Background(lipgloss.Color("#8B5CF6")).
+		ButtonActive: selectionStyle(theme.Primary).
+			Foreground(theme.Text).
 			Padding(0, 2),
 		Suggestion: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
+			Foreground(theme.Muted).
 			PaddingLeft(2),
 		SuggestionSel: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#8B5CF6")).
+			Foreground(theme.Primary).
 			PaddingLeft(2),
+		Field: lipgloss.NewStyle().
+			Foreground(theme.Subtle).
+			PaddingLeft(4).
+			Width(12),
+		FieldSel: lipgloss.NewStyle().
+			Foreground(theme.Primary).
+			Bold(true).
+			PaddingLeft(4).
+			Width(12),
 	}
 }
 
@@ -118,14 +151,28 @@ func NewAddProjectModal() *AddProjectModal {
 	ti.CharLimit = 256
 	ti.Width = 50
 
-	return &AddProjectModal{
+	m := &AddProjectModal{
 		state:           AddProjectStateInput,
 		pathInput:       ti,
 		pathCompleter:   NewPathCompleter(),
 		suggestionIndex: -1,
+		tree:            NewDirTree(),
 		selected:        make(map[int]bool),
 		styles:          DefaultAddProjectStyles(),
 	}
+	placeholders := [detectedFieldCount]string{
+		detectedName: "web",
+		detectedCmd:  "npm run dev",
+		detectedPort: "none",
+	}
+	for i := range m.edits {
+		edit := textinput.New()
+		edit.Placeholder = placeholders[i]
+		edit.CharLimit = 1024
+		edit.Width = 40
+		m.edits[i] = edit
+	}
+	return m
 }
 
 // CompleteTab handles Tab key for path autocompletion
@@ -183,11 +230,53 @@ func (m *AddProjectModal) HasSuggestionSelected() bool {
 	return m.suggestionIndex >= 0 && m.suggestionIndex < len(m.suggestions)
 }
 
+// Browse switches to browsing directories, starting from the path typed
+// if it exists, or else the home directory
+func (m *AddProjectModal) Browse() {
+	root := ""
+	if path := m.pathInput.Value(); path != "" {
+		for dir := filepath.Clean(expandTilde(path)); ; dir = filepath.Dir(dir) {
+			if info, err := os.Stat(dir); err == nil && info.IsDir() {
+				root = dir
+				break
+			}
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+	}
+	if root == "" {
+		root, _ = os.UserHomeDir()
+	}
+	if abs, err := filepath.Abs(root); err == nil {
+		root = abs
+	}
+
+	m.tree.SetRoot(root)
+	m.state = AddProjectStateBrowse
+	m.pathInput.Blur()
+}
+
+// Tree returns the directory tree browsed
+func (m *AddProjectModal) Tree() *DirTree {
+	return m.tree
+}
+
+// UseBrowsed fills in the path of the directory selected in the tree
+func (m *AddProjectModal) UseBrowsed() {
+	m.pathInput.SetValue(shortenHome(m.tree.Selected()))
+	m.pathInput.CursorEnd()
+	m.UpdateSuggestions()
+}
+
 // SetSize sets the modal dimensions
 func (m *AddProjectModal) SetSize(width, height int) {
 	m.width = width
 	m.height = height
 	m.pathInput.Width = width - 10
+	for i := range m.edits {
+		m.edits[i].Width = width - 22
+	}
 }
 
 // State returns the current state
@@ -206,6 +295,7 @@ func (m *AddProjectModal) Reset() {
 	m.detected = nil
 	m.selected = make(map[int]bool)
 	m.cursor = 0
+	m.editError = ""
 	m.error = ""
 }
 
@@ -269,6 +359,114 @@ func (m *AddProjectModal) ToggleSelected() {
 	m.selected[m.cursor] = !m.selected[m.cursor]
 }
 
+// EditSelected edits the name, command and port of the service under the
+// cursor
+func (m *AddProjectModal) EditSelected() {
+	if m.detected == nil || m.cursor >= len(m.detected.Services) {
+		return
+	}
+	svc := m.detected.Services[m.cursor]
+	port := ""
+	if svc.Port > 0 {
+		port = strconv.Itoa(svc.Port)
+	}
+	m.edits[detectedName].SetValue(svc.Name)
+	m.edits[detectedCmd].SetValue(detectedCommand(svc))
+	m.edits[detectedPort].SetValue(port)
+	for i := range m.edits {
+		m.edits[i].CursorEnd()
+	}
+	m.editError = ""
+	m.focusEdit(detectedName)
+	m.state = AddProjectStateEdit
+}
+
+// EditNext focuses the next field of the service being edited
+func (m *AddProjectModal) EditNext() {
+	m.focusEdit((m.editField + 1) % detectedFieldCount)
+}
+
+// EditPrev focuses the previous field of the service being edited
+func (m *AddProjectModal) EditPrev() {
+	m.focusEdit((m.editField + detectedFieldCount - 1) % detectedFieldCount)
+}
+
+// focusEdit focuses a field of the service being edited
+func (m *AddProjectModal) focusEdit(field int) {
+	for i := range m.edits {
+		m.edits[i].Blur()
+	}
+	m.editField = field
+	m.edits[field].Focus()
+}
+
+// EditInput returns the text input of the focused field
+func (m *AddProjectModal) EditInput() *textinput.Model {
+	return &m.edits[m.editField]
+}
+
+// ApplyEdit validates the edited fields and updates the service with them,
+// selecting it. It returns false, showing why, if a field is invalid.
+func (m *AddProjectModal) ApplyEdit() bool {
+	name := strings.TrimSpace(m.edits[detectedName].Value())
+	cmd := strings.TrimSpace(m.edits[detectedCmd].Value())
+	port := strings.TrimSpace(m.edits[detectedPort].Value())
+
+	switch {
+	case name == "":
+		m.editError = "name: cannot be empty"
+		return false
+	case strings.ContainsAny(name, "/# \t"):
+		m.editError = "name: must not contain '/', '#' or spaces"
+		return false
+	case cmd == "":
+		m.editError = "cmd: cannot be empty"
+		return false
+	}
+	for i, svc := range m.detected.Services {
+		if i != m.cursor && svc.Name == name {
+			m.editError = fmt.Sprintf("name: another service is named %q", name)
+			return false
+		}
+	}
+	n := 0
+	if port != "" {
+		var err error
+		n, err = strconv.Atoi(port)
+		if err != nil || n < 1 || n > 65535 {
+			m.editError = fmt.Sprintf("port: %q is not a port number", port)
+			return false
+		}
+	}
+
+	svc := &m.detected.Services[m.cursor]
+	svc.Name = name
+	if cmd != detectedCommand(*svc) {
+		svc.DevCommand = cmd
+	}
+	svc.Port = n
+	m.selected[m.cursor] = true
+	m.CancelEdit()
+	return true
+}
+
+// CancelEdit goes back to the preview, discarding any unapplied edits
+func (m *AddProjectModal) CancelEdit() {
+	for i := range m.edits {
+		m.edits[i].Blur()
+	}
+	m.editError = ""
+	m.state = AddProjectStatePreview
+}
+
+// detectedCommand returns the command a detected service will run
+func detectedCommand(svc discovery.DetectedService) string {
+	if svc.DevCommand != "" {
+		return svc.DevCommand
+	}
+	return svc.Command
+}
+
 // GetSelectedServices returns the selected services
 func (m *AddProjectModal) GetSelectedServices() []discovery.DetectedService {
 	if m.detected == nil {
@@ -330,9 +528,11 @@ func (m *AddProjectModal) View() string {
 	switch m.state {
 	case AddProjectStateInput:
 		b.WriteString(m.renderInput())
+	case AddProjectStateBrowse:
+		b.WriteString(m.renderBrowse())
 	case AddProjectStateScanning:
 		b.WriteString(m.styles.Subtitle.Render("Scanning..."))
-	case AddProjectStatePreview:
+	case AddProjectStatePreview, AddProjectStateEdit:
 		b.WriteString(m.renderPreview())
 	case AddProjectStateError:
 		b.WriteString(m.renderError())
@@ -368,7 +568,20 @@ func (m *AddProjectModal) renderInput() string {
 	}
 
 	b.WriteString("\n")
-	b.WriteString(m.styles.Help.Render("Tab autocomplete • ↑↓ select • Enter scan • Esc cancel"))
+	b.WriteString(m.styles.Help.Render("Tab autocomplete • ctrl+t browse • ↑↓ select • Enter scan • Esc cancel"))
+
+	return b.String()
+}
+
+// renderBrowse renders the directory tree
+func (m *AddProjectModal) renderBrowse() string {
+	var b strings.Builder
+
+	b.WriteString(m.styles.Label.Render("Choose a project directory:"))
+	b.WriteString("\n\n")
+	b.WriteString(m.tree.View(max(m.width-6, 10), max(m.height-10, 5)))
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.Help.Render("↑↓ navigate • →/← expand/collapse • Backspace parent • Enter scan • Esc type path"))
 
 	return b.String()
 }
@@ -408,16 +621,51 @@ func (m *AddProjectModal) renderPreview() string {
 		b.WriteString(line)
 		b.WriteString("\n")
 
-		// Show command for selected item
-		if i == m.cursor && svc.DevCommand != "" {
-			cmd := m.styles.Command.Render("  → " + svc.DevCommand)
-			b.WriteString(cmd)
+		if i != m.cursor {
+			continue
+		}
+		if m.state == AddProjectStateEdit {
+			b.WriteString(m.renderEdit())
+			continue
+		}
+
+		// Show command and port for selected item
+		if cmd := detectedCommand(svc); cmd != "" {
+			if svc.Port > 0 {
+				cmd += fmt.Sprintf("  :%d", svc.Port)
+			}
+			b.WriteString(m.styles.Command.Render("  → " + cmd))
 			b.WriteString("\n")
 		}
 	}
 
 	b.WriteString("\n")
-	b.WriteString(m.styles.Help.Render("↑↓ navigate • Space toggle • Enter confirm • Esc back"))
+	if m.state == AddProjectStateEdit {
+		b.WriteString(m.styles.Help.Render("Tab/↑↓ field • Enter apply • Esc discard"))
+	} else {
+		b.WriteString(m.styles.Help.Render("↑↓ navigate • Space toggle • e edit • Enter confirm • Esc back"))
+	}
+
+	return b.String()
+}
+
+// renderEdit renders the fields of the service being edited, below it
+func (m *AddProjectModal) renderEdit() string {
+	var b strings.Builder
+
+	for i := range m.edits {
+		if i == m.editField {
+			b.WriteString(m.styles.FieldSel.Render(detectedLabels[i]))
+		} else {
+			b.WriteString(m.styles.Field.Render(detectedLabels[i]))
+		}
+		b.WriteString(m.edits[i].View())
+		b.WriteString("\n")
+	}
+	if m.editError != "" {
+		b.WriteString(m.styles.Error.PaddingLeft(4).Render(m.editError))
+		b.WriteString("\n")
+	}
 
 	return b.String()
 }
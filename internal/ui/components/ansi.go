@@ -0,0 +1,253 @@
+package components
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	// ansiReset ends the colors of a line, so they don't leak into the next
+	ansiReset = "\x1b[0m"
+
+	// hyperlinkReset ends an OSC 8 hyperlink
+	hyperlinkReset = "\x1b]8;;\x1b\\"
+)
+
+// sanitizeLine removes control characters and ANSI codes that break the
+// layout, keeping OSC 8 hyperlinks so URLs stay clickable
+func sanitizeLine(s string) string {
+	line, _ := cleanLine(s, false)
+	return line
+}
+
+// sanitizeColoredLine removes control characters and ANSI codes that break
+// the layout, keeping color and text attribute (SGR) codes. It returns false
+// if the line has none.
+func sanitizeColoredLine(s string) (string, bool) {
+	line, colored := cleanLine(s, true)
+	if !colored {
+		return line, false
+	}
+	return line + ansiReset, true
+}
+
+// cleanLine sanitizes a line, keeping SGR codes if keepSGR is set. It
+// returns true if it kept any. Hyperlinks are kept and closed by the end of
+// the line; malformed UTF-8 is replaced, and C1 control characters removed.
+func cleanLine(s string, keepSGR bool) (string, bool) {
+	var result strings.Builder
+	result.Grow(len(s))
+
+	kept := false
+	linked := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if c == '\x1b' {
+			seq, n := parseEscape(s[i:])
+			if keepSGR && isSGR(seq) {
+				result.WriteString(seq)
+				kept = true
+			} else if uri, ok := parseHyperlink(seq); ok && (uri != "" || linked) {
+				result.WriteString(seq)
+				linked = uri != ""
+			}
+			i += n - 1
+			continue
+		}
+
+		if c >= utf8.RuneSelf {
+			r, size := utf8.DecodeRuneInString(s[i:])
+			switch {
+			case r == utf8.RuneError && size == 1:
+				result.WriteRune(utf8.RuneError)
+			case r >= 0x80 && r <= 0x9f:
+				// C1 controls, which some terminals read as escape sequences
+			default:
+				result.WriteString(s[i : i+size])
+			}
+			i += size - 1
+			continue
+		}
+
+		// Skip carriage return and newline
+		if c == '\r' || c == '\n' {
+			continue
+		}
+		// Replace tab with spaces
+		if c == '\t' {
+			result.WriteString("    ")
+			continue
+		}
+		// Skip other control characters
+		if c < 32 || c == 0x7f {
+			continue
+		}
+		result.WriteByte(c)
+	}
+
+	if linked {
+		result.WriteString(hyperlinkReset)
+	}
+	return result.String(), kept
+}
+
+// parseHyperlink returns the URI of an OSC 8 hyperlink sequence
+// (ESC ] 8 ; params ; URI ST), empty for the one ending a link. It returns
+// false if seq is no well-formed hyperlink.
+func parseHyperlink(seq string) (string, bool) {
+	body, ok := strings.CutPrefix(seq, "\x1b]8;")
+	if !ok {
+		return "", false
+	}
+	if end, ok := strings.CutSuffix(body, "\a"); ok {
+		body = end
+	} else if end, ok := strings.CutSuffix(body, "\x1b\\"); ok {
+		body = end
+	} else {
+		return "", false
+	}
+
+	_, uri, ok := strings.Cut(body, ";")
+	if !ok {
+		return "", false
+	}
+	// URIs are limited to printable ASCII, anything else is percent-encoded
+	for i := 0; i < len(uri); i++ {
+		if uri[i] < 32 || uri[i] > 126 {
+			return "", false
+		}
+	}
+	return uri, true
+}
+
+// plainText returns s without escape sequences, as copied to the clipboard
+func plainText(s string) string {
+	if strings.IndexByte(s, '\x1b') < 0 {
+		return s
+	}
+	return ansi.Strip(s)
+}
+
+// escapeRanges returns the byte ranges of the escape sequences in s
+func escapeRanges(s string) [][2]int {
+	var ranges [][2]int
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\x1b' {
+			continue
+		}
+		_, n := parseEscape(s[i:])
+		ranges = append(ranges, [2]int{i, i + n})
+		i += n - 1
+	}
+	return ranges
+}
+
+// parseEscape returns the escape sequence s starts with and its length:
+// a CSI sequence (ESC [ params final), an OSC string (ESC ] ... BEL or
+// ESC \), or ESC and the character after it
+func parseEscape(s string) (string, int) {
+	if len(s) < 2 {
+		return s, len(s)
+	}
+
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return s[:i+1], i + 1
+			}
+		}
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return s[:i+1], i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return s[:i+2], i + 2
+			}
+		}
+	default:
+		return s[:2], 2
+	}
+
+	// Unterminated sequences swallow the rest of the line
+	return s, len(s)
+}
+
+// isSGR returns true if seq sets colors or text attributes (ESC [ ... m),
+// which don't move the cursor and so are safe to show
+func isSGR(seq string) bool {
+	if len(seq) < 3 || seq[1] != '[' || seq[len(seq)-1] != 'm' {
+		return false
+	}
+	for i := 2; i < len(seq)-1; i++ {
+		c := seq[i]
+		if (c < '0' || c > '9') && c != ';' && c != ':' {
+			return false
+		}
+	}
+	return true
+}
+
+// wrapLine wraps a line to rows of width, breaking at spaces where it can.
+// Colors and hyperlinks still in effect at the end of a row carry over to
+// the next.
+func wrapLine(s string, width int) []string {
+	rows := strings.Split(ansi.Wrap(s, width, ""), "\n")
+
+	open, link := "", ""
+	for i, row := range rows {
+		row = open + link + row
+		open, link = openSGR(row), openHyperlink(row)
+		if link != "" {
+			row += hyperlinkReset
+		}
+		if open != "" {
+			row += ansiReset
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// openHyperlink returns the hyperlink of s still open at its end
+func openHyperlink(s string) string {
+	link := ""
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\x1b' {
+			continue
+		}
+		seq, n := parseEscape(s[i:])
+		if uri, ok := parseHyperlink(seq); ok {
+			link = ""
+			if uri != "" {
+				link = seq
+			}
+		}
+		i += n - 1
+	}
+	return link
+}
+
+// openSGR returns the SGR codes of s still in effect at its end
+func openSGR(s string) string {
+	var open strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\x1b' {
+			continue
+		}
+		seq, n := parseEscape(s[i:])
+		if isSGR(seq) {
+			if seq == ansiReset || seq == "\x1b[m" {
+				open.Reset()
+			} else {
+				open.WriteString(seq)
+			}
+		}
+		i += n - 1
+	}
+	return open.String()
+}
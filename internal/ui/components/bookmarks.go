@@ -0,0 +1,155 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/paralerdev/paraler/internal/log"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// BookmarksModal lists the bookmarked log lines to jump to one
+type BookmarksModal struct {
+	visible  bool
+	marks    []log.Entry
+	selected int
+	offset   int
+	width    int
+	height   int
+	styles   BookmarksStyles
+}
+
+// BookmarksStyles contains styles for the modal
+type BookmarksStyles struct {
+	Container    lipgloss.Style
+	Title        lipgloss.Style
+	Service      lipgloss.Style
+	Timestamp    lipgloss.Style
+	Item         lipgloss.Style
+	SelectedItem lipgloss.Style
+	Help         lipgloss.Style
+}
+
+// DefaultBookmarksStyles returns default styles
+func DefaultBookmarksStyles() BookmarksStyles {
+	return BookmarksStyles{
+		Container: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(theme.Accent).
+			Padding(1, 2),
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Accent),
+		Service: lipgloss.NewStyle().
+			Foreground(theme.Primary),
+		Timestamp: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+		Item: lipgloss.NewStyle().
+			Foreground(theme.Subtle),
+		SelectedItem: lipgloss.NewStyle().
+			Foreground(theme.Text).
+			Bold(true),
+		Help: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			MarginTop(1),
+	}
+}
+
+// NewBookmarksModal creates a new bookmarks modal
+func NewBookmarksModal() *BookmarksModal {
+	return &BookmarksModal{
+		styles: DefaultBookmarksStyles(),
+	}
+}
+
+// SetSize sets the modal size
+func (m *BookmarksModal) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Show shows the modal with bookmarked entries, selecting the latest
+func (m *BookmarksModal) Show(marks []log.Entry) {
+	m.marks = marks
+	m.selected = max(0, len(marks)-1)
+	m.offset = 0
+	m.visible = true
+}
+
+// Hide hides the modal
+func (m *BookmarksModal) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns true if modal is visible
+func (m *BookmarksModal) IsVisible() bool {
+	return m.visible
+}
+
+// MoveUp moves selection up
+func (m *BookmarksModal) MoveUp() {
+	if m.selected > 0 {
+		m.selected--
+	}
+}
+
+// MoveDown moves selection down
+func (m *BookmarksModal) MoveDown() {
+	if m.selected < len(m.marks)-1 {
+		m.selected++
+	}
+}
+
+// Selected returns the selected bookmark, if any
+func (m *BookmarksModal) Selected() (log.Entry, bool) {
+	if m.selected < len(m.marks) {
+		return m.marks[m.selected], true
+	}
+	return log.Entry{}, false
+}
+
+// View renders the modal
+func (m *BookmarksModal) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	contentWidth := m.width - 6
+	if contentWidth < 20 {
+		contentWidth = 20
+	}
+
+	var b strings.Builder
+
+	b.WriteString(m.styles.Title.Render(fmt.Sprintf("Bookmarks (%d)", len(m.marks))))
+	b.WriteString("\n\n")
+
+	// Keep the selection in view
+	visible := max(3, m.height-10)
+	if m.selected < m.offset {
+		m.offset = m.selected
+	}
+	if m.selected >= m.offset+visible {
+		m.offset = m.selected - visible + 1
+	}
+	end := min(m.offset+visible, len(m.marks))
+
+	for i := m.offset; i < end; i++ {
+		mark := m.marks[i]
+		line := m.styles.Timestamp.Render(formatClock(mark.Timestamp)) + " " +
+			m.styles.Service.Render(mark.ServiceID.String()) + " "
+		if i == m.selected {
+			line = m.styles.SelectedItem.Render("→ ") + line + m.styles.SelectedItem.Render(sanitizeLine(mark.Line))
+		} else {
+			line = "  " + line + m.styles.Item.Render(sanitizeLine(mark.Line))
+		}
+		b.WriteString(truncateString(line, contentWidth))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(m.styles.Help.Render("↑/↓ select • Enter jump to line • Esc close"))
+
+	return m.styles.Container.
+		Width(m.width).
+		Render(b.String())
+}
@@ -0,0 +1,30 @@
+package components
+
+import (
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+)
+
+// Layout and time zone times are shown in
+var (
+	clockLayout   = config.DefaultTimeLayout
+	clockLocation = time.Local
+)
+
+// SetTimeFormat sets the layout times of day are shown in and the time
+// zone of all times shown
+func SetTimeFormat(layout string, loc *time.Location) {
+	clockLayout = layout
+	clockLocation = loc
+}
+
+// formatClock formats the time of day of t
+func formatClock(t time.Time) string {
+	return t.In(clockLocation).Format(clockLayout)
+}
+
+// formatTime formats t in a layout, in the time zone times are shown in
+func formatTime(t time.Time, layout string) string {
+	return t.In(clockLocation).Format(layout)
+}
@@ -14,6 +14,11 @@ const (
 	ConfirmNone ConfirmAction = iota
 	ConfirmDeleteService
 	ConfirmDeleteProject
+	ConfirmClearAllLogs
+	ConfirmRestartService
+	ConfirmStopProject
+	ConfirmStopAll
+	ConfirmQuit
 )
 
 // ConfirmModal is a confirmation dialog
@@ -41,19 +46,19 @@ func DefaultConfirmStyles() ConfirmStyles {
 	return ConfirmStyles{
 		Container: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#EF4444")).
+			BorderForeground(theme.Error).
 			Padding(1, 2),
 		Title: lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#EF4444")),
+			Foreground(theme.Error),
 		Message: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F9FAFB")).
+			Foreground(theme.Text).
 			MarginTop(1),
 		Warning: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F59E0B")).
+			Foreground(theme.Warning).
 			Italic(true),
 		Help: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
+			Foreground(theme.Muted).
 			MarginTop(1),
 	}
 }
@@ -85,6 +90,39 @@ func (m *ConfirmModal) Show(action ConfirmAction, projectName, serviceName strin
 		m.title = "Delete Project"
 		m.targetName = projectName
 		m.message = fmt.Sprintf("Delete project '%s' and all its services?", projectName)
+	case ConfirmClearAllLogs:
+		m.title = "Clear All Logs"
+		m.targetName = ""
+		m.message = "Clear the logs of every service?"
+	case ConfirmRestartService:
+		m.title = "Restart Service"
+		m.targetName = serviceName
+		m.message = fmt.Sprintf("Restart '%s' to apply the changes?", serviceName)
+	case ConfirmStopProject:
+		m.title = "Stop Project"
+		m.targetName = projectName
+		m.message = fmt.Sprintf("Stop all services of project '%s'?", projectName)
+	}
+}
+
+// ShowRunning shows the confirmation dialog for an action that stops every
+// running service, saying how many run
+func (m *ConfirmModal) ShowRunning(action ConfirmAction, running int) {
+	m.action = action
+	m.projectName = ""
+	m.targetName = ""
+
+	services := "services"
+	if running == 1 {
+		services = "service"
+	}
+	switch action {
+	case ConfirmStopAll:
+		m.title = "Stop All"
+		m.message = fmt.Sprintf("Stop %d running %s?", running, services)
+	case ConfirmQuit:
+		m.title = "Quit"
+		m.message = fmt.Sprintf("Quit and stop %d running %s?", running, services)
 	}
 }
 
@@ -131,7 +169,11 @@ func (m *ConfirmModal) View() string {
 		b.WriteString("\n\n")
 	}
 
-	b.WriteString(m.styles.Help.Render("y confirm • n/Esc cancel"))
+	if m.action == ConfirmQuit {
+		b.WriteString(m.styles.Help.Render("y/q quit • n/Esc cancel"))
+	} else {
+		b.WriteString(m.styles.Help.Render("y confirm • n/Esc cancel"))
+	}
 
 	return m.styles.Container.
 		Width(m.width).
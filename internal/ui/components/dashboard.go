@@ -0,0 +1,278 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+	"github.com/paralerdev/paraler/internal/log"
+	"github.com/paralerdev/paraler/internal/process"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// DashboardErrorWindow is how far back the dashboard counts errors
+const DashboardErrorWindow = 5 * time.Minute
+
+// Dashboard is an overview of every service in a table: status, health,
+// port, uptime, restarts and recent errors
+type Dashboard struct {
+	ids      []config.ServiceID
+	selected int
+	offset   int // First row shown, when not all rows fit
+	width    int
+	height   int
+	styles   DashboardStyles
+}
+
+// DashboardStyles contains dashboard styles
+type DashboardStyles struct {
+	Title    lipgloss.Style
+	Summary  lipgloss.Style
+	Header   lipgloss.Style
+	Row      lipgloss.Style
+	Selected lipgloss.Style
+	Running  lipgloss.Style
+	Starting lipgloss.Style
+	Failed   lipgloss.Style
+	Stopped  lipgloss.Style
+	Errors   lipgloss.Style
+	Help     lipgloss.Style
+}
+
+// DefaultDashboardStyles returns default styles
+func DefaultDashboardStyles() DashboardStyles {
+	return DashboardStyles{
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Primary),
+		Summary: lipgloss.NewStyle().
+			Foreground(theme.Subtle),
+		Header: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Muted),
+		Row: lipgloss.NewStyle().
+			Foreground(theme.Text),
+		Selected: selectionStyle(theme.Surface),
+		Running: lipgloss.NewStyle().
+			Foreground(theme.Success),
+		Starting: lipgloss.NewStyle().
+			Foreground(theme.Warning),
+		Failed: lipgloss.NewStyle().
+			Foreground(theme.Error),
+		Stopped: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+		Errors: lipgloss.NewStyle().
+			Foreground(theme.Error).
+			Bold(true),
+		Help: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+	}
+}
+
+// NewDashboard creates a new dashboard
+func NewDashboard() *Dashboard {
+	return &Dashboard{
+		styles: DefaultDashboardStyles(),
+	}
+}
+
+// SetSize sets the dashboard dimensions
+func (d *Dashboard) SetSize(width, height int) {
+	d.width = width
+	d.height = height
+}
+
+// SetServices sets the services listed, in display order, keeping the
+// selected one selected
+func (d *Dashboard) SetServices(ids []config.ServiceID) {
+	selected := d.Selected()
+	d.ids = ids
+	d.Select(selected)
+}
+
+// Select selects a service
+func (d *Dashboard) Select(id config.ServiceID) {
+	for i, other := range d.ids {
+		if other == id {
+			d.selected = i
+			return
+		}
+	}
+	d.selected = min(d.selected, max(len(d.ids)-1, 0))
+}
+
+// Selected returns the selected service, or an empty ID if there is none
+func (d *Dashboard) Selected() config.ServiceID {
+	if d.selected >= 0 && d.selected < len(d.ids) {
+		return d.ids[d.selected]
+	}
+	return config.ServiceID{}
+}
+
+// MoveUp selects the previous service
+func (d *Dashboard) MoveUp() {
+	if d.selected > 0 {
+		d.selected--
+	}
+}
+
+// MoveDown selects the next service
+func (d *Dashboard) MoveDown() {
+	if d.selected < len(d.ids)-1 {
+		d.selected++
+	}
+}
+
+// View renders the dashboard
+func (d *Dashboard) View(manager *process.Manager, logBuffer *log.Buffer) string {
+	var b strings.Builder
+	innerWidth := max(d.width-4, 1) // borders and padding
+
+	// Title and a summary of what needs attention
+	running, failed, unhealthy := 0, 0, 0
+	for _, id := range d.ids {
+		proc := manager.Get(id)
+		if proc == nil {
+			continue
+		}
+		switch proc.Status() {
+		case process.StatusRunning:
+			running++
+			if proc.Health() == process.HealthUnhealthy {
+				unhealthy++
+			}
+		case process.StatusFailed:
+			failed++
+		}
+	}
+	summary := fmt.Sprintf("%d/%d running", running, len(d.ids))
+	if failed > 0 {
+		summary += fmt.Sprintf(" · %d failed", failed)
+	}
+	if unhealthy > 0 {
+		summary += fmt.Sprintf(" · %d unhealthy", unhealthy)
+	}
+	b.WriteString(d.styles.Title.Render("Overview") + "  " + d.styles.Summary.Render(summary))
+	b.WriteString("\n\n")
+
+	// Columns; the service column takes the width left
+	nameWidth := len("SERVICE")
+	for _, id := range d.ids {
+		nameWidth = max(nameWidth, len(id.String()))
+	}
+	const restWidth = 10 + 10 + 7 + 9 + 9 + 10 // widths of the other columns
+	nameWidth = max(min(nameWidth+2, innerWidth-restWidth), 8)
+	header := fmt.Sprintf("%-*s%-10s%-10s%-7s%-9s%-9s%s",
+		nameWidth, "SERVICE", "STATUS", "HEALTH", "PORT", "UPTIME", "RESTARTS",
+		fmt.Sprintf("ERRORS %dM", int(DashboardErrorWindow.Minutes())))
+	b.WriteString(d.styles.Header.Render(ansi.Truncate(header, innerWidth, "")))
+	b.WriteString("\n")
+
+	// Rows, scrolled to keep the selected one visible above the help
+	rows := max(d.height-7, 1) // borders, title, header and help
+	if d.selected < d.offset {
+		d.offset = d.selected
+	}
+	if d.selected >= d.offset+rows {
+		d.offset = d.selected - rows + 1
+	}
+	d.offset = max(min(d.offset, len(d.ids)-rows), 0)
+
+	since := time.Now().Add(-DashboardErrorWindow)
+	for i := d.offset; i < len(d.ids) && i < d.offset+rows; i++ {
+		b.WriteString(d.renderRow(i, nameWidth, innerWidth, manager, logBuffer, since))
+		b.WriteString("\n")
+	}
+	if len(d.ids) == 0 {
+		b.WriteString(d.styles.Stopped.Render("No services configured"))
+		b.WriteString("\n")
+	}
+
+	content := strings.TrimSuffix(b.String(), "\n")
+	content = lipgloss.PlaceVertical(max(d.height-3, 1), lipgloss.Top, content)
+	content += "\n" + d.styles.Help.Render(ansi.Truncate("↑↓ select • s/x/r start/stop/restart • i info • enter logs • 0/Esc close", innerWidth, "…"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Primary).
+		Padding(0, 1).
+		Width(max(d.width-2, 1)).
+		Height(max(d.height-2, 1)).
+		Render(content)
+}
+
+// renderRow renders the row of a service
+func (d *Dashboard) renderRow(i, nameWidth, innerWidth int, manager *process.Manager, logBuffer *log.Buffer, since time.Time) string {
+	id := d.ids[i]
+	proc := manager.Get(id)
+
+	status := process.StatusStopped
+	health, port, uptime, restarts := "-", "-", "-", "-"
+	if proc != nil {
+		status = proc.Status()
+		if status == process.StatusRunning && proc.Health() != process.HealthUnknown {
+			health = proc.Health().String()
+		}
+		if p := proc.Resolved().Port; p > 0 {
+			port = fmt.Sprintf("%d", p)
+		}
+		if !proc.IsDone() && proc.PID() > 0 {
+			uptime = formatDuration(proc.Uptime().Round(time.Second))
+		}
+		restarts = fmt.Sprintf("%d", proc.RestartCount())
+	}
+	errors := 0
+	if logBuffer != nil {
+		errors = logBuffer.RecentErrorCount(id, since)
+	}
+
+	name := ansi.Truncate(id.String(), nameWidth-2, "…")
+	statusText := fmt.Sprintf("%-10s", status.String())
+	healthText := fmt.Sprintf("%-10s", health)
+	errorsText := "0"
+	if errors > 0 {
+		errorsText = fmt.Sprintf("%d", errors)
+	}
+
+	// Selected rows are plain, so the selection background runs through
+	selected := i == d.selected
+	style := func(s lipgloss.Style, text string) string {
+		if selected {
+			return text
+		}
+		return s.Render(text)
+	}
+	switch status {
+	case process.StatusRunning, process.StatusSucceeded:
+		statusText = style(d.styles.Running, statusText)
+	case process.StatusStarting, process.StatusStopping, process.StatusPaused:
+		statusText = style(d.styles.Starting, statusText)
+	case process.StatusFailed:
+		statusText = style(d.styles.Failed, statusText)
+	default:
+		statusText = style(d.styles.Stopped, statusText)
+	}
+	switch health {
+	case process.HealthHealthy.String():
+		healthText = style(d.styles.Running, healthText)
+	case process.HealthUnhealthy.String():
+		healthText = style(d.styles.Failed, healthText)
+	default:
+		healthText = style(d.styles.Stopped, healthText)
+	}
+	if errors > 0 {
+		errorsText = style(d.styles.Errors, errorsText)
+	} else {
+		errorsText = style(d.styles.Stopped, errorsText)
+	}
+
+	name += strings.Repeat(" ", max(nameWidth-ansi.StringWidth(name), 0))
+	row := fmt.Sprintf("%s%s%s%-7s%-9s%-9s%s", name, statusText, healthText, port, uptime, restarts, errorsText)
+	row = ansi.Truncate(row, innerWidth, "")
+	if selected {
+		return d.styles.Selected.Render(row + strings.Repeat(" ", max(innerWidth-ansi.StringWidth(row), 0)))
+	}
+	return d.styles.Row.Render(row)
+}
@@ -0,0 +1,202 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/process"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DetailModal shows details of a service, including its recent exits
+type DetailModal struct {
+	visible bool
+	proc    *process.Process
+	width   int
+	styles  DetailStyles
+}
+
+// DetailStyles contains styles for the modal
+type DetailStyles struct {
+	Container lipgloss.Style
+	Title     lipgloss.Style
+	Label     lipgloss.Style
+	Value     lipgloss.Style
+	Section   lipgloss.Style
+	Failed    lipgloss.Style
+	Stopped   lipgloss.Style
+	Succeeded lipgloss.Style
+	Help      lipgloss.Style
+}
+
+// DefaultDetailStyles returns default styles
+func DefaultDetailStyles() DetailStyles {
+	return DetailStyles{
+		Container: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(theme.Accent).
+			Padding(1, 2),
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Accent),
+		Label: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			Width(10),
+		Value: lipgloss.NewStyle().
+			Foreground(theme.Text),
+		Section: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Subtle),
+		Failed: lipgloss.NewStyle().
+			Foreground(theme.Error),
+		Stopped: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+		Succeeded: lipgloss.NewStyle().
+			Foreground(theme.Success),
+		Help: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			MarginTop(1),
+	}
+}
+
+// NewDetailModal creates a new detail modal
+func NewDetailModal() *DetailModal {
+	return &DetailModal{
+		styles: DefaultDetailStyles(),
+	}
+}
+
+// SetSize sets the modal width
+func (m *DetailModal) SetSize(width int) {
+	m.width = width
+}
+
+// Show shows the modal for a service
+func (m *DetailModal) Show(proc *process.Process) {
+	m.proc = proc
+	m.visible = true
+}
+
+// Hide hides the modal
+func (m *DetailModal) Hide() {
+	m.visible = false
+	m.proc = nil
+}
+
+// IsVisible returns true if modal is visible
+func (m *DetailModal) IsVisible() bool {
+	return m.visible
+}
+
+// View renders the modal
+func (m *DetailModal) View() string {
+	if !m.visible || m.proc == nil {
+		return ""
+	}
+
+	p := m.proc
+	var b strings.Builder
+
+	b.WriteString(m.styles.Title.Render(p.ID.String()))
+	b.WriteString("\n\n")
+
+	m.writeField(&b, "Status", p.Status().String())
+	if pid := p.PID(); pid > 0 {
+		m.writeField(&b, "PID", fmt.Sprintf("%d", pid))
+		m.writeField(&b, "Uptime", formatDuration(p.Uptime()))
+		if stats := p.Stats(); stats.RSS > 0 {
+			m.writeField(&b, "CPU", fmt.Sprintf("%.1f%%", stats.CPU))
+			m.writeField(&b, "Memory", formatBytes(stats.RSS))
+		}
+	}
+	m.writeField(&b, "Restarts", fmt.Sprintf("%d", p.RestartCount()))
+	resolved := p.Resolved()
+	m.writeField(&b, "Command", resolved.Cmd)
+	m.writeField(&b, "Directory", p.Cwd)
+	if resolved.Port > 0 {
+		m.writeField(&b, "Port", fmt.Sprintf("%d", resolved.Port))
+	}
+	switch health := p.Health(); health {
+	case process.HealthHealthy:
+		m.writeField(&b, "Health", health.String())
+	case process.HealthUnhealthy:
+		if err := p.HealthError(); err != nil {
+			m.writeField(&b, "Health", health.String()+": "+err.Error())
+		} else {
+			m.writeField(&b, "Health", health.String())
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.styles.Section.Render("Recent exits"))
+	b.WriteString("\n")
+
+	history := p.ExitHistory()
+	if len(history) == 0 {
+		b.WriteString(m.styles.Stopped.Render("  none yet"))
+		b.WriteString("\n")
+	}
+	for _, r := range history {
+		b.WriteString(m.renderExit(r))
+		b.WriteString("\n")
+	}
+
+	// Regular crashes stand out more as an interval than as timestamps
+	if len(history) >= 2 {
+		span := history[0].Time.Sub(history[len(history)-1].Time)
+		interval := span / time.Duration(len(history)-1)
+		b.WriteString(m.styles.Stopped.Render(fmt.Sprintf("  exits every %s on average", formatDuration(interval))))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(m.styles.Help.Render("Esc/i close"))
+
+	return m.styles.Container.
+		Width(m.width).
+		Render(b.String())
+}
+
+// writeField writes a label/value line
+func (m *DetailModal) writeField(b *strings.Builder, label, value string) {
+	b.WriteString(m.styles.Label.Render(label))
+	b.WriteString(m.styles.Value.Render(value))
+	b.WriteString("\n")
+}
+
+// renderExit renders one line of the exit history
+func (m *DetailModal) renderExit(r process.ExitRecord) string {
+	when := formatClock(r.Time)
+	if time.Since(r.Time) > 24*time.Hour {
+		when = formatTime(r.Time, "Jan 02 15:04")
+	}
+
+	reason := fmt.Sprintf("exit %d", r.ExitCode)
+	if r.Signal != "" {
+		reason = r.Signal
+	}
+
+	style := m.styles.Stopped
+	switch r.Status {
+	case process.StatusFailed:
+		style = m.styles.Failed
+	case process.StatusSucceeded:
+		style = m.styles.Succeeded
+	}
+
+	return fmt.Sprintf("  %s  %s  %-8s  after %s",
+		when, style.Render(fmt.Sprintf("%-9s", r.Status)), reason, formatDuration(r.Uptime))
+}
+
+// formatDuration formats a duration compactly (e.g. 1h02m, 4m10s, 850ms)
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Hour:
+		return d.Round(time.Second).String()
+	default:
+		d = d.Round(time.Minute)
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
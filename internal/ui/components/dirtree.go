@@ -0,0 +1,271 @@
+package components
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/paralerdev/paraler/internal/discovery"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// dirNode is a directory in a DirTree
+type dirNode struct {
+	path     string
+	name     string
+	depth    int
+	markers  []string   // Files services are detected by
+	children []*dirNode // Loaded on first expand
+	loaded   bool
+	expanded bool
+}
+
+// DirTree browses directories, showing which ones contain services
+type DirTree struct {
+	root   *dirNode
+	rows   []*dirNode // Visible nodes, in display order
+	cursor int
+	offset int // First row shown
+	styles DirTreeStyles
+}
+
+// DirTreeStyles contains directory tree styles
+type DirTreeStyles struct {
+	Dir      lipgloss.Style
+	Selected lipgloss.Style
+	Arrow    lipgloss.Style
+	Marker   lipgloss.Style
+}
+
+// DefaultDirTreeStyles returns default styles
+func DefaultDirTreeStyles() DirTreeStyles {
+	return DirTreeStyles{
+		Dir: lipgloss.NewStyle().
+			Foreground(theme.Text),
+		Selected: selectionStyle(theme.Surface).
+			Foreground(theme.Text),
+		Arrow: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+		Marker: lipgloss.NewStyle().
+			Foreground(theme.Primary),
+	}
+}
+
+// NewDirTree creates a directory tree
+func NewDirTree() *DirTree {
+	return &DirTree{
+		styles: DefaultDirTreeStyles(),
+	}
+}
+
+// SetRoot shows a directory and its subdirectories
+func (t *DirTree) SetRoot(path string) {
+	t.root = newDirNode(path, 0)
+	t.root.name = shortenHome(path)
+	t.root.expanded = true
+	t.root.load()
+	t.cursor = 0
+	t.offset = 0
+	t.refresh()
+}
+
+// Root returns the directory the tree shows
+func (t *DirTree) Root() string {
+	if t.root == nil {
+		return ""
+	}
+	return t.root.path
+}
+
+// RootUp shows the parent of the root, keeping the old root selected
+func (t *DirTree) RootUp() {
+	if t.root == nil {
+		return
+	}
+	old := t.root.path
+	parent := filepath.Dir(old)
+	if parent == old {
+		return
+	}
+	t.SetRoot(parent)
+	for i, node := range t.rows {
+		if node.path == old {
+			t.cursor = i
+		}
+	}
+}
+
+// Selected returns the path of the selected directory
+func (t *DirTree) Selected() string {
+	if t.cursor >= 0 && t.cursor < len(t.rows) {
+		return t.rows[t.cursor].path
+	}
+	return t.Root()
+}
+
+// MoveUp selects the previous directory
+func (t *DirTree) MoveUp() {
+	if t.cursor > 0 {
+		t.cursor--
+	}
+}
+
+// MoveDown selects the next directory
+func (t *DirTree) MoveDown() {
+	if t.cursor < len(t.rows)-1 {
+		t.cursor++
+	}
+}
+
+// Expand shows the subdirectories of the selected directory, or selects
+// its first one if they're shown already
+func (t *DirTree) Expand() {
+	if t.cursor >= len(t.rows) {
+		return
+	}
+	node := t.rows[t.cursor]
+	node.load()
+	if node.expanded {
+		if len(node.children) > 0 {
+			t.cursor++
+		}
+		return
+	}
+	node.expanded = true
+	t.refresh()
+}
+
+// Collapse hides the subdirectories of the selected directory, or selects
+// its parent if they're hidden already
+func (t *DirTree) Collapse() {
+	if t.cursor >= len(t.rows) {
+		return
+	}
+	node := t.rows[t.cursor]
+	if node.expanded && len(node.children) > 0 {
+		node.expanded = false
+		t.refresh()
+		return
+	}
+	for i := t.cursor - 1; i >= 0; i-- {
+		if t.rows[i].depth < node.depth {
+			t.cursor = i
+			return
+		}
+	}
+}
+
+// Toggle expands or collapses the selected directory
+func (t *DirTree) Toggle() {
+	if t.cursor >= len(t.rows) {
+		return
+	}
+	if node := t.rows[t.cursor]; node.expanded {
+		t.Collapse()
+	} else {
+		t.Expand()
+	}
+}
+
+// refresh lists the visible nodes
+func (t *DirTree) refresh() {
+	t.rows = t.rows[:0]
+	var walk func(node *dirNode)
+	walk = func(node *dirNode) {
+		for _, child := range node.children {
+			t.rows = append(t.rows, child)
+			if child.expanded {
+				walk(child)
+			}
+		}
+	}
+	if t.root != nil {
+		t.rows = append(t.rows, t.root)
+		if t.root.expanded {
+			walk(t.root)
+		}
+	}
+	t.cursor = max(min(t.cursor, len(t.rows)-1), 0)
+}
+
+// View renders up to height rows of the tree, keeping the selected one
+// visible
+func (t *DirTree) View(width, height int) string {
+	height = max(height, 1)
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	}
+	if t.cursor >= t.offset+height {
+		t.offset = t.cursor - height + 1
+	}
+	t.offset = max(min(t.offset, len(t.rows)-height), 0)
+
+	var lines []string
+	for i := t.offset; i < len(t.rows) && i < t.offset+height; i++ {
+		lines = append(lines, t.renderRow(t.rows[i], i == t.cursor, width))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderRow renders a directory with its markers
+func (t *DirTree) renderRow(node *dirNode, selected bool, width int) string {
+	arrow := "▸ "
+	switch {
+	case node.expanded && node.loaded && len(node.children) == 0:
+		arrow = "  "
+	case node.expanded:
+		arrow = "▾ "
+	}
+	indent := strings.Repeat("  ", node.depth)
+	name := strings.TrimSuffix(node.name, string(filepath.Separator)) + string(filepath.Separator)
+	markers := ""
+	if len(node.markers) > 0 {
+		markers = "  " + strings.Join(node.markers, " ")
+	}
+
+	// Selected rows are plain, so the selection background runs through
+	if selected {
+		row := ansi.Truncate(indent+arrow+name+markers, width, "…")
+		return t.styles.Selected.Render(row + strings.Repeat(" ", max(width-ansi.StringWidth(row), 0)))
+	}
+	row := indent + t.styles.Arrow.Render(arrow) + t.styles.Dir.Render(name) + t.styles.Marker.Render(markers)
+	return ansi.Truncate(row, width, "…")
+}
+
+// newDirNode creates a node for a directory
+func newDirNode(path string, depth int) *dirNode {
+	return &dirNode{
+		path:    path,
+		name:    filepath.Base(path),
+		depth:   depth,
+		markers: discovery.FindMarkers(path),
+	}
+}
+
+// load reads the subdirectories of a node once, leaving out hidden ones
+// and dependencies
+func (n *dirNode) load() {
+	if n.loaded {
+		return
+	}
+	n.loaded = true
+
+	entries, err := os.ReadDir(n.path)
+	if err != nil {
+		return
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || strings.HasPrefix(name, ".") || name == "node_modules" {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		n.children = append(n.children, newDirNode(filepath.Join(n.path, name), n.depth+1))
+	}
+}
@@ -0,0 +1,377 @@
+package components
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/paralerdev/paraler/internal/config"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Fields of the service editor, in display order
+const (
+	EditorName = iota // Only shown when adding a service
+	EditorCmd
+	EditorCwd
+	EditorPort
+	EditorHealth
+	EditorEnv
+	EditorDependsOn
+	EditorAutoRestart
+	editorFieldCount
+)
+
+// editorLabels are the config keys the fields edit
+var editorLabels = [editorFieldCount]string{
+	EditorName:        "name",
+	EditorCmd:         "cmd",
+	EditorCwd:         "cwd",
+	EditorPort:        "port",
+	EditorHealth:      "health",
+	EditorEnv:         "env",
+	EditorDependsOn:   "depends_on",
+	EditorAutoRestart: "auto_restart",
+}
+
+// ServiceEditor is a modal for editing the common settings of a service,
+// or adding a service to a project
+type ServiceEditor struct {
+	visible     bool
+	adding      bool // Adding a service rather than editing one
+	serviceID   config.ServiceID
+	service     config.Service // Config being edited, for the fields not shown
+	inputs      [editorFieldCount]textinput.Model
+	autoRestart bool
+	focused     int
+	errorMsg    string
+	width       int
+	styles      ServiceEditorStyles
+}
+
+// ServiceEditorStyles contains styles for the modal
+type ServiceEditorStyles struct {
+	Container    lipgloss.Style
+	Title        lipgloss.Style
+	Label        lipgloss.Style
+	LabelFocused lipgloss.Style
+	Error        lipgloss.Style
+	Help         lipgloss.Style
+}
+
+// DefaultServiceEditorStyles returns default styles
+func DefaultServiceEditorStyles() ServiceEditorStyles {
+	return ServiceEditorStyles{
+		Container: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(theme.Primary).
+			Padding(1, 2),
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Primary),
+		Label: lipgloss.NewStyle().
+			Foreground(theme.Subtle).
+			Width(14),
+		LabelFocused: lipgloss.NewStyle().
+			Foreground(theme.Primary).
+			Bold(true).
+			Width(14),
+		Error: lipgloss.NewStyle().
+			Foreground(theme.Error).
+			MarginTop(1),
+		Help: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			MarginTop(1),
+	}
+}
+
+// NewServiceEditor creates a new service editor
+func NewServiceEditor() *ServiceEditor {
+	e := &ServiceEditor{
+		styles: DefaultServiceEditorStyles(),
+	}
+	placeholders := [editorFieldCount]string{
+		EditorName:      "redis",
+		EditorCmd:       "npm run dev",
+		EditorCwd:       "project path",
+		EditorPort:      "none",
+		EditorHealth:    "http://localhost:3000/health",
+		EditorEnv:       "KEY=value, OTHER=value",
+		EditorDependsOn: "db, cache",
+	}
+	for i := range e.inputs {
+		ti := textinput.New()
+		ti.Placeholder = placeholders[i]
+		ti.CharLimit = 1024
+		ti.Width = 40
+		e.inputs[i] = ti
+	}
+	return e
+}
+
+// SetSize sets the modal width
+func (e *ServiceEditor) SetSize(width int) {
+	e.width = width
+	for i := range e.inputs {
+		e.inputs[i].Width = width - 24
+	}
+}
+
+// Show shows the editor filled in with a service's config
+func (e *ServiceEditor) Show(id config.ServiceID, service config.Service) {
+	e.serviceID = id
+	e.service = service
+	e.errorMsg = ""
+
+	health := service.Health
+	if service.HealthCmd != "" {
+		health = service.HealthCmd
+	}
+	port := ""
+	if service.Port > 0 {
+		port = strconv.Itoa(service.Port)
+	}
+	values := [editorFieldCount]string{
+		EditorCmd:       service.Cmd,
+		EditorCwd:       service.Cwd,
+		EditorPort:      port,
+		EditorHealth:    health,
+		EditorEnv:       strings.Join(service.Env, ", "),
+		EditorDependsOn: strings.Join(service.DependsOn, ", "),
+	}
+	for i := range e.inputs {
+		e.inputs[i].SetValue(values[i])
+		e.inputs[i].CursorEnd()
+	}
+	e.autoRestart = service.AutoRestart
+	e.adding = false
+	e.focus(EditorCmd)
+	e.visible = true
+}
+
+// ShowNew shows the editor empty, to add a service to a project
+func (e *ServiceEditor) ShowNew(project string) {
+	e.serviceID = config.ServiceID{Project: project}
+	e.service = config.Service{}
+	e.errorMsg = ""
+	for i := range e.inputs {
+		e.inputs[i].SetValue("")
+	}
+	e.autoRestart = false
+	e.adding = true
+	e.focus(EditorName)
+	e.visible = true
+}
+
+// IsAdding returns true if the editor adds a service
+func (e *ServiceEditor) IsAdding() bool {
+	return e.adding
+}
+
+// Name returns the name of the service being added
+func (e *ServiceEditor) Name() string {
+	return strings.TrimSpace(e.inputs[EditorName].Value())
+}
+
+// Hide hides the modal
+func (e *ServiceEditor) Hide() {
+	e.visible = false
+	e.inputs[e.focused].Blur()
+}
+
+// IsVisible returns true if modal is visible
+func (e *ServiceEditor) IsVisible() bool {
+	return e.visible
+}
+
+// ServiceID returns the service being edited
+func (e *ServiceEditor) ServiceID() config.ServiceID {
+	return e.serviceID
+}
+
+// Next focuses the next field
+func (e *ServiceEditor) Next() {
+	next := (e.focused + 1) % editorFieldCount
+	if next == EditorName && !e.adding {
+		next++
+	}
+	e.focus(next)
+}
+
+// Prev focuses the previous field
+func (e *ServiceEditor) Prev() {
+	prev := (e.focused + editorFieldCount - 1) % editorFieldCount
+	if prev == EditorName && !e.adding {
+		prev = editorFieldCount - 1
+	}
+	e.focus(prev)
+}
+
+// focus moves the cursor to a field
+func (e *ServiceEditor) focus(field int) {
+	e.inputs[e.focused].Blur()
+	e.focused = field
+	if field != EditorAutoRestart {
+		e.inputs[field].Focus()
+	}
+}
+
+// Focused returns the focused field
+func (e *ServiceEditor) Focused() int {
+	return e.focused
+}
+
+// ToggleAutoRestart switches auto_restart on or off
+func (e *ServiceEditor) ToggleAutoRestart() {
+	e.autoRestart = !e.autoRestart
+}
+
+// Input returns the text input of the focused field, or nil for the
+// auto_restart switch
+func (e *ServiceEditor) Input() *textinput.Model {
+	if e.focused == EditorAutoRestart {
+		return nil
+	}
+	return &e.inputs[e.focused]
+}
+
+// SetError sets an error message
+func (e *ServiceEditor) SetError(err string) {
+	e.errorMsg = err
+}
+
+// Service returns the service config with the edited fields
+func (e *ServiceEditor) Service() (config.Service, error) {
+	service := e.service
+
+	cmd := strings.TrimSpace(e.inputs[EditorCmd].Value())
+	if cmd != service.Cmd {
+		// An edited list cmd becomes a shell command line
+		service.Cmd = cmd
+		service.Argv = nil
+	}
+	service.Cwd = config.ExpandPath(strings.TrimSpace(e.inputs[EditorCwd].Value()))
+
+	service.Port = 0
+	if port := strings.TrimSpace(e.inputs[EditorPort].Value()); port != "" {
+		n, err := strconv.Atoi(port)
+		if err != nil || n < 1 || n > 65535 {
+			return service, fmt.Errorf("port: %q is not a port number", port)
+		}
+		service.Port = n
+	}
+
+	health := strings.TrimSpace(e.inputs[EditorHealth].Value())
+	if service.HealthCmd != "" {
+		service.HealthCmd = health
+	} else {
+		service.Health = health
+	}
+
+	service.Env = splitEnv(e.inputs[EditorEnv].Value())
+	for _, env := range service.Env {
+		if !strings.Contains(env, "=") {
+			return service, fmt.Errorf("env: %q is not KEY=value", env)
+		}
+	}
+	service.DependsOn = splitList(e.inputs[EditorDependsOn].Value())
+	service.AutoRestart = e.autoRestart
+	return service, nil
+}
+
+// splitList splits a comma-separated list, dropping empty items
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// envKey matches the start of a KEY=value item
+var envKey = regexp.MustCompile(`^\s*[A-Za-z_][A-Za-z0-9_]*=`)
+
+// splitEnv splits a comma-separated list of KEY=value items. Only commas
+// followed by a KEY= separate items, so values may contain commas.
+func splitEnv(s string) []string {
+	var items []string
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		if len(items) > 0 && !envKey.MatchString(part) {
+			items[len(items)-1] += "," + part
+			continue
+		}
+		items = append(items, part)
+	}
+
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
+
+// View renders the modal
+func (e *ServiceEditor) View() string {
+	if !e.visible {
+		return ""
+	}
+
+	var b strings.Builder
+
+	if e.adding {
+		b.WriteString(e.styles.Title.Render("Add Service to " + e.serviceID.Project))
+	} else {
+		b.WriteString(e.styles.Title.Render("Edit Service: " + e.serviceID.Base().String()))
+	}
+	b.WriteString("\n\n")
+
+	for i := range e.inputs {
+		if i == EditorName && !e.adding {
+			continue
+		}
+		label := editorLabels[i]
+		if i == EditorHealth && e.service.HealthCmd != "" {
+			label = "health cmd"
+		}
+		if i == e.focused {
+			b.WriteString(e.styles.LabelFocused.Render(label))
+		} else {
+			b.WriteString(e.styles.Label.Render(label))
+		}
+
+		if i == EditorAutoRestart {
+			check := "[ ]"
+			if e.autoRestart {
+				check = "[x]"
+			}
+			if i == e.focused {
+				check = e.styles.LabelFocused.Render(check)
+			}
+			b.WriteString(" " + check)
+		} else {
+			b.WriteString(e.inputs[i].View())
+		}
+		b.WriteString("\n")
+	}
+
+	if e.errorMsg != "" {
+		b.WriteString(e.styles.Error.Render(e.errorMsg))
+		b.WriteString("\n")
+	}
+
+	if e.adding {
+		b.WriteString(e.styles.Help.Render("Tab/↑↓ field • space toggle • enter add • Esc cancel"))
+	} else {
+		b.WriteString(e.styles.Help.Render("Tab/↑↓ field • space toggle • enter save • Esc cancel"))
+	}
+
+	return e.styles.Container.
+		Width(e.width).
+		Render(b.String())
+}
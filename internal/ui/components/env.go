@@ -0,0 +1,294 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/paralerdev/paraler/internal/config"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// EnvVar is an environment variable passed to a service
+type EnvVar struct {
+	Key    string
+	Value  string
+	Source string // "config", or "paraler" for variables paraler sets
+}
+
+// String returns the variable as KEY=value
+func (v EnvVar) String() string {
+	return v.Key + "=" + v.Value
+}
+
+// Secret returns true if the key names something to keep off screen
+func (v EnvVar) Secret() bool {
+	return IsSecretEnvKey(v.Key)
+}
+
+// secretKeyParts are the words of a key that mark its value as secret, on
+// their own or at the end of a word, as in APIKEY
+var secretKeyParts = []string{"SECRET", "TOKEN", "PASSWORD", "PASSWD", "PASS", "KEY", "CREDENTIAL", "AUTH", "PRIVATE", "SALT", "DSN"}
+
+// IsSecretEnvKey returns true if an environment variable's key looks like
+// it holds a secret, such as API_KEY or DB_PASSWORD
+func IsSecretEnvKey(key string) bool {
+	for _, part := range strings.FieldsFunc(strings.ToUpper(key), func(r rune) bool {
+		return r == '_' || r == '-' || r == '.'
+	}) {
+		for _, secret := range secretKeyParts {
+			if strings.HasSuffix(part, secret) || strings.HasSuffix(part, secret+"S") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MaskEnv masks the value of a KEY=value entry if its key looks secret
+func MaskEnv(entry string) string {
+	k, _, ok := strings.Cut(entry, "=")
+	if ok && IsSecretEnvKey(k) {
+		return k + "=" + envMask
+	}
+	return entry
+}
+
+// envMask replaces secret values, hiding their length too
+const envMask = "••••••••"
+
+// ResolveEnv lists the variables a service gets on top of paraler's own
+// environment, sorted by key. configured is the service's env from the
+// config and resolved the env of its current run; later entries win.
+func ResolveEnv(configured, resolved []string) []EnvVar {
+	fromConfig := make(map[string]bool, len(configured))
+	for _, entry := range configured {
+		fromConfig[entry] = true
+	}
+
+	index := make(map[string]int)
+	var vars []EnvVar
+	for _, entry := range resolved {
+		k, v, _ := strings.Cut(entry, "=")
+		if k == "" {
+			continue
+		}
+		source := "paraler"
+		if fromConfig[entry] {
+			source = "config"
+		}
+		if i, ok := index[k]; ok {
+			vars[i] = EnvVar{Key: k, Value: v, Source: source}
+			continue
+		}
+		index[k] = len(vars)
+		vars = append(vars, EnvVar{Key: k, Value: v, Source: source})
+	}
+
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Key < vars[j].Key })
+	return vars
+}
+
+// EnvModal lists the environment of a service, masking secrets until
+// revealed
+type EnvModal struct {
+	visible   bool
+	serviceID config.ServiceID
+	vars      []EnvVar
+	selected  int
+	offset    int // First variable shown
+	revealed  bool
+	width     int
+	height    int
+	styles    EnvStyles
+}
+
+// EnvStyles contains styles for the modal
+type EnvStyles struct {
+	Container lipgloss.Style
+	Title     lipgloss.Style
+	Key       lipgloss.Style
+	Value     lipgloss.Style
+	Masked    lipgloss.Style
+	Source    lipgloss.Style
+	Selected  lipgloss.Style
+	Empty     lipgloss.Style
+	Help      lipgloss.Style
+}
+
+// DefaultEnvStyles returns default styles
+func DefaultEnvStyles() EnvStyles {
+	return EnvStyles{
+		Container: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(theme.Accent).
+			Padding(1, 2),
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Accent),
+		Key: lipgloss.NewStyle().
+			Foreground(theme.Primary),
+		Value: lipgloss.NewStyle().
+			Foreground(theme.Text),
+		Masked: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+		Source: lipgloss.NewStyle().
+			Foreground(theme.Subtle),
+		Selected: selectionStyle(theme.Surface),
+		Empty: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+		Help: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			MarginTop(1),
+	}
+}
+
+// NewEnvModal creates a new environment modal
+func NewEnvModal() *EnvModal {
+	return &EnvModal{
+		styles: DefaultEnvStyles(),
+	}
+}
+
+// SetSize sets the modal dimensions
+func (m *EnvModal) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Show shows the environment of a service, with secrets masked
+func (m *EnvModal) Show(serviceID config.ServiceID, vars []EnvVar) {
+	m.visible = true
+	m.serviceID = serviceID
+	m.vars = vars
+	m.selected = 0
+	m.offset = 0
+	m.revealed = false
+}
+
+// Hide hides the modal
+func (m *EnvModal) Hide() {
+	m.visible = false
+	m.vars = nil
+}
+
+// IsVisible returns true if modal is visible
+func (m *EnvModal) IsVisible() bool {
+	return m.visible
+}
+
+// MoveUp selects the previous variable
+func (m *EnvModal) MoveUp() {
+	if m.selected > 0 {
+		m.selected--
+	}
+}
+
+// MoveDown selects the next variable
+func (m *EnvModal) MoveDown() {
+	if m.selected < len(m.vars)-1 {
+		m.selected++
+	}
+}
+
+// ToggleReveal shows or masks secret values
+func (m *EnvModal) ToggleReveal() {
+	m.revealed = !m.revealed
+}
+
+// Selected returns the selected variable, if any
+func (m *EnvModal) Selected() (EnvVar, bool) {
+	if m.selected >= 0 && m.selected < len(m.vars) {
+		return m.vars[m.selected], true
+	}
+	return EnvVar{}, false
+}
+
+// Vars returns every variable listed
+func (m *EnvModal) Vars() []EnvVar {
+	return m.vars
+}
+
+// rows returns the number of variables shown at once
+func (m *EnvModal) rows() int {
+	// Borders, padding, title and help
+	return max(m.height-10, 3)
+}
+
+// View renders the modal
+func (m *EnvModal) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	innerWidth := max(m.width-4, 1) // padding
+	var b strings.Builder
+
+	title := "Environment: " + m.serviceID.String()
+	if len(m.vars) > 0 {
+		title += fmt.Sprintf(" (%d)", len(m.vars))
+	}
+	b.WriteString(m.styles.Title.Render(ansi.Truncate(title, innerWidth, "…")))
+	b.WriteString("\n\n")
+
+	if len(m.vars) == 0 {
+		b.WriteString(m.styles.Empty.Render("No variables set besides paraler's own environment"))
+		b.WriteString("\n")
+	}
+
+	// Scroll to keep the selected variable visible
+	rows := m.rows()
+	if m.selected < m.offset {
+		m.offset = m.selected
+	}
+	if m.selected >= m.offset+rows {
+		m.offset = m.selected - rows + 1
+	}
+	m.offset = max(min(m.offset, len(m.vars)-rows), 0)
+
+	keyWidth := 0
+	for _, v := range m.vars {
+		keyWidth = max(keyWidth, len(v.Key))
+	}
+	keyWidth = min(keyWidth, innerWidth/2)
+
+	for i := m.offset; i < len(m.vars) && i < m.offset+rows; i++ {
+		b.WriteString(m.renderVar(m.vars[i], i == m.selected, keyWidth, innerWidth))
+		b.WriteString("\n")
+	}
+
+	help := "↑↓ select • y copy • Y copy all • v reveal • Esc/I close"
+	if m.revealed {
+		help = "↑↓ select • y copy • Y copy all • v mask • Esc/I close"
+	}
+	if len(m.vars) > rows {
+		help += " • " + positionText(m.offset, min(m.offset+rows, len(m.vars)), len(m.vars))
+	}
+	b.WriteString(m.styles.Help.Render(ansi.Truncate(help, innerWidth, "…")))
+
+	return m.styles.Container.
+		Width(m.width).
+		Render(b.String())
+}
+
+// renderVar renders a variable as key, value and where it comes from
+func (m *EnvModal) renderVar(v EnvVar, selected bool, keyWidth, innerWidth int) string {
+	source := " " + v.Source
+	k := ansi.Truncate(v.Key, keyWidth, "…")
+	k += strings.Repeat(" ", max(keyWidth-ansi.StringWidth(k), 0)) + "  "
+	valueWidth := max(innerWidth-ansi.StringWidth(k)-ansi.StringWidth(source)-1, 1)
+
+	value, valueStyle := v.Value, m.styles.Value
+	if v.Secret() && !m.revealed {
+		value, valueStyle = envMask, m.styles.Masked
+	}
+	value = ansi.Truncate(value, valueWidth, "…")
+	value += strings.Repeat(" ", max(valueWidth-ansi.StringWidth(value), 0)) + " "
+
+	// Selected rows are plain, so the selection background runs through
+	if selected {
+		return m.styles.Selected.Render(k + value + source)
+	}
+	return m.styles.Key.Render(k) + valueStyle.Render(value) + m.styles.Source.Render(source)
+}
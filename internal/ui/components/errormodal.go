@@ -0,0 +1,109 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ErrorModal shows why an action running in the background failed, and
+// what may fix it
+type ErrorModal struct {
+	visible bool
+	title   string
+	reason  string
+	hint    string
+	width   int
+	styles  ErrorModalStyles
+}
+
+// ErrorModalStyles contains styles for the modal
+type ErrorModalStyles struct {
+	Container lipgloss.Style
+	Title     lipgloss.Style
+	Reason    lipgloss.Style
+	Hint      lipgloss.Style
+	Help      lipgloss.Style
+}
+
+// DefaultErrorModalStyles returns default styles
+func DefaultErrorModalStyles() ErrorModalStyles {
+	return ErrorModalStyles{
+		Container: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(theme.Error).
+			Padding(1, 2),
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Error),
+		Reason: lipgloss.NewStyle().
+			Foreground(theme.Text),
+		Hint: lipgloss.NewStyle().
+			Foreground(theme.Subtle),
+		Help: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			MarginTop(1),
+	}
+}
+
+// NewErrorModal creates a new error modal
+func NewErrorModal() *ErrorModal {
+	return &ErrorModal{
+		styles: DefaultErrorModalStyles(),
+	}
+}
+
+// SetSize sets the modal width
+func (m *ErrorModal) SetSize(width int) {
+	m.width = width
+}
+
+// Show shows a failure with its reason and, if known, a suggested fix
+func (m *ErrorModal) Show(title string, err error, hint string) {
+	m.visible = true
+	m.title = title
+	m.reason = err.Error()
+	m.hint = hint
+}
+
+// Hide hides the modal
+func (m *ErrorModal) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns true if modal is visible
+func (m *ErrorModal) IsVisible() bool {
+	return m.visible
+}
+
+// Text returns the failure as plain text, for the clipboard
+func (m *ErrorModal) Text() string {
+	return m.title + ": " + m.reason
+}
+
+// View renders the modal
+func (m *ErrorModal) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	innerWidth := max(m.width-4, 1) // padding
+	var b strings.Builder
+
+	b.WriteString(m.styles.Title.Render("✖ " + m.title))
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.Reason.Width(innerWidth).Render(m.reason))
+	b.WriteString("\n")
+
+	if m.hint != "" {
+		b.WriteString("\n")
+		b.WriteString(m.styles.Hint.Width(innerWidth).Render("→ " + m.hint))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(m.styles.Help.Render("Enter/Esc close • y copy error"))
+
+	return m.styles.Container.
+		Width(m.width).
+		Render(b.String())
+}
@@ -0,0 +1,63 @@
+package components
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Bonuses of fuzzyScore for matches that are likely what was meant
+const (
+	fuzzyMatchScore       = 1
+	fuzzyConsecutiveBonus = 4 // matched right after the previous match
+	fuzzyWordStartBonus   = 6 // matched at the start of the text or a word
+	fuzzyGapPenalty       = 1 // per character skipped between matches
+)
+
+// fuzzyScore matches pattern as a case-insensitive subsequence of text,
+// returning false if it doesn't match. Higher scores are better matches:
+// consecutive characters and characters starting words (after "/", "-",
+// "_", "." or a space) score more, skipped characters less.
+func fuzzyScore(pattern, text string) (int, bool) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" {
+		return 0, true
+	}
+	text = strings.ToLower(text)
+
+	score := 0
+	last := -1 // index in text of the previous match
+	prev := rune(0)
+	p, size := utf8.DecodeRuneInString(pattern)
+	i := 0
+	for _, r := range text {
+		if r == p {
+			score += fuzzyMatchScore
+			switch {
+			case last >= 0 && last == i-1:
+				score += fuzzyConsecutiveBonus
+			case i == 0 || isWordSeparator(prev):
+				score += fuzzyWordStartBonus
+			}
+			if last >= 0 {
+				score -= (i - last - 1) * fuzzyGapPenalty
+			}
+			last = i
+
+			pattern = pattern[size:]
+			if pattern == "" {
+				return score, true
+			}
+			p, size = utf8.DecodeRuneInString(pattern)
+		}
+		prev = r
+		i++
+	}
+	return 0, false
+}
+
+// isWordSeparator returns true for the characters separating words in
+// project and service names
+func isWordSeparator(r rune) bool {
+	return r == '/' || r == '-' || r == '_' || r == '.' || r == '#' || unicode.IsSpace(r)
+}
@@ -0,0 +1,176 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// HelpKey is a key and what it does
+type HelpKey struct {
+	Key  string
+	Desc string
+}
+
+// HelpGroup is a titled group of keys, such as the keys of a panel
+type HelpGroup struct {
+	Title string
+	Keys  []HelpKey
+}
+
+const (
+	helpKeyWidth    = 14 // Width of the keys of an entry
+	helpColumnWidth = 38 // Width of an entry, keys and description
+)
+
+// HelpScreen lists every key binding, grouped by where it applies, and
+// scrolls when they don't fit
+type HelpScreen struct {
+	groups []HelpGroup
+	offset int // First line shown
+	width  int
+	height int
+	styles HelpScreenStyles
+}
+
+// HelpScreenStyles contains help screen styles
+type HelpScreenStyles struct {
+	Container lipgloss.Style
+	Title     lipgloss.Style
+	Group     lipgloss.Style
+	Key       lipgloss.Style
+	Desc      lipgloss.Style
+	Help      lipgloss.Style
+}
+
+// DefaultHelpScreenStyles returns default styles
+func DefaultHelpScreenStyles() HelpScreenStyles {
+	return HelpScreenStyles{
+		Container: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(theme.Primary).
+			Padding(0, 1),
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Primary),
+		Group: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Accent),
+		Key: lipgloss.NewStyle().
+			Foreground(theme.Primary).
+			Bold(true),
+		Desc: lipgloss.NewStyle().
+			Foreground(theme.Subtle),
+		Help: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+	}
+}
+
+// NewHelpScreen creates a help screen listing groups of keys
+func NewHelpScreen(groups []HelpGroup) *HelpScreen {
+	return &HelpScreen{
+		groups: groups,
+		styles: DefaultHelpScreenStyles(),
+	}
+}
+
+// SetSize sets the screen dimensions
+func (h *HelpScreen) SetSize(width, height int) {
+	h.width = width
+	h.height = height
+	h.clampOffset()
+}
+
+// ScrollUp scrolls up by n lines
+func (h *HelpScreen) ScrollUp(n int) {
+	h.offset -= n
+	h.clampOffset()
+}
+
+// ScrollDown scrolls down by n lines
+func (h *HelpScreen) ScrollDown(n int) {
+	h.offset += n
+	h.clampOffset()
+}
+
+// ScrollTop scrolls to the first line
+func (h *HelpScreen) ScrollTop() {
+	h.offset = 0
+}
+
+// ScrollBottom scrolls to the last line
+func (h *HelpScreen) ScrollBottom() {
+	h.offset = len(h.lines())
+	h.clampOffset()
+}
+
+// PageHeight returns the number of lines shown at once
+func (h *HelpScreen) PageHeight() int {
+	// Borders, title and the hint below the keys
+	return max(h.height-6, 1)
+}
+
+// clampOffset keeps the last page filled
+func (h *HelpScreen) clampOffset() {
+	h.offset = max(min(h.offset, len(h.lines())-h.PageHeight()), 0)
+}
+
+// lines lays the groups out below each other, with their keys in as many
+// columns as fit
+func (h *HelpScreen) lines() []string {
+	innerWidth := max(h.width-4, 1)
+	columns := max(innerWidth/helpColumnWidth, 1)
+
+	var lines []string
+	for i, group := range h.groups {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, h.styles.Group.Render(group.Title))
+		for row := 0; row < len(group.Keys); row += columns {
+			var b strings.Builder
+			for _, k := range group.Keys[row:min(row+columns, len(group.Keys))] {
+				key := h.styles.Key.Render(k.Key)
+				cell := "  " + key + strings.Repeat(" ", max(helpKeyWidth-lipgloss.Width(k.Key), 1)) + h.styles.Desc.Render(k.Desc)
+				cell = ansi.Truncate(cell, helpColumnWidth-1, "…")
+				b.WriteString(cell + strings.Repeat(" ", max(helpColumnWidth-lipgloss.Width(cell), 0)))
+			}
+			lines = append(lines, ansi.Truncate(strings.TrimRight(b.String(), " "), innerWidth, ""))
+		}
+	}
+	return lines
+}
+
+// View renders the help screen
+func (h *HelpScreen) View() string {
+	lines := h.lines()
+	page := h.PageHeight()
+	end := min(h.offset+page, len(lines))
+
+	var b strings.Builder
+	b.WriteString(h.styles.Title.Render("Keybindings"))
+	b.WriteString("\n\n")
+	b.WriteString(strings.Join(lines[h.offset:end], "\n"))
+	for range page - (end - h.offset) {
+		b.WriteString("\n")
+	}
+	b.WriteString("\n\n")
+
+	hint := "↑↓/pgup/pgdn scroll • ?/Esc/q close"
+	if len(lines) > page {
+		hint += " • " + positionText(h.offset, end, len(lines))
+	}
+	b.WriteString(h.styles.Help.Render(ansi.Truncate(hint, max(h.width-4, 1), "…")))
+
+	return h.styles.Container.
+		Width(max(h.width-2, 1)).
+		Height(max(h.height-2, 1)).
+		Render(b.String())
+}
+
+// positionText describes the lines shown out of a total
+func positionText(start, end, total int) string {
+	return fmt.Sprintf("%d–%d/%d", start+1, end, total)
+}
@@ -0,0 +1,156 @@
+package components
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Keys structured loggers (zap, slog, pino, logrus, bunyan...) use for the
+// level, message and timestamp of a JSON log line
+var (
+	jsonLevelKeys = []string{"level", "lvl", "severity", "log.level"}
+	jsonMsgKeys   = []string{"msg", "message"}
+	jsonTimeKeys  = []string{"time", "ts", "timestamp", "@timestamp"}
+)
+
+// jsonLog is a parsed JSON log line
+type jsonLog struct {
+	level     LogLevel
+	levelName string
+	msg       string
+	object    map[string]any
+	fields    []string // keys shown inline, in display order
+}
+
+// parseJSONLog parses a line holding a JSON object. Fields other than the
+// level, message and timestamp are shown inline: those listed in selected,
+// or all of them in key order if selected is empty.
+func parseJSONLog(line string, selected []string) (jsonLog, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "{") || !strings.HasSuffix(trimmed, "}") {
+		return jsonLog{}, false
+	}
+
+	dec := json.NewDecoder(strings.NewReader(trimmed))
+	dec.UseNumber()
+	var object map[string]any
+	if err := dec.Decode(&object); err != nil {
+		return jsonLog{}, false
+	}
+
+	entry := jsonLog{object: object}
+	levelKey := firstKey(object, jsonLevelKeys)
+	if levelKey != "" {
+		entry.level, entry.levelName = parseJSONLevel(object[levelKey])
+	}
+	msgKey := firstKey(object, jsonMsgKeys)
+	if msgKey != "" {
+		entry.msg = formatJSONValue(object[msgKey])
+	}
+	timeKey := firstKey(object, jsonTimeKeys)
+
+	if len(selected) > 0 {
+		for _, key := range selected {
+			if _, ok := object[key]; ok {
+				entry.fields = append(entry.fields, key)
+			}
+		}
+		return entry, true
+	}
+
+	for key := range object {
+		if key != levelKey && key != msgKey && key != timeKey {
+			entry.fields = append(entry.fields, key)
+		}
+	}
+	sort.Strings(entry.fields)
+	return entry, true
+}
+
+// firstKey returns the first of keys present in object
+func firstKey(object map[string]any, keys []string) string {
+	for _, key := range keys {
+		if _, ok := object[key]; ok {
+			return key
+		}
+	}
+	return ""
+}
+
+// parseJSONLevel parses a level name, or a numeric pino/bunyan level
+func parseJSONLevel(value any) (LogLevel, string) {
+	if n, ok := value.(json.Number); ok {
+		level, err := n.Int64()
+		if err != nil {
+			return LogLevelNormal, n.String()
+		}
+		switch {
+		case level >= 50:
+			return LogLevelError, "ERROR"
+		case level >= 40:
+			return LogLevelWarn, "WARN"
+		case level >= 30:
+			return LogLevelInfo, "INFO"
+		default:
+			return LogLevelDebug, "DEBUG"
+		}
+	}
+
+	name := strings.ToUpper(formatJSONValue(value))
+	switch name {
+	case "TRACE", "DEBUG", "VERBOSE":
+		return LogLevelDebug, name
+	case "INFO", "NOTICE":
+		return LogLevelInfo, name
+	case "WARN", "WARNING":
+		return LogLevelWarn, "WARN"
+	case "ERROR", "ERR", "FATAL", "PANIC", "DPANIC", "CRITICAL", "CRIT", "ALERT", "EMERGENCY", "EMERG":
+		return LogLevelError, name
+	default:
+		return LogLevelNormal, name
+	}
+}
+
+// formatJSONValue formats a value compactly: strings without quotes,
+// everything else as JSON
+func formatJSONValue(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// formatInlineValue formats a value shown as key=value, quoting strings
+// with spaces or line breaks logfmt-style so fields stay distinguishable
+func formatInlineValue(value any) string {
+	s := formatJSONValue(value)
+	if _, ok := value.(string); ok && (s == "" || strings.ContainsAny(s, " =\"\t\r\n")) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// formatJSONValueIndented formats a value for the expanded view, spreading
+// objects and arrays over several lines
+func formatJSONValueIndented(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		return string(data)
+	}
+	return out.String()
+}
@@ -0,0 +1,67 @@
+package components
+
+import (
+	"regexp"
+
+	"github.com/paralerdev/paraler/internal/config"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// levelRule is a compiled config.LevelRule
+type levelRule struct {
+	re    *regexp.Regexp
+	level LogLevel // LogLevelNormal if the rule only sets a color
+	style *lipgloss.Style
+}
+
+// compileLevelRules compiles the level rules of every service in cfg,
+// keyed by project and service name. Invalid patterns are skipped; the
+// config is validated when loaded.
+func compileLevelRules(cfg *config.Config) map[config.ServiceID][]levelRule {
+	rules := make(map[config.ServiceID][]levelRule)
+	for projectName, project := range cfg.Projects {
+		for serviceName, service := range project.Services {
+			id := config.ServiceID{Project: projectName, Service: serviceName}
+			for _, rule := range service.LogLevels {
+				re, err := regexp.Compile(rule.Match)
+				if err != nil {
+					continue
+				}
+				compiled := levelRule{re: re}
+				if rule.Level != "" {
+					compiled.level, _ = parseJSONLevel(rule.Level)
+				}
+				if rule.Color != "" {
+					style := lipgloss.NewStyle().Foreground(lipgloss.Color(rule.Color))
+					compiled.style = &style
+				}
+				rules[id] = append(rules[id], compiled)
+			}
+		}
+	}
+	return rules
+}
+
+// SetLevelRules sets the rules that set the level and color of the lines
+// of each service in cfg
+func (l *LogPanel) SetLevelRules(cfg *config.Config) {
+	l.levelRules = compileLevelRules(cfg)
+	l.rulesChanged++
+}
+
+// detectLevel returns the level of a line of a service, and the style of
+// the rule it matches if that sets a color. ruled is true if a rule
+// matched, in which case stderr no longer implies an error.
+func (l *LogPanel) detectLevel(id config.ServiceID, line string) (level LogLevel, style *lipgloss.Style, ruled bool) {
+	for _, rule := range l.levelRules[config.ServiceID{Project: id.Project, Service: id.Service}] {
+		if !rule.re.MatchString(line) {
+			continue
+		}
+		level = rule.level
+		if level == LogLevelNormal {
+			level = detectLogLevel(line)
+		}
+		return level, rule.style, true
+	}
+	return detectLogLevel(line), nil, false
+}
@@ -2,13 +2,20 @@ package components
 
 import (
 	"fmt"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/paralerdev/paraler/internal/config"
 	"github.com/paralerdev/paraler/internal/log"
 	"github.com/paralerdev/paraler/internal/process"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // LogPanel displays logs for a selected service
@@ -17,8 +24,14 @@ type LogPanel struct {
 	serviceID     config.ServiceID
 	serviceConfig *config.Service
 	serviceStatus process.Status
-	filter        string
+	serviceStats  process.Stats
+	servicePort   int // Port the service runs on, 0 when it doesn't run
+	healthErr     error // Why the service's health check fails, nil if it doesn't
+	spinner       int // Tick of the status spinner
+	filter        *log.Filter
+	filterErr     error // Error of the expression being typed
 	filtering     bool
+	highlight     bool // Highlight filter matches instead of hiding other lines
 	autoScroll    bool
 	scrollOffset  int
 	width         int
@@ -28,14 +41,99 @@ type LogPanel struct {
 	lines         []string
 	rawLines      []string // Lines without styling for copying
 	viewHeight    int
+	expandJSON    bool // Show every field of JSON log lines on its own line
+	jumpTo        int  // Buffer index of an entry to scroll to, or -1
+
+	// Show lines without the colors services print them in
+	stripColors bool
+
+	// Format of the timestamps lines start with (see config.TimestampFormats)
+	timestamps string
+
+	// Rows shown, lines wrapped over several rows in wrap mode. rowLines
+	// holds the line of each row and lineRows the first row of each line.
+	wrap     bool
+	rows     []string
+	rowLines []int
+	lineRows []int
+
+	// Lines from the bottom the view is kept at while history loads, or -1
+	holdBottom int
+
+	// Levels shown per service, and the rules setting the level of lines
+	// by project and service name
+	levelFilters map[config.ServiceID]LevelFilter
+	stderrOnly   map[config.ServiceID]bool
+	levelRules   map[config.ServiceID][]levelRule
+
+	// Entries shown, and the index in entries of each line, for exporting
+	entries     []log.Entry
+	lineEntries []int
+
+	// Bookmarked entries per service, and the lines shown of bookmarked
+	// entries
+	bookmarks     map[config.ServiceID][]log.Entry
+	bookmarkLines []int
+
+	// Entries shown, rendered; bookmarksChanged and rulesChanged count
+	// changes that render them again
+	cache            renderCache
+	bookmarksChanged int
+	rulesChanged     int
+
+	// First lines of each run of error lines
+	errorLines []int
+
+	// The bookmark or error line jumped to last, while the view hasn't
+	// scrolled since
+	lastJumpLine   int
+	lastJumpOffset int
+
+	// Services interleaved in the merged view, and their prefix colors
+	mergedIDs    []config.ServiceID
+	mergedColors map[config.ServiceID]string
+
+	// Entries added to each service shown when the view stopped following
+	// new lines, to count those added since
+	pausedAt map[config.ServiceID]uint64
+
+	// Filter and scroll position of each service left, restored when it's
+	// selected again, and the entry to show at the top on the next update
+	views     map[config.ServiceID]serviceView
+	restoreTo *uint64
 
 	// Copy mode state
 	copyMode        bool
 	copyCursor      int  // Current cursor position in copy mode
-	copySelecting   bool // Whether we're selecting (after pressing v)
-	copySelectStart int  // Start of selection
+	copyCol         int  // Column of the cursor, in runes of the raw line
+	copyScroll      int  // Columns scrolled right, when lines don't wrap
+	copySelecting   bool // Whether we're selecting (after pressing v, c or ctrl+v)
+	copySelection   CopySelection
+	copySelectStart int // Start of selection
+	copySelectCol   int // Column the selection started at
 }
 
+// serviceView is how the logs of a service were shown when it was left
+type serviceView struct {
+	filter     *log.Filter
+	input      string
+	highlight  bool
+	autoScroll bool
+	top        uint64 // Entry shown at the top
+}
+
+// CopySelection is what a copy mode selection spans
+type CopySelection int
+
+const (
+	// CopyLines selects whole lines
+	CopyLines CopySelection = iota
+	// CopyChars selects from one character to another, across lines
+	CopyChars
+	// CopyBlock selects the same columns of each line
+	CopyBlock
+)
+
 // LogPanelStyles contains log panel styles
 type LogPanelStyles struct {
 	Container       lipgloss.Style
@@ -46,6 +144,11 @@ type LogPanelStyles struct {
 	Timestamp       lipgloss.Style
 	FilterPrompt    lipgloss.Style
 	FilterInput     lipgloss.Style
+	FilterError     lipgloss.Style
+	FilterHint      lipgloss.Style
+	Highlight       lipgloss.Style
+	Dimmed          lipgloss.Style
+	Bookmark        lipgloss.Style
 	NoLogs          lipgloss.Style
 	ServiceColor    lipgloss.Style
 	Footer          lipgloss.Style
@@ -54,10 +157,17 @@ type LogPanelStyles struct {
 	CopyModeCursor  lipgloss.Style
 	CopyModeSelect  lipgloss.Style
 	CopyModeStatus  lipgloss.Style
+	Scrollbar       lipgloss.Style
+	Position        lipgloss.Style
+	Paused          lipgloss.Style
+	Unhealthy       lipgloss.Style
 	StatusRunning   lipgloss.Style
 	StatusStopped   lipgloss.Style
 	StatusStarting  lipgloss.Style
 	StatusFailed    lipgloss.Style
+	StatusPaused    lipgloss.Style
+	JSONKey         lipgloss.Style
+	JSONValue       lipgloss.Style
 }
 
 // DefaultLogPanelStyles returns default styles
@@ -65,69 +175,96 @@ func DefaultLogPanelStyles() LogPanelStyles {
 	return LogPanelStyles{
 		Container: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#374151")),
+			BorderForeground(theme.Border),
 		Title: lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#6B7280")).
+			Foreground(theme.Muted).
 			Padding(0, 1),
 		TitleFocused: lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#8B5CF6")).
+			Foreground(theme.Primary).
 			Padding(0, 1),
 		Line: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F9FAFB")),
+			Foreground(theme.Text),
 		LineStderr: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#EF4444")),
+			Foreground(theme.Error),
 		Timestamp: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")),
+			Foreground(theme.Muted),
 		FilterPrompt: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#8B5CF6")).
+			Foreground(theme.Primary).
 			Bold(true),
 		FilterInput: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F9FAFB")),
+			Foreground(theme.Text),
+		FilterError: lipgloss.NewStyle().
+			Foreground(theme.Error),
+		FilterHint: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+		Highlight: selectionStyle(theme.Warning).
+			Foreground(theme.Contrast),
+		Dimmed: lipgloss.NewStyle().
+			Foreground(theme.Dimmed),
+		Bookmark: lipgloss.NewStyle().
+			Foreground(theme.Warning).
+			Bold(true),
 		NoLogs: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
+			Foreground(theme.Muted).
 			Italic(true),
 		ServiceColor: lipgloss.NewStyle().
 			Bold(true),
 		Footer: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
+			Foreground(theme.Muted).
 			MarginTop(1),
 		FooterLabel: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#8B5CF6")),
+			Foreground(theme.Primary),
 		FooterValue: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#9CA3AF")),
-		CopyModeCursor: lipgloss.NewStyle().
-			Background(lipgloss.Color("#374151")),
-		CopyModeSelect: lipgloss.NewStyle().
-			Background(lipgloss.Color("#4C1D95")).
-			Foreground(lipgloss.Color("#F9FAFB")),
+			Foreground(theme.Subtle),
+		CopyModeCursor: selectionStyle(theme.Border),
+		CopyModeSelect: selectionStyle(theme.Selection).
+			Foreground(theme.Text),
 		CopyModeStatus: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#8B5CF6")).
+			Foreground(theme.Primary).
 			Bold(true),
+		Scrollbar: lipgloss.NewStyle().
+			Foreground(theme.Subtle),
+		Position: lipgloss.NewStyle().
+			Foreground(theme.Subtle),
+		Paused: lipgloss.NewStyle().
+			Foreground(theme.Warning),
+		Unhealthy: lipgloss.NewStyle().
+			Foreground(theme.Error),
 		StatusRunning: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#10B981")).
+			Foreground(theme.Success).
 			Bold(true),
 		StatusStopped: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")),
+			Foreground(theme.Muted),
 		StatusStarting: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F59E0B")),
+			Foreground(theme.Warning),
 		StatusFailed: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#EF4444")).
+			Foreground(theme.Error).
 			Bold(true),
+		StatusPaused: lipgloss.NewStyle().
+			Foreground(theme.Info).
+			Bold(true),
+		JSONKey: lipgloss.NewStyle().
+			Foreground(theme.Primary),
+		JSONValue: lipgloss.NewStyle().
+			Foreground(theme.Subtle),
 	}
 }
 
 // NewLogPanel creates a new log panel
 func NewLogPanel() *LogPanel {
 	ti := textinput.New()
-	ti.Placeholder = "Filter logs..."
+	ti.Placeholder = "Filter logs (regexp, !exclude)..."
 	ti.CharLimit = 100
 
 	return &LogPanel{
-		filterInput: ti,
-		autoScroll:  true,
-		styles:      DefaultLogPanelStyles(),
+		filterInput:    ti,
+		autoScroll:     true,
+		jumpTo:         -1,
+		holdBottom:     -1,
+		lastJumpOffset: -1,
+		styles:         DefaultLogPanelStyles(),
 	}
 }
 
@@ -153,14 +290,58 @@ func (l *LogPanel) SetFocused(focused bool) {
 	l.focused = focused
 }
 
-// SetService sets the current service to display
+// SetService sets the current service to display, remembering the filter
+// and scroll position of the one left and restoring those of the new one
 func (l *LogPanel) SetService(id config.ServiceID) {
-	if l.serviceID != id {
+	if l.serviceID == id {
+		return
+	}
+	if l.IsMerged() {
+		// The merged view keeps its own filter
 		l.serviceID = id
-		l.autoScroll = true
+		return
+	}
+	if l.serviceID != (config.ServiceID{}) {
+		if l.views == nil {
+			l.views = make(map[config.ServiceID]serviceView)
+		}
+		l.views[l.serviceID] = l.currentView()
+	}
+
+	l.serviceID = id
+	view, ok := l.views[id]
+	if !ok {
+		view = serviceView{autoScroll: true}
+	}
+	l.filter = view.filter
+	l.filterErr = nil
+	l.filterInput.SetValue(view.input)
+	l.highlight = view.highlight
+	l.autoScroll = view.autoScroll
+	l.restoreTo = nil
+	if !view.autoScroll {
+		l.restoreTo = &view.top
 	}
 }
 
+// currentView returns how the logs of the service are shown
+func (l *LogPanel) currentView() serviceView {
+	view := serviceView{
+		filter:     l.filter,
+		input:      l.filter.String(),
+		highlight:  l.highlight,
+		autoScroll: l.autoScroll,
+	}
+	if l.scrollOffset < len(l.rowLines) {
+		if line := l.rowLines[l.scrollOffset]; line < len(l.lineEntries) {
+			if entry := l.lineEntries[line]; entry < len(l.cache.entries) {
+				view.top = l.cache.entries[entry].seq
+			}
+		}
+	}
+	return view
+}
+
 // SetServiceConfig sets the current service configuration for footer display
 func (l *LogPanel) SetServiceConfig(cfg *config.Service) {
 	l.serviceConfig = cfg
@@ -171,6 +352,29 @@ func (l *LogPanel) SetStatus(status process.Status) {
 	l.serviceStatus = status
 }
 
+// SetStats sets the current service resource usage
+func (l *LogPanel) SetStats(stats process.Stats) {
+	l.serviceStats = stats
+}
+
+// SetSpinner sets the tick that spinners of starting and stopping services
+// are at
+func (l *LogPanel) SetSpinner(tick int) {
+	l.spinner = tick
+}
+
+// SetHealthError sets why the service's health check fails, shown in the
+// footer, or nil if it doesn't
+func (l *LogPanel) SetHealthError(err error) {
+	l.healthErr = err
+}
+
+// SetPort sets the port the service runs on, shown instead of the
+// configured one, or 0 when it doesn't run
+func (l *LogPanel) SetPort(port int) {
+	l.servicePort = port
+}
+
 // formatStatus returns a formatted status string with color
 func (l *LogPanel) formatStatus() string {
 	if l.serviceID.Service == "" {
@@ -181,11 +385,15 @@ func (l *LogPanel) formatStatus() string {
 	case process.StatusRunning:
 		return l.styles.StatusRunning.Render("[running]")
 	case process.StatusStarting:
-		return l.styles.StatusStarting.Render("[starting]")
+		return l.styles.StatusStarting.Render("[" + spinnerFrame(l.serviceStatus, l.spinner) + " starting]")
 	case process.StatusStopping:
-		return l.styles.StatusStarting.Render("[stopping]")
+		return l.styles.StatusStarting.Render("[" + spinnerFrame(l.serviceStatus, l.spinner) + " stopping]")
 	case process.StatusFailed:
 		return l.styles.StatusFailed.Render("[failed]")
+	case process.StatusSucceeded:
+		return l.styles.StatusRunning.Render("[succeeded]")
+	case process.StatusPaused:
+		return l.styles.StatusPaused.Render("[paused]")
 	default:
 		return l.styles.StatusStopped.Render("[stopped]")
 	}
@@ -205,27 +413,101 @@ func (l *LogPanel) StopFilter() {
 	l.SetSize(l.width, l.height)
 }
 
-// ApplyFilter applies the current filter
-func (l *LogPanel) ApplyFilter() {
-	l.filter = l.filterInput.Value()
+// ApplyFilter applies the current filter. An invalid expression keeps the
+// filter prompt open and returns false.
+func (l *LogPanel) ApplyFilter() bool {
+	filter, err := log.ParseFilter(l.filterInput.Value())
+	if err != nil {
+		l.filterErr = err
+		return false
+	}
+	l.filter = filter
+	l.filterErr = nil
 	l.StopFilter()
+	return true
 }
 
 // ClearFilter clears the filter
 func (l *LogPanel) ClearFilter() {
-	l.filter = ""
+	l.filter = nil
+	l.filterErr = nil
 	l.filterInput.SetValue("")
 	l.StopFilter()
 }
 
+// ValidateFilter checks the expression being typed, so an invalid regexp
+// is reported before it is applied
+func (l *LogPanel) ValidateFilter() {
+	_, l.filterErr = log.ParseFilter(l.filterInput.Value())
+}
+
+// SetStripColors sets whether the colors services print lines in are
+// stripped, showing lines in the colors of their level instead
+func (l *LogPanel) SetStripColors(strip bool) {
+	l.stripColors = strip
+}
+
+// ToggleJSON toggles between showing JSON log lines on one line and
+// expanding them to every field on its own line
+func (l *LogPanel) ToggleJSON() {
+	l.expandJSON = !l.expandJSON
+}
+
+// IsJSONExpanded returns true if JSON log lines are expanded
+func (l *LogPanel) IsJSONExpanded() bool {
+	return l.expandJSON
+}
+
+// ShowMerged shows the logs of several services interleaved in
+// chronological order, each line prefixed with its service in color
+func (l *LogPanel) ShowMerged(ids []config.ServiceID, colors map[config.ServiceID]string) {
+	l.mergedIDs = ids
+	l.mergedColors = colors
+	l.autoScroll = true
+}
+
+// HideMerged goes back to the logs of the selected service
+func (l *LogPanel) HideMerged() {
+	l.mergedIDs = nil
+	l.mergedColors = nil
+	l.autoScroll = true
+}
+
+// IsMerged returns true if the merged view is shown
+func (l *LogPanel) IsMerged() bool {
+	return len(l.mergedIDs) > 0
+}
+
+// ScrollToEntry scrolls to the entry at index in the service's log buffer
+// on the next update. A filter hiding lines switches to highlighting, so
+// the entry is shown in context.
+func (l *LogPanel) ScrollToEntry(index int) {
+	if l.filter != nil {
+		l.highlight = true
+	}
+	l.autoScroll = false
+	l.jumpTo = index
+}
+
+// ToggleHighlight switches between hiding lines that don't match the filter
+// and showing all lines with matches highlighted
+func (l *LogPanel) ToggleHighlight() {
+	l.highlight = !l.highlight
+}
+
+// IsHighlighting returns true if the filter highlights instead of hiding
+func (l *LogPanel) IsHighlighting() bool {
+	return l.highlight
+}
+
 // IsFiltering returns true if in filter mode
 func (l *LogPanel) IsFiltering() bool {
 	return l.filtering
 }
 
-// Filter returns the current filter string
+// Filter returns the current filter expression
 func (l *LogPanel) Filter() string {
-	return l.filter
+	return l.filter.String()
 }
 
 // FilterInput returns the filter input model
@@ -244,70 +526,572 @@ const (
 	LogLevelError
 )
 
-// Update updates the log panel with new entries
+// ToggleBookmark bookmarks the line at the bottom of the view, the latest
+// one while following new lines, or removes its bookmark. It returns
+// whether the line is now bookmarked, and false for ok if there's no line.
+func (l *LogPanel) ToggleBookmark() (marked, ok bool) {
+	row := min(l.scrollOffset+l.viewHeight, len(l.rows)) - 1
+	if row < 0 {
+		return false, false
+	}
+	entry := l.entries[l.lineEntries[l.rowLines[row]]]
+
+	marks := l.bookmarks[entry.ServiceID]
+	for i, mark := range marks {
+		if sameEntry(mark, entry) {
+			l.bookmarks[entry.ServiceID] = append(marks[:i:i], marks[i+1:]...)
+			l.bookmarksChanged++
+			return false, true
+		}
+	}
+	if l.bookmarks == nil {
+		l.bookmarks = make(map[config.ServiceID][]log.Entry)
+	}
+	l.bookmarks[entry.ServiceID] = append(marks, entry)
+	l.bookmarksChanged++
+	return true, true
+}
+
+// ShownIDs returns the services whose logs are shown
+func (l *LogPanel) ShownIDs() []config.ServiceID {
+	if l.IsMerged() {
+		return l.mergedIDs
+	}
+	return []config.ServiceID{l.serviceID}
+}
+
+// Bookmarks returns the bookmarked entries of the services shown, oldest
+// first
+func (l *LogPanel) Bookmarks() []log.Entry {
+	var marks []log.Entry
+	for _, id := range l.ShownIDs() {
+		marks = append(marks, l.bookmarks[id]...)
+	}
+	sort.SliceStable(marks, func(i, j int) bool {
+		return marks[i].Timestamp.Before(marks[j].Timestamp)
+	})
+	return marks
+}
+
+// NextBookmark scrolls to the next bookmarked line, or the previous one if
+// back is set. It returns false if there is none.
+func (l *LogPanel) NextBookmark(back bool) bool {
+	return l.jumpToNext(l.bookmarkLines, back)
+}
+
+// NextError scrolls to the next stderr or error line, or the previous one
+// if back is set, skipping the rest of a run of them such as a stack
+// trace. It returns false if there is none.
+func (l *LogPanel) NextError(back bool) bool {
+	return l.jumpToNext(l.errorLines, back)
+}
+
+// jumpToNext scrolls to the next of lines, or the previous one if back is
+// set, returning false if there is none
+func (l *LogPanel) jumpToNext(lines []int, back bool) bool {
+	// Continue from the line jumped to last, or else the middle of the view
+	current := l.lastJumpLine
+	if l.scrollOffset != l.lastJumpOffset || l.autoScroll {
+		current = -1
+		if row := min(l.scrollOffset+l.viewHeight/2, len(l.rows)-1); row >= 0 {
+			current = l.rowLines[row]
+		}
+	}
+
+	target := -1
+	for _, line := range lines {
+		if back && line < current {
+			target = line
+		}
+		if !back && line > current {
+			target = line
+			break
+		}
+	}
+	if target < 0 {
+		return false
+	}
+	l.jumpToLine(target)
+	return true
+}
+
+// ScrollToBookmark scrolls to the line of a bookmarked entry. It returns
+// false if the line isn't shown, e.g. because of the filter.
+func (l *LogPanel) ScrollToBookmark(entry log.Entry) bool {
+	for _, line := range l.bookmarkLines {
+		if sameEntry(l.entries[l.lineEntries[line]], entry) {
+			l.jumpToLine(line)
+			return true
+		}
+	}
+	return false
+}
+
+// jumpToLine shows a line in the middle of the view, remembering it to
+// jump on from
+func (l *LogPanel) jumpToLine(line int) {
+	l.centerLine(line)
+	l.lastJumpLine = line
+	l.lastJumpOffset = l.scrollOffset
+}
+
+// isBookmarked returns true if an entry is bookmarked
+func (l *LogPanel) isBookmarked(entry log.Entry) bool {
+	for _, mark := range l.bookmarks[entry.ServiceID] {
+		if sameEntry(mark, entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameEntry returns true if a and b are the same log line
+func sameEntry(a, b log.Entry) bool {
+	return a.ServiceID == b.ServiceID && a.Timestamp.Equal(b.Timestamp) &&
+		a.Line == b.Line && a.IsStderr == b.IsStderr
+}
+
+// LevelFilter selects the log levels shown for a service
+type LevelFilter int
+
+const (
+	LevelFilterAll     LevelFilter = iota // all lines
+	LevelFilterNoDebug                    // hide debug and trace lines
+	LevelFilterWarn                       // only warnings, errors and stderr
+)
+
+// String returns the footer label of the filter
+func (f LevelFilter) String() string {
+	switch f {
+	case LevelFilterNoDebug:
+		return "no debug"
+	case LevelFilterWarn:
+		return "warn+error"
+	default:
+		return "all"
+	}
+}
+
+// shows returns true if lines of a level are shown. Stderr lines without
+// a detected level count as errors.
+func (f LevelFilter) shows(level LogLevel, isStderr bool) bool {
+	switch f {
+	case LevelFilterNoDebug:
+		return level != LogLevelDebug
+	case LevelFilterWarn:
+		return level == LogLevelWarn || level == LogLevelError || (isStderr && level == LogLevelNormal)
+	default:
+		return true
+	}
+}
+
+// CycleLevelFilter switches the levels shown for a service between all,
+// no debug and warnings and errors only
+func (l *LogPanel) CycleLevelFilter(id config.ServiceID) LevelFilter {
+	if l.levelFilters == nil {
+		l.levelFilters = make(map[config.ServiceID]LevelFilter)
+	}
+	next := (l.levelFilters[id] + 1) % (LevelFilterWarn + 1)
+	if next == LevelFilterAll {
+		delete(l.levelFilters, id)
+	} else {
+		l.levelFilters[id] = next
+	}
+	return next
+}
+
+// ToggleStderrOnly switches between showing only the stderr lines of a
+// service and all its lines, returning true if only stderr is shown now
+func (l *LogPanel) ToggleStderrOnly(id config.ServiceID) bool {
+	if l.stderrOnly[id] {
+		delete(l.stderrOnly, id)
+		return false
+	}
+	if l.stderrOnly == nil {
+		l.stderrOnly = make(map[config.ServiceID]bool)
+	}
+	l.stderrOnly[id] = true
+	return true
+}
+
+// Update updates the log panel with new entries. Entries are rendered once,
+// as they're added, and all of them again only when what they're shown
+// with changes (filter, levels, timestamps...).
 func (l *LogPanel) Update(buffer *log.Buffer) {
 	// Don't update in copy mode (freeze logs)
 	if l.copyMode {
 		return
 	}
 
-	entries := buffer.GetFiltered(l.serviceID, l.filter)
+	changed := l.refreshCache(buffer, time.Now())
+	if changed || l.cache.width != l.contentWidth() || l.cache.wrap != l.wrap {
+		l.layoutLines()
+	}
+
+	jumpLine := -1
+	if l.jumpTo >= 0 {
+		jumpLine = l.cachedLine(l.serviceID, l.cache.oldest[l.serviceID]+uint64(l.jumpTo))
+		l.jumpTo = -1
+	}
+
+	topLine := -1
+	if l.restoreTo != nil {
+		topLine = l.cachedLine(l.serviceID, *l.restoreTo)
+		l.restoreTo = nil
+	}
+
+	if jumpLine >= 0 {
+		l.centerLine(jumpLine)
+	} else if topLine >= 0 {
+		// Back to where the service was left
+		l.scrollOffset = max(0, min(l.rowOf(topLine), len(l.rows)-l.viewHeight))
+	} else if l.holdBottom >= 0 {
+		// Lines were added above, keep showing the same ones
+		l.scrollOffset = max(0, len(l.rows)-l.holdBottom)
+		l.holdBottom = -1
+	} else if l.autoScroll {
+		l.scrollToBottom()
+	}
+}
+
+// renderEntry renders the lines of an entry, returning false if the filter
+// or the level filter hides it
+func (l *LogPanel) renderEntry(entry log.Entry, prefixes, rawPrefixes map[config.ServiceID]string, now time.Time) (renderedEntry, bool) {
+	if !l.highlight && !l.filter.Match(entry.Line) {
+		return renderedEntry{}, false
+	}
+
+	// Sanitize the line - remove ANSI codes and control chars
+	cleanLine := sanitizeLine(entry.Line)
+
+	// Structured lines carry their own level, others match the service's
+	// level rules or are guessed. A line matching a rule is no error just
+	// because it's on stderr.
+	parsed, isJSON := parseJSONLog(cleanLine, l.logFields())
+	level := parsed.level
+	isStderr := entry.IsStderr
+	var ruleStyle *lipgloss.Style
+	if !isJSON {
+		var ruled bool
+		level, ruleStyle, ruled = l.detectLevel(entry.ServiceID, cleanLine)
+		isStderr = isStderr && !ruled
+	}
+	if !l.levelFilters[entry.ServiceID].shows(level, isStderr) ||
+		(l.stderrOnly[entry.ServiceID] && !entry.IsStderr) {
+		return renderedEntry{}, false
+	}
+
+	rendered := renderedEntry{
+		entry:      entry,
+		isError:    isStderr || level == LogLevelError,
+		bookmarked: l.isBookmarked(entry),
+	}
+
+	// Lines start with the timestamp, with service color if available
+	var prefix, rawPrefix string
+	if ts := l.timestampText(entry.Timestamp, now); ts != "" {
+		prefix = l.formatTimestamp(ts) + " "
+		rawPrefix = ts + " "
+	}
+
+	// The merged view prefixes lines with their service
+	if service, ok := prefixes[entry.ServiceID]; ok {
+		prefix += service + " "
+		rawPrefix += rawPrefixes[entry.ServiceID] + " "
+	}
+
+	if rendered.bookmarked {
+		prefix = l.styles.Bookmark.Render("◆") + " " + prefix
+	}
+
+	// Store raw line for copying
+	rawLine := rawPrefix + plainText(cleanLine)
+
+	// In highlight mode, lines a negated filter would hide are dimmed
+	if l.highlight && l.filter.Negated() && !l.filter.Match(entry.Line) {
+		rendered.lines = []string{prefix + l.styles.Dimmed.Render(cleanLine)}
+		rendered.rawLines = []string{rawLine}
+		return rendered, true
+	}
+
+	if isJSON {
+		rendered.lines, rendered.rawLines = l.renderJSONLog(prefix, rawLine, parsed, entry.IsStderr)
+		return rendered, true
+	}
+
+	// Format line based on its level rule, own colors, level and stderr
+	var line string
+	if ruleStyle != nil {
+		line = l.renderHighlighted(cleanLine, *ruleStyle)
+	} else if colored, ok := l.nativeColors(entry.Line); ok {
+		line = colored
+	} else if isStderr {
+		line = l.renderHighlighted(cleanLine, l.styles.LineStderr)
+	} else {
+		line = l.renderHighlighted(cleanLine, l.lineStyle(level))
+	}
+
+	rendered.lines = []string{prefix + line}
+	rendered.rawLines = []string{rawLine}
+	return rendered, true
+}
 
-	l.lines = nil
-	l.rawLines = nil
-	for _, entry := range entries {
-		// Sanitize the line - remove ANSI codes and control chars
-		cleanLine := sanitizeLine(entry.Line)
+// centerLine scrolls to show a line in the middle of the view
+func (l *LogPanel) centerLine(line int) {
+	l.autoScroll = false
+	l.scrollOffset = max(0, min(l.rowOf(line)-l.viewHeight/2, len(l.rows)-l.viewHeight))
+}
 
-		// Store raw line for copying
-		rawLine := fmt.Sprintf("%s %s", entry.Timestamp.Format("15:04:05"), cleanLine)
-		l.rawLines = append(l.rawLines, rawLine)
+// rowOf returns the first row of a line
+func (l *LogPanel) rowOf(line int) int {
+	if line < len(l.lineRows) {
+		return l.lineRows[line]
+	}
+	return len(l.rows)
+}
 
-		// Detect log level
-		level := detectLogLevel(cleanLine)
+// contentWidth returns the width available to log lines
+func (l *LogPanel) contentWidth() int {
+	// Account for borders
+	return max(l.width-4, 10)
+}
 
-		// Format timestamp with service color if available
-		timestamp := l.formatTimestamp(entry.Timestamp.Format("15:04:05"))
+// logFields returns the JSON fields the service shows inline
+func (l *LogPanel) logFields() []string {
+	if l.serviceConfig == nil {
+		return nil
+	}
+	return l.serviceConfig.LogFields
+}
 
-		// Format line based on level and stderr
-		var line string
-		if entry.IsStderr {
-			line = l.styles.LineStderr.Render(cleanLine)
+// renderJSONLog renders a JSON log line as its level, message and inline
+// fields after prefix, returning its lines and raw lines. When expanded,
+// every field of the object follows on its own line.
+func (l *LogPanel) renderJSONLog(prefix, rawLine string, entry jsonLog, isStderr bool) (lines, rawLines []string) {
+	var parts []string
+	if entry.levelName != "" {
+		parts = append(parts, formatLevelName(entry.levelName, entry.level))
+	}
+	if entry.msg != "" {
+		if isStderr && entry.level == LogLevelNormal {
+			parts = append(parts, l.renderHighlighted(sanitizeLine(entry.msg), l.styles.LineStderr))
 		} else {
-			line = l.formatLineByLevel(cleanLine, level)
+			parts = append(parts, l.renderHighlighted(sanitizeLine(entry.msg), l.lineStyle(entry.level)))
 		}
+	}
+	if !l.expandJSON {
+		for _, key := range entry.fields {
+			parts = append(parts, l.styles.JSONKey.Render(key+"=")+
+				l.renderHighlighted(sanitizeLine(formatInlineValue(entry.object[key])), l.styles.JSONValue))
+		}
+	}
+	lines = append(lines, prefix+strings.Join(parts, " "))
+	rawLines = append(rawLines, rawLine)
 
-		l.lines = append(l.lines, fmt.Sprintf("%s %s", timestamp, line))
+	if !l.expandJSON {
+		return lines, rawLines
 	}
 
-	if l.autoScroll {
-		l.scrollToBottom()
+	keys := make([]string, 0, len(entry.object))
+	for key := range entry.object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	const indent = "    "
+	for _, key := range keys {
+		// Multi-line values (nested objects, stack traces) continue
+		// aligned under the first line
+		prefix := indent + key + ": "
+		for _, part := range strings.Split(formatJSONValueIndented(entry.object[key]), "\n") {
+			part = sanitizeLine(part)
+			lines = append(lines, l.styles.JSONKey.Render(prefix)+l.renderHighlighted(part, l.styles.JSONValue))
+			rawLines = append(rawLines, prefix+plainText(part))
+			prefix = strings.Repeat(" ", len(prefix))
+		}
+	}
+	return lines, rawLines
+}
+
+// formatLevelName renders the level of a structured log line as a fixed-width
+// column colored by level
+func formatLevelName(name string, level LogLevel) string {
+	name = fmt.Sprintf("%-5s", name)
+	switch level {
+	case LogLevelError:
+		return lipgloss.NewStyle().Foreground(theme.Error).Bold(true).Render(name)
+	case LogLevelWarn:
+		return lipgloss.NewStyle().Foreground(theme.Warning).Bold(true).Render(name)
+	case LogLevelInfo:
+		return lipgloss.NewStyle().Foreground(theme.Success).Render(name)
+	case LogLevelDebug:
+		return lipgloss.NewStyle().Foreground(theme.Muted).Render(name)
+	default:
+		return lipgloss.NewStyle().Foreground(theme.Subtle).Render(name)
 	}
 }
 
+// mergedPrefixes returns the styled and plain line prefixes of the services
+// in the merged view, padded to the same width. Services are named without
+// their project unless they come from several projects.
+func (l *LogPanel) mergedPrefixes() (map[config.ServiceID]string, map[config.ServiceID]string) {
+	if !l.IsMerged() {
+		return nil, nil
+	}
+
+	qualify := false
+	for _, id := range l.mergedIDs {
+		if id.Project != l.mergedIDs[0].Project {
+			qualify = true
+		}
+	}
+
+	names := make(map[config.ServiceID]string, len(l.mergedIDs))
+	width := 0
+	for _, id := range l.mergedIDs {
+		name := id.String()
+		if !qualify {
+			name = strings.TrimPrefix(name, id.Project+"/")
+		}
+		names[id] = name
+		width = max(width, ansi.StringWidth(name))
+	}
+
+	styled := make(map[config.ServiceID]string, len(names))
+	plain := make(map[config.ServiceID]string, len(names))
+	for i, id := range l.mergedIDs {
+		color := lipgloss.Color(l.mergedColors[id])
+		if color == "" {
+			color = theme.ServiceColors[i%len(theme.ServiceColors)]
+		}
+		plain[id] = names[id] + strings.Repeat(" ", width-ansi.StringWidth(names[id]))
+		styled[id] = l.styles.ServiceColor.Foreground(color).Render(plain[id])
+	}
+	return styled, plain
+}
+
+// timestampText formats the timestamp of a line in the timestamp format,
+// returning "" if timestamps are hidden
+func (l *LogPanel) timestampText(t, now time.Time) string {
+	switch l.timestamps {
+	case config.TimestampsMillis:
+		return formatTime(t, "15:04:05.000")
+	case config.TimestampsDate:
+		return formatTime(t, "2006-01-02 15:04:05")
+	case config.TimestampsRelative:
+		return fmt.Sprintf("%8s", formatAgo(now.Sub(t)))
+	case config.TimestampsNone:
+		return ""
+	default:
+		return formatClock(t)
+	}
+}
+
+// formatAgo formats how long ago something happened in its largest unit
+func formatAgo(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds ago", int(max(d, 0).Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// SetTimestamps sets the format of the timestamps lines start with, one of
+// config.TimestampFormats ("" for the default)
+func (l *LogPanel) SetTimestamps(format string) {
+	l.timestamps = format
+}
+
+// CycleTimestamps switches to the next timestamp format, returning it
+func (l *LogPanel) CycleTimestamps() string {
+	current := l.timestamps
+	if current == "" {
+		current = config.TimestampsTime
+	}
+	formats := config.TimestampFormats
+	next := formats[0]
+	for i, format := range formats {
+		if format == current {
+			next = formats[(i+1)%len(formats)]
+		}
+	}
+	l.timestamps = next
+	return next
+}
+
 // formatTimestamp formats timestamp with service color if available
 func (l *LogPanel) formatTimestamp(ts string) string {
-	if l.serviceConfig != nil && l.serviceConfig.Color != "" {
+	if !l.IsMerged() && l.serviceConfig != nil && l.serviceConfig.Color != "" {
 		style := lipgloss.NewStyle().Foreground(lipgloss.Color(l.serviceConfig.Color))
 		return style.Render(ts)
 	}
 	return l.styles.Timestamp.Render(ts)
 }
 
-// formatLineByLevel applies color based on log level
-func (l *LogPanel) formatLineByLevel(line string, level LogLevel) string {
+// lineStyle returns the style of a line by log level
+func (l *LogPanel) lineStyle(level LogLevel) lipgloss.Style {
 	switch level {
 	case LogLevelError:
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444")).Render(line)
+		return lipgloss.NewStyle().Foreground(theme.Error)
 	case LogLevelWarn:
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("#F59E0B")).Render(line)
+		return lipgloss.NewStyle().Foreground(theme.Warning)
 	case LogLevelDebug:
-		return lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280")).Render(line)
+		return lipgloss.NewStyle().Foreground(theme.Muted)
 	default:
-		return l.styles.Line.Render(line)
+		return l.styles.Line
 	}
 }
 
+// nativeColors returns a line in the colors the service printed it in. It
+// returns false if the line has none, colors are stripped, or matches of
+// the filter are highlighted.
+func (l *LogPanel) nativeColors(line string) (string, bool) {
+	if l.stripColors || (l.highlight && l.filter != nil && !l.filter.Negated()) {
+		return "", false
+	}
+	return sanitizeColoredLine(line)
+}
+
+// renderHighlighted renders text with style, marking matches of the filter
+// when it highlights instead of hiding lines
+func (l *LogPanel) renderHighlighted(text string, style lipgloss.Style) string {
+	if !l.highlight || l.filter.Negated() {
+		return style.Render(text)
+	}
+
+	// Matches in hyperlink URIs aren't shown, and would break the links
+	matches := l.filter.FindAll(text)
+	if escapes := escapeRanges(text); len(escapes) > 0 {
+		matches = slices.DeleteFunc(matches, func(loc []int) bool {
+			return slices.ContainsFunc(escapes, func(r [2]int) bool {
+				return loc[0] < r[1] && loc[1] > r[0]
+			})
+		})
+	}
+	if len(matches) == 0 {
+		return style.Render(text)
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range matches {
+		if loc[0] > last {
+			b.WriteString(style.Render(text[last:loc[0]]))
+		}
+		b.WriteString(l.styles.Highlight.Render(text[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	if last < len(text) {
+		b.WriteString(style.Render(text[last:]))
+	}
+	return b.String()
+}
+
 // detectLogLevel detects the log level from line content
 func detectLogLevel(line string) LogLevel {
 	upper := strings.ToUpper(line)
@@ -336,47 +1120,9 @@ func detectLogLevel(line string) LogLevel {
 	return LogLevelNormal
 }
 
-// sanitizeLine removes control characters and ANSI codes that break the layout
-func sanitizeLine(s string) string {
-	var result strings.Builder
-	result.Grow(len(s))
-
-	inEscape := false
-	for _, r := range s {
-		// Skip ANSI escape sequences
-		if r == '\x1b' {
-			inEscape = true
-			continue
-		}
-		if inEscape {
-			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
-				inEscape = false
-			}
-			continue
-		}
-
-		// Skip carriage return and newline
-		if r == '\r' || r == '\n' {
-			continue
-		}
-		// Replace tab with spaces
-		if r == '\t' {
-			result.WriteString("    ")
-			continue
-		}
-		// Skip other control characters
-		if r < 32 {
-			continue
-		}
-		result.WriteRune(r)
-	}
-
-	return result.String()
-}
-
 // scrollToBottom scrolls to the bottom of the logs
 func (l *LogPanel) scrollToBottom() {
-	maxOffset := len(l.lines) - l.viewHeight
+	maxOffset := len(l.rows) - l.viewHeight
 	if maxOffset < 0 {
 		maxOffset = 0
 	}
@@ -393,7 +1139,7 @@ func (l *LogPanel) ScrollUp() {
 
 // ScrollDown scrolls down
 func (l *LogPanel) ScrollDown() {
-	maxOffset := len(l.lines) - l.viewHeight
+	maxOffset := len(l.rows) - l.viewHeight
 	if maxOffset < 0 {
 		maxOffset = 0
 	}
@@ -416,7 +1162,7 @@ func (l *LogPanel) PageUp() {
 
 // PageDown scrolls down a page
 func (l *LogPanel) PageDown() {
-	maxOffset := len(l.lines) - l.viewHeight
+	maxOffset := len(l.rows) - l.viewHeight
 	if maxOffset < 0 {
 		maxOffset = 0
 	}
@@ -435,6 +1181,59 @@ func (l *LogPanel) GoToTop() {
 	l.scrollOffset = 0
 }
 
+// AtTop returns true if scrolled up to the first line
+func (l *LogPanel) AtTop() bool {
+	return !l.autoScroll && l.scrollOffset == 0
+}
+
+// IsFollowing returns true if the view follows new lines at the bottom
+func (l *LogPanel) IsFollowing() bool {
+	return l.autoScroll
+}
+
+// newLines returns the number of entries added to the services shown since
+// the view stopped following new lines, noting when it did
+func (l *LogPanel) newLines() int {
+	if l.autoScroll {
+		l.pausedAt = nil
+		return 0
+	}
+	if l.pausedAt == nil {
+		l.pausedAt = make(map[config.ServiceID]uint64)
+		for id, version := range l.cache.versions {
+			l.pausedAt[id] = version.Added
+		}
+		return 0
+	}
+
+	count := 0
+	for _, id := range l.ShownIDs() {
+		if since, ok := l.pausedAt[id]; ok {
+			count += int(l.cache.versions[id].Added - since)
+		}
+	}
+	return count
+}
+
+// HoldPosition keeps the lines shown in view on the next update, as older
+// lines are about to be added above them
+func (l *LogPanel) HoldPosition() {
+	l.holdBottom = len(l.rows) - l.scrollOffset
+}
+
+// ToggleWrap toggles wrapping long lines over several rows instead of
+// truncating them
+func (l *LogPanel) ToggleWrap() {
+	l.wrap = !l.wrap
+	// Lines are laid out again on the next update
+	l.holdBottom = -1
+}
+
+// IsWrapping returns true if long lines are wrapped
+func (l *LogPanel) IsWrapping() bool {
+	return l.wrap
+}
+
 // GoToBottom scrolls to bottom
 func (l *LogPanel) GoToBottom() {
 	l.autoScroll = true
@@ -451,10 +1250,13 @@ func (l *LogPanel) EnterCopyMode() {
 	l.copyMode = true
 	l.autoScroll = false
 	l.copySelecting = false
+	l.copyCol = 0
+	l.copyScroll = 0
 	// Position cursor at the last visible line
-	l.copyCursor = l.scrollOffset + l.viewHeight - 1
-	if l.copyCursor >= len(l.lines) {
-		l.copyCursor = len(l.lines) - 1
+	lastRow := min(l.scrollOffset+l.viewHeight, len(l.rows)) - 1
+	l.copyCursor = len(l.lines) - 1
+	if lastRow >= 0 && lastRow < len(l.rowLines) {
+		l.copyCursor = l.rowLines[lastRow]
 	}
 }
 
@@ -477,10 +1279,7 @@ func (l *LogPanel) CopyModeCursorUp() {
 	}
 	if l.copyCursor > 0 {
 		l.copyCursor--
-		// Scroll if cursor goes above visible area
-		if l.copyCursor < l.scrollOffset {
-			l.scrollOffset = l.copyCursor
-		}
+		l.copyModeShowCursor()
 	}
 }
 
@@ -491,24 +1290,185 @@ func (l *LogPanel) CopyModeCursorDown() {
 	}
 	if l.copyCursor < len(l.lines)-1 {
 		l.copyCursor++
-		// Scroll if cursor goes below visible area
-		if l.copyCursor >= l.scrollOffset+l.viewHeight {
-			l.scrollOffset = l.copyCursor - l.viewHeight + 1
+		l.copyModeShowCursor()
+	}
+}
+
+// CopyModeCursorLeft moves the cursor a character left in copy mode
+func (l *LogPanel) CopyModeCursorLeft() {
+	l.copyModeMoveTo(l.copyModeCol() - 1)
+}
+
+// CopyModeCursorRight moves the cursor a character right in copy mode
+func (l *LogPanel) CopyModeCursorRight() {
+	l.copyModeMoveTo(l.copyModeCol() + 1)
+}
+
+// CopyModeLineStart moves the cursor to the start of its line
+func (l *LogPanel) CopyModeLineStart() {
+	l.copyModeMoveTo(0)
+}
+
+// CopyModeLineEnd moves the cursor to the end of its line
+func (l *LogPanel) CopyModeLineEnd() {
+	l.copyModeMoveTo(len(l.copyModeLine(l.copyCursor)) - 1)
+}
+
+// CopyModeWordNext moves the cursor to the start of the next word of its
+// line. Words are separated by spaces, so paths and IDs are one word.
+func (l *LogPanel) CopyModeWordNext() {
+	line := l.copyModeLine(l.copyCursor)
+	col := l.copyModeCol()
+	for col < len(line) && !unicode.IsSpace(line[col]) {
+		col++
+	}
+	for col < len(line) && unicode.IsSpace(line[col]) {
+		col++
+	}
+	if col < len(line) {
+		l.copyModeMoveTo(col)
+	}
+}
+
+// CopyModeWordPrev moves the cursor to the start of the word it is in, or
+// of the previous word when it is at the start of one
+func (l *LogPanel) CopyModeWordPrev() {
+	line := l.copyModeLine(l.copyCursor)
+	col := l.copyModeCol()
+	for col > 0 && unicode.IsSpace(line[col-1]) {
+		col--
+	}
+	for col > 0 && !unicode.IsSpace(line[col-1]) {
+		col--
+	}
+	l.copyModeMoveTo(col)
+}
+
+// copyModeMoveTo moves the cursor to a column of its line
+func (l *LogPanel) copyModeMoveTo(col int) {
+	if !l.copyMode {
+		return
+	}
+	l.copyCol = max(min(col, len(l.copyModeLine(l.copyCursor))-1), 0)
+	l.copyModeShowCursor()
+}
+
+// copyModeLine returns the raw line at index as runes
+func (l *LogPanel) copyModeLine(index int) []rune {
+	if index < 0 || index >= len(l.rawLines) {
+		return nil
+	}
+	return []rune(l.rawLines[index])
+}
+
+// copyModeCol returns the column of the cursor. The column is kept when
+// moving over shorter lines, so the cursor is at their end meanwhile.
+func (l *LogPanel) copyModeCol() int {
+	return max(min(l.copyCol, len(l.copyModeLine(l.copyCursor))-1), 0)
+}
+
+// copyModeShowCursor scrolls the cursor into view
+func (l *LogPanel) copyModeShowCursor() {
+	first, last := l.rowOf(l.copyCursor), l.rowOf(l.copyCursor+1)-1
+	width := l.contentWidth()
+	col := l.copyModeCol()
+	if l.wrap {
+		raw := l.copyModeLine(l.copyCursor)
+		row := first
+		for row < last && l.copyModeRowStart(raw, l.copyCursor, row+1) <= col {
+			row++
+		}
+		first, last = row, row
+	} else {
+		raw := l.copyModeLine(l.copyCursor)
+		if col < l.copyScroll {
+			l.copyScroll = col
+		}
+		// Wide characters take two columns
+		for l.copyScroll < col && col < len(raw) && ansi.StringWidth(string(raw[l.copyScroll:col+1])) > width {
+			l.copyScroll++
 		}
 	}
+
+	if first < l.scrollOffset {
+		l.scrollOffset = first
+	}
+	if last >= l.scrollOffset+l.viewHeight {
+		l.scrollOffset = last - l.viewHeight + 1
+	}
 }
 
-// CopyModeToggleSelect toggles selection in copy mode
-func (l *LogPanel) CopyModeToggleSelect() {
+// copyModeRowStart returns the character of the raw line a row of a wrapped
+// line starts at. Wide characters take two columns, so a row starts after
+// the characters of those above, and the spaces dropped where they broke.
+func (l *LogPanel) copyModeRowStart(raw []rune, line, row int) int {
+	start := 0
+	for r := l.rowOf(line); r <= row && r < len(l.rows); r++ {
+		text := []rune(ansi.Strip(l.rows[r]))
+		for start < len(raw) && raw[start] == ' ' && (len(text) == 0 || text[0] != ' ') {
+			start++
+		}
+		if r < row {
+			start += len(text)
+		}
+	}
+	return start
+}
+
+// CopyModeToggleSelect starts selecting lines, characters or a block from
+// the cursor, switches what the selection spans, or stops selecting
+func (l *LogPanel) CopyModeToggleSelect(selection CopySelection) {
 	if !l.copyMode {
 		return
 	}
-	if l.copySelecting {
+	switch {
+	case l.copySelecting && l.copySelection == selection:
 		l.copySelecting = false
-	} else {
+	case l.copySelecting:
+		l.copySelection = selection
+	default:
 		l.copySelecting = true
+		l.copySelection = selection
 		l.copySelectStart = l.copyCursor
+		l.copySelectCol = l.copyModeCol()
+	}
+}
+
+// copyModeSelected returns the columns from and to (exclusive) of the line
+// at index, n runes long, that are selected, and whether the line is
+// selected at all. Without a selection the cursor's line is.
+func (l *LogPanel) copyModeSelected(index, n int) (from, to int, ok bool) {
+	if !l.copySelecting {
+		return 0, n, index == l.copyCursor
+	}
+
+	startLine, startCol := l.copySelectStart, l.copySelectCol
+	endLine, endCol := l.copyCursor, l.copyModeCol()
+	if startLine > endLine || (startLine == endLine && startCol > endCol) {
+		startLine, endLine = endLine, startLine
+		startCol, endCol = endCol, startCol
+	}
+	if index < startLine || index > endLine {
+		return 0, 0, false
 	}
+
+	switch l.copySelection {
+	case CopyChars:
+		from, to = 0, n
+		if index == startLine {
+			from = startCol
+		}
+		if index == endLine {
+			to = endCol + 1
+		}
+	case CopyBlock:
+		from = min(l.copySelectCol, l.copyModeCol())
+		to = max(l.copySelectCol, l.copyModeCol()) + 1
+	default:
+		from, to = 0, n
+	}
+	to = min(to, n)
+	return min(from, to), to, true
 }
 
 // CopyModeGetSelectedText returns the selected text for copying
@@ -540,12 +1500,56 @@ func (l *LogPanel) CopyModeGetSelectedText() string {
 
 	var lines []string
 	for i := start; i <= end; i++ {
-		lines = append(lines, l.rawLines[i])
+		line := l.copyModeLine(i)
+		from, to, _ := l.copyModeSelected(i, len(line))
+		lines = append(lines, string(line[from:to]))
 	}
 
 	return strings.Join(lines, "\n")
 }
 
+// ExportEntries returns the entries to export: those of the lines selected
+// in copy mode, or else all entries shown (filtered, merged, or limited to
+// some levels)
+func (l *LogPanel) ExportEntries() []log.Entry {
+	start, end := 0, len(l.lineEntries)-1
+	if l.copyMode {
+		start, end = l.copyCursor, l.copyCursor
+		if l.copySelecting {
+			start = min(l.copySelectStart, l.copyCursor)
+			end = max(l.copySelectStart, l.copyCursor)
+		}
+	}
+	return l.entriesBetween(start, end)
+}
+
+// PipeEntries returns the entries to pipe to a command: the copy mode
+// selection, or all entries shown
+func (l *LogPanel) PipeEntries() []log.Entry {
+	if l.copyMode && l.copySelecting {
+		return l.ExportEntries()
+	}
+	return l.entriesBetween(0, len(l.lineEntries)-1)
+}
+
+// entriesBetween returns the entries of lines start to end, inclusive
+func (l *LogPanel) entriesBetween(start, end int) []log.Entry {
+	if start < 0 || end >= len(l.lineEntries) {
+		return nil
+	}
+
+	// Lines of an expanded JSON entry export it once
+	var entries []log.Entry
+	last := -1
+	for i := start; i <= end; i++ {
+		if index := l.lineEntries[i]; index != last {
+			entries = append(entries, l.entries[index])
+			last = index
+		}
+	}
+	return entries
+}
+
 // CopyModeIsLineSelected returns true if the line at index is selected
 func (l *LogPanel) CopyModeIsLineSelected(index int) bool {
 	if !l.copyMode {
@@ -566,24 +1570,110 @@ func (l *LogPanel) CopyModeIsCursor(index int) bool {
 	return l.copyMode && index == l.copyCursor
 }
 
+// copyModeRow renders row i in copy mode. Rows of the cursor's line and
+// of selected lines are drawn from the raw line, with the selection and
+// the cursor highlighted; others are kept as rendered unless scrolled.
+func (l *LogPanel) copyModeRow(i int, row string, width int) string {
+	index := l.rowLines[i]
+	raw := l.copyModeLine(index)
+	from, to, selected := l.copyModeSelected(index, len(raw))
+	isCursor := index == l.copyCursor
+	if !selected && !isCursor && (l.wrap || l.copyScroll == 0) {
+		return row
+	}
+
+	// Characters of the raw line shown in this row
+	start, end := l.copyScroll, len(raw)
+	if l.wrap {
+		start = l.copyModeRowStart(raw, index, i)
+		if i+1 < l.rowOf(index+1) {
+			end = l.copyModeRowStart(raw, index, i+1)
+		}
+	}
+
+	// Selections reaching the end of a line run to the edge of the panel
+	padded := selected && to == len(raw) && (!l.copySelecting || l.copySelection != CopyBlock)
+	cursor := l.copyModeCol()
+
+	var b strings.Builder
+	var run []rune
+	var runStyle *lipgloss.Style
+	flush := func() {
+		if runStyle != nil {
+			b.WriteString(runStyle.Render(string(run)))
+		} else {
+			b.WriteString(string(run))
+		}
+		run = run[:0]
+	}
+	for col, cells := start, 0; ; col++ {
+		char := ' '
+		if col < end {
+			char = raw[col]
+		}
+		charWidth := ansi.StringWidth(string(char))
+		if cells+charWidth > width {
+			break
+		}
+		cells += charWidth
+
+		var style *lipgloss.Style
+		switch {
+		case isCursor && col == cursor:
+			style = &l.styles.CopyModeCursor
+		case selected && col >= from && col < to, padded && col >= len(raw):
+			style = &l.styles.CopyModeSelect
+		case col >= len(raw):
+			flush()
+			return b.String()
+		}
+
+		if style != runStyle {
+			flush()
+			runStyle = style
+		}
+		run = append(run, char)
+	}
+	flush()
+	return b.String()
+}
+
 // View renders the log panel
 func (l *LogPanel) View(buffer *log.Buffer) string {
 	var b strings.Builder
 
 	// Title with status
 	title := "Logs"
-	if l.serviceID.Service != "" {
-		title = fmt.Sprintf("Logs: %s/%s", l.serviceID.Project, l.serviceID.Service)
+	if l.IsMerged() {
+		title = fmt.Sprintf("All logs (%d services)", len(l.mergedIDs))
+	} else if l.serviceID.Service != "" {
+		title = "Logs: " + l.serviceID.String()
 	}
 
 	// Add status indicator
 	statusText := l.formatStatus()
-	if statusText != "" {
+	if statusText != "" && !l.IsMerged() {
 		title += " " + statusText
 	}
 
-	if l.filter != "" {
-		title += fmt.Sprintf(" (filter: %s)", l.filter)
+	if l.filter != nil {
+		if l.highlight {
+			title += fmt.Sprintf(" (highlight: %s)", l.filter)
+		} else {
+			title += fmt.Sprintf(" (filter: %s)", l.filter)
+		}
+	}
+
+	if l.expandJSON {
+		title += " (json expanded)"
+	}
+
+	if l.wrap {
+		title += " (wrap)"
+	}
+
+	if l.IsMerged() || l.serviceID.Service != "" {
+		title += " · " + l.throughput(buffer)
 	}
 
 	if l.focused {
@@ -596,16 +1686,12 @@ func (l *LogPanel) View(buffer *log.Buffer) string {
 	// Update content
 	l.Update(buffer)
 
-	// Calculate content width (account for borders)
-	contentWidth := l.width - 4
-	if contentWidth < 10 {
-		contentWidth = 10
-	}
+	contentWidth := l.contentWidth()
 
 	// Render log lines
 	if len(l.lines) == 0 {
 		noLogsMsg := "No logs yet. Start a service to see output."
-		if l.filter != "" {
+		if l.filter != nil {
 			noLogsMsg = "No logs match the filter."
 		}
 		b.WriteString(l.styles.NoLogs.Render(noLogsMsg))
@@ -613,19 +1699,19 @@ func (l *LogPanel) View(buffer *log.Buffer) string {
 		// Calculate visible range
 		start := l.scrollOffset
 		end := start + l.viewHeight
-		if end > len(l.lines) {
-			end = len(l.lines)
+		if end > len(l.rows) {
+			end = len(l.rows)
 		}
-		if start > len(l.lines) {
-			start = len(l.lines)
+		if start > len(l.rows) {
+			start = len(l.rows)
 		}
 
-		// Render visible lines with truncation
+		// Render visible rows with truncation
 		for i := start; i < end; i++ {
 			if i > start {
 				b.WriteString("\n")
 			}
-			line := l.lines[i]
+			line := l.rows[i]
 			// Truncate line to fit width
 			if lipgloss.Width(line) > contentWidth {
 				line = truncateString(line, contentWidth)
@@ -633,22 +1719,7 @@ func (l *LogPanel) View(buffer *log.Buffer) string {
 
 			// Apply copy mode highlighting
 			if l.copyMode {
-				if l.CopyModeIsLineSelected(i) {
-					// Use raw line for consistent styling in copy mode
-					rawLine := ""
-					if i < len(l.rawLines) {
-						rawLine = l.rawLines[i]
-						if len(rawLine) > contentWidth {
-							rawLine = rawLine[:contentWidth-1] + "…"
-						}
-					}
-					line = l.styles.CopyModeSelect.Render(rawLine)
-					// Pad to width
-					padLen := contentWidth - lipgloss.Width(line)
-					if padLen > 0 {
-						line = l.styles.CopyModeSelect.Render(rawLine + strings.Repeat(" ", padLen))
-					}
-				}
+				line = l.copyModeRow(i, line, contentWidth)
 			}
 
 			b.WriteString(line)
@@ -658,8 +1729,15 @@ func (l *LogPanel) View(buffer *log.Buffer) string {
 	// Filter input
 	if l.filtering {
 		b.WriteString("\n")
-		b.WriteString(l.styles.FilterPrompt.Render("/"))
-		b.WriteString(l.filterInput.View())
+		prompt := l.styles.FilterPrompt.Render("/") + l.filterInput.View()
+		if l.filterErr != nil {
+			prompt += " " + l.styles.FilterError.Render(l.filterErr.Error())
+		} else if l.highlight {
+			prompt += " " + l.styles.FilterHint.Render("highlighting matches (tab: hide other lines)")
+		} else {
+			prompt += " " + l.styles.FilterHint.Render("hiding other lines (tab: highlight matches)")
+		}
+		b.WriteString(truncateString(prompt, contentWidth))
 	}
 
 	// Copy mode status
@@ -672,11 +1750,37 @@ func (l *LogPanel) View(buffer *log.Buffer) string {
 				lines = -lines
 			}
 			lines++
-			status += fmt.Sprintf("%d lines selected │ ", lines)
+			switch l.copySelection {
+			case CopyChars:
+				status += fmt.Sprintf("%d chars selected │ ", utf8.RuneCountInString(l.CopyModeGetSelectedText()))
+			case CopyBlock:
+				cols := l.copyModeCol() - l.copySelectCol
+				if cols < 0 {
+					cols = -cols
+				}
+				status += fmt.Sprintf("%d×%d block selected │ ", lines, cols+1)
+			default:
+				status += fmt.Sprintf("%d lines selected │ ", lines)
+			}
 		}
-		status += "↑↓:move  v:select  y:copy  Esc:exit"
-		b.WriteString(l.styles.CopyModeStatus.Render(status))
-	} else if l.serviceConfig != nil && !l.filtering {
+		status += "↑↓←→ w b:move  v/c/^v:select lines/chars/block  y:copy  e:export  |:pipe  Esc:exit"
+		b.WriteString(l.styles.CopyModeStatus.Render(truncateString(status, contentWidth)))
+	} else if newLines := l.newLines(); !l.autoScroll && !l.filtering && len(l.lines) > 0 {
+		// Scrolled up: say that output goes on meanwhile
+		banner := "⏸ following paused"
+		if newLines == 1 {
+			banner += " — 1 new line"
+		} else if newLines > 1 {
+			banner += fmt.Sprintf(" — %s new lines", formatCount(newLines))
+		}
+		banner += " (End to resume)"
+		b.WriteString("\n")
+		b.WriteString(l.styles.Paused.Render(truncateString(banner, contentWidth)))
+	} else if l.healthErr != nil && !l.filtering && !l.IsMerged() {
+		// A failing health check goes before the usual footer
+		b.WriteString("\n")
+		b.WriteString(l.styles.Unhealthy.Render(truncateString("✗ unhealthy: "+l.healthErr.Error(), contentWidth)))
+	} else if l.serviceConfig != nil && !l.filtering && !l.IsMerged() {
 		// Footer with env/port info (only when not in copy mode)
 		footer := l.renderFooter()
 		if footer != "" {
@@ -712,11 +1816,11 @@ func (l *LogPanel) renderWithBorder(content string) string {
 	}
 
 	// Border color
-	borderColor := "#374151"
+	borderColor := theme.Border
 	if l.focused {
-		borderColor = "#8B5CF6"
+		borderColor = theme.Primary
 	}
-	borderStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(borderColor))
+	borderStyle := lipgloss.NewStyle().Foreground(borderColor)
 
 	var result strings.Builder
 
@@ -724,31 +1828,84 @@ func (l *LogPanel) renderWithBorder(content string) string {
 	result.WriteString(borderStyle.Render("╭" + strings.Repeat("─", innerWidth) + "╮"))
 	result.WriteString("\n")
 
-	// Content lines with side borders
-	for _, line := range lines {
+	// Content lines with side borders. Log rows follow the title, and the
+	// right border next to them doubles as a scrollbar.
+	thumbStart, thumbLen := l.scrollbar()
+	for i, line := range lines {
 		result.WriteString(borderStyle.Render("│"))
-		// Pad line to inner width
+		// Pad line to inner width, or cut it off in narrow split panels
 		visWidth := lipgloss.Width(line)
 		if visWidth < innerWidth {
 			line = line + strings.Repeat(" ", innerWidth-visWidth)
+		} else if visWidth > innerWidth {
+			line = truncateString(line, innerWidth)
 		}
 		result.WriteString(line)
-		result.WriteString(borderStyle.Render("│"))
+		if row := i - 1; thumbLen > 0 && row >= thumbStart && row < thumbStart+thumbLen {
+			result.WriteString(l.styles.Scrollbar.Render("┃"))
+		} else {
+			result.WriteString(borderStyle.Render("│"))
+		}
 		result.WriteString("\n")
 	}
 
-	// Bottom border
-	result.WriteString(borderStyle.Render("╰" + strings.Repeat("─", innerWidth) + "╯"))
+	// Bottom border, with the lines shown out of how many there are
+	position := l.position()
+	if position != "" && lipgloss.Width(position)+4 <= innerWidth {
+		position = " " + position + " "
+		result.WriteString(borderStyle.Render("╰" + strings.Repeat("─", innerWidth-lipgloss.Width(position)-1)))
+		result.WriteString(l.styles.Position.Render(position))
+		result.WriteString(borderStyle.Render("─╯"))
+	} else {
+		result.WriteString(borderStyle.Render("╰" + strings.Repeat("─", innerWidth) + "╯"))
+	}
 
 	return result.String()
 }
 
+// scrollbar returns the first row and the number of rows of the scrollbar
+// thumb, relative to the rows shown, or a length of 0 when all rows fit
+func (l *LogPanel) scrollbar() (start, length int) {
+	total := len(l.rows)
+	if total <= l.viewHeight || l.viewHeight <= 0 {
+		return 0, 0
+	}
+	length = max(l.viewHeight*l.viewHeight/total, 1)
+	offset := max(min(l.scrollOffset, total-l.viewHeight), 0)
+	start = (l.viewHeight - length) * offset / (total - l.viewHeight)
+	return start, length
+}
+
+// position returns the first and last lines shown and the number of lines,
+// like "123–160/8,400", or "" when there are none
+func (l *LogPanel) position() string {
+	if len(l.rows) == 0 {
+		return ""
+	}
+	first := min(max(l.scrollOffset, 0), len(l.rows)-1)
+	last := min(first+l.viewHeight, len(l.rows)) - 1
+	return fmt.Sprintf("%s–%s/%s",
+		formatCount(l.rowLines[first]+1),
+		formatCount(l.rowLines[last]+1),
+		formatCount(len(l.lines)))
+}
+
+// formatCount formats a count with thousands separators
+func formatCount(n int) string {
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
 // ServiceID returns the current service ID
 func (l *LogPanel) ServiceID() config.ServiceID {
 	return l.serviceID
 }
 
 // truncateString truncates a string to maxWidth, handling ANSI escape codes
+// and wide characters
 func truncateString(s string, maxWidth int) string {
 	if maxWidth <= 0 {
 		return ""
@@ -759,45 +1916,11 @@ func truncateString(s string, maxWidth int) string {
 		return s
 	}
 
-	// Truncate character by character, preserving ANSI codes
-	var result strings.Builder
-	visibleWidth := 0
-	inEscape := false
-
-	for _, r := range s {
-		if r == '\x1b' {
-			inEscape = true
-			result.WriteRune(r)
-			continue
-		}
-
-		if inEscape {
-			result.WriteRune(r)
-			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
-				inEscape = false
-			}
-			continue
-		}
-
-		// Regular character - check if it fits
-		charWidth := 1
-		if r > 127 {
-			charWidth = 2 // Assume wide characters for safety
-		}
-
-		if visibleWidth+charWidth > maxWidth-1 {
-			result.WriteString("…")
-			break
-		}
-
-		result.WriteRune(r)
-		visibleWidth += charWidth
-	}
+	// Escape codes past the cut are kept, so hyperlinks still end
+	result := ansi.Truncate(s, maxWidth, "…")
 
 	// Reset any open ANSI sequences
-	result.WriteString("\x1b[0m")
-
-	return result.String()
+	return result + "\x1b[0m"
 }
 
 // renderFooter renders the footer with service info
@@ -808,21 +1931,57 @@ func (l *LogPanel) renderFooter() string {
 
 	var parts []string
 
-	// Port info
-	if l.serviceConfig.Port > 0 {
+	// Level filter (only when lines are hidden)
+	if filter := l.levelFilters[l.serviceID]; filter != LevelFilterAll {
+		levelInfo := fmt.Sprintf("%s %s",
+			l.styles.FooterLabel.Render("Level:"),
+			l.styles.FooterValue.Render(filter.String()))
+		parts = append(parts, levelInfo)
+	}
+	if l.stderrOnly[l.serviceID] {
+		parts = append(parts, fmt.Sprintf("%s %s",
+			l.styles.FooterLabel.Render("Stream:"),
+			l.styles.FooterValue.Render("stderr only")))
+	}
+
+	// Port info, with the configured port if it runs on another one
+	port := l.serviceConfig.Port
+	if l.servicePort > 0 {
+		port = l.servicePort
+	}
+	if port > 0 {
+		value := fmt.Sprintf("%d", port)
+		if l.serviceConfig.Port > 0 && port != l.serviceConfig.Port {
+			value += fmt.Sprintf(" (%d in use)", l.serviceConfig.Port)
+		}
 		portInfo := fmt.Sprintf("%s %s",
 			l.styles.FooterLabel.Render("Port:"),
-			l.styles.FooterValue.Render(fmt.Sprintf("%d", l.serviceConfig.Port)))
+			l.styles.FooterValue.Render(value))
 		parts = append(parts, portInfo)
 	}
 
+	// Resource usage (only while running)
+	if l.serviceStatus == process.StatusRunning && l.serviceStats.RSS > 0 {
+		cpuInfo := fmt.Sprintf("%s %s",
+			l.styles.FooterLabel.Render("CPU:"),
+			l.styles.FooterValue.Render(fmt.Sprintf("%.1f%%", l.serviceStats.CPU)))
+		memInfo := fmt.Sprintf("%s %s",
+			l.styles.FooterLabel.Render("Mem:"),
+			l.styles.FooterValue.Render(formatBytes(l.serviceStats.RSS)))
+		parts = append(parts, cpuInfo, memInfo)
+	}
+
 	// Env info (show first 3 vars)
 	if len(l.serviceConfig.Env) > 0 {
 		envVars := l.serviceConfig.Env
 		if len(envVars) > 3 {
 			envVars = envVars[:3]
 		}
-		envStr := strings.Join(envVars, ", ")
+		masked := make([]string, len(envVars))
+		for i, env := range envVars {
+			masked[i] = MaskEnv(env)
+		}
+		envStr := strings.Join(masked, ", ")
 		if len(l.serviceConfig.Env) > 3 {
 			envStr += fmt.Sprintf(" (+%d more)", len(l.serviceConfig.Env)-3)
 		}
@@ -846,3 +2005,17 @@ func (l *LogPanel) renderFooter() string {
 
 	return strings.Join(parts, " │ ")
 }
+
+// formatBytes formats a byte count as a short human-readable size
+func formatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "KMGT"[exp])
+}
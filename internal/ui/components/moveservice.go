@@ -34,23 +34,23 @@ func DefaultMoveServiceStyles() MoveServiceStyles {
 	return MoveServiceStyles{
 		Container: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#8B5CF6")).
+			BorderForeground(theme.Primary).
 			Padding(1, 2),
 		Title: lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#8B5CF6")),
+			Foreground(theme.Primary),
 		ServiceName: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F9FAFB")).
+			Foreground(theme.Text).
 			Bold(true),
 		Item: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#9CA3AF")).
+			Foreground(theme.Subtle).
 			PaddingLeft(2),
 		SelectedItem: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F9FAFB")).
+			Foreground(theme.Text).
 			Bold(true).
 			PaddingLeft(2),
 		Help: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
+			Foreground(theme.Muted).
 			MarginTop(1),
 	}
 }
@@ -0,0 +1,171 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/process"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// OrphanModal lists processes left running by a crashed paraler session
+// and lets the user adopt or kill them
+type OrphanModal struct {
+	visible  bool
+	orphans  []process.ProcessRecord
+	selected int
+	width    int
+	styles   OrphanStyles
+}
+
+// OrphanStyles contains styles for the modal
+type OrphanStyles struct {
+	Container    lipgloss.Style
+	Title        lipgloss.Style
+	Description  lipgloss.Style
+	Item         lipgloss.Style
+	SelectedItem lipgloss.Style
+	Detail       lipgloss.Style
+	Help         lipgloss.Style
+}
+
+// DefaultOrphanStyles returns default styles
+func DefaultOrphanStyles() OrphanStyles {
+	return OrphanStyles{
+		Container: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(theme.Warning).
+			Padding(1, 2),
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Warning),
+		Description: lipgloss.NewStyle().
+			Foreground(theme.Subtle),
+		Item: lipgloss.NewStyle().
+			Foreground(theme.Subtle).
+			PaddingLeft(2),
+		SelectedItem: lipgloss.NewStyle().
+			Foreground(theme.Text).
+			Bold(true).
+			PaddingLeft(2),
+		Detail: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			PaddingLeft(6),
+		Help: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			MarginTop(1),
+	}
+}
+
+// NewOrphanModal creates a new orphan modal
+func NewOrphanModal() *OrphanModal {
+	return &OrphanModal{
+		styles: DefaultOrphanStyles(),
+	}
+}
+
+// SetSize sets the modal width
+func (m *OrphanModal) SetSize(width int) {
+	m.width = width
+}
+
+// Show shows the modal with the given orphans
+func (m *OrphanModal) Show(orphans []process.ProcessRecord) {
+	m.orphans = orphans
+	m.selected = 0
+	m.visible = len(orphans) > 0
+}
+
+// Hide hides the modal
+func (m *OrphanModal) Hide() {
+	m.visible = false
+	m.orphans = nil
+}
+
+// IsVisible returns true if modal is visible
+func (m *OrphanModal) IsVisible() bool {
+	return m.visible
+}
+
+// MoveUp moves selection up
+func (m *OrphanModal) MoveUp() {
+	if m.selected > 0 {
+		m.selected--
+	}
+}
+
+// MoveDown moves selection down
+func (m *OrphanModal) MoveDown() {
+	if m.selected < len(m.orphans)-1 {
+		m.selected++
+	}
+}
+
+// Selected returns the currently selected orphan
+func (m *OrphanModal) Selected() (process.ProcessRecord, bool) {
+	if m.selected < len(m.orphans) {
+		return m.orphans[m.selected], true
+	}
+	return process.ProcessRecord{}, false
+}
+
+// Orphans returns all orphans still listed
+func (m *OrphanModal) Orphans() []process.ProcessRecord {
+	return m.orphans
+}
+
+// Remove drops a resolved orphan from the list, hiding the modal once
+// nothing is left
+func (m *OrphanModal) Remove(pid int) {
+	for i, o := range m.orphans {
+		if o.PID == pid {
+			m.orphans = append(m.orphans[:i], m.orphans[i+1:]...)
+			break
+		}
+	}
+	if m.selected >= len(m.orphans) && m.selected > 0 {
+		m.selected--
+	}
+	if len(m.orphans) == 0 {
+		m.Hide()
+	}
+}
+
+// View renders the modal
+func (m *OrphanModal) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	var b strings.Builder
+
+	b.WriteString(m.styles.Title.Render("Orphaned processes found"))
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.Description.Render("A previous session exited without stopping these services:"))
+	b.WriteString("\n\n")
+
+	for i, o := range m.orphans {
+		label := fmt.Sprintf("%s (%s)  PID %d", o.Service, o.Project, o.PID)
+		if i == m.selected {
+			b.WriteString(m.styles.SelectedItem.Render("→ " + label))
+		} else {
+			b.WriteString(m.styles.Item.Render("  " + label))
+		}
+		b.WriteString("\n")
+
+		detail := o.Cmd
+		if !o.StartedAt.IsZero() {
+			detail = fmt.Sprintf("%s • started %s ago", detail, time.Since(o.StartedAt).Round(time.Second))
+		}
+		b.WriteString(m.styles.Detail.Render(detail))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.styles.Help.Render("a adopt • x kill • A adopt all • X kill all • Esc leave running"))
+
+	return m.styles.Container.
+		Width(m.width).
+		Render(b.String())
+}
@@ -66,10 +66,7 @@ func (p *PathCompleter) Complete(input string) []string {
 
 		// Convert back to use ~ if it was used
 		if strings.HasPrefix(input, "~") {
-			home, _ := os.UserHomeDir()
-			if strings.HasPrefix(fullPath, home) {
-				fullPath = "~" + fullPath[len(home):]
-			}
+			fullPath = shortenHome(fullPath)
 		}
 
 		suggestions = append(suggestions, fullPath+string(filepath.Separator))
@@ -137,6 +134,15 @@ func expandTilde(path string) string {
 	return path
 }
 
+// shortenHome replaces the home directory at the start of a path with ~
+func shortenHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" || (path != home && !strings.HasPrefix(path, home+string(filepath.Separator))) {
+		return path
+	}
+	return "~" + path[len(home):]
+}
+
 // CommonPrefix returns the longest common prefix of suggestions
 func CommonPrefix(suggestions []string) string {
 	if len(suggestions) == 0 {
@@ -0,0 +1,217 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PipeModal asks for a command to pipe log lines to, then shows its output
+type PipeModal struct {
+	visible  bool
+	lines    int
+	input    textinput.Model
+	running  bool
+	done     bool
+	output   []string
+	errorMsg string
+	offset   int
+	width    int
+	height   int
+	styles   PipeStyles
+}
+
+// PipeStyles contains styles for the modal
+type PipeStyles struct {
+	Container lipgloss.Style
+	Title     lipgloss.Style
+	Label     lipgloss.Style
+	Output    lipgloss.Style
+	Error     lipgloss.Style
+	Help      lipgloss.Style
+}
+
+// DefaultPipeStyles returns default styles
+func DefaultPipeStyles() PipeStyles {
+	return PipeStyles{
+		Container: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(theme.Accent).
+			Padding(1, 2),
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Accent),
+		Label: lipgloss.NewStyle().
+			Foreground(theme.Subtle),
+		Output: lipgloss.NewStyle().
+			Foreground(theme.Text),
+		Error: lipgloss.NewStyle().
+			Foreground(theme.Error),
+		Help: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			MarginTop(1),
+	}
+}
+
+// NewPipeModal creates a new pipe modal
+func NewPipeModal() *PipeModal {
+	ti := textinput.New()
+	ti.Placeholder = "jq -r .level | sort | uniq -c"
+	ti.CharLimit = 256
+	ti.Width = 40
+
+	return &PipeModal{
+		input:  ti,
+		styles: DefaultPipeStyles(),
+	}
+}
+
+// SetSize sets the modal size
+func (m *PipeModal) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.input.Width = width - 10
+}
+
+// Show shows the modal to pipe a number of lines, keeping the last command
+func (m *PipeModal) Show(lines int) {
+	m.lines = lines
+	m.running = false
+	m.done = false
+	m.output = nil
+	m.errorMsg = ""
+	m.offset = 0
+	m.input.Focus()
+	m.input.CursorEnd()
+	m.visible = true
+}
+
+// Hide hides the modal
+func (m *PipeModal) Hide() {
+	m.visible = false
+	m.input.Blur()
+}
+
+// IsVisible returns true if modal is visible
+func (m *PipeModal) IsVisible() bool {
+	return m.visible
+}
+
+// Command returns the entered command
+func (m *PipeModal) Command() string {
+	return strings.TrimSpace(m.input.Value())
+}
+
+// Input returns the text input model
+func (m *PipeModal) Input() *textinput.Model {
+	return &m.input
+}
+
+// SetRunning shows that the command is running
+func (m *PipeModal) SetRunning() {
+	m.running = true
+	m.input.Blur()
+}
+
+// IsRunning returns true if the command is running
+func (m *PipeModal) IsRunning() bool {
+	return m.running
+}
+
+// SetResult shows the output of the command, and its error if it failed
+func (m *PipeModal) SetResult(output string, err error) {
+	m.running = false
+	m.done = true
+	m.output = strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if output == "" {
+		m.output = nil
+	}
+	m.errorMsg = ""
+	if err != nil {
+		m.errorMsg = err.Error()
+	}
+	m.offset = 0
+}
+
+// HasResult returns true if the modal shows the output of the command
+func (m *PipeModal) HasResult() bool {
+	return m.done
+}
+
+// outputHeight returns the number of output lines shown at once
+func (m *PipeModal) outputHeight() int {
+	return max(3, m.height-12)
+}
+
+// ScrollUp scrolls the output up by n lines
+func (m *PipeModal) ScrollUp(n int) {
+	m.offset = max(0, m.offset-n)
+}
+
+// ScrollDown scrolls the output down by n lines
+func (m *PipeModal) ScrollDown(n int) {
+	m.offset = max(0, min(m.offset+n, len(m.output)-m.outputHeight()))
+}
+
+// View renders the modal
+func (m *PipeModal) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	contentWidth := m.width - 6
+	if contentWidth < 20 {
+		contentWidth = 20
+	}
+
+	var b strings.Builder
+
+	b.WriteString(m.styles.Title.Render(fmt.Sprintf("Pipe %d lines", m.lines)))
+	b.WriteString("\n\n")
+
+	if !m.running && !m.done {
+		b.WriteString(m.styles.Label.Render("Command:"))
+		b.WriteString("\n")
+		b.WriteString(m.input.View())
+		b.WriteString("\n")
+		b.WriteString(m.styles.Help.Render("Enter run • Esc cancel"))
+		return m.styles.Container.
+			Width(m.width).
+			Render(b.String())
+	}
+
+	b.WriteString(m.styles.Label.Render("$ " + m.Command()))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.running:
+		b.WriteString(m.styles.Label.Render("Running..."))
+		b.WriteString("\n")
+	case len(m.output) == 0 && m.errorMsg == "":
+		b.WriteString(m.styles.Label.Render("(no output)"))
+		b.WriteString("\n")
+	}
+
+	end := min(m.offset+m.outputHeight(), len(m.output))
+	for _, line := range m.output[m.offset:end] {
+		b.WriteString(m.styles.Output.Render(truncateString(sanitizeLine(line), contentWidth)))
+		b.WriteString("\n")
+	}
+	if len(m.output) > end-m.offset {
+		b.WriteString(m.styles.Label.Render(fmt.Sprintf("lines %d-%d of %d", m.offset+1, end, len(m.output))))
+		b.WriteString("\n")
+	}
+
+	if m.errorMsg != "" {
+		b.WriteString(m.styles.Error.Render(truncateString(m.errorMsg, contentWidth)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(m.styles.Help.Render("↑/↓ scroll • Esc close"))
+
+	return m.styles.Container.
+		Width(m.width).
+		Render(b.String())
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/paralerdev/paraler/internal/config"
 	"github.com/paralerdev/paraler/internal/process"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // PortConflictModal shows port conflict information and options
@@ -14,6 +15,7 @@ type PortConflictModal struct {
 	visible      bool
 	conflict     *process.PortConflictInfo
 	serviceID    config.ServiceID // The service we're trying to start
+	movable      bool             // The service can start on another port
 	width        int
 	styles       PortConflictStyles
 }
@@ -34,23 +36,23 @@ func DefaultPortConflictStyles() PortConflictStyles {
 	return PortConflictStyles{
 		Container: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#F59E0B")).
+			BorderForeground(theme.Warning).
 			Padding(1, 2),
 		Title: lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#F59E0B")),
+			Foreground(theme.Warning),
 		Port: lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#F9FAFB")),
+			Foreground(theme.Text),
 		ProcessInfo: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#9CA3AF")).
+			Foreground(theme.Subtle).
 			MarginTop(1),
 		Label: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")),
+			Foreground(theme.Muted),
 		Value: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F9FAFB")),
+			Foreground(theme.Text),
 		Help: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
+			Foreground(theme.Muted).
 			MarginTop(1),
 	}
 }
@@ -67,11 +69,13 @@ func (m *PortConflictModal) SetSize(width int) {
 	m.width = width
 }
 
-// Show shows the modal with conflict info
-func (m *PortConflictModal) Show(serviceID config.ServiceID, conflict *process.PortConflictInfo) {
+// Show shows the modal with conflict info. movable offers to start the
+// service on another port, for services whose port is configured.
+func (m *PortConflictModal) Show(serviceID config.ServiceID, conflict *process.PortConflictInfo, movable bool) {
 	m.visible = true
 	m.serviceID = serviceID
 	m.conflict = conflict
+	m.movable = movable
 }
 
 // Hide hides the modal
@@ -95,6 +99,11 @@ func (m *PortConflictModal) ServiceID() config.ServiceID {
 	return m.serviceID
 }
 
+// IsMovable returns true if the service can start on another port
+func (m *PortConflictModal) IsMovable() bool {
+	return m.movable
+}
+
 // View renders the modal
 func (m *PortConflictModal) View() string {
 	if !m.visible || m.conflict == nil {
@@ -137,9 +146,7 @@ func (m *PortConflictModal) View() string {
 			if maxLen < 20 {
 				maxLen = 20
 			}
-			if len(cmd) > maxLen {
-				cmd = cmd[:maxLen-3] + "..."
-			}
+			cmd = ansi.Truncate(cmd, maxLen, "...")
 			b.WriteString(m.styles.Label.Render("Command: "))
 			b.WriteString(m.styles.Value.Render(cmd))
 			b.WriteString("\n")
@@ -149,8 +156,8 @@ func (m *PortConflictModal) View() string {
 	b.WriteString("\n")
 
 	// Help
-	if m.conflict.IsParalerService {
-		b.WriteString(m.styles.Help.Render("k kill & start • Esc cancel"))
+	if m.movable {
+		b.WriteString(m.styles.Help.Render("k kill & start • p start on next free port • Esc cancel"))
 	} else {
 		b.WriteString(m.styles.Help.Render("k kill & start • Esc cancel"))
 	}
@@ -43,18 +43,18 @@ func DefaultRenameStyles() RenameStyles {
 	return RenameStyles{
 		Container: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#8B5CF6")).
+			BorderForeground(theme.Primary).
 			Padding(1, 2),
 		Title: lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#8B5CF6")),
+			Foreground(theme.Primary),
 		Label: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#9CA3AF")),
+			Foreground(theme.Subtle),
 		Error: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#EF4444")).
+			Foreground(theme.Error).
 			MarginTop(1),
 		Help: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")).
+			Foreground(theme.Muted).
 			MarginTop(1),
 	}
 }
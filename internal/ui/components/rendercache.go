@@ -0,0 +1,277 @@
+package components
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+	"github.com/paralerdev/paraler/internal/log"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// renderedEntry is a log entry shown in the panel, rendered once
+type renderedEntry struct {
+	entry      log.Entry
+	seq        uint64 // number of the entry in its service's buffer
+	lines      []string
+	rawLines   []string
+	isError    bool
+	bookmarked bool
+
+	// Rows of each line wrapped to wrapWidth, in wrap mode
+	wrapped   [][]string
+	wrapWidth int
+}
+
+// renderKey holds what rendered lines depend on besides their entries.
+// Lines are rendered again when it changes.
+type renderKey struct {
+	services   string
+	filter     string
+	highlight  bool
+	levels     string
+	stderr     string
+	timestamps string
+	clock      string // layout and zone of times of day
+	now        int64  // with relative timestamps, the second rendered at
+	expandJSON bool
+	colors     bool
+	bookmarks  int
+	rules      int
+	fields     string
+	color      string
+}
+
+// renderCache keeps the entries shown rendered, so only entries added to
+// the buffer since are rendered on update
+type renderCache struct {
+	key      renderKey
+	versions map[config.ServiceID]log.Version
+	oldest   map[config.ServiceID]uint64 // number of the oldest entry kept
+	entries  []renderedEntry
+
+	// Width and wrap mode the rows were laid out with
+	width int
+	wrap  bool
+}
+
+// renderKey returns what the panel's lines depend on now
+func (l *LogPanel) renderKey(now time.Time) renderKey {
+	ids := l.ShownIDs()
+	levels := make([]LevelFilter, len(ids))
+	stderr := make([]bool, len(ids))
+	for i, id := range ids {
+		levels[i] = l.levelFilters[id]
+		stderr[i] = l.stderrOnly[id]
+	}
+
+	key := renderKey{
+		services:   fmt.Sprint(ids, l.mergedColors),
+		filter:     l.filter.String(),
+		highlight:  l.highlight,
+		levels:     fmt.Sprint(levels),
+		stderr:     fmt.Sprint(stderr),
+		timestamps: l.timestamps,
+		clock:      clockLayout + " " + clockLocation.String(),
+		expandJSON: l.expandJSON,
+		colors:     l.stripColors,
+		bookmarks:  l.bookmarksChanged,
+		rules:      l.rulesChanged,
+		fields:     strings.Join(l.logFields(), ","),
+	}
+	if l.timestamps == config.TimestampsRelative {
+		key.now = now.Unix()
+	}
+	if l.serviceConfig != nil {
+		key.color = l.serviceConfig.Color
+	}
+	return key
+}
+
+// refreshCache brings the rendered entries up to date with the buffer,
+// rendering only entries added since the last refresh unless what lines
+// depend on changed. It returns false if nothing changed.
+func (l *LogPanel) refreshCache(buffer *log.Buffer, now time.Time) bool {
+	c := &l.cache
+	key := l.renderKey(now)
+	if key != c.key || c.versions == nil {
+		l.rebuildCache(buffer, key, now)
+		return true
+	}
+
+	// Collect the entries added to each service since
+	var added []log.Entry
+	var seqs []uint64
+	trimmed := false
+	for _, id := range l.ShownIDs() {
+		entries, oldest, version, ok := buffer.GetSince(id, c.versions[id])
+		if !ok {
+			l.rebuildCache(buffer, key, now)
+			return true
+		}
+		c.versions[id] = version
+		if oldest != c.oldest[id] {
+			c.oldest[id] = oldest
+			trimmed = true
+		}
+		for i, entry := range entries {
+			added = append(added, entry)
+			seqs = append(seqs, version.Added-uint64(len(entries)-i-1))
+		}
+	}
+	if len(added) == 0 && !trimmed {
+		return false
+	}
+
+	if trimmed {
+		c.entries = slices.DeleteFunc(c.entries, func(r renderedEntry) bool {
+			return r.seq < c.oldest[r.entry.ServiceID]
+		})
+	}
+
+	merged := l.IsMerged()
+	if merged {
+		sort.Stable(byTime{added, seqs})
+	}
+	prefixes, rawPrefixes := l.mergedPrefixes()
+	for i, entry := range added {
+		rendered, ok := l.renderEntry(entry, prefixes, rawPrefixes, now)
+		if !ok {
+			continue
+		}
+		rendered.seq = seqs[i]
+
+		// In the merged view, entries printed before the last one shown (by
+		// a service slower to send output) go before it
+		pos := len(c.entries)
+		for merged && pos > 0 && entry.Timestamp.Before(c.entries[pos-1].entry.Timestamp) {
+			pos--
+		}
+		c.entries = slices.Insert(c.entries, pos, rendered)
+	}
+	return true
+}
+
+// rebuildCache renders all entries of the services shown again
+func (l *LogPanel) rebuildCache(buffer *log.Buffer, key renderKey, now time.Time) {
+	c := &l.cache
+	c.key = key
+	c.versions = make(map[config.ServiceID]log.Version)
+	c.oldest = make(map[config.ServiceID]uint64)
+	c.entries = c.entries[:0]
+
+	var entries []log.Entry
+	var seqs []uint64
+	for _, id := range l.ShownIDs() {
+		serviceEntries, version := buffer.GetVersion(id)
+		c.versions[id] = version
+		c.oldest[id] = version.Added - uint64(len(serviceEntries)) + 1
+		for i, entry := range serviceEntries {
+			entries = append(entries, entry)
+			seqs = append(seqs, c.oldest[id]+uint64(i))
+		}
+	}
+
+	// Stable, so lines of a service printed at the same time keep their order
+	if l.IsMerged() {
+		sort.Stable(byTime{entries, seqs})
+	}
+
+	prefixes, rawPrefixes := l.mergedPrefixes()
+	for i, entry := range entries {
+		if rendered, ok := l.renderEntry(entry, prefixes, rawPrefixes, now); ok {
+			rendered.seq = seqs[i]
+			c.entries = append(c.entries, rendered)
+		}
+	}
+}
+
+// byTime sorts entries, and their numbers along, by timestamp
+type byTime struct {
+	entries []log.Entry
+	seqs    []uint64
+}
+
+func (b byTime) Len() int { return len(b.entries) }
+
+func (b byTime) Less(i, j int) bool {
+	return b.entries[i].Timestamp.Before(b.entries[j].Timestamp)
+}
+
+func (b byTime) Swap(i, j int) {
+	b.entries[i], b.entries[j] = b.entries[j], b.entries[i]
+	b.seqs[i], b.seqs[j] = b.seqs[j], b.seqs[i]
+}
+
+// layoutLines sets the lines and rows shown from the rendered entries,
+// wrapping lines over several rows in wrap mode
+func (l *LogPanel) layoutLines() {
+	c := &l.cache
+	c.width = l.contentWidth()
+	c.wrap = l.wrap
+
+	l.entries = l.entries[:0]
+	l.lines = l.lines[:0]
+	l.rawLines = l.rawLines[:0]
+	l.lineEntries = l.lineEntries[:0]
+	l.bookmarkLines = l.bookmarkLines[:0]
+	l.errorLines = l.errorLines[:0]
+	l.rows = l.rows[:0]
+	l.rowLines = l.rowLines[:0]
+	l.lineRows = l.lineRows[:0]
+
+	lastError := false
+	for i := range c.entries {
+		rendered := &c.entries[i]
+		if rendered.bookmarked {
+			l.bookmarkLines = append(l.bookmarkLines, len(l.lines))
+		}
+		if rendered.isError && !lastError {
+			l.errorLines = append(l.errorLines, len(l.lines))
+		}
+		lastError = rendered.isError
+
+		if l.wrap && rendered.wrapWidth != c.width {
+			rendered.wrapped = make([][]string, len(rendered.lines))
+			for j, line := range rendered.lines {
+				if lipgloss.Width(line) > c.width {
+					rendered.wrapped[j] = wrapLine(line, c.width)
+				}
+			}
+			rendered.wrapWidth = c.width
+		}
+
+		for j, line := range rendered.lines {
+			l.lineRows = append(l.lineRows, len(l.rows))
+			if l.wrap && rendered.wrapped[j] != nil {
+				for _, row := range rendered.wrapped[j] {
+					l.rows = append(l.rows, row)
+					l.rowLines = append(l.rowLines, len(l.lines))
+				}
+			} else {
+				l.rows = append(l.rows, line)
+				l.rowLines = append(l.rowLines, len(l.lines))
+			}
+			l.lines = append(l.lines, line)
+			l.lineEntries = append(l.lineEntries, len(l.entries))
+		}
+		l.rawLines = append(l.rawLines, rendered.rawLines...)
+		l.entries = append(l.entries, rendered.entry)
+	}
+}
+
+// cachedLine returns the first line shown of the entry of a service
+// numbered seq, or of the first entry shown after it, or -1
+func (l *LogPanel) cachedLine(id config.ServiceID, seq uint64) int {
+	line := 0
+	for _, rendered := range l.cache.entries {
+		if rendered.entry.ServiceID == id && rendered.seq >= seq {
+			return line
+		}
+		line += len(rendered.lines)
+	}
+	return -1
+}
@@ -0,0 +1,273 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/paralerdev/paraler/internal/config"
+	"github.com/paralerdev/paraler/internal/log"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxSearchHits caps the hits listed, so a broad query stays responsive
+const maxSearchHits = 500
+
+// SearchHit is a log line matching a search
+type SearchHit struct {
+	Entry log.Entry
+	Index int // Position of the entry in the service's log buffer
+}
+
+// SearchModal searches the logs of all services at once
+type SearchModal struct {
+	visible   bool
+	input     textinput.Model
+	hits      []SearchHit
+	truncated bool
+	err       error
+	selected  int
+	offset    int
+	width     int
+	height    int
+	styles    SearchStyles
+}
+
+// SearchStyles contains styles for the modal
+type SearchStyles struct {
+	Container    lipgloss.Style
+	Title        lipgloss.Style
+	Prompt       lipgloss.Style
+	Error        lipgloss.Style
+	Service      lipgloss.Style
+	Timestamp    lipgloss.Style
+	Item         lipgloss.Style
+	SelectedItem lipgloss.Style
+	Info         lipgloss.Style
+	Help         lipgloss.Style
+}
+
+// DefaultSearchStyles returns default styles
+func DefaultSearchStyles() SearchStyles {
+	return SearchStyles{
+		Container: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(theme.Accent).
+			Padding(1, 2),
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Accent),
+		Prompt: lipgloss.NewStyle().
+			Foreground(theme.Primary).
+			Bold(true),
+		Error: lipgloss.NewStyle().
+			Foreground(theme.Error),
+		Service: lipgloss.NewStyle().
+			Foreground(theme.Primary).
+			Bold(true),
+		Timestamp: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+		Item: lipgloss.NewStyle().
+			Foreground(theme.Subtle),
+		SelectedItem: lipgloss.NewStyle().
+			Foreground(theme.Text).
+			Bold(true),
+		Info: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			Italic(true),
+		Help: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			MarginTop(1),
+	}
+}
+
+// NewSearchModal creates a new search modal
+func NewSearchModal() *SearchModal {
+	ti := textinput.New()
+	ti.Placeholder = "Search all logs (regexp, !exclude)..."
+	ti.CharLimit = 100
+
+	return &SearchModal{
+		input:  ti,
+		styles: DefaultSearchStyles(),
+	}
+}
+
+// SetSize sets the modal size
+func (m *SearchModal) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.input.Width = width - 10
+}
+
+// Show shows the modal, keeping the previous query and its hits
+func (m *SearchModal) Show() {
+	m.visible = true
+	m.input.Focus()
+}
+
+// Hide hides the modal
+func (m *SearchModal) Hide() {
+	m.visible = false
+	m.input.Blur()
+}
+
+// IsVisible returns true if modal is visible
+func (m *SearchModal) IsVisible() bool {
+	return m.visible
+}
+
+// Input returns the query input model
+func (m *SearchModal) Input() *textinput.Model {
+	return &m.input
+}
+
+// Search runs the query against the logs of services, listing hits
+// grouped by service in the order given
+func (m *SearchModal) Search(buffer *log.Buffer, ids []config.ServiceID) {
+	m.hits = nil
+	m.truncated = false
+	m.selected = 0
+	m.offset = 0
+
+	filter, err := log.ParseFilter(m.input.Value())
+	m.err = err
+	if filter == nil {
+		return
+	}
+
+	for _, id := range ids {
+		for i, entry := range buffer.Get(id) {
+			if !filter.Match(entry.Line) {
+				continue
+			}
+			if len(m.hits) == maxSearchHits {
+				m.truncated = true
+				return
+			}
+			m.hits = append(m.hits, SearchHit{Entry: entry, Index: i})
+		}
+	}
+}
+
+// MoveUp moves selection up
+func (m *SearchModal) MoveUp() {
+	if m.selected > 0 {
+		m.selected--
+	}
+}
+
+// MoveDown moves selection down
+func (m *SearchModal) MoveDown() {
+	if m.selected < len(m.hits)-1 {
+		m.selected++
+	}
+}
+
+// Selected returns the selected hit, if any
+func (m *SearchModal) Selected() (SearchHit, bool) {
+	if m.selected < len(m.hits) {
+		return m.hits[m.selected], true
+	}
+	return SearchHit{}, false
+}
+
+// View renders the modal
+func (m *SearchModal) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	contentWidth := m.width - 6
+	if contentWidth < 20 {
+		contentWidth = 20
+	}
+
+	var b strings.Builder
+
+	b.WriteString(m.styles.Title.Render("Search all logs"))
+	b.WriteString("\n\n")
+	b.WriteString(m.styles.Prompt.Render("/"))
+	b.WriteString(m.input.View())
+	b.WriteString("\n")
+	if m.err != nil {
+		b.WriteString(truncateString(m.styles.Error.Render(m.err.Error()), contentWidth))
+	}
+	b.WriteString("\n")
+
+	switch {
+	case m.input.Value() == "":
+		b.WriteString(m.styles.Info.Render("Type to search the logs of every service."))
+	case len(m.hits) == 0 && m.err == nil:
+		b.WriteString(m.styles.Info.Render("No matches."))
+	default:
+		b.WriteString(m.renderHits(contentWidth))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.styles.Help.Render("↑/↓ select • Enter jump to line • Esc close"))
+
+	return m.styles.Container.
+		Width(m.width).
+		Render(b.String())
+}
+
+// renderHits renders the window of hits around the selection, with a
+// header for each service
+func (m *SearchModal) renderHits(width int) string {
+	// Rows are service headers (hit -1) and hits
+	type row struct {
+		text string
+		hit  int
+	}
+	var rows []row
+	var current config.ServiceID
+	selectedRow := 0
+	for i, hit := range m.hits {
+		if i == 0 || hit.Entry.ServiceID != current {
+			current = hit.Entry.ServiceID
+			rows = append(rows, row{text: m.styles.Service.Render(current.String()), hit: -1})
+		}
+		if i == m.selected {
+			selectedRow = len(rows)
+		}
+
+		line := m.styles.Timestamp.Render(formatClock(hit.Entry.Timestamp)) + " "
+		if i == m.selected {
+			line = m.styles.SelectedItem.Render("→ ") + line + m.styles.SelectedItem.Render(sanitizeLine(hit.Entry.Line))
+		} else {
+			line = "  " + line + m.styles.Item.Render(sanitizeLine(hit.Entry.Line))
+		}
+		rows = append(rows, row{text: truncateString(line, width), hit: i})
+	}
+
+	// Keep the selection in view
+	visible := m.height - 14
+	if visible < 3 {
+		visible = 3
+	}
+	if selectedRow < m.offset {
+		m.offset = selectedRow
+		// Show the header of the selected hit's service
+		if m.offset > 0 && rows[m.offset-1].hit == -1 {
+			m.offset--
+		}
+	}
+	if selectedRow >= m.offset+visible {
+		m.offset = selectedRow - visible + 1
+	}
+	end := min(m.offset+visible, len(rows))
+
+	var lines []string
+	for _, r := range rows[m.offset:end] {
+		lines = append(lines, r.text)
+	}
+
+	summary := fmt.Sprintf("%d matches", len(m.hits))
+	if m.truncated {
+		summary = fmt.Sprintf("first %d matches", maxSearchHits)
+	}
+	lines = append(lines, "", m.styles.Info.Render(summary))
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,123 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/process"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ShutdownScreen shows the progress of stopping services when paraler quits
+type ShutdownScreen struct {
+	procs  []*process.Process
+	width  int
+	height int
+	styles ShutdownStyles
+}
+
+// ShutdownStyles contains styles for the shutdown screen
+type ShutdownStyles struct {
+	Container lipgloss.Style
+	Title     lipgloss.Style
+	Service   lipgloss.Style
+	Waiting   lipgloss.Style
+	Stopping  lipgloss.Style
+	Killing   lipgloss.Style
+	Stopped   lipgloss.Style
+	Help      lipgloss.Style
+}
+
+// DefaultShutdownStyles returns default styles
+func DefaultShutdownStyles() ShutdownStyles {
+	return ShutdownStyles{
+		Container: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(theme.Accent).
+			Padding(1, 2),
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Accent),
+		Service: lipgloss.NewStyle().
+			Foreground(theme.Text),
+		Waiting: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+		Stopping: lipgloss.NewStyle().
+			Foreground(theme.Warning),
+		Killing: lipgloss.NewStyle().
+			Foreground(theme.Error),
+		Stopped: lipgloss.NewStyle().
+			Foreground(theme.Success),
+		Help: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			MarginTop(1),
+	}
+}
+
+// NewShutdownScreen creates a new shutdown screen
+func NewShutdownScreen() *ShutdownScreen {
+	return &ShutdownScreen{
+		styles: DefaultShutdownStyles(),
+	}
+}
+
+// SetSize sets the screen size
+func (s *ShutdownScreen) SetSize(width, height int) {
+	s.width = width
+	s.height = height
+}
+
+// Start shows progress for the services being stopped
+func (s *ShutdownScreen) Start(procs []*process.Process) {
+	s.procs = procs
+}
+
+// View renders the screen
+func (s *ShutdownScreen) View() string {
+	stopped := 0
+	nameWidth := 0
+	for _, p := range s.procs {
+		if p.IsDone() {
+			stopped++
+		}
+		nameWidth = max(nameWidth, len(p.ID.String()))
+	}
+
+	var b strings.Builder
+	b.WriteString(s.styles.Title.Render(fmt.Sprintf("Stopping services… %d/%d stopped", stopped, len(s.procs))))
+	b.WriteString("\n\n")
+
+	for _, p := range s.procs {
+		b.WriteString(s.styles.Service.Render(fmt.Sprintf("%-*s", nameWidth, p.ID.String())))
+		b.WriteString("  ")
+		b.WriteString(s.renderProgress(p))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(s.styles.Help.Render("paraler exits once every service has stopped"))
+
+	return lipgloss.Place(s.width, s.height, lipgloss.Center, lipgloss.Center,
+		s.styles.Container.Render(b.String()))
+}
+
+// renderProgress renders where a service is in stopping: waiting for its
+// dependents to stop, counting down the grace period, or being killed
+func (s *ShutdownScreen) renderProgress(p *process.Process) string {
+	switch p.Status() {
+	case process.StatusStarting, process.StatusRunning:
+		return s.styles.Waiting.Render("◌ waiting for dependents")
+	case process.StatusStopping:
+		requested := p.StopRequestedAt()
+		if requested.IsZero() {
+			return s.styles.Stopping.Render("◐ stopping")
+		}
+		left := process.StopGracePeriod - time.Since(requested)
+		if left <= 0 {
+			return s.styles.Killing.Render("✖ force killing")
+		}
+		return s.styles.Stopping.Render(fmt.Sprintf("◐ stopping, force kill in %ds", int(left.Seconds())+1))
+	default:
+		return s.styles.Stopped.Render("✔ stopped")
+	}
+}
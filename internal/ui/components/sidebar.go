@@ -2,31 +2,107 @@ package components
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/paralerdev/paraler/internal/config"
 	"github.com/paralerdev/paraler/internal/log"
 	"github.com/paralerdev/paraler/internal/process"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // SidebarItem represents a single item in the sidebar
 type SidebarItem struct {
 	ID        config.ServiceID
 	IsProject bool
+	IsGroup   bool // Header of a replicated service, followed by its replicas
+	IsAll     bool // Virtual item on top standing for all services
 	Name      string
 }
 
+// isService returns true if the item is a selectable service (or replica)
+func (i SidebarItem) isService() bool {
+	return !i.IsProject && !i.IsGroup && !i.IsAll
+}
+
+// selectable returns true if the item can be selected: a service, a
+// project header or the all services item
+func (i SidebarItem) selectable() bool {
+	return !i.IsGroup
+}
+
+// SortMode is the order of the projects and services in the sidebar
+type SortMode int
+
+const (
+	SortAlphabetical SortMode = iota
+	SortConfig                // the order of the config file
+	SortRunning               // running services first
+	SortRecent                // recently started or restarted services first
+	sortModeCount
+)
+
+// sortModeNames are the names of the sort modes, as shown and saved
+var sortModeNames = [sortModeCount]string{
+	SortAlphabetical: "alphabetical",
+	SortConfig:       "config order",
+	SortRunning:      "running first",
+	SortRecent:       "recently used",
+}
+
+// String returns the name of the sort mode
+func (m SortMode) String() string {
+	if m < 0 || m >= sortModeCount {
+		return sortModeNames[SortAlphabetical]
+	}
+	return sortModeNames[m]
+}
+
+// Next returns the sort mode cycled to after m
+func (m SortMode) Next() SortMode {
+	return (m + 1) % sortModeCount
+}
+
+// ParseSortMode returns the sort mode with a name, or alphabetical for
+// unknown names
+func ParseSortMode(name string) SortMode {
+	for mode, modeName := range sortModeNames {
+		if modeName == name {
+			return SortMode(mode)
+		}
+	}
+	return SortAlphabetical
+}
+
 // Sidebar is the service list component
 type Sidebar struct {
-	items       []SidebarItem
+	cfg         *config.Config
+	all         []SidebarItem   // Every item, including the services of collapsed projects
+	items       []SidebarItem   // Items shown
+	collapsed   map[string]bool // Collapsed projects
+	sortMode    SortMode
+	running     map[string]bool      // Services running when last sorted, by base ID
+	lastUsed    map[string]time.Time // When services were last used, by base ID
 	selected    int
 	width       int
 	height      int
 	focused     bool
 	styles      SidebarStyles
-	multiSelect map[int]bool // Selected items for multi-select mode
+	multiSelect map[config.ServiceID]bool // Selected services for multi-select mode
+	rowItems    []int                     // Item rendered on each row below the title
+	spinner     int                       // Tick of the status spinners
+
+	// Find prompt: items matching the query, best first
+	finding     bool
+	findInput   textinput.Model
+	findMatches []config.ServiceID
+	findMatch   int         // Index in findMatches of the selected service
+	findFrom    SidebarItem // Selection before finding, restored on cancel
 }
 
 // SidebarStyles contains sidebar-specific styles
@@ -35,6 +111,7 @@ type SidebarStyles struct {
 	Title            lipgloss.Style
 	TitleFocused     lipgloss.Style
 	ProjectHeader    lipgloss.Style
+	ProjectSelected  lipgloss.Style
 	Item             lipgloss.Style
 	ItemSelected     lipgloss.Style
 	SelectionMarker  lipgloss.Style
@@ -43,12 +120,19 @@ type SidebarStyles struct {
 	StatusStopped    lipgloss.Style
 	StatusFailed     lipgloss.Style
 	StatusStarting   lipgloss.Style
+	StatusPaused     lipgloss.Style
 	StatusIndicator  lipgloss.Style
 	HealthHealthy    lipgloss.Style
 	HealthUnhealthy  lipgloss.Style
 	HealthUnknown    lipgloss.Style
 	MultiSelectMark  lipgloss.Style
 	ErrorBadge       lipgloss.Style
+	ErrorBadgeSeen   lipgloss.Style
+	Uptime           lipgloss.Style
+	RestartBadge     lipgloss.Style
+	FindPrompt       lipgloss.Style
+	FindCount        lipgloss.Style
+	SortHint         lipgloss.Style
 }
 
 // DefaultSidebarStyles returns the default sidebar styles
@@ -56,60 +140,87 @@ func DefaultSidebarStyles() SidebarStyles {
 	return SidebarStyles{
 		Container: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#374151")),
+			BorderForeground(theme.Border),
 		Title: lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#6B7280")).
+			Foreground(theme.Muted).
 			Padding(0, 1),
 		TitleFocused: lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#8B5CF6")).
+			Foreground(theme.Primary).
 			Padding(0, 1),
 		ProjectHeader: lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#8B5CF6")).
+			Foreground(theme.Primary).
+			MarginTop(1),
+		ProjectSelected: selectionStyle(theme.Surface).
+			Bold(true).
+			Foreground(theme.Primary).
 			MarginTop(1),
 		Item: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F9FAFB")),
+			Foreground(theme.Text),
 		ItemSelected: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F9FAFB")).
+			Foreground(theme.Text).
 			Bold(true),
 		SelectionMarker: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#8B5CF6")).
+			Foreground(theme.Primary).
 			Bold(true),
 		StatusRunning: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#10B981")),
+			Foreground(theme.Success),
 		StatusStopped: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")),
+			Foreground(theme.Muted),
 		StatusFailed: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#EF4444")),
+			Foreground(theme.Error),
 		StatusStarting: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F59E0B")),
+			Foreground(theme.Warning),
+		StatusPaused: lipgloss.NewStyle().
+			Foreground(theme.Info),
 		StatusIndicator: lipgloss.NewStyle().
 			Bold(true),
 		HealthHealthy: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#10B981")),
+			Foreground(theme.Success),
 		HealthUnhealthy: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#EF4444")),
+			Foreground(theme.Error),
 		HealthUnknown: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")),
-		ItemMultiSelect: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#F9FAFB")).
-			Background(lipgloss.Color("#374151")),
+			Foreground(theme.Muted),
+		ItemMultiSelect: selectionStyle(theme.Border).
+			Foreground(theme.Text),
 		MultiSelectMark: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#8B5CF6")).
+			Foreground(theme.Primary).
 			Bold(true),
 		ErrorBadge: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#EF4444")).
+			Foreground(theme.Error).
+			Bold(true),
+		ErrorBadgeSeen: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+		Uptime: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+		RestartBadge: lipgloss.NewStyle().
+			Foreground(theme.Warning),
+		FindPrompt: lipgloss.NewStyle().
+			Foreground(theme.Primary).
 			Bold(true),
+		FindCount: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+		SortHint: lipgloss.NewStyle().
+			Foreground(theme.Muted),
 	}
 }
 
 // NewSidebar creates a new sidebar
 func NewSidebar(cfg *config.Config) *Sidebar {
+	ti := textinput.New()
+	ti.Placeholder = "service"
+	ti.Prompt = ""
+	ti.CharLimit = 64
+
 	s := &Sidebar{
+		cfg:         cfg,
 		styles:      DefaultSidebarStyles(),
-		multiSelect: make(map[int]bool),
+		collapsed:   make(map[string]bool),
+		lastUsed:    make(map[string]time.Time),
+		multiSelect: make(map[config.ServiceID]bool),
+		findInput:   ti,
 	}
 	s.buildItems(cfg)
 	return s
@@ -117,44 +228,252 @@ func NewSidebar(cfg *config.Config) *Sidebar {
 
 // buildItems builds the sidebar items from config
 func (s *Sidebar) buildItems(cfg *config.Config) {
-	s.items = nil
+	s.all = nil
 
-	// Sort project names for consistent ordering
-	projectNames := make([]string, 0, len(cfg.Projects))
-	for name := range cfg.Projects {
-		projectNames = append(projectNames, name)
-	}
-	sort.Strings(projectNames)
-
-	for _, projectName := range projectNames {
+	for _, projectName := range s.projectNames(cfg) {
 		project := cfg.Projects[projectName]
 
 		// Add project header
-		s.items = append(s.items, SidebarItem{
+		s.all = append(s.all, SidebarItem{
 			ID:        config.ServiceID{Project: projectName},
 			IsProject: true,
 			Name:      projectName,
 		})
 
-		// Sort service names
-		serviceNames := make([]string, 0, len(project.Services))
-		for name := range project.Services {
-			serviceNames = append(serviceNames, name)
-		}
-		sort.Strings(serviceNames)
-
-		// Add services
-		for _, serviceName := range serviceNames {
-			s.items = append(s.items, SidebarItem{
-				ID: config.ServiceID{
-					Project: projectName,
-					Service: serviceName,
-				},
-				IsProject: false,
-				Name:      serviceName,
+		// Add services; replicated services get a header with one item
+		// per replica below it
+		for _, serviceName := range s.serviceNames(projectName, project) {
+			id := config.ServiceID{
+				Project: projectName,
+				Service: serviceName,
+			}
+			service := project.Services[serviceName]
+			if !service.IsReplicated() {
+				s.all = append(s.all, SidebarItem{
+					ID:        id,
+					IsProject: false,
+					Name:      serviceName,
+				})
+				continue
+			}
+
+			s.all = append(s.all, SidebarItem{
+				ID:      id,
+				IsGroup: true,
+				Name:    serviceName,
 			})
+			for i := 1; i <= service.Replicas; i++ {
+				id.Instance = i
+				s.all = append(s.all, SidebarItem{
+					ID:   id,
+					Name: fmt.Sprintf("#%d", i),
+				})
+			}
+		}
+	}
+
+	if len(s.all) > 0 {
+		s.all = append([]SidebarItem{{IsAll: true, Name: "All services"}}, s.all...)
+	}
+	s.filterItems()
+}
+
+// projectNames returns the project names in the order of the sort mode
+func (s *Sidebar) projectNames(cfg *config.Config) []string {
+	if s.sortMode == SortConfig {
+		return cfg.OrderedProjectNames()
+	}
+	names := make([]string, 0, len(cfg.Projects))
+	for name := range cfg.Projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	s.sortByUse(names, func(name string) []string {
+		var ids []string
+		for service := range cfg.Projects[name].Services {
+			ids = append(ids, config.ServiceID{Project: name, Service: service}.String())
+		}
+		return ids
+	})
+	return names
+}
+
+// serviceNames returns the service names of a project in the order of the
+// sort mode
+func (s *Sidebar) serviceNames(projectName string, project config.Project) []string {
+	if s.sortMode == SortConfig {
+		return project.OrderedServiceNames()
+	}
+	names := make([]string, 0, len(project.Services))
+	for name := range project.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	s.sortByUse(names, func(name string) []string {
+		return []string{config.ServiceID{Project: projectName, Service: name}.String()}
+	})
+	return names
+}
+
+// sortByUse moves the names standing for running or recently used services
+// first in those sort modes, keeping the order otherwise. ids returns the
+// base IDs of the services a name stands for.
+func (s *Sidebar) sortByUse(names []string, ids func(string) []string) {
+	switch s.sortMode {
+	case SortRunning:
+		running := func(name string) bool {
+			return slices.ContainsFunc(ids(name), func(id string) bool { return s.running[id] })
+		}
+		sort.SliceStable(names, func(a, b int) bool {
+			return running(names[a]) && !running(names[b])
+		})
+	case SortRecent:
+		lastUsed := func(name string) time.Time {
+			var last time.Time
+			for _, id := range ids(name) {
+				if used := s.lastUsed[id]; used.After(last) {
+					last = used
+				}
+			}
+			return last
 		}
+		sort.SliceStable(names, func(a, b int) bool {
+			return lastUsed(names[a]).After(lastUsed(names[b]))
+		})
+	}
+}
+
+// SetSortMode orders the projects and services by a sort mode
+func (s *Sidebar) SetSortMode(mode SortMode, manager *process.Manager) {
+	s.sortMode = mode
+	s.running = runningServices(manager)
+	s.buildItems(s.cfg)
+}
+
+// SortMode returns the order of the projects and services
+func (s *Sidebar) SortMode() SortMode {
+	return s.sortMode
+}
+
+// Refresh rebuilds the items after services were added to the config,
+// keeping the selection
+func (s *Sidebar) Refresh() {
+	s.buildItems(s.cfg)
+}
+
+// Resort orders the items again when sorting running services first and
+// the services running changed
+func (s *Sidebar) Resort(manager *process.Manager) {
+	if s.sortMode != SortRunning {
+		return
+	}
+	running := runningServices(manager)
+	if maps.Equal(running, s.running) {
+		return
 	}
+	s.running = running
+	s.buildItems(s.cfg)
+}
+
+// runningServices returns the base IDs of the services with an instance
+// that is starting or running
+func runningServices(manager *process.Manager) map[string]bool {
+	running := make(map[string]bool)
+	for _, proc := range manager.All() {
+		if !proc.IsDone() {
+			running[proc.ID.Base().String()] = true
+		}
+	}
+	return running
+}
+
+// MarkUsed records that a service was used now, for sorting recently used
+// services first
+func (s *Sidebar) MarkUsed(id config.ServiceID) {
+	s.lastUsed[id.Base().String()] = time.Now()
+	if s.sortMode == SortRecent {
+		s.buildItems(s.cfg)
+	}
+}
+
+// SetLastUsed sets when services were last used, by base ID
+func (s *Sidebar) SetLastUsed(lastUsed map[string]time.Time) {
+	s.lastUsed = make(map[string]time.Time, len(lastUsed))
+	maps.Copy(s.lastUsed, lastUsed)
+	if s.sortMode == SortRecent {
+		s.buildItems(s.cfg)
+	}
+}
+
+// LastUsed returns when services were last used, by base ID
+func (s *Sidebar) LastUsed() map[string]time.Time {
+	return maps.Clone(s.lastUsed)
+}
+
+// filterItems shows all items but the services of collapsed projects,
+// keeping the selected item selected
+func (s *Sidebar) filterItems() {
+	var selected SidebarItem
+	if item := s.SelectedItem(); item != nil {
+		selected = *item
+	}
+
+	s.items = nil
+	for _, item := range s.all {
+		if !item.IsProject && !item.IsAll && s.collapsed[item.ID.Project] {
+			continue
+		}
+		s.items = append(s.items, item)
+	}
+	if !s.selectItem(selected) {
+		s.selected = 0
+	}
+}
+
+// selectItem selects an item if it is shown, returning false if it isn't
+func (s *Sidebar) selectItem(item SidebarItem) bool {
+	for i, it := range s.items {
+		if it == item {
+			s.selected = i
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleCollapsed collapses the selected project, or expands it if it is
+// collapsed, returning false if no project is selected
+func (s *Sidebar) ToggleCollapsed() bool {
+	item := s.SelectedItem()
+	if item == nil || !item.IsProject {
+		return false
+	}
+	if s.collapsed[item.Name] {
+		delete(s.collapsed, item.Name)
+	} else {
+		s.collapsed[item.Name] = true
+	}
+	s.filterItems()
+	return true
+}
+
+// SetCollapsed collapses the given projects and expands the others
+func (s *Sidebar) SetCollapsed(projects []string) {
+	s.collapsed = make(map[string]bool)
+	for _, project := range projects {
+		s.collapsed[project] = true
+	}
+	s.filterItems()
+}
+
+// Collapsed returns the names of the collapsed projects, sorted
+func (s *Sidebar) Collapsed() []string {
+	projects := make([]string, 0, len(s.collapsed))
+	for project := range s.collapsed {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+	return projects
 }
 
 // SetSize sets the sidebar dimensions
@@ -163,50 +482,67 @@ func (s *Sidebar) SetSize(width, height int) {
 	s.height = height
 }
 
+// SetSpinner sets the tick that spinners of starting and stopping services
+// are at
+func (s *Sidebar) SetSpinner(tick int) {
+	s.spinner = tick
+}
+
 // SetFocused sets the focus state
 func (s *Sidebar) SetFocused(focused bool) {
 	s.focused = focused
 }
 
-// MoveUp moves selection up
+// MoveUp moves selection up to the previous item
 func (s *Sidebar) MoveUp() {
-	if s.selected > 0 {
-		newSelected := s.selected - 1
-		// Skip project headers
-		if s.items[newSelected].IsProject {
-			if newSelected > 0 {
-				newSelected--
-			} else {
-				// Can't go up, stay at current position
-				return
-			}
+	// Skip replica group headers
+	for i := s.selected - 1; i >= 0; i-- {
+		if s.items[i].selectable() {
+			s.selected = i
+			return
 		}
-		s.selected = newSelected
 	}
 }
 
-// MoveDown moves selection down
+// MoveDown moves selection down to the next item
 func (s *Sidebar) MoveDown() {
-	if s.selected < len(s.items)-1 {
-		newSelected := s.selected + 1
-		// Skip project headers
-		if s.items[newSelected].IsProject {
-			if newSelected < len(s.items)-1 {
-				newSelected++
-			} else {
-				// Can't go down, stay at current position
-				return
-			}
+	// Skip replica group headers
+	for i := s.selected + 1; i < len(s.items); i++ {
+		if s.items[i].selectable() {
+			s.selected = i
+			return
 		}
-		s.selected = newSelected
 	}
 }
 
+// NextProject moves selection down to the next project header
+func (s *Sidebar) NextProject() bool {
+	for i := s.selected + 1; i < len(s.items); i++ {
+		if s.items[i].IsProject {
+			s.selected = i
+			return true
+		}
+	}
+	return false
+}
+
+// PrevProject moves selection up to the header of the selected service's
+// project, or of the previous project if a header is selected
+func (s *Sidebar) PrevProject() bool {
+	for i := s.selected - 1; i >= 0; i-- {
+		if s.items[i].IsProject {
+			s.selected = i
+			return true
+		}
+	}
+	return false
+}
+
 // Selected returns the currently selected service ID
 func (s *Sidebar) Selected() config.ServiceID {
 	if s.selected >= 0 && s.selected < len(s.items) {
 		item := s.items[s.selected]
-		if !item.IsProject {
+		if item.isService() {
 			return item.ID
 		}
 	}
@@ -227,6 +563,12 @@ func (s *Sidebar) IsProjectSelected() bool {
 	return item != nil && item.IsProject
 }
 
+// IsAllSelected returns true if the all services item is selected
+func (s *Sidebar) IsAllSelected() bool {
+	item := s.SelectedItem()
+	return item != nil && item.IsAll
+}
+
 // SelectedProjectName returns the project name of the selected item
 func (s *Sidebar) SelectedProjectName() string {
 	item := s.SelectedItem()
@@ -245,12 +587,23 @@ func (s *Sidebar) SelectedIndex() int {
 func (s *Sidebar) View(manager *process.Manager, logBuffer *log.Buffer) string {
 	var b strings.Builder
 
-	// Title
+	// Title with the sort mode, or the find prompt
 	title := "Services"
-	if s.focused {
-		b.WriteString(s.styles.TitleFocused.Render(title))
+	hint := ""
+	if s.sortMode != SortAlphabetical {
+		hint = s.styles.SortHint.Render("· " + s.sortMode.String())
+	}
+	if s.finding {
+		s.findInput.Width = max(s.width-14, 1)
+		prompt := " " + s.styles.FindPrompt.Render("Find:") + " " + s.findInput.View()
+		if s.findInput.Value() != "" {
+			prompt += " " + s.styles.FindCount.Render(fmt.Sprintf("%d/%d", min(s.findMatch+1, len(s.findMatches)), len(s.findMatches)))
+		}
+		b.WriteString(ansi.Truncate(prompt, max(s.width-2, 1), "…"))
+	} else if s.focused {
+		b.WriteString(ansi.Truncate(s.styles.TitleFocused.Render(title)+hint, max(s.width-2, 1), "…"))
 	} else {
-		b.WriteString(s.styles.Title.Render(title))
+		b.WriteString(ansi.Truncate(s.styles.Title.Render(title)+hint, max(s.width-2, 1), "…"))
 	}
 	b.WriteString("\n")
 
@@ -258,22 +611,64 @@ func (s *Sidebar) View(manager *process.Manager, logBuffer *log.Buffer) string {
 	availableHeight := s.height - 4 // Title + borders
 
 	// Render items
+	s.rowItems = s.rowItems[:0]
 	for i, item := range s.items {
-		if i >= availableHeight {
+		if len(s.rowItems) >= availableHeight {
 			break
 		}
 
-		if item.IsProject {
-			// Project header (not selectable)
+		start := b.Len()
+		if item.IsAll {
+			// All services: running count, and a dot if any is running
+			running, total := manager.RunningCount(), manager.TotalCount()
+			selMarker := "  "
+			if i == s.selected {
+				selMarker = s.styles.SelectionMarker.Render("› ")
+			}
+			indicator := s.styles.StatusStopped.Render("◆")
+			if running > 0 {
+				indicator = s.styles.StatusRunning.Render("◆")
+			}
+			text := fmt.Sprintf("%s %s %s %s", selMarker, indicator, item.Name,
+				s.styles.StatusStopped.Render(fmt.Sprintf("%d/%d", running, total)))
+			if i == s.selected {
+				b.WriteString(s.styles.ItemSelected.Render(text))
+			} else {
+				b.WriteString(s.styles.Item.Render(text))
+			}
+		} else if item.IsProject {
+			// Project header; collapsed ones summarize their services
 			projectName := item.Name
-			maxProjectLen := s.width - 6 // borders + "▸ " prefix + margin
+			arrow := "▾ "
+			summary, summaryLen := "", 0
+			if s.collapsed[item.Name] {
+				arrow = "▸ "
+				summary, summaryLen = s.projectSummary(manager, item.Name)
+			}
+			maxProjectLen := s.width - 6 - summaryLen // borders + arrow prefix + margin
 			if maxProjectLen < 3 {
 				maxProjectLen = 3
 			}
-			if len(projectName) > maxProjectLen {
-				projectName = projectName[:maxProjectLen-1] + "…"
+			projectName = ansi.Truncate(projectName, maxProjectLen, "…")
+			style := s.styles.ProjectHeader
+			if i == s.selected {
+				style = s.styles.ProjectSelected
+			}
+			b.WriteString(style.Render(arrow+projectName) + summary)
+		} else if item.IsGroup {
+			// Replica group header (not selectable): name and running count
+			running, total := 0, 0
+			for _, proc := range manager.Instances(item.ID) {
+				total++
+				if proc.IsRunning() {
+					running++
+				}
+			}
+			text := fmt.Sprintf("   ▾ %s %d/%d", item.Name, running, total)
+			if innerWidth := s.width - 2; innerWidth > 3 {
+				text = ansi.Truncate(text, innerWidth, "…")
 			}
-			b.WriteString(s.styles.ProjectHeader.Render("▸ " + projectName))
+			b.WriteString(s.styles.StatusStopped.Render(text))
 		} else {
 			// Service item
 			proc := manager.Get(item.ID)
@@ -286,6 +681,12 @@ func (s *Sidebar) View(manager *process.Manager, logBuffer *log.Buffer) string {
 
 			// Status indicator
 			indicator := s.getStatusIndicator(status)
+			if proc != nil && proc.IsCrashLooping() {
+				indicator = s.styles.StatusFailed.Render("↻")
+				if theme.Accessible {
+					indicator = s.styles.StatusFailed.Render("LOOP ")
+				}
+			}
 
 			// Health indicator (only show for running services)
 			healthIndicator := ""
@@ -293,6 +694,14 @@ func (s *Sidebar) View(manager *process.Manager, logBuffer *log.Buffer) string {
 				healthIndicator = " " + s.getHealthIndicator(health)
 			}
 
+			// Port badge when auto_port moved the service to another port
+			portBadge := ""
+			if proc != nil && status == process.StatusRunning && proc.Config.Port > 0 {
+				if port := proc.Resolved().Port; port != proc.Config.Port {
+					portBadge = fmt.Sprintf(" :%d", port)
+				}
+			}
+
 			// Multi-select marker
 			multiMarker := " "
 			if s.IsMultiSelected(i) {
@@ -301,12 +710,16 @@ func (s *Sidebar) View(manager *process.Manager, logBuffer *log.Buffer) string {
 
 			serviceName := item.Name
 
-			// Error badge (only show if errors exist)
+			// Error badge: the count of errors since the logs were last
+			// viewed, or a dim mark if all errors have been seen
 			errorBadge := ""
 			errorBadgeLen := 0
 			if logBuffer != nil {
-				errorCount := logBuffer.ErrorCount(item.ID)
-				if errorCount > 0 {
+				errorCount := logBuffer.NewErrorCount(item.ID)
+				if errorCount == 0 && logBuffer.ErrorCount(item.ID) > 0 {
+					errorBadge = s.styles.ErrorBadgeSeen.Render(" !")
+					errorBadgeLen = 2
+				} else if errorCount > 0 {
 					if errorCount > 99 {
 						errorBadge = s.styles.ErrorBadge.Render(" !")
 						errorBadgeLen = 2
@@ -323,24 +736,61 @@ func (s *Sidebar) View(manager *process.Manager, logBuffer *log.Buffer) string {
 				selMarker = s.styles.SelectionMarker.Render("› ")
 			}
 
+			// Replicas are indented below their group header
+			indent := ""
+			if item.ID.Instance > 0 {
+				indent = "  "
+			}
+
 			// Calculate available width for service name
-			// prefix: selMarker(2) + multiMarker(1) + indicator(1) + space(1) = 5
-			// suffix: healthIndicator(0-2) + errorBadge(0-4)
-			prefixLen := 5
-			suffixLen := len(healthIndicator) + errorBadgeLen
+			// prefix: selMarker(2) + multiMarker(1) + indent(0-2) + indicator(1) + space(1) = 5-7
+			// suffix: portBadge(0-6) + healthIndicator(0-2) + errorBadge(0-4)
+			prefixLen := 4 + len(indent) + ansi.StringWidth(indicator)
+			suffixLen := ansi.StringWidth(healthIndicator) + len(portBadge) + errorBadgeLen
 			innerWidth := s.width - 2 // borders
 			maxNameLen := innerWidth - prefixLen - suffixLen - 1
 			if maxNameLen < 3 {
 				maxNameLen = 3
 			}
 
-			// Truncate service name if needed
-			if len(serviceName) > maxNameLen {
-				serviceName = serviceName[:maxNameLen-1] + "…"
+			// Memory, uptime and restart badge of live services,
+			// right-aligned; the memory goes first, then the uptime, then the
+			// badge, when the name needs the room
+			memory, uptime, restarts := "", "", ""
+			if proc != nil && (status == process.StatusRunning || status == process.StatusPaused) {
+				if rss := proc.Stats().RSS; rss > 0 {
+					memory = " " + formatMemory(rss)
+				}
+				uptime = " " + formatUptime(proc.Uptime())
+				if n := proc.RestartCount(); n > 0 {
+					restarts = fmt.Sprintf(" ↻%d", n)
+				}
+			}
+			minNameLen := min(ansi.StringWidth(serviceName), 8)
+			if maxNameLen-ansi.StringWidth(memory+uptime+restarts) < minNameLen {
+				memory = ""
 			}
+			if maxNameLen-ansi.StringWidth(uptime+restarts) < minNameLen {
+				uptime = ""
+			}
+			if maxNameLen-ansi.StringWidth(restarts) < minNameLen {
+				restarts = ""
+			}
+			metaLen := ansi.StringWidth(memory + uptime + restarts)
+			maxNameLen -= metaLen
+
+			// Truncate service name if needed
+			serviceName = ansi.Truncate(serviceName, maxNameLen, "…")
 
 			// Item text
-			text := fmt.Sprintf("%s%s%s %s%s%s", selMarker, multiMarker, indicator, serviceName, healthIndicator, errorBadge)
+			if portBadge != "" {
+				portBadge = s.styles.StatusStarting.Render(portBadge)
+			}
+			text := fmt.Sprintf("%s%s%s%s %s%s%s%s", selMarker, multiMarker, indent, indicator, serviceName, portBadge, healthIndicator, errorBadge)
+			if metaLen > 0 {
+				pad := max(innerWidth-1-ansi.StringWidth(text)-metaLen, 0)
+				text += strings.Repeat(" ", pad) + s.styles.Uptime.Render(memory+uptime) + s.styles.RestartBadge.Render(restarts)
+			}
 
 			// Apply style
 			if i == s.selected || s.IsMultiSelected(i) {
@@ -349,6 +799,9 @@ func (s *Sidebar) View(manager *process.Manager, logBuffer *log.Buffer) string {
 				b.WriteString(s.styles.Item.Render(text))
 			}
 		}
+		for range strings.Count(b.String()[start:], "\n") + 1 {
+			s.rowItems = append(s.rowItems, i)
+		}
 		b.WriteString("\n")
 	}
 
@@ -357,6 +810,61 @@ func (s *Sidebar) View(manager *process.Manager, logBuffer *log.Buffer) string {
 	return s.renderWithBorder(content)
 }
 
+// formatUptime formats how long a service has run in at most two units,
+// such as 45s, 12m or 2h13m
+func formatUptime(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// formatMemory formats resident memory in at most four characters, such
+// as 512K, 180M or 1.2G
+func formatMemory(b uint64) string {
+	switch {
+	case b < 1<<20:
+		return fmt.Sprintf("%dK", b>>10)
+	case b < 1<<30:
+		return fmt.Sprintf("%dM", b>>20)
+	default:
+		return fmt.Sprintf("%.1fG", float64(b)/(1<<30))
+	}
+}
+
+// projectSummary renders the status dot and running count shown on a
+// collapsed project's header, returning it with its width. The dot is
+// failed if any service failed, else running if any runs.
+func (s *Sidebar) projectSummary(manager *process.Manager, project string) (string, int) {
+	running, total := 0, 0
+	failed := false
+	for _, proc := range manager.GetByProject(project) {
+		total++
+		if proc.IsRunning() {
+			running++
+		}
+		if proc.Status() == process.StatusFailed {
+			failed = true
+		}
+	}
+	status := process.StatusStopped
+	switch {
+	case failed:
+		status = process.StatusFailed
+	case running > 0:
+		status = process.StatusRunning
+	}
+	count := fmt.Sprintf("%d/%d", running, total)
+	indicator := s.getStatusIndicator(status)
+	return " " + indicator + " " + s.styles.StatusStopped.Render(count), 2 + ansi.StringWidth(indicator) + len(count)
+}
+
 // renderWithBorder renders content with manual box-drawing borders
 func (s *Sidebar) renderWithBorder(content string) string {
 	lines := strings.Split(content, "\n")
@@ -379,11 +887,11 @@ func (s *Sidebar) renderWithBorder(content string) string {
 	}
 
 	// Border color
-	borderColor := "#374151"
+	borderColor := theme.Border
 	if s.focused {
-		borderColor = "#8B5CF6"
+		borderColor = theme.Primary
 	}
-	borderStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(borderColor))
+	borderStyle := lipgloss.NewStyle().Foreground(borderColor)
 
 	var result strings.Builder
 
@@ -412,23 +920,58 @@ func (s *Sidebar) renderWithBorder(content string) string {
 
 // getStatusIndicator returns the status indicator character
 func (s *Sidebar) getStatusIndicator(status process.Status) string {
+	if theme.Accessible {
+		return s.getStatusLabel(status)
+	}
 	switch status {
 	case process.StatusRunning:
 		return s.styles.StatusRunning.Render("●")
-	case process.StatusStarting:
-		return s.styles.StatusStarting.Render("○")
-	case process.StatusStopping:
-		return s.styles.StatusStarting.Render("◐")
+	case process.StatusStarting, process.StatusStopping:
+		return s.styles.StatusStarting.Render(spinnerFrame(status, s.spinner))
 	case process.StatusFailed:
 		return s.styles.StatusFailed.Render("●")
+	case process.StatusSucceeded:
+		return s.styles.StatusRunning.Render("✔")
+	case process.StatusPaused:
+		return s.styles.StatusPaused.Render("‖")
 	default:
 		return s.styles.StatusStopped.Render("○")
 	}
 }
 
+// getStatusLabel returns the status as a word, for accessible mode
+func (s *Sidebar) getStatusLabel(status process.Status) string {
+	switch status {
+	case process.StatusRunning:
+		return s.styles.StatusRunning.Render("RUN  ")
+	case process.StatusStarting:
+		return s.styles.StatusStarting.Render("START")
+	case process.StatusStopping:
+		return s.styles.StatusStarting.Render("STOP ")
+	case process.StatusFailed:
+		return s.styles.StatusFailed.Render("FAIL ")
+	case process.StatusSucceeded:
+		return s.styles.StatusRunning.Render("DONE ")
+	case process.StatusPaused:
+		return s.styles.StatusPaused.Render("PAUSE")
+	default:
+		return s.styles.StatusStopped.Render("OFF  ")
+	}
+}
+
 // getHealthIndicator returns the health indicator character
 // Returns empty string for unknown status (no health check configured)
 func (s *Sidebar) getHealthIndicator(health process.HealthStatus) string {
+	if theme.Accessible {
+		switch health {
+		case process.HealthHealthy:
+			return s.styles.HealthHealthy.Render("OK")
+		case process.HealthUnhealthy:
+			return s.styles.HealthUnhealthy.Render("UNHEALTHY")
+		default:
+			return ""
+		}
+	}
 	switch health {
 	case process.HealthHealthy:
 		return s.styles.HealthHealthy.Render("✓")
@@ -454,43 +997,223 @@ func (s *Sidebar) ItemCount() int {
 	return len(s.items)
 }
 
-// ServiceCount returns the number of services (excluding project headers)
+// ServiceCount returns the number of services and replicas (excluding
+// headers), including those of collapsed projects
 func (s *Sidebar) ServiceCount() int {
 	count := 0
-	for _, item := range s.items {
-		if !item.IsProject {
+	for _, item := range s.all {
+		if item.isService() {
 			count++
 		}
 	}
 	return count
 }
 
+// Select selects a service, expanding its project if it is collapsed, and
+// returns false if it isn't listed
+func (s *Sidebar) Select(id config.ServiceID) bool {
+	for _, item := range s.all {
+		if item.isService() && item.ID == id {
+			if s.collapsed[id.Project] {
+				delete(s.collapsed, id.Project)
+				s.filterItems()
+			}
+			return s.selectItem(item)
+		}
+	}
+	return false
+}
+
+// SelectAll selects the all services item
+func (s *Sidebar) SelectAll() {
+	for i, item := range s.items {
+		if item.IsAll {
+			s.selected = i
+			return
+		}
+	}
+}
+
+// SelectAt selects the service, project header or all services item shown
+// on a row of the sidebar, counted from its top border, returning false for
+// other rows
+func (s *Sidebar) SelectAt(row int) bool {
+	// Items start below the top border and the title
+	i := row - 2
+	if i < 0 || i >= len(s.rowItems) || !s.items[s.rowItems[i]].selectable() {
+		return false
+	}
+	s.selected = s.rowItems[i]
+	return true
+}
+
+// ServiceIDs returns the listed services and replicas in display order,
+// including those of collapsed projects
+func (s *Sidebar) ServiceIDs() []config.ServiceID {
+	var ids []config.ServiceID
+	for _, item := range s.all {
+		if item.isService() {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
+
 // SelectFirst selects the first service
 func (s *Sidebar) SelectFirst() {
 	for i, item := range s.items {
-		if !item.IsProject {
+		if item.isService() {
 			s.selected = i
 			return
 		}
 	}
 }
 
-// ToggleMultiSelect toggles multi-select for the current item
+// StartFind opens the find prompt
+func (s *Sidebar) StartFind() {
+	s.finding = true
+	s.findFrom = SidebarItem{}
+	if item := s.SelectedItem(); item != nil {
+		s.findFrom = *item
+	}
+	s.findMatches = nil
+	s.findMatch = 0
+	s.findInput.SetValue("")
+	s.findInput.Focus()
+}
+
+// EndFind closes the find prompt, keeping the service found or going back
+// to the one selected before
+func (s *Sidebar) EndFind(keep bool) {
+	s.finding = false
+	s.findInput.Blur()
+	if !keep {
+		s.restoreFindFrom()
+	}
+}
+
+// restoreFindFrom selects the item selected before finding
+func (s *Sidebar) restoreFindFrom() {
+	if s.findFrom.isService() {
+		s.Select(s.findFrom.ID)
+	} else {
+		s.selectItem(s.findFrom)
+	}
+}
+
+// IsFinding returns true if the find prompt is open
+func (s *Sidebar) IsFinding() bool {
+	return s.finding
+}
+
+// FindInput returns the text input of the find prompt
+func (s *Sidebar) FindInput() *textinput.Model {
+	return &s.findInput
+}
+
+// UpdateFind matches the services against the find query and selects the
+// best match, expanding its project if it is collapsed
+func (s *Sidebar) UpdateFind() {
+	type match struct {
+		id    config.ServiceID
+		score int
+	}
+	query := s.findInput.Value()
+	var matches []match
+	for _, item := range s.all {
+		if !item.isService() {
+			continue
+		}
+		name := item.ID.Project + "/" + item.ID.Service
+		if item.ID.Instance > 0 {
+			name += item.Name
+		}
+		if score, ok := fuzzyScore(query, name); ok {
+			matches = append(matches, match{item.ID, score})
+		}
+	}
+	// Stable, so equal matches keep the sidebar order
+	sort.SliceStable(matches, func(a, b int) bool {
+		return matches[a].score > matches[b].score
+	})
+
+	s.findMatches = s.findMatches[:0]
+	for _, m := range matches {
+		s.findMatches = append(s.findMatches, m.id)
+	}
+	s.findMatch = 0
+	if len(s.findMatches) > 0 && query != "" {
+		s.Select(s.findMatches[0])
+	} else {
+		s.restoreFindFrom()
+	}
+}
+
+// NextMatch selects the next best match of the find query
+func (s *Sidebar) NextMatch() {
+	if len(s.findMatches) == 0 {
+		return
+	}
+	s.findMatch = (s.findMatch + 1) % len(s.findMatches)
+	s.Select(s.findMatches[s.findMatch])
+}
+
+// PrevMatch selects the previous match of the find query
+func (s *Sidebar) PrevMatch() {
+	if len(s.findMatches) == 0 {
+		return
+	}
+	s.findMatch = (s.findMatch + len(s.findMatches) - 1) % len(s.findMatches)
+	s.Select(s.findMatches[s.findMatch])
+}
+
+// ToggleMultiSelect toggles multi-select for the current item. On a project
+// header it selects all of the project's services, or deselects them if
+// they're all selected already.
 func (s *Sidebar) ToggleMultiSelect() {
 	if s.selected >= 0 && s.selected < len(s.items) {
 		item := s.items[s.selected]
-		if !item.IsProject {
-			s.multiSelect[s.selected] = !s.multiSelect[s.selected]
-			if !s.multiSelect[s.selected] {
-				delete(s.multiSelect, s.selected)
+		if item.IsProject {
+			s.toggleProjectMultiSelect(item.ID.Project)
+			return
+		}
+		if item.isService() {
+			s.multiSelect[item.ID] = !s.multiSelect[item.ID]
+			if !s.multiSelect[item.ID] {
+				delete(s.multiSelect, item.ID)
 			}
 		}
 	}
 }
 
+// toggleProjectMultiSelect selects all services of a project, collapsed or
+// not, or deselects them if they're all selected
+func (s *Sidebar) toggleProjectMultiSelect(project string) {
+	var ids []config.ServiceID
+	all := true
+	for _, item := range s.all {
+		if item.isService() && item.ID.Project == project {
+			ids = append(ids, item.ID)
+			all = all && s.multiSelect[item.ID]
+		}
+	}
+	for _, id := range ids {
+		if all {
+			delete(s.multiSelect, id)
+		} else {
+			s.multiSelect[id] = true
+		}
+	}
+}
+
+// MultiSelectCount returns the number of multi-selected services
+func (s *Sidebar) MultiSelectCount() int {
+	return len(s.multiSelect)
+}
+
 // ClearMultiSelect clears all multi-selections
 func (s *Sidebar) ClearMultiSelect() {
-	s.multiSelect = make(map[int]bool)
+	s.multiSelect = make(map[config.ServiceID]bool)
 }
 
 // HasMultiSelect returns true if there are multi-selected items
@@ -498,21 +1221,21 @@ func (s *Sidebar) HasMultiSelect() bool {
 	return len(s.multiSelect) > 0
 }
 
-// GetMultiSelected returns all multi-selected service IDs
+// GetMultiSelected returns all multi-selected service IDs in display order
 func (s *Sidebar) GetMultiSelected() []config.ServiceID {
 	var ids []config.ServiceID
-	for i := range s.multiSelect {
-		if i >= 0 && i < len(s.items) {
-			item := s.items[i]
-			if !item.IsProject {
-				ids = append(ids, item.ID)
-			}
+	for _, item := range s.all {
+		if item.isService() && s.multiSelect[item.ID] {
+			ids = append(ids, item.ID)
 		}
 	}
 	return ids
 }
 
-// IsMultiSelected returns true if the index is multi-selected
+// IsMultiSelected returns true if the item at index is multi-selected
 func (s *Sidebar) IsMultiSelected(index int) bool {
-	return s.multiSelect[index]
+	if index < 0 || index >= len(s.items) || !s.items[index].isService() {
+		return false
+	}
+	return s.multiSelect[s.items[index].ID]
 }
@@ -0,0 +1,149 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/paralerdev/paraler/internal/config"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// signalDescriptions explains what servers commonly do on each signal
+var signalDescriptions = map[string]string{
+	"SIGHUP":  "reload config",
+	"SIGUSR1": "user-defined (e.g. toggle debug)",
+	"SIGUSR2": "user-defined (e.g. reopen logs)",
+	"SIGINT":  "interrupt",
+	"SIGQUIT": "quit (and dump goroutines in Go)",
+}
+
+// SignalModal lets the user pick a signal to send to a service
+type SignalModal struct {
+	visible   bool
+	serviceID config.ServiceID
+	signals   []string
+	selected  int
+	width     int
+	styles    SignalStyles
+}
+
+// SignalStyles contains styles for the modal
+type SignalStyles struct {
+	Container    lipgloss.Style
+	Title        lipgloss.Style
+	Item         lipgloss.Style
+	SelectedItem lipgloss.Style
+	Description  lipgloss.Style
+	Help         lipgloss.Style
+}
+
+// DefaultSignalStyles returns default styles
+func DefaultSignalStyles() SignalStyles {
+	return SignalStyles{
+		Container: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(theme.Accent).
+			Padding(1, 2),
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Accent),
+		Item: lipgloss.NewStyle().
+			Foreground(theme.Subtle).
+			PaddingLeft(2),
+		SelectedItem: lipgloss.NewStyle().
+			Foreground(theme.Text).
+			Bold(true).
+			PaddingLeft(2),
+		Description: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+		Help: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			MarginTop(1),
+	}
+}
+
+// NewSignalModal creates a new signal modal
+func NewSignalModal() *SignalModal {
+	return &SignalModal{
+		styles: DefaultSignalStyles(),
+	}
+}
+
+// SetSize sets the modal width
+func (m *SignalModal) SetSize(width int) {
+	m.width = width
+}
+
+// Show shows the modal for a service with the signals that can be sent
+func (m *SignalModal) Show(serviceID config.ServiceID, signals []string) {
+	m.serviceID = serviceID
+	m.signals = signals
+	m.selected = 0
+	m.visible = true
+}
+
+// Hide hides the modal
+func (m *SignalModal) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns true if modal is visible
+func (m *SignalModal) IsVisible() bool {
+	return m.visible
+}
+
+// ServiceID returns the service the signal is sent to
+func (m *SignalModal) ServiceID() config.ServiceID {
+	return m.serviceID
+}
+
+// MoveUp moves selection up
+func (m *SignalModal) MoveUp() {
+	if m.selected > 0 {
+		m.selected--
+	}
+}
+
+// MoveDown moves selection down
+func (m *SignalModal) MoveDown() {
+	if m.selected < len(m.signals)-1 {
+		m.selected++
+	}
+}
+
+// Selected returns the currently selected signal
+func (m *SignalModal) Selected() string {
+	if m.selected < len(m.signals) {
+		return m.signals[m.selected]
+	}
+	return ""
+}
+
+// View renders the modal
+func (m *SignalModal) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	var b strings.Builder
+
+	b.WriteString(m.styles.Title.Render(fmt.Sprintf("Send signal to %s", m.serviceID.Service)))
+	b.WriteString("\n\n")
+
+	for i, sig := range m.signals {
+		desc := m.styles.Description.Render(signalDescriptions[sig])
+		if i == m.selected {
+			b.WriteString(m.styles.SelectedItem.Render("→ "+sig) + "  " + desc)
+		} else {
+			b.WriteString(m.styles.Item.Render("  "+sig) + "  " + desc)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.styles.Help.Render("↑/↓ select • Enter send • Esc cancel"))
+
+	return m.styles.Container.
+		Width(m.width).
+		Render(b.String())
+}
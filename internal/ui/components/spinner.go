@@ -0,0 +1,41 @@
+package components
+
+import (
+	"time"
+
+	"github.com/paralerdev/paraler/internal/process"
+)
+
+// SpinnerInterval is how often spinners move to their next frame
+const SpinnerInterval = 100 * time.Millisecond
+
+var (
+	// startingFrames spin while a service starts
+	startingFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	// stoppingFrames spin while a service stops, slower than starting ones
+	stoppingFrames = []string{"◐", "◐", "◓", "◓", "◑", "◑", "◒", "◒"}
+)
+
+// spinnerFrame returns the frame of the spinner of a status at a tick, or
+// an empty string if the status doesn't spin
+func spinnerFrame(status process.Status, tick int) string {
+	var frames []string
+	switch status {
+	case process.StatusStarting:
+		frames = startingFrames
+	case process.StatusStopping:
+		frames = stoppingFrames
+	default:
+		return ""
+	}
+	if theme.Accessible {
+		return frames[0]
+	}
+	return frames[tick%len(frames)]
+}
+
+// Spins returns true if a service with this status shows a spinner that
+// moves
+func Spins(status process.Status) bool {
+	return !theme.Accessible && spinnerFrame(status, 0) != ""
+}
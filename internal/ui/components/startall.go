@@ -0,0 +1,346 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+	"github.com/paralerdev/paraler/internal/process"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// StartState is how far a service got in a start-all run
+type StartState int
+
+const (
+	StartWaiting  StartState = iota // Waiting for its dependencies
+	StartStarting                   // Started, not ready yet
+	StartReady                      // Ready, or a task that succeeded
+	StartFailed                     // Failed to start or exited
+	StartSkipped                    // Not started, or stopped before it was ready
+)
+
+// String returns the state as shown in the modal
+func (s StartState) String() string {
+	switch s {
+	case StartStarting:
+		return "starting"
+	case StartReady:
+		return "ready"
+	case StartFailed:
+		return "failed"
+	case StartSkipped:
+		return "not started"
+	default:
+		return "waiting"
+	}
+}
+
+// startRow is a service in a start-all run
+type startRow struct {
+	id    config.ServiceID
+	state StartState
+	began time.Time // When it began starting
+	ended time.Time // When it became ready or failed
+}
+
+// StartAllModal shows the progress of starting all services, in
+// dependency order
+type StartAllModal struct {
+	visible  bool
+	rows     []startRow
+	began    time.Time // When the run began
+	ended    time.Time // When the run ended, zero while it runs
+	offset   int       // First row shown
+	spinner  int
+	width    int
+	height   int
+	styles   StartAllStyles
+}
+
+// StartAllStyles contains styles for the modal
+type StartAllStyles struct {
+	Container lipgloss.Style
+	Title     lipgloss.Style
+	Summary   lipgloss.Style
+	Service   lipgloss.Style
+	Waiting   lipgloss.Style
+	Starting  lipgloss.Style
+	Ready     lipgloss.Style
+	Failed    lipgloss.Style
+	Elapsed   lipgloss.Style
+	Help      lipgloss.Style
+}
+
+// DefaultStartAllStyles returns default styles
+func DefaultStartAllStyles() StartAllStyles {
+	return StartAllStyles{
+		Container: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(theme.Primary).
+			Padding(1, 2),
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(theme.Primary),
+		Summary: lipgloss.NewStyle().
+			Foreground(theme.Subtle),
+		Service: lipgloss.NewStyle().
+			Foreground(theme.Text),
+		Waiting: lipgloss.NewStyle().
+			Foreground(theme.Muted),
+		Starting: lipgloss.NewStyle().
+			Foreground(theme.Warning),
+		Ready: lipgloss.NewStyle().
+			Foreground(theme.Success),
+		Failed: lipgloss.NewStyle().
+			Foreground(theme.Error),
+		Elapsed: lipgloss.NewStyle().
+			Foreground(theme.Subtle),
+		Help: lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			MarginTop(1),
+	}
+}
+
+// NewStartAllModal creates a new start-all modal
+func NewStartAllModal() *StartAllModal {
+	return &StartAllModal{
+		styles: DefaultStartAllStyles(),
+	}
+}
+
+// SetSize sets the modal dimensions
+func (m *StartAllModal) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// SetSpinner sets the tick that spinners of starting services are at
+func (m *StartAllModal) SetSpinner(tick int) {
+	m.spinner = tick
+}
+
+// Show shows the progress of starting services, in the order given
+func (m *StartAllModal) Show(ids []config.ServiceID) {
+	m.visible = true
+	m.began = time.Now()
+	m.ended = time.Time{}
+	m.offset = 0
+	m.rows = make([]startRow, len(ids))
+	for i, id := range ids {
+		m.rows[i] = startRow{id: id}
+	}
+}
+
+// Hide hides the modal; the services keep starting
+func (m *StartAllModal) Hide() {
+	m.visible = false
+}
+
+// IsVisible returns true if modal is visible
+func (m *StartAllModal) IsVisible() bool {
+	return m.visible
+}
+
+// Finish marks the run as over: services still waiting weren't started
+func (m *StartAllModal) Finish(manager *process.Manager) {
+	m.Refresh(manager)
+	m.ended = time.Now()
+	for i := range m.rows {
+		if m.rows[i].state == StartWaiting {
+			m.rows[i].state = StartSkipped
+		}
+	}
+}
+
+// IsRunning returns true until the run is over
+func (m *StartAllModal) IsRunning() bool {
+	return m.visible && m.ended.IsZero()
+}
+
+// Refresh updates the state of each service
+func (m *StartAllModal) Refresh(manager *process.Manager) {
+	if !m.ended.IsZero() {
+		return
+	}
+	now := time.Now()
+	for i := range m.rows {
+		row := &m.rows[i]
+		if row.state == StartReady || row.state == StartFailed || row.state == StartSkipped {
+			continue
+		}
+		proc := manager.Get(row.id)
+		if proc == nil {
+			row.state = StartSkipped
+			row.ended = now
+			continue
+		}
+
+		state := m.stateOf(proc)
+		if state != StartWaiting && row.began.IsZero() {
+			row.began = now
+			if startedAt := proc.StartedAt(); startedAt.After(m.began) && startedAt.Before(now) {
+				row.began = startedAt
+			}
+		}
+		if state != StartWaiting && state != StartStarting {
+			row.ended = now
+		}
+		row.state = state
+	}
+}
+
+// stateOf returns how far a service got since the run began
+func (m *StartAllModal) stateOf(proc *process.Process) StartState {
+	status := proc.Status()
+	if status == process.StatusStarting {
+		return StartStarting
+	}
+	if !proc.StartedAt().After(m.began) {
+		return StartWaiting
+	}
+
+	switch status {
+	case process.StatusRunning, process.StatusPaused:
+		// Ready the way dependents wait for it: its ready line, or else a
+		// health check that doesn't fail
+		if proc.HasReadyPattern() {
+			if proc.IsReady() {
+				return StartReady
+			}
+			return StartStarting
+		}
+		if proc.Health() == process.HealthUnhealthy {
+			return StartStarting
+		}
+		return StartReady
+	case process.StatusSucceeded:
+		return StartReady
+	case process.StatusFailed:
+		return StartFailed
+	default:
+		return StartSkipped
+	}
+}
+
+// rowsShown returns the number of services shown at once
+func (m *StartAllModal) rowsShown() int {
+	// Borders, padding, title and help
+	return max(m.height-10, 3)
+}
+
+// View renders the modal
+func (m *StartAllModal) View() string {
+	if !m.visible {
+		return ""
+	}
+
+	innerWidth := max(m.width-4, 1) // padding
+	var b strings.Builder
+
+	counts := make(map[StartState]int)
+	for _, row := range m.rows {
+		counts[row.state]++
+	}
+	end := m.ended
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	title := "Starting all services"
+	if !m.ended.IsZero() {
+		title = "Started all services"
+	}
+	summary := fmt.Sprintf("%d/%d ready", counts[StartReady], len(m.rows))
+	if counts[StartFailed] > 0 {
+		summary += fmt.Sprintf(" · %d failed", counts[StartFailed])
+	}
+	if counts[StartSkipped] > 0 {
+		summary += fmt.Sprintf(" · %d not started", counts[StartSkipped])
+	}
+	summary += " · " + formatElapsed(end.Sub(m.began))
+	b.WriteString(ansi.Truncate(m.styles.Title.Render(title)+"  "+m.styles.Summary.Render(summary), innerWidth, "…"))
+	b.WriteString("\n\n")
+
+	// Keep the first service still in progress in view
+	rows := m.rowsShown()
+	first := 0
+	for i, row := range m.rows {
+		if row.state == StartWaiting || row.state == StartStarting {
+			first = i
+			break
+		}
+	}
+	if first < m.offset || first >= m.offset+rows {
+		m.offset = first
+	}
+	m.offset = max(min(m.offset, len(m.rows)-rows), 0)
+
+	nameWidth := 0
+	for _, row := range m.rows {
+		nameWidth = max(nameWidth, ansi.StringWidth(row.id.String()))
+	}
+	nameWidth = min(nameWidth, max(innerWidth-24, 8))
+
+	for i := m.offset; i < len(m.rows) && i < m.offset+rows; i++ {
+		b.WriteString(m.renderRow(m.rows[i], nameWidth, innerWidth))
+		b.WriteString("\n")
+	}
+
+	help := "Esc close, services keep starting"
+	if !m.ended.IsZero() {
+		help = "Esc/Enter close"
+	}
+	if len(m.rows) > rows {
+		help += " • " + positionText(m.offset, min(m.offset+rows, len(m.rows)), len(m.rows))
+	}
+	b.WriteString(m.styles.Help.Render(ansi.Truncate(help, innerWidth, "…")))
+
+	return m.styles.Container.
+		Width(m.width).
+		Render(b.String())
+}
+
+// renderRow renders a service with its state and how long it took
+func (m *StartAllModal) renderRow(row startRow, nameWidth, innerWidth int) string {
+	var icon string
+	var style lipgloss.Style
+	switch row.state {
+	case StartStarting:
+		icon, style = spinnerFrame(process.StatusStarting, m.spinner), m.styles.Starting
+	case StartReady:
+		icon, style = "✔", m.styles.Ready
+	case StartFailed:
+		icon, style = "✖", m.styles.Failed
+	case StartSkipped:
+		icon, style = "–", m.styles.Waiting
+	default:
+		icon, style = "○", m.styles.Waiting
+	}
+
+	var elapsed string
+	switch {
+	case !row.ended.IsZero() && !row.began.IsZero():
+		elapsed = formatElapsed(row.ended.Sub(row.began))
+	case !row.began.IsZero():
+		elapsed = formatElapsed(time.Since(row.began))
+	case row.state == StartWaiting:
+		elapsed = formatElapsed(time.Since(m.began))
+	}
+
+	name := ansi.Truncate(row.id.String(), nameWidth, "…")
+	name += strings.Repeat(" ", max(nameWidth-ansi.StringWidth(name), 0))
+	line := style.Render(icon) + " " + m.styles.Service.Render(name) + "  " +
+		style.Render(fmt.Sprintf("%-12s", row.state.String())) + m.styles.Elapsed.Render(elapsed)
+	return ansi.Truncate(line, innerWidth, "")
+}
+
+// formatElapsed formats a duration to a tenth of a second below a minute
+func formatElapsed(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return formatDuration(d)
+}
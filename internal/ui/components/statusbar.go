@@ -3,15 +3,35 @@ package components
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/paralerdev/paraler/internal/config"
 	"github.com/paralerdev/paraler/internal/process"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // StatusBar shows status and keybindings
 type StatusBar struct {
-	width  int
-	styles StatusBarStyles
+	width      int
+	styles     StatusBarStyles
+	alert      string
+	alertUntil time.Time
+	activity   string           // Operation in progress, such as reloading config
+	selected   config.ServiceID // Service shown next to the running count
+	filter     string           // Log filter applied
+	multi      int              // Number of multi-selected services
+	hintsStart int              // column of the first key hint rendered, or -1
+}
+
+// statusHints are the keys and descriptions of the key hints
+var statusHints = [][2]string{
+	{"s", "start"},
+	{"x", "stop"},
+	{"r", "restart"},
+	{"f", "fullscreen"},
+	{"?", "help"},
+	{"q", "quit"},
 }
 
 // StatusBarStyles contains status bar styles
@@ -23,35 +43,49 @@ type StatusBarStyles struct {
 	RunningCount lipgloss.Style
 	StoppedCount lipgloss.Style
 	Info         lipgloss.Style
+	Alert        lipgloss.Style
+	Healthy      lipgloss.Style
+	Unhealthy    lipgloss.Style
+	Activity     lipgloss.Style
 }
 
 // DefaultStatusBarStyles returns default styles
 func DefaultStatusBarStyles() StatusBarStyles {
 	return StatusBarStyles{
 		Container: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#9CA3AF")).
+			Foreground(theme.Subtle).
 			Padding(0, 1),
 		Key: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#8B5CF6")).
+			Foreground(theme.Primary).
 			Bold(true),
 		Desc: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")),
+			Foreground(theme.Muted),
 		Sep: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#374151")),
+			Foreground(theme.Border),
 		RunningCount: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#10B981")).
+			Foreground(theme.Success).
 			Bold(true),
 		StoppedCount: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6B7280")),
+			Foreground(theme.Muted),
 		Info: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#9CA3AF")),
+			Foreground(theme.Subtle),
+		Alert: lipgloss.NewStyle().
+			Foreground(theme.Error).
+			Bold(true),
+		Healthy: lipgloss.NewStyle().
+			Foreground(theme.Success),
+		Unhealthy: lipgloss.NewStyle().
+			Foreground(theme.Error),
+		Activity: lipgloss.NewStyle().
+			Foreground(theme.Warning),
 	}
 }
 
 // NewStatusBar creates a new status bar
 func NewStatusBar() *StatusBar {
 	return &StatusBar{
-		styles: DefaultStatusBarStyles(),
+		styles:     DefaultStatusBarStyles(),
+		hintsStart: -1,
 	}
 }
 
@@ -60,12 +94,29 @@ func (s *StatusBar) SetWidth(width int) {
 	s.width = width
 }
 
-// View renders the status bar
-func (s *StatusBar) View(manager *process.Manager, showHelp bool) string {
-	if showHelp {
-		return s.renderHelp()
-	}
+// ShowAlert replaces the key hints with an alert for the given duration
+func (s *StatusBar) ShowAlert(text string, d time.Duration) {
+	s.alert = text
+	s.alertUntil = time.Now().Add(d)
+}
+
+// SetContext sets the selected service, the log filter and the number of
+// multi-selected services, shown after the running count
+func (s *StatusBar) SetContext(selected config.ServiceID, filter string, multi int) {
+	s.selected = selected
+	s.filter = filter
+	s.multi = multi
+}
 
+// SetActivity replaces the key hints with an operation in progress, such as
+// "reloading config…", until it's set to ""
+func (s *StatusBar) SetActivity(text string) {
+	s.activity = text
+}
+
+// View renders the status bar
+func (s *StatusBar) View(manager *process.Manager) string {
+	s.hintsStart = -1
 	return s.renderStatus(manager)
 }
 
@@ -82,17 +133,37 @@ func (s *StatusBar) renderStatus(manager *process.Manager) string {
 		statusStyle = s.styles.StoppedCount
 	}
 	status := statusStyle.Render(fmt.Sprintf("Running: %d/%d", running, total))
+	sep := s.styles.Sep.Render(" │ ")
 
 	// Right side: key hints
-	hints := []string{
-		s.keyHint("s", "start"),
-		s.keyHint("x", "stop"),
-		s.keyHint("r", "restart"),
-		s.keyHint("f", "fullscreen"),
-		s.keyHint("?", "help"),
-		s.keyHint("q", "quit"),
+	hints := make([]string, len(statusHints))
+	for i, hint := range statusHints {
+		hints[i] = s.keyHint(hint[0], hint[1])
+	}
+	keysHelp := strings.Join(hints, sep)
+
+	alerting := s.alert != "" && time.Now().Before(s.alertUntil)
+	if alerting {
+		alert := s.alert
+		maxLen := s.width - lipgloss.Width(status) - 6
+		if maxLen > 3 {
+			alert = ansi.Truncate(alert, maxLen, "…")
+		}
+		keysHelp = s.styles.Alert.Render(alert)
+	} else if s.activity != "" {
+		keysHelp = s.styles.Activity.Render(s.activity)
+	}
+
+	// The selected service and filter, as far as they fit
+	room := s.width - lipgloss.Width(status) - lipgloss.Width(keysHelp) - 5
+	for _, part := range s.contextParts(manager) {
+		if w := lipgloss.Width(sep + part); w <= room {
+			status += sep + part
+			room -= w
+		} else {
+			break
+		}
 	}
-	keysHelp := strings.Join(hints, s.styles.Sep.Render(" │ "))
 
 	// Calculate spacing
 	statusWidth := lipgloss.Width(status)
@@ -102,53 +173,63 @@ func (s *StatusBar) renderStatus(manager *process.Manager) string {
 	if padding < 1 {
 		padding = 1
 	}
+	if !alerting && s.activity == "" {
+		// After the container's padding
+		s.hintsStart = 1 + statusWidth + padding
+	}
 
 	return s.styles.Container.
 		Width(s.width).
 		Render(status + strings.Repeat(" ", padding) + keysHelp)
 }
 
-// renderHelp renders the full help view
-func (s *StatusBar) renderHelp() string {
-	var b strings.Builder
-
-	b.WriteString(s.styles.Info.Render("Keybindings:"))
-	b.WriteString("\n\n")
-
-	helpItems := [][]string{
-		{"Navigation", "↑/k up", "↓/j down", "Tab switch panel", "pgup/pgdn scroll"},
-		{"Services", "s start", "x stop", "r restart"},
-		{"Bulk", "S start all", "X stop all"},
-		{"Logs", "/ filter", "c clear", "g top", "G bottom", "y copy mode", "f fullscreen"},
-		{"Projects", "a add", "d delete service", "D delete project"},
-		{"Other", "? help", "q quit"},
+// contextParts renders the number of multi-selected services, the selected
+// service with its port, health and uptime, and the log filter, most
+// important first
+func (s *StatusBar) contextParts(manager *process.Manager) []string {
+	var parts []string
+	if s.multi > 0 {
+		parts = append(parts, s.styles.Key.Render(fmt.Sprintf("%d selected", s.multi)))
 	}
-
-	for _, group := range helpItems {
-		category := group[0]
-		items := group[1:]
-
-		b.WriteString(s.styles.Key.Render(category))
-		b.WriteString(s.styles.Sep.Render(": "))
-
-		for i, item := range items {
-			parts := strings.SplitN(item, " ", 2)
-			if len(parts) == 2 {
-				b.WriteString(s.styles.Key.Render(parts[0]))
-				b.WriteString(" ")
-				b.WriteString(s.styles.Desc.Render(parts[1]))
-			}
-			if i < len(items)-1 {
-				b.WriteString(s.styles.Sep.Render(" │ "))
+	if proc := manager.Get(s.selected); proc != nil {
+		service := s.styles.Info.Render(s.selected.String())
+		if port := proc.Resolved().Port; port > 0 {
+			service += s.styles.Desc.Render(fmt.Sprintf(" :%d", port))
+		}
+		parts = append(parts, service)
+
+		status := proc.Status()
+		if status == process.StatusRunning || status == process.StatusPaused {
+			switch proc.Health() {
+			case process.HealthHealthy:
+				parts = append(parts, s.styles.Healthy.Render("healthy"))
+			case process.HealthUnhealthy:
+				parts = append(parts, s.styles.Unhealthy.Render("unhealthy"))
 			}
+			parts = append(parts, s.styles.Desc.Render("up "+formatUptime(proc.Uptime())))
 		}
-		b.WriteString("\n")
 	}
+	if s.filter != "" {
+		parts = append(parts, s.styles.Desc.Render("filter: ")+s.styles.Info.Render(s.filter))
+	}
+	return parts
+}
 
-	b.WriteString("\n")
-	b.WriteString(s.styles.Info.Render("Press any key to close help"))
-
-	return s.styles.Container.Render(b.String())
+// HintAt returns the key of the key hint rendered at a column, or "" if
+// there is none
+func (s *StatusBar) HintAt(x int) string {
+	if s.hintsStart < 0 {
+		return ""
+	}
+	start := s.hintsStart
+	for _, hint := range statusHints {
+		end := start + lipgloss.Width(s.keyHint(hint[0], hint[1]))
+		if x >= start && x < end {
+			return hint[0]
+		}
+		start = end + lipgloss.Width(" │ ")
+	}
+	return ""
 }
 
 // keyHint formats a key hint
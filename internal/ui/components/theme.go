@@ -0,0 +1,171 @@
+package components
+
+import (
+	"os"
+
+	"github.com/paralerdev/paraler/internal/config"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme holds the colors components are drawn with (see config.ThemeColors)
+type Theme struct {
+	Primary   lipgloss.Color
+	Accent    lipgloss.Color
+	Text      lipgloss.Color
+	Subtle    lipgloss.Color
+	Muted     lipgloss.Color
+	Dimmed    lipgloss.Color
+	Border    lipgloss.Color
+	Surface   lipgloss.Color
+	Selection lipgloss.Color
+	Success   lipgloss.Color
+	Warning   lipgloss.Color
+	Error     lipgloss.Color
+	Info      lipgloss.Color
+	Contrast  lipgloss.Color
+
+	// ServiceColors tell services without a color apart in the all logs
+	// timeline
+	ServiceColors []lipgloss.Color
+
+	// NoColor is set when NO_COLOR is, so selections that are only shown
+	// by a background color are shown in reverse video instead
+	NoColor bool
+
+	// Accessible is set with --accessible or NO_COLOR: states shown by a
+	// color or a symbol alone are spelled out, and spinners don't spin
+	Accessible bool
+}
+
+// theme is the theme components are created with
+var theme = DarkTheme()
+
+// SetTheme sets the theme of the components created afterwards
+func SetTheme(t Theme) {
+	theme = t
+}
+
+// DarkTheme returns the default theme, for dark terminal backgrounds
+func DarkTheme() Theme {
+	return Theme{
+		Primary:   lipgloss.Color("#8B5CF6"),
+		Accent:    lipgloss.Color("#7C3AED"),
+		Text:      lipgloss.Color("#F9FAFB"),
+		Subtle:    lipgloss.Color("#9CA3AF"),
+		Muted:     lipgloss.Color("#6B7280"),
+		Dimmed:    lipgloss.Color("#4B5563"),
+		Border:    lipgloss.Color("#374151"),
+		Surface:   lipgloss.Color("#1F2937"),
+		Selection: lipgloss.Color("#4C1D95"),
+		Success:   lipgloss.Color("#10B981"),
+		Warning:   lipgloss.Color("#F59E0B"),
+		Error:     lipgloss.Color("#EF4444"),
+		Info:      lipgloss.Color("#3B82F6"),
+		Contrast:  lipgloss.Color("#111827"),
+		ServiceColors: []lipgloss.Color{
+			"#60A5FA", "#34D399", "#F472B6", "#FBBF24", "#A78BFA", "#F87171", "#2DD4BF", "#FB923C",
+		},
+	}
+}
+
+// LightTheme returns a theme for light terminal backgrounds
+func LightTheme() Theme {
+	return Theme{
+		Primary:   lipgloss.Color("#6D28D9"),
+		Accent:    lipgloss.Color("#5B21B6"),
+		Text:      lipgloss.Color("#111827"),
+		Subtle:    lipgloss.Color("#4B5563"),
+		Muted:     lipgloss.Color("#6B7280"),
+		Dimmed:    lipgloss.Color("#9CA3AF"),
+		Border:    lipgloss.Color("#D1D5DB"),
+		Surface:   lipgloss.Color("#E5E7EB"),
+		Selection: lipgloss.Color("#DDD6FE"),
+		Success:   lipgloss.Color("#047857"),
+		Warning:   lipgloss.Color("#B45309"),
+		Error:     lipgloss.Color("#DC2626"),
+		Info:      lipgloss.Color("#1D4ED8"),
+		Contrast:  lipgloss.Color("#FFFFFF"),
+		ServiceColors: []lipgloss.Color{
+			"#2563EB", "#059669", "#DB2777", "#B45309", "#7C3AED", "#DC2626", "#0D9488", "#EA580C",
+		},
+	}
+}
+
+// HighContrastTheme returns a theme of the terminal's own bright ANSI
+// colors, so it follows its palette
+func HighContrastTheme() Theme {
+	return Theme{
+		Primary:   lipgloss.Color("13"),
+		Accent:    lipgloss.Color("13"),
+		Text:      lipgloss.Color("15"),
+		Subtle:    lipgloss.Color("15"),
+		Muted:     lipgloss.Color("7"),
+		Dimmed:    lipgloss.Color("8"),
+		Border:    lipgloss.Color("7"),
+		Surface:   lipgloss.Color("8"),
+		Selection: lipgloss.Color("4"),
+		Success:   lipgloss.Color("10"),
+		Warning:   lipgloss.Color("11"),
+		Error:     lipgloss.Color("9"),
+		Info:      lipgloss.Color("14"),
+		Contrast:  lipgloss.Color("0"),
+		ServiceColors: []lipgloss.Color{
+			"12", "10", "13", "11", "14", "9",
+		},
+	}
+}
+
+// LoadTheme returns the theme configured, honoring NO_COLOR
+func LoadTheme(cfg config.Theme) Theme {
+	var t Theme
+	switch cfg.Preset {
+	case config.ThemeLight:
+		t = LightTheme()
+	case config.ThemeHighContrast:
+		t = HighContrastTheme()
+	default:
+		t = DarkTheme()
+	}
+
+	colors := map[string]*lipgloss.Color{
+		"primary":   &t.Primary,
+		"accent":    &t.Accent,
+		"text":      &t.Text,
+		"subtle":    &t.Subtle,
+		"muted":     &t.Muted,
+		"dimmed":    &t.Dimmed,
+		"border":    &t.Border,
+		"surface":   &t.Surface,
+		"selection": &t.Selection,
+		"success":   &t.Success,
+		"warning":   &t.Warning,
+		"error":     &t.Error,
+		"info":      &t.Info,
+		"contrast":  &t.Contrast,
+	}
+	for name, color := range cfg.Colors {
+		if c, ok := colors[name]; ok {
+			*c = lipgloss.Color(color)
+		}
+	}
+
+	// Colors are dropped by lipgloss already, see https://no-color.org
+	t.NoColor = os.Getenv("NO_COLOR") != ""
+	t.Accessible = t.NoColor
+	return t
+}
+
+// SetAccessible spells out states and stops spinners, as with NO_COLOR
+// but keeping colors
+func SetAccessible() {
+	theme.Accessible = true
+}
+
+// selectionStyle returns a style marking a selection with a background
+// color, or in reverse video without colors
+func selectionStyle(background lipgloss.Color) lipgloss.Style {
+	if theme.NoColor {
+		return lipgloss.NewStyle().Reverse(true)
+	}
+	return lipgloss.NewStyle().Background(background)
+}
@@ -0,0 +1,61 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/paralerdev/paraler/internal/log"
+)
+
+// throughputSeconds is the number of seconds the line rate is averaged and
+// charted over
+const throughputSeconds = 10
+
+// sparkBlocks are the bars of a sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// throughput formats the line rate of the services shown, with a sparkline
+// of it while they print, and the number of lines buffered
+func (l *LogPanel) throughput(buffer *log.Buffer) string {
+	counts := make([]int, throughputSeconds)
+	total, buffered := 0, 0
+	for _, id := range l.ShownIDs() {
+		for i, n := range buffer.LinesPerSecond(id, throughputSeconds) {
+			counts[i] += n
+			total += n
+		}
+		buffered += buffer.Count(id)
+	}
+
+	text := formatRate(float64(total)/throughputSeconds) + "/s"
+	if total > 0 && !theme.Accessible {
+		text += " " + sparkline(counts)
+	}
+	return fmt.Sprintf("%s · %d lines", text, buffered)
+}
+
+// formatRate formats a rate of lines per second, with a decimal below 10
+func formatRate(rate float64) string {
+	if rate < 10 {
+		return fmt.Sprintf("%.1f", rate)
+	}
+	return fmt.Sprintf("%.0f", rate)
+}
+
+// sparkline charts counts as bars scaled to the largest
+func sparkline(counts []int) string {
+	peak := 0
+	for _, n := range counts {
+		peak = max(peak, n)
+	}
+
+	var b strings.Builder
+	for _, n := range counts {
+		level := 0
+		if peak > 0 {
+			level = n * (len(sparkBlocks) - 1) / peak
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
@@ -0,0 +1,189 @@
+package components
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ToastLevel is the severity of a toast
+type ToastLevel int
+
+const (
+	ToastInfo ToastLevel = iota
+	ToastSuccess
+	ToastWarning
+	ToastError
+)
+
+// Duration returns how long toasts of a level are shown
+func (l ToastLevel) Duration() time.Duration {
+	switch l {
+	case ToastError:
+		return 8 * time.Second
+	case ToastWarning:
+		return 5 * time.Second
+	default:
+		return 3 * time.Second
+	}
+}
+
+// maxToasts is how many toasts are shown at once; older ones are dropped
+const maxToasts = 4
+
+// toast is a single notification
+type toast struct {
+	level ToastLevel
+	text  string
+	until time.Time
+}
+
+// Toasts shows short-lived notifications about the results of actions,
+// stacked above the status bar
+type Toasts struct {
+	toasts []toast
+	width  int
+	styles ToastStyles
+}
+
+// ToastStyles contains toast styles
+type ToastStyles struct {
+	Container lipgloss.Style
+	Info      lipgloss.Style
+	Success   lipgloss.Style
+	Warning   lipgloss.Style
+	Error     lipgloss.Style
+	Text      lipgloss.Style
+}
+
+// DefaultToastStyles returns default styles
+func DefaultToastStyles() ToastStyles {
+	return ToastStyles{
+		Container: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			Padding(0, 1),
+		Info: lipgloss.NewStyle().
+			Foreground(theme.Info).
+			Bold(true),
+		Success: lipgloss.NewStyle().
+			Foreground(theme.Success).
+			Bold(true),
+		Warning: lipgloss.NewStyle().
+			Foreground(theme.Warning).
+			Bold(true),
+		Error: lipgloss.NewStyle().
+			Foreground(theme.Error).
+			Bold(true),
+		Text: lipgloss.NewStyle().
+			Foreground(theme.Text),
+	}
+}
+
+// NewToasts creates an empty toast area
+func NewToasts() *Toasts {
+	return &Toasts{
+		styles: DefaultToastStyles(),
+	}
+}
+
+// SetWidth sets the maximum width of a toast
+func (t *Toasts) SetWidth(width int) {
+	t.width = width
+}
+
+// Push shows a toast for the duration of its level
+func (t *Toasts) Push(level ToastLevel, text string) {
+	t.toasts = append(t.toasts, toast{
+		level: level,
+		text:  text,
+		until: time.Now().Add(level.Duration()),
+	})
+	if len(t.toasts) > maxToasts {
+		t.toasts = t.toasts[len(t.toasts)-maxToasts:]
+	}
+}
+
+// Prune drops the toasts that have expired
+func (t *Toasts) Prune(now time.Time) {
+	kept := t.toasts[:0]
+	for _, toast := range t.toasts {
+		if now.Before(toast.until) {
+			kept = append(kept, toast)
+		}
+	}
+	t.toasts = kept
+}
+
+// Dismiss drops all toasts
+func (t *Toasts) Dismiss() {
+	t.toasts = nil
+}
+
+// IsEmpty returns true if no toast is shown
+func (t *Toasts) IsEmpty() bool {
+	return len(t.toasts) == 0
+}
+
+// View renders the toasts, newest at the bottom
+func (t *Toasts) View() string {
+	views := make([]string, 0, len(t.toasts))
+	for _, toast := range t.toasts {
+		views = append(views, t.renderToast(toast))
+	}
+	return lipgloss.JoinVertical(lipgloss.Right, views...)
+}
+
+// renderToast renders a toast in a box colored by its level
+func (t *Toasts) renderToast(toast toast) string {
+	icon, style, color := "•", t.styles.Info, theme.Info
+	switch toast.level {
+	case ToastSuccess:
+		icon, style, color = "✔", t.styles.Success, theme.Success
+	case ToastWarning:
+		icon, style, color = "⚠", t.styles.Warning, theme.Warning
+	case ToastError:
+		icon, style, color = "✖", t.styles.Error, theme.Error
+	}
+
+	text := toast.text
+	if maxWidth := t.width - 6; maxWidth > 3 {
+		// Border, padding and the icon
+		text = ansi.Truncate(text, maxWidth, "…")
+	}
+	return t.styles.Container.
+		BorderForeground(color).
+		Render(style.Render(icon) + " " + t.styles.Text.Render(text))
+}
+
+// Overlay draws block over background with its top left corner at column x
+// and row y, keeping the background around it
+func Overlay(background, block string, x, y int) string {
+	lines := strings.Split(background, "\n")
+	for i, line := range strings.Split(block, "\n") {
+		row := y + i
+		if row < 0 || row >= len(lines) {
+			continue
+		}
+		bg := lines[row]
+		if width := lipgloss.Width(bg); width < x {
+			bg += strings.Repeat(" ", x-width)
+		}
+		left := ansi.Truncate(bg, x, "")
+		right := ansi.TruncateLeft(bg, x+lipgloss.Width(line), "")
+		lines[row] = left + "\x1b[0m" + line + "\x1b[0m" + right
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Dim redraws a view without its colors, in the dimmed color, so it
+// recedes behind what is drawn over it
+func Dim(view string) string {
+	style := lipgloss.NewStyle().Foreground(theme.Dimmed)
+	lines := strings.Split(ansi.Strip(view), "\n")
+	for i, line := range lines {
+		lines[i] = style.Render(line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -1,6 +1,11 @@
 package ui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"strings"
+
+	"github.com/paralerdev/paraler/internal/ui/components"
+	"github.com/charmbracelet/bubbles/key"
+)
 
 // KeyMap defines all key bindings
 type KeyMap struct {
@@ -29,14 +34,57 @@ type KeyMap struct {
 	Confirm       key.Binding
 	ReloadConfig    key.Binding
 	ExportLogs      key.Binding
+	ClearAllLogs    key.Binding
 	ToggleSelect    key.Binding
 	ClearSelect     key.Binding
 	MoveService     key.Binding
 	Rename          key.Binding
+	RenameService   key.Binding
+	EditService     key.Binding
+	FindService     key.Binding
+	ToggleCollapse  key.Binding
+	SortServices    key.Binding
+	Dashboard       key.Binding
 	CopyMode        key.Binding
 	CopyModeSelect  key.Binding
 	CopyModeCopy    key.Binding
+	CopyModeLeft    key.Binding
+	CopyModeRight   key.Binding
+	CopyModeWord    key.Binding
+	CopyModeWordBack key.Binding
+	CopyModeLineStart key.Binding
+	CopyModeLineEnd key.Binding
+	CopyModeChars   key.Binding
+	CopyModeBlock   key.Binding
+	PipeLogs        key.Binding
 	Fullscreen      key.Binding
+	ExpandJSON      key.Binding
+	WrapLines       key.Binding
+	Timestamps      key.Binding
+	Bookmark        key.Binding
+	Bookmarks       key.Binding
+	NextBookmark    key.Binding
+	PrevBookmark    key.Binding
+	NextError       key.Binding
+	PrevError       key.Binding
+	NextGroup       key.Binding
+	PrevGroup       key.Binding
+	AckErrors       key.Binding
+	SearchLogs      key.Binding
+	MergedLogs      key.Binding
+	LevelFilter     key.Binding
+	StderrOnly      key.Binding
+	SplitLogs       key.Binding
+	StackLogs       key.Binding
+	CloseLogPanel   key.Binding
+	SendSignal      key.Binding
+	Pause           key.Binding
+	Info            key.Binding
+	AddService      key.Binding
+	Env             key.Binding
+	OpenURL         key.Binding
+	OpenEditor      key.Binding
+	Upgrade         key.Binding
 }
 
 // DefaultKeyMap returns the default key bindings
@@ -82,6 +130,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("c"),
 			key.WithHelp("c", "clear logs"),
 		),
+		ClearAllLogs: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "clear all logs"),
+		),
 		Help: key.NewBinding(
 			key.WithKeys("?"),
 			key.WithHelp("?", "help"),
@@ -156,7 +208,31 @@ func DefaultKeyMap() KeyMap {
 		),
 		Rename: key.NewBinding(
 			key.WithKeys("ctrl+r"),
-			key.WithHelp("ctrl+r", "rename"),
+			key.WithHelp("ctrl+r", "rename project/service"),
+		),
+		RenameService: key.NewBinding(
+			key.WithKeys("f2"),
+			key.WithHelp("f2", "rename service"),
+		),
+		FindService: key.NewBinding(
+			key.WithKeys("ctrl+f"),
+			key.WithHelp("ctrl+f", "find service"),
+		),
+		ToggleCollapse: key.NewBinding(
+			key.WithKeys("enter", " "),
+			key.WithHelp("enter", "collapse project"),
+		),
+		SortServices: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", "sort services"),
+		),
+		Dashboard: key.NewBinding(
+			key.WithKeys("0"),
+			key.WithHelp("0", "dashboard"),
+		),
+		EditService: key.NewBinding(
+			key.WithKeys("E"),
+			key.WithHelp("E", "edit service"),
 		),
 		CopyMode: key.NewBinding(
 			key.WithKeys("y"),
@@ -164,16 +240,160 @@ func DefaultKeyMap() KeyMap {
 		),
 		CopyModeSelect: key.NewBinding(
 			key.WithKeys("v"),
-			key.WithHelp("v", "select"),
+			key.WithHelp("v", "select lines"),
 		),
 		CopyModeCopy: key.NewBinding(
 			key.WithKeys("y", "enter"),
 			key.WithHelp("y", "copy"),
 		),
+		CopyModeLeft: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "left"),
+		),
+		CopyModeRight: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "right"),
+		),
+		CopyModeWord: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "next word"),
+		),
+		CopyModeWordBack: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "previous word"),
+		),
+		CopyModeLineStart: key.NewBinding(
+			key.WithKeys("0", "home"),
+			key.WithHelp("0", "line start"),
+		),
+		CopyModeLineEnd: key.NewBinding(
+			key.WithKeys("$", "end"),
+			key.WithHelp("$", "line end"),
+		),
+		CopyModeChars: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "select characters"),
+		),
+		CopyModeBlock: key.NewBinding(
+			key.WithKeys("ctrl+v"),
+			key.WithHelp("ctrl+v", "select block"),
+		),
+		PipeLogs: key.NewBinding(
+			key.WithKeys("|"),
+			key.WithHelp("|", "pipe to command"),
+		),
 		Fullscreen: key.NewBinding(
 			key.WithKeys("f"),
 			key.WithHelp("f", "fullscreen"),
 		),
+		ExpandJSON: key.NewBinding(
+			key.WithKeys("J"),
+			key.WithHelp("J", "expand JSON"),
+		),
+		WrapLines: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "wrap lines"),
+		),
+		Timestamps: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "timestamp format"),
+		),
+		Bookmark: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "bookmark line"),
+		),
+		Bookmarks: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", "list bookmarks"),
+		),
+		NextBookmark: key.NewBinding(
+			key.WithKeys("]"),
+			key.WithHelp("]", "next bookmark"),
+		),
+		PrevBookmark: key.NewBinding(
+			key.WithKeys("["),
+			key.WithHelp("[", "previous bookmark"),
+		),
+		NextError: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next error"),
+		),
+		PrevError: key.NewBinding(
+			key.WithKeys("N"),
+			key.WithHelp("N", "previous error"),
+		),
+		NextGroup: key.NewBinding(
+			key.WithKeys("}"),
+			key.WithHelp("}", "next project/error"),
+		),
+		PrevGroup: key.NewBinding(
+			key.WithKeys("{"),
+			key.WithHelp("{", "previous project/error"),
+		),
+		AckErrors: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "acknowledge errors"),
+		),
+		SearchLogs: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "search all logs"),
+		),
+		MergedLogs: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "all logs timeline"),
+		),
+		LevelFilter: key.NewBinding(
+			key.WithKeys("l"),
+			key.WithHelp("l", "cycle log levels"),
+		),
+		StderrOnly: key.NewBinding(
+			key.WithKeys("!"),
+			key.WithHelp("!", "stderr only"),
+		),
+		SplitLogs: key.NewBinding(
+			key.WithKeys("|"),
+			key.WithHelp("|", "split logs"),
+		),
+		StackLogs: key.NewBinding(
+			key.WithKeys("-"),
+			key.WithHelp("-", "stack/side by side"),
+		),
+		CloseLogPanel: key.NewBinding(
+			key.WithKeys("ctrl+w"),
+			key.WithHelp("ctrl+w", "close log panel"),
+		),
+		SendSignal: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "send signal"),
+		),
+		Pause: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "pause/resume"),
+		),
+		Info: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", "service info"),
+		),
+		AddService: key.NewBinding(
+			key.WithKeys("+"),
+			key.WithHelp("+", "add service"),
+		),
+		OpenURL: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "open in browser"),
+		),
+		OpenEditor: key.NewBinding(
+			key.WithKeys("O"),
+			key.WithHelp("O", "open in editor"),
+		),
+		Env: key.NewBinding(
+			key.WithKeys("I"),
+			key.WithHelp("I", "environment"),
+		),
+		Upgrade: key.NewBinding(
+			key.WithKeys("U"),
+			key.WithHelp("U", "upgrade in place"),
+		),
 	}
 }
 
@@ -184,13 +404,72 @@ func (k KeyMap) ShortHelp() []key.Binding {
 
 // FullHelp returns the full help
 func (k KeyMap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{
-		{k.Up, k.Down, k.Tab},
-		{k.Start, k.Stop, k.Restart},
-		{k.StartAll, k.StopAll},
-		{k.Filter, k.ClearLogs},
-		{k.DeleteService, k.DeleteProject},
-		{k.MoveService, k.Rename, k.ReloadConfig},
-		{k.Help, k.Quit},
+	sections := k.helpSections()
+	help := make([][]key.Binding, len(sections))
+	for i, section := range sections {
+		help[i] = section.bindings
+	}
+	return help
+}
+
+// helpSection is a group of bindings that apply in the same context
+type helpSection struct {
+	title    string
+	bindings []key.Binding
+}
+
+// helpSections returns the bindings grouped by where they apply
+func (k KeyMap) helpSections() []helpSection {
+	return []helpSection{
+		{"Navigation", []key.Binding{k.Up, k.Down, k.PrevGroup, k.NextGroup, k.Tab, k.PageUp, k.PageDown, k.Home, k.End, k.FindService, k.Dashboard, k.Fullscreen}},
+		{"Services", []key.Binding{k.Start, k.Stop, k.Restart, k.Pause, k.SendSignal, k.Info, k.Env, k.OpenURL, k.OpenEditor, k.StartAll, k.StopAll, k.ToggleSelect, k.ClearSelect}},
+		{"Sidebar", []key.Binding{k.ToggleCollapse, k.SortServices, k.AddProject, k.AddService, k.EditService, k.MoveService, k.Rename, k.RenameService, k.DeleteService, k.DeleteProject, k.ReloadConfig}},
+		{"Logs", []key.Binding{k.Filter, k.SearchLogs, k.MergedLogs, k.LevelFilter, k.StderrOnly, k.NextError, k.PrevError, k.AckErrors, k.ClearLogs, k.ClearAllLogs, k.ExportLogs, k.ExpandJSON, k.WrapLines, k.Timestamps}},
+		{"Copy mode", []key.Binding{k.CopyMode, k.CopyModeLeft, k.CopyModeRight, k.CopyModeWord, k.CopyModeWordBack, k.CopyModeLineStart, k.CopyModeLineEnd, k.CopyModeSelect, k.CopyModeChars, k.CopyModeBlock, k.CopyModeCopy, k.PipeLogs, k.Escape}},
+		{"Bookmarks", []key.Binding{k.Bookmark, k.Bookmarks, k.PrevBookmark, k.NextBookmark}},
+		{"Split logs", []key.Binding{k.SplitLogs, k.StackLogs, k.CloseLogPanel}},
+		{"Other", []key.Binding{k.Help, k.Upgrade, k.Quit}},
+	}
+}
+
+// HelpGroups returns the bindings for the help screen. Keys are listed as
+// bound rather than as their help text, so remapped keys show up as such.
+func (k KeyMap) HelpGroups() []components.HelpGroup {
+	var groups []components.HelpGroup
+	for _, section := range k.helpSections() {
+		group := components.HelpGroup{Title: section.title}
+		for _, binding := range section.bindings {
+			if !binding.Enabled() {
+				continue
+			}
+			group.Keys = append(group.Keys, components.HelpKey{
+				Key:  keyNames(binding.Keys()),
+				Desc: binding.Help().Desc,
+			})
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// keyNames joins keys for display, with arrows and space spelled out
+func keyNames(keys []string) string {
+	names := make([]string, len(keys))
+	for i, k := range keys {
+		switch k {
+		case "up":
+			names[i] = "↑"
+		case "down":
+			names[i] = "↓"
+		case "left":
+			names[i] = "←"
+		case "right":
+			names[i] = "→"
+		case " ":
+			names[i] = "space"
+		default:
+			names[i] = k
+		}
 	}
+	return strings.Join(names, "/")
 }
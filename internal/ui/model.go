@@ -1,9 +1,14 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"image"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/paralerdev/paraler/internal/config"
@@ -13,6 +18,18 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// sinkAlertInterval limits how often failing log sinks are alerted
+const sinkAlertInterval = 30 * time.Second
+
+// pipeTimeout limits how long a command logs are piped to may run
+const pipeTimeout = 30 * time.Second
+
+// mouseWheelLines is the number of log lines a mouse wheel step scrolls
+const mouseWheelLines = 3
+
+// maxLogPanels limits how many log panels the logs can be split into
+const maxLogPanels = 4
+
 // Focus represents which panel is focused
 type Focus int
 
@@ -31,18 +48,43 @@ type Model struct {
 	// Process management
 	manager *process.Manager
 
-	// Log buffer
+	// Log buffer, and log files output is persisted to if enabled
 	logBuffer *log.Buffer
+	logStore  *log.Store
+
+	// External destinations all output is forwarded to
+	sinks         []log.Sink
+	sinkAlertedAt time.Time
 
 	// UI components
 	sidebar            *components.Sidebar
 	logPanel           *components.LogPanel
 	statusBar          *components.StatusBar
+	toasts             *components.Toasts
 	addProjectModal    *components.AddProjectModal
 	confirmModal       *components.ConfirmModal
 	moveServiceModal   *components.MoveServiceModal
 	renameModal        *components.RenameModal
+	serviceEditor      *components.ServiceEditor
 	portConflictModal  *components.PortConflictModal
+	orphanModal        *components.OrphanModal
+	signalModal        *components.SignalModal
+	detailModal        *components.DetailModal
+	envModal           *components.EnvModal
+	errorModal         *components.ErrorModal
+	startAllModal      *components.StartAllModal
+	searchModal        *components.SearchModal
+	bookmarksModal     *components.BookmarksModal
+	pipeModal          *components.PipeModal
+	shutdownScreen     *components.ShutdownScreen
+	dashboard          *components.Dashboard
+	helpScreen         *components.HelpScreen
+
+	// Log panels the logs are split into, side by side or stacked, and
+	// where they're drawn; logPanel is the one focused last
+	logPanels   []*components.LogPanel
+	logBounds   []image.Rectangle
+	stackedLogs bool
 
 	// UI state
 	focus             Focus
@@ -51,10 +93,32 @@ type Model struct {
 	showConfirm       bool
 	showMoveService   bool
 	showRename        bool
+	showEditor        bool
 	showPortConflict  bool
+	showOrphans       bool
+	showSignal        bool
+	showDetail        bool
+	showEnv           bool
+	showError         bool
+	count             int // Count prefix typed for the next motion, 0 if none
+	showStartAll      bool
+	showSearch        bool
+	showBookmarks     bool
+	showPipe          bool
+	showDashboard     bool
 	fullscreen        bool
+	upgradeRequested  bool
+	exportFormat      log.ExportFormat
+	noColor           bool // NO_COLOR is set, so service colors are stripped too
+	pipeEntries       []log.Entry // lines the pipe modal's command reads
+	shuttingDown      bool
+	spinning          bool // a spinner tick is pending
+	spinnerTick       int
+	lastOutput        time.Time // when the last batch of output was handled
+	inlineHeight      int // rows used below the prompt, 0 in the alternate screen
 	width            int
 	height           int
+	sidebarWidth     int
 	ready            bool
 
 	// Key bindings
@@ -63,25 +127,55 @@ type Model struct {
 
 // NewModel creates a new root model
 func NewModel(cfg *config.Config, configPath string) *Model {
-	manager := process.NewManager(cfg)
+	logBuffer := log.NewBuffer(1000)
+	manager := newManager(cfg, configPath, logBuffer)
+
+	// Components are created with the theme, so it can't change until restart
+	theme := components.LoadTheme(cfg.Theme)
+	components.SetTheme(theme)
 
+	keys := DefaultKeyMap()
 	m := &Model{
 		config:            cfg,
 		configPath:        configPath,
 		manager:           manager,
-		logBuffer:         log.NewBuffer(1000),
-		sidebar:           components.NewSidebar(cfg),
+		logBuffer:         logBuffer,
+		sidebar:           newSidebar(cfg, configPath, manager),
 		logPanel:          components.NewLogPanel(),
 		statusBar:         components.NewStatusBar(),
+		toasts:            components.NewToasts(),
 		addProjectModal:   components.NewAddProjectModal(),
 		confirmModal:      components.NewConfirmModal(),
 		moveServiceModal:  components.NewMoveServiceModal(),
 		renameModal:       components.NewRenameModal(),
+		serviceEditor:     components.NewServiceEditor(),
 		portConflictModal: components.NewPortConflictModal(),
+		orphanModal:       components.NewOrphanModal(),
+		signalModal:       components.NewSignalModal(),
+		detailModal:       components.NewDetailModal(),
+		envModal:          components.NewEnvModal(),
+		errorModal:        components.NewErrorModal(),
+		startAllModal:     components.NewStartAllModal(),
+		searchModal:       components.NewSearchModal(),
+		bookmarksModal:    components.NewBookmarksModal(),
+		pipeModal:         components.NewPipeModal(),
+		shutdownScreen:    components.NewShutdownScreen(),
+		dashboard:         components.NewDashboard(),
 		focus:             FocusSidebar,
-		keys:              DefaultKeyMap(),
+		keys:              keys,
+		helpScreen:        components.NewHelpScreen(keys.HelpGroups()),
+		exportFormat:      log.ExportPlain,
+		noColor:           theme.NoColor,
 	}
 
+	m.logPanels = []*components.LogPanel{m.logPanel}
+	m.configureLogPanels()
+	m.openHistory()
+	if cfg.Logs.Persist {
+		m.openLogStore()
+	}
+	m.openSinks()
+
 	// Select first service if available
 	if m.sidebar.ServiceCount() > 0 {
 		m.sidebar.SelectFirst()
@@ -91,6 +185,126 @@ func NewModel(cfg *config.Config, configPath string) *Model {
 	return m
 }
 
+// newManager creates a process manager that records started PIDs in the
+// state file for the config, clears the logs of services restarting when
+// configured to, and monitors its services
+func newManager(cfg *config.Config, configPath string, logBuffer *log.Buffer) *process.Manager {
+	manager := process.NewManager(cfg)
+	manager.SetStatePath(process.DefaultStatePath(configPath))
+	manager.SetLogClearer(logBuffer.Clear)
+	manager.StartMonitor()
+	return manager
+}
+
+// openLogStore opens the log files output is persisted to and fills the
+// log buffer with what earlier sessions wrote
+func (m *Model) openLogStore() {
+	dir := log.DefaultStoreDir(m.configPath)
+	if m.config.Logs.Dir != "" {
+		dir = config.ExpandPath(m.config.Logs.Dir)
+	}
+	m.logStore = log.NewStore(dir, m.config.Logs)
+
+	for _, proc := range m.manager.All() {
+		entries, _ := m.logStore.Tail(proc.ID, log.DefaultBufferSize)
+		for _, entry := range entries {
+			m.logBuffer.Add(entry)
+		}
+	}
+}
+
+// logHistoryLines returns the number of older log lines of a service kept
+// on disk, 0 for the default
+func (m *Model) logHistoryLines(id config.ServiceID) int {
+	if service, ok := m.config.Projects[id.Project].Services[id.Service]; ok && service.LogHistory > 0 {
+		return service.LogHistory
+	}
+	return m.config.Logs.History
+}
+
+// loadLogHistory loads older lines of the shown service from disk once the
+// log panel is scrolled to the top
+func (m *Model) loadLogHistory() {
+	if !m.logPanel.AtTop() || m.logPanel.IsMerged() {
+		return
+	}
+	m.logPanel.HoldPosition()
+	m.logBuffer.LoadHistory(m.logPanel.ServiceID(), log.DefaultBufferSize)
+}
+
+// dropLogHistory releases the older lines loaded for the shown service once
+// the log panel follows new lines again
+func (m *Model) dropLogHistory() {
+	if m.logPanel.IsFollowing() {
+		m.logBuffer.DropHistory(m.logPanel.ServiceID())
+	}
+}
+
+// persistLog writes an entry to the log files if enabled. On failure,
+// persisting stops so the alert isn't repeated for every line.
+func (m *Model) persistLog(entry log.Entry) {
+	if m.logStore == nil {
+		return
+	}
+	if err := m.logStore.Write(entry); err != nil {
+		m.logStore.Close()
+		m.logStore = nil
+		m.statusBar.ShowAlert(fmt.Sprintf("Failed to write log file, no longer persisting logs: %v", err), 5*time.Second)
+	}
+}
+
+// openHistory keeps the output trimmed from the log buffer on disk
+func (m *Model) openHistory() {
+	if err := m.logBuffer.EnableHistory(m.logHistoryLines); err != nil {
+		m.statusBar.ShowAlert(fmt.Sprintf("Failed to keep log history on disk: %v", err), 5*time.Second)
+	}
+}
+
+// openSinks opens the configured log sinks, skipping those that fail
+func (m *Model) openSinks() {
+	for _, cfg := range m.config.Sinks {
+		sink, err := log.NewSink(cfg)
+		if err != nil {
+			m.statusBar.ShowAlert(fmt.Sprintf("Failed to open %s log sink: %v", cfg.Type, err), 10*time.Second)
+			continue
+		}
+		m.sinks = append(m.sinks, sink)
+	}
+}
+
+// forwardLog sends an entry to the log sinks. Failures are alerted at most
+// every sinkAlertInterval, as a sink that is down fails for every line.
+func (m *Model) forwardLog(entry log.Entry) {
+	for _, sink := range m.sinks {
+		err := sink.Write(entry)
+		if err != nil && time.Since(m.sinkAlertedAt) > sinkAlertInterval {
+			m.sinkAlertedAt = time.Now()
+			m.statusBar.ShowAlert(fmt.Sprintf("Failed to forward logs: %v", err), 5*time.Second)
+		}
+	}
+}
+
+// Close flushes and closes the log files and sinks once the UI has exited.
+// Output arriving afterwards reopens the log files, the sinks and log
+// history stay closed until Reopen.
+func (m *Model) Close() {
+	m.logBuffer.Close()
+	if m.logStore != nil {
+		m.logStore.Close()
+	}
+	for _, sink := range m.sinks {
+		sink.Close()
+	}
+	m.sinks = nil
+}
+
+// Reopen reopens the log history and sinks after Close, for the UI to run
+// again
+func (m *Model) Reopen() {
+	m.openHistory()
+	m.openSinks()
+}
+
 // Config returns the current config
 func (m *Model) Config() *config.Config {
 	return m.config
@@ -105,12 +319,15 @@ func (m *Model) ConfigPath() string {
 func (m *Model) ReloadConfig() {
 	// Stop all processes
 	m.manager.StopAll()
+	m.manager.Close()
+	m.HideDetail()
 
 	// Reload manager
-	m.manager = process.NewManager(m.config)
+	m.manager = newManager(m.config, m.configPath, m.logBuffer)
 
 	// Rebuild sidebar
-	m.sidebar = components.NewSidebar(m.config)
+	m.sidebar = newSidebar(m.config, m.configPath, m.manager)
+	m.configureLogPanels()
 
 	// Recalculate layout
 	m.calculateLayout()
@@ -159,13 +376,84 @@ func (m *Model) ShowConfirmDeleteService() {
 
 // ShowConfirmDeleteProject shows confirmation for deleting a project
 func (m *Model) ShowConfirmDeleteProject() {
-	selected := m.sidebar.Selected()
-	if selected.Project == "" {
+	project := m.sidebar.SelectedProjectName()
+	if project == "" {
+		return
+	}
+	m.confirmModal.Show(components.ConfirmDeleteProject, project, "")
+	m.confirmModal.SetSize(m.width / 2)
+	m.showConfirm = true
+}
+
+// ShowConfirmClearAllLogs shows confirmation for clearing the logs of
+// every service
+func (m *Model) ShowConfirmClearAllLogs() {
+	m.confirmModal.Show(components.ConfirmClearAllLogs, "", "")
+	m.confirmModal.SetSize(m.width / 2)
+	m.showConfirm = true
+}
+
+// ShowConfirmRestartService asks to restart a running service to apply
+// its edited config
+func (m *Model) ShowConfirmRestartService(id config.ServiceID) {
+	m.confirmModal.Show(components.ConfirmRestartService, id.Project, id.Service)
+	m.confirmModal.SetSize(m.width / 2)
+	m.showConfirm = true
+}
+
+// ShowConfirmStopProject asks to stop the services of a project, or says
+// that none is running
+func (m *Model) ShowConfirmStopProject(project string) {
+	running := false
+	for _, proc := range m.manager.GetByProject(project) {
+		if !proc.IsDone() {
+			running = true
+			break
+		}
+	}
+	if !running {
+		m.statusBar.ShowAlert(project+" has no running services", 2*time.Second)
+		return
+	}
+	m.confirmModal.Show(components.ConfirmStopProject, project, "")
+	m.confirmModal.SetSize(m.width / 2)
+	m.showConfirm = true
+}
+
+// ShowConfirmStopAll asks to stop every running service, or says that none
+// is running
+func (m *Model) ShowConfirmStopAll() {
+	running := m.activeCount()
+	if running == 0 {
+		m.statusBar.ShowAlert("No running services", 2*time.Second)
 		return
 	}
-	m.confirmModal.Show(components.ConfirmDeleteProject, selected.Project, "")
+	m.confirmModal.ShowRunning(components.ConfirmStopAll, running)
+	m.confirmModal.SetSize(m.width / 2)
+	m.showConfirm = true
+}
+
+// confirmQuit asks to quit if services are running, or quits
+func (m *Model) confirmQuit() tea.Cmd {
+	running := m.activeCount()
+	if running == 0 {
+		return m.shutdown()
+	}
+	m.confirmModal.ShowRunning(components.ConfirmQuit, running)
 	m.confirmModal.SetSize(m.width / 2)
 	m.showConfirm = true
+	return nil
+}
+
+// activeCount returns the number of services that haven't stopped
+func (m *Model) activeCount() int {
+	count := 0
+	for _, proc := range m.manager.All() {
+		if !proc.IsDone() {
+			count++
+		}
+	}
+	return count
 }
 
 // HideConfirm hides the confirmation modal
@@ -283,13 +571,27 @@ func (m *Model) MoveService(serviceName, fromProject, toProject string) error {
 	return nil
 }
 
-// ShowRename shows the rename modal for the project of the selected service
+// ShowRename shows the rename modal for the selected project header, or
+// else the selected service
 func (m *Model) ShowRename() {
-	selected := m.sidebar.Selected()
-	if selected.Project == "" {
+	if !m.sidebar.IsProjectSelected() {
+		m.ShowRenameService()
+		return
+	}
+	m.renameModal.ShowRenameProject(m.sidebar.SelectedProjectName())
+	m.renameModal.SetSize(m.width / 2)
+	m.showRename = true
+}
+
+// ShowRenameService shows the rename modal for the selected service; a
+// replica renames the service it belongs to
+func (m *Model) ShowRenameService() {
+	selected := m.sidebar.Selected().Base()
+	if selected.Service == "" {
+		m.statusBar.ShowAlert("Select a service to rename", 2*time.Second)
 		return
 	}
-	m.renameModal.ShowRenameProject(selected.Project)
+	m.renameModal.ShowRenameService(selected.Project, selected.Service)
 	m.renameModal.SetSize(m.width / 2)
 	m.showRename = true
 }
@@ -351,9 +653,115 @@ func (m *Model) RenameService(projectName, oldName, newName string) error {
 	return nil
 }
 
+// ShowEditor shows the editor for the selected service
+func (m *Model) ShowEditor() {
+	selected := m.sidebar.Selected()
+	if selected.Service == "" {
+		return
+	}
+	service, ok := m.config.Projects[selected.Project].Services[selected.Service]
+	if !ok {
+		return
+	}
+	m.serviceEditor.Show(selected.Base(), service)
+	m.serviceEditor.SetSize(max(m.width/2, 60))
+	m.showEditor = true
+}
+
+// ShowAddService shows the editor to add a service to the selected project
+func (m *Model) ShowAddService() {
+	project := m.sidebar.SelectedProjectName()
+	if project == "" {
+		m.statusBar.ShowAlert("Select a project to add a service to", 2*time.Second)
+		return
+	}
+	m.serviceEditor.ShowNew(project)
+	m.serviceEditor.SetSize(max(m.width/2, 60))
+	m.showEditor = true
+}
+
+// HideEditor hides the service editor
+func (m *Model) HideEditor() {
+	m.serviceEditor.Hide()
+	m.showEditor = false
+}
+
+// SaveServiceEdit writes the edited service to the config file and applies
+// it to the service's processes, asking to restart them if they run
+func (m *Model) SaveServiceEdit() error {
+	if m.serviceEditor.IsAdding() {
+		return m.addService()
+	}
+	id := m.serviceEditor.ServiceID()
+	service, err := m.serviceEditor.Service()
+	if err != nil {
+		return err
+	}
+	if err := m.config.UpdateService(id.Project, id.Service, service); err != nil {
+		return err
+	}
+	if err := m.config.Save(m.configPath); err != nil {
+		return err
+	}
+	m.HideEditor()
+	m.manager.UpdateService(id)
+
+	// Show the new config in the log panel footer
+	m.updateLogPanelService()
+
+	for _, proc := range m.manager.Instances(id) {
+		if proc.HasPendingConfig() {
+			m.ShowConfirmRestartService(id)
+			return nil
+		}
+	}
+	m.statusBar.ShowAlert("Saved "+id.Service, 2*time.Second)
+	return nil
+}
+
+// addService adds the service filled in in the editor to its project and
+// selects it, leaving the other services running
+func (m *Model) addService() error {
+	id := config.ServiceID{Project: m.serviceEditor.ServiceID().Project, Service: m.serviceEditor.Name()}
+	service, err := m.serviceEditor.Service()
+	if err != nil {
+		return err
+	}
+	if err := m.config.AddService(id.Project, id.Service, service); err != nil {
+		return err
+	}
+	if err := m.config.Save(m.configPath); err != nil {
+		return err
+	}
+	m.HideEditor()
+	m.manager.AddService(id)
+
+	m.sidebar.Refresh()
+	m.sidebar.Select(id)
+	m.updateLogPanelService()
+	m.statusBar.ShowAlert(fmt.Sprintf("Added %s, press s to start it", id.Service), 3*time.Second)
+	return nil
+}
+
+// restartEdited restarts the instances of a service with its edited config
+func (m *Model) restartEdited(id config.ServiceID) tea.Cmd {
+	return func() tea.Msg {
+		for _, proc := range m.manager.Instances(id) {
+			m.logBuffer.Clear(proc.ID) // Clear old logs/errors
+			m.manager.Restart(proc.ID)
+		}
+		return nil
+	}
+}
+
 // ShowPortConflict shows the port conflict modal
 func (m *Model) ShowPortConflict(serviceID config.ServiceID, conflict *process.PortConflictInfo) {
-	m.portConflictModal.Show(serviceID, conflict)
+	// Only a port the service is started with can be moved
+	movable := false
+	if proc := m.manager.Get(serviceID); proc != nil {
+		movable = proc.Resolved().Port == conflict.Port
+	}
+	m.portConflictModal.Show(serviceID, conflict, movable)
 	m.portConflictModal.SetSize(m.width / 2)
 	m.showPortConflict = true
 }
@@ -374,11 +782,326 @@ func (m *Model) IsPortConflictVisible() bool {
 	return m.showPortConflict
 }
 
+// ShowOrphans shows the orphaned processes modal
+func (m *Model) ShowOrphans(orphans []process.ProcessRecord) {
+	m.orphanModal.Show(orphans)
+	m.orphanModal.SetSize(m.width / 2)
+	m.showOrphans = m.orphanModal.IsVisible()
+}
+
+// HideOrphans hides the orphaned processes modal
+func (m *Model) HideOrphans() {
+	m.orphanModal.Hide()
+	m.showOrphans = false
+}
+
+// IsOrphansVisible returns true if the orphaned processes modal is visible
+func (m *Model) IsOrphansVisible() bool {
+	return m.showOrphans
+}
+
+// ShowSignal shows the modal for sending a signal to the selected service
+func (m *Model) ShowSignal() {
+	selected := m.sidebar.Selected()
+	if selected.Service == "" {
+		return
+	}
+	if len(process.Signals) == 0 {
+		m.statusBar.ShowAlert("Sending signals is not supported on this platform", 5*time.Second)
+		return
+	}
+	if proc := m.manager.Get(selected); proc == nil || !proc.IsRunning() {
+		m.statusBar.ShowAlert(selected.Service+" is not running", 5*time.Second)
+		return
+	}
+	m.signalModal.Show(selected, process.Signals)
+	m.signalModal.SetSize(m.width / 2)
+	m.showSignal = true
+}
+
+// HideSignal hides the signal modal
+func (m *Model) HideSignal() {
+	m.signalModal.Hide()
+	m.showSignal = false
+}
+
+// IsSignalVisible returns true if the signal modal is visible
+func (m *Model) IsSignalVisible() bool {
+	return m.showSignal
+}
+
+// ShowSearch shows the modal for searching the logs of all services
+func (m *Model) ShowSearch() {
+	m.searchModal.SetSize(m.width*3/4, m.height)
+	m.searchModal.Show()
+	m.searchModal.Search(m.logBuffer, m.sidebar.ServiceIDs())
+	m.showSearch = true
+}
+
+// HideSearch hides the search modal
+func (m *Model) HideSearch() {
+	m.searchModal.Hide()
+	m.showSearch = false
+}
+
+// IsSearchVisible returns true if the search modal is visible
+func (m *Model) IsSearchVisible() bool {
+	return m.showSearch
+}
+
+// jumpToSearchHit selects the service of a search hit and scrolls its logs
+// to the matching line
+func (m *Model) jumpToSearchHit(hit components.SearchHit) {
+	if !m.sidebar.Select(hit.Entry.ServiceID) {
+		return
+	}
+	m.updateLogPanelService()
+	m.logPanel.HideMerged()
+	m.logPanel.ScrollToEntry(hit.Index)
+	m.setFocus(FocusLogs)
+}
+
+// toggleBookmark bookmarks the line at the bottom of the logs, or removes
+// its bookmark
+func (m *Model) toggleBookmark() {
+	marked, ok := m.logPanel.ToggleBookmark()
+	switch {
+	case !ok:
+		m.statusBar.ShowAlert("No log line to bookmark", 2*time.Second)
+	case marked:
+		m.statusBar.ShowAlert("Bookmarked the last line shown", 2*time.Second)
+	default:
+		m.statusBar.ShowAlert("Removed bookmark", 2*time.Second)
+	}
+}
+
+// nextBookmark scrolls the logs to the next or previous bookmarked line
+func (m *Model) nextBookmark(back bool) {
+	if !m.logPanel.NextBookmark(back) {
+		m.statusBar.ShowAlert("No more bookmarks", 2*time.Second)
+	}
+}
+
+// nextError scrolls the logs to the next or previous error
+func (m *Model) nextError(back bool) {
+	if !m.logPanel.NextError(back) {
+		m.statusBar.ShowAlert("No more errors", 2*time.Second)
+	}
+}
+
+// ShowBookmarks shows the bookmarked lines of the logs shown
+func (m *Model) ShowBookmarks() {
+	marks := m.logPanel.Bookmarks()
+	if len(marks) == 0 {
+		m.statusBar.ShowAlert("No bookmarks, press b to bookmark the last line shown", 3*time.Second)
+		return
+	}
+	m.bookmarksModal.SetSize(m.width*3/4, m.height)
+	m.bookmarksModal.Show(marks)
+	m.showBookmarks = true
+}
+
+// HideBookmarks hides the bookmarks modal
+func (m *Model) HideBookmarks() {
+	m.bookmarksModal.Hide()
+	m.showBookmarks = false
+}
+
+// IsBookmarksVisible returns true if the bookmarks modal is visible
+func (m *Model) IsBookmarksVisible() bool {
+	return m.showBookmarks
+}
+
+// ShowPipe shows the modal to pipe the selected log lines, or all lines
+// shown, to a command
+func (m *Model) ShowPipe() {
+	entries := m.logPanel.PipeEntries()
+	if len(entries) == 0 {
+		m.statusBar.ShowAlert("No logs to pipe", 2*time.Second)
+		return
+	}
+	m.pipeEntries = entries
+	m.pipeModal.SetSize(m.width*3/4, m.height)
+	m.pipeModal.Show(len(entries))
+	m.showPipe = true
+}
+
+// HidePipe hides the pipe modal
+func (m *Model) HidePipe() {
+	m.pipeModal.Hide()
+	m.showPipe = false
+	m.pipeEntries = nil
+}
+
+// IsPipeVisible returns true if the pipe modal is visible
+func (m *Model) IsPipeVisible() bool {
+	return m.showPipe
+}
+
+// PipeLogs runs a command through the shell with the lines of entries as
+// its input, returning its output (stdout and stderr combined)
+func PipeLogs(entries []log.Entry, command string) (string, error) {
+	var input strings.Builder
+	for _, entry := range entries {
+		input.WriteString(entry.Line)
+		input.WriteString("\n")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pipeTimeout)
+	defer cancel()
+
+	cmd := process.ShellCommand(ctx, command)
+	cmd.Stdin = strings.NewReader(input.String())
+	output, err := cmd.CombinedOutput()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s", pipeTimeout)
+	}
+	return string(output), err
+}
+
+// ShowDetail shows the detail modal of the selected service
+func (m *Model) ShowDetail() {
+	selected := m.sidebar.Selected()
+	if selected.Service == "" {
+		return
+	}
+	proc := m.manager.Get(selected)
+	if proc == nil {
+		return
+	}
+	m.detailModal.Show(proc)
+	m.detailModal.SetSize(m.width / 2)
+	m.showDetail = true
+}
+
+// HideDetail hides the detail modal
+func (m *Model) HideDetail() {
+	m.detailModal.Hide()
+	m.showDetail = false
+}
+
+// IsDetailVisible returns true if the detail modal is visible
+func (m *Model) IsDetailVisible() bool {
+	return m.showDetail
+}
+
+// ShowEnv shows the environment of the selected service
+func (m *Model) ShowEnv() {
+	selected := m.sidebar.Selected()
+	if selected.Service == "" {
+		return
+	}
+	proc := m.manager.Get(selected)
+	if proc == nil {
+		return
+	}
+	m.envModal.Show(proc.ID, components.ResolveEnv(proc.Config.Env, proc.Resolved().Env))
+	m.envModal.SetSize(m.width*3/4, m.height)
+	m.showEnv = true
+}
+
+// HideEnv hides the environment modal
+func (m *Model) HideEnv() {
+	m.envModal.Hide()
+	m.showEnv = false
+}
+
+// ShowError shows why an action failed in the background, over any other
+// modal, with a suggested fix when the cause is a common one
+func (m *Model) ShowError(title string, err error) {
+	m.errorModal.Show(title, err, errorHint(err))
+	m.errorModal.SetSize(min(max(m.width/2, 50), m.width))
+	m.showError = true
+}
+
+// HideError hides the error modal
+func (m *Model) HideError() {
+	m.errorModal.Hide()
+	m.showError = false
+}
+
+// errorHint suggests a fix for common causes of a failure, or returns ""
+func errorHint(err error) string {
+	msg := err.Error()
+	switch {
+	case errors.Is(err, os.ErrPermission):
+		return "Check the permissions of the file or directory; paraler needs to write to it"
+	case errors.Is(err, os.ErrNotExist):
+		return "Check that the path exists; it may have been moved or deleted"
+	case strings.Contains(msg, "yaml:"):
+		return "Fix the syntax of the config file, then press R to reload it"
+	case strings.Contains(msg, "already exists"):
+		return "Pick a name that isn't taken"
+	case strings.Contains(msg, "cannot be empty"), strings.Contains(msg, "must not contain"):
+		return "Enter a name without '/', '#' or spaces"
+	case strings.Contains(msg, "no service selected"):
+		return "Select a service in the sidebar first"
+	case strings.Contains(msg, "not found"):
+		return "The config may have changed on disk; press R to reload it"
+	case strings.HasPrefix(msg, "project "):
+		return "Fix the config file, then press R to reload it"
+	}
+	return ""
+}
+
+// OpenURL opens the selected service in the default browser
+func (m *Model) OpenURL() tea.Cmd {
+	selected := m.sidebar.Selected()
+	proc := m.manager.Get(selected)
+	if proc == nil {
+		return nil
+	}
+	// Placeholders are left until the service first starts
+	url := proc.Resolved().BrowserURL()
+	if url == "" || strings.Contains(url, "{{") {
+		m.statusBar.ShowAlert(fmt.Sprintf("No URL for %s: set its port or url", selected), 3*time.Second)
+		return nil
+	}
+	if err := startDetached(browserCommand(url)); err != nil {
+		return m.toast(components.ToastError, fmt.Sprintf("Failed to open %s: %v", url, err))
+	}
+	return m.toast(components.ToastInfo, "Opened "+url)
+}
+
+// OpenEditor opens the working directory of the selected service in the
+// user's editor. A terminal editor takes over the screen until it exits.
+func (m *Model) OpenEditor() tea.Cmd {
+	proc := m.manager.Get(m.sidebar.Selected())
+	if proc == nil {
+		return nil
+	}
+	cmd, inTerminal, err := editorCommand(proc.Cwd)
+	if err != nil {
+		return m.toast(components.ToastError, fmt.Sprintf("Can't open an editor: %v", err))
+	}
+	if inTerminal {
+		return tea.ExecProcess(cmd, func(err error) tea.Msg {
+			return editorClosedMsg{Error: err}
+		})
+	}
+	if err := startDetached(cmd); err != nil {
+		return m.toast(components.ToastError, fmt.Sprintf("Failed to open editor: %v", err))
+	}
+	return m.toast(components.ToastInfo, "Opened "+proc.Cwd+" in the editor")
+}
+
+// UpgradeRequested returns true if the UI exited to upgrade paraler in place
+func (m *Model) UpgradeRequested() bool {
+	return m.upgradeRequested
+}
+
+// UpgradeFailed shows why an in-place upgrade failed, once the UI is back
+func (m *Model) UpgradeFailed(err error) {
+	m.upgradeRequested = false
+	m.statusBar.ShowAlert(fmt.Sprintf("Upgrade failed: %v", err), 10*time.Second)
+}
+
 // Init initializes the model
 func (m *Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.listenForOutput(),
-		m.tickHealth(),
+		m.listenForEvents(),
+		m.findOrphans(),
 	)
 }
 
@@ -390,8 +1113,27 @@ func (m *Model) Manager() *process.Manager {
 // updateLogPanelService updates the log panel to show the selected service
 func (m *Model) updateLogPanelService() {
 	selected := m.sidebar.Selected()
+	previous := m.logPanel.ServiceID()
+	if previous != selected {
+		m.logBuffer.DropHistory(previous)
+	}
 	m.logPanel.SetService(selected)
 
+	// Project headers keep the logs shown
+	if m.sidebar.IsProjectSelected() {
+		return
+	}
+
+	// The all services item shows their merged logs
+	if m.sidebar.IsAllSelected() {
+		m.logPanel.SetServiceConfig(nil)
+		m.showRunningLogs(m.logPanel)
+		return
+	}
+	if previous.Service == "" && m.logPanel.IsMerged() {
+		m.logPanel.HideMerged()
+	}
+
 	// Set service config for footer
 	if selected.Service != "" {
 		if project, ok := m.config.Projects[selected.Project]; ok {
@@ -404,38 +1146,175 @@ func (m *Model) updateLogPanelService() {
 	m.logPanel.SetServiceConfig(nil)
 }
 
-// updateLogPanelStatus updates the log panel with current service status
+// toggleCollapsed collapses or expands the selected project, remembering
+// the collapsed projects for the next session
+func (m *Model) toggleCollapsed() {
+	if m.sidebar.ToggleCollapsed() {
+		m.saveUIState()
+	}
+}
+
+// cycleSortMode switches the sidebar to the next sort mode, remembering it
+// for the next session
+func (m *Model) cycleSortMode() {
+	mode := m.sidebar.SortMode().Next()
+	m.sidebar.SetSortMode(mode, m.manager)
+	m.saveUIState()
+	m.statusBar.ShowAlert("Sorted by "+mode.String(), 2*time.Second)
+}
+
+// markUsed records that services were started or restarted, for sorting
+// recently used services first
+func (m *Model) markUsed(ids ...config.ServiceID) {
+	for _, id := range ids {
+		m.sidebar.MarkUsed(id)
+	}
+	m.saveUIState()
+}
+
+// updateLogPanelStatus updates the log panels with the current status of
+// their services
 func (m *Model) updateLogPanelStatus() {
-	selected := m.sidebar.Selected()
-	if selected.Service == "" {
-		m.logPanel.SetStatus(process.StatusStopped)
+	for _, panel := range m.logPanels {
+		if panel.ServiceID().Service == "" && m.sidebar.ServiceCount() > 0 {
+			m.showRunningLogs(panel)
+		}
+		proc := m.manager.Get(panel.ServiceID())
+		if proc != nil {
+			panel.SetStatus(proc.Status())
+			panel.SetStats(proc.Stats())
+			panel.SetHealthError(nil)
+			if proc.Health() == process.HealthUnhealthy {
+				panel.SetHealthError(proc.HealthError())
+			}
+			if proc.IsDone() {
+				panel.SetPort(0)
+			} else {
+				panel.SetPort(proc.Resolved().Port)
+			}
+		} else {
+			panel.SetStatus(process.StatusStopped)
+			panel.SetStats(process.Stats{})
+			panel.SetHealthError(nil)
+			panel.SetPort(0)
+		}
+	}
+}
+
+// showRunningLogs shows the merged logs of the running services in a log
+// panel showing all services, keeping its scroll position unless the
+// services changed
+func (m *Model) showRunningLogs(panel *components.LogPanel) {
+	var ids []config.ServiceID
+	for _, id := range m.sidebar.ServiceIDs() {
+		if proc := m.manager.Get(id); proc != nil && proc.IsRunning() {
+			ids = append(ids, id)
+		}
+	}
+	if panel.IsMerged() && slices.Equal(panel.ShownIDs(), ids) {
+		return
+	}
+	if len(ids) == 0 {
+		panel.HideMerged()
 		return
 	}
+	panel.ShowMerged(ids, m.serviceColors(ids))
+}
 
-	proc := m.manager.Get(selected)
-	if proc != nil {
-		m.logPanel.SetStatus(proc.Status())
+// serviceColors returns the configured colors of services
+func (m *Model) serviceColors(ids []config.ServiceID) map[config.ServiceID]string {
+	colors := make(map[config.ServiceID]string)
+	for _, id := range ids {
+		if service, ok := m.config.Projects[id.Project].Services[id.Service]; ok && service.Color != "" {
+			colors[id] = service.Color
+		}
+	}
+	return colors
+}
+
+// configureLogPanels applies the logs config to the log panels
+func (m *Model) configureLogPanels() {
+	components.SetTimeFormat(m.config.Logs.Layout(), m.config.Logs.Location())
+	for _, panel := range m.logPanels {
+		panel.SetStripColors(m.config.Logs.StripColors || m.noColor)
+		panel.SetTimestamps(m.config.Logs.Timestamps)
+		panel.SetLevelRules(m.config)
+	}
+}
+
+// splitLogs opens another log panel with the selected service, next to or
+// below the others
+func (m *Model) splitLogs() {
+	if len(m.logPanels) >= maxLogPanels {
+		m.statusBar.ShowAlert(fmt.Sprintf("At most %d log panels", maxLogPanels), 2*time.Second)
+		return
+	}
+	m.logPanel = components.NewLogPanel()
+	m.logPanels = append(m.logPanels, m.logPanel)
+	m.configureLogPanels()
+	m.updateLogPanelService()
+	m.calculateLayout()
+	m.setFocus(FocusLogs)
+}
+
+// closeLogPanel closes the focused log panel, unless it's the last one
+func (m *Model) closeLogPanel() {
+	if len(m.logPanels) == 1 {
+		return
+	}
+	i := slices.Index(m.logPanels, m.logPanel)
+	m.logPanels = slices.Delete(m.logPanels, i, i+1)
+	m.calculateLayout()
+	m.activateLogPanel(min(i, len(m.logPanels)-1))
+}
+
+// toggleStackedLogs switches between log panels side by side and stacked
+func (m *Model) toggleStackedLogs() {
+	m.stackedLogs = !m.stackedLogs
+	m.calculateLayout()
+}
+
+// activateLogPanel focuses a log panel and selects its service, so service
+// keys act on it and the sidebar selects its services from now on
+func (m *Model) activateLogPanel(i int) {
+	m.logPanel = m.logPanels[i]
+	if id := m.logPanel.ServiceID(); id.Service != "" {
+		m.sidebar.Select(id)
 	} else {
-		m.logPanel.SetStatus(process.StatusStopped)
+		m.sidebar.SelectAll()
 	}
+	m.setFocus(FocusLogs)
 }
 
 // setFocus sets the focus to a specific panel
 func (m *Model) setFocus(focus Focus) {
 	m.focus = focus
 	m.sidebar.SetFocused(focus == FocusSidebar)
-	m.logPanel.SetFocused(focus == FocusLogs)
+	for _, panel := range m.logPanels {
+		panel.SetFocused(focus == FocusLogs && panel == m.logPanel)
+	}
+}
+
+// modalVisible returns true if a modal is shown over the panels
+func (m *Model) modalVisible() bool {
+	return m.showPipe || m.showOrphans || m.showPortConflict || m.showSignal ||
+		m.showSearch || m.showBookmarks || m.showDetail || m.showEnv || m.showError || m.showStartAll || m.showConfirm ||
+		m.showMoveService || m.showRename || m.showEditor || m.showAddProject
 }
 
-// toggleFocus switches focus between panels
+// toggleFocus switches focus from the sidebar to each log panel in turn
+// and back
 func (m *Model) toggleFocus() {
-	if m.fullscreen {
+	i := slices.Index(m.logPanels, m.logPanel)
+	switch {
+	case m.focus == FocusSidebar:
+		m.activateLogPanel(0)
+	case i+1 < len(m.logPanels):
+		m.activateLogPanel(i + 1)
+	case m.fullscreen:
 		// In fullscreen, always focus on logs
-		return
-	}
-	if m.focus == FocusSidebar {
-		m.setFocus(FocusLogs)
-	} else {
+		m.activateLogPanel(0)
+	default:
 		m.setFocus(FocusSidebar)
 	}
 }
@@ -453,6 +1332,108 @@ func (m *Model) toggleFullscreen() {
 	m.calculateLayout()
 }
 
+// toggleDashboard shows the overview of all services instead of the
+// sidebar and logs, starting at the selected service, or hides it
+func (m *Model) toggleDashboard() {
+	m.showDashboard = !m.showDashboard
+	if m.showDashboard {
+		m.dashboard.SetServices(m.sidebar.ServiceIDs())
+		m.dashboard.Select(m.sidebar.Selected())
+	}
+}
+
+// selectDashboardService selects the service highlighted in the dashboard
+// in the sidebar, so actions on the selected service apply to it
+func (m *Model) selectDashboardService() bool {
+	id := m.dashboard.Selected()
+	if id.Service == "" || !m.sidebar.Select(id) {
+		return false
+	}
+	m.updateLogPanelService()
+	return true
+}
+
+// startFind opens the sidebar's find prompt, leaving fullscreen so it's
+// visible
+func (m *Model) startFind() {
+	if m.fullscreen {
+		m.toggleFullscreen()
+	}
+	m.setFocus(FocusSidebar)
+	m.sidebar.StartFind()
+}
+
+// toggleMergedLogs switches between the selected service's logs and a
+// timeline of the multi-selected services (or all services) interleaved
+func (m *Model) toggleMergedLogs() {
+	// The all services item shows the merged logs already
+	if m.sidebar.IsAllSelected() {
+		return
+	}
+	if m.logPanel.IsMerged() {
+		m.logPanel.HideMerged()
+		return
+	}
+
+	ids := m.sidebar.ServiceIDs()
+	if m.sidebar.HasMultiSelect() {
+		// Keep the sidebar order, so services get the same colors each time
+		selected := make(map[config.ServiceID]bool)
+		for _, id := range m.sidebar.GetMultiSelected() {
+			selected[id] = true
+		}
+		var filtered []config.ServiceID
+		for _, id := range ids {
+			if selected[id] {
+				filtered = append(filtered, id)
+			}
+		}
+		ids = filtered
+	}
+	if len(ids) == 0 {
+		return
+	}
+	m.logPanel.ShowMerged(ids, m.serviceColors(ids))
+}
+
+// cycleLevelFilter switches the log levels shown for the selected service
+func (m *Model) cycleLevelFilter() {
+	selected := m.sidebar.Selected()
+	if selected.Service == "" {
+		return
+	}
+	filter := m.logPanel.CycleLevelFilter(selected)
+	m.statusBar.ShowAlert(fmt.Sprintf("%s logs: %s", selected.Service, filter), 2*time.Second)
+}
+
+// acknowledgeErrors marks the errors of services as seen, so the sidebar
+// only counts new ones
+func (m *Model) acknowledgeErrors(ids []config.ServiceID) {
+	for _, id := range ids {
+		m.logBuffer.AcknowledgeErrors(id)
+	}
+}
+
+// acknowledgeAllErrors marks the errors of every service as seen
+func (m *Model) acknowledgeAllErrors() {
+	m.acknowledgeErrors(m.sidebar.ServiceIDs())
+	m.statusBar.ShowAlert("Errors acknowledged", 2*time.Second)
+}
+
+// toggleStderrOnly switches between only the stderr lines of the selected
+// service and all its lines
+func (m *Model) toggleStderrOnly() {
+	selected := m.sidebar.Selected()
+	if selected.Service == "" {
+		return
+	}
+	stream := "all output"
+	if m.logPanel.ToggleStderrOnly(selected) {
+		stream = "stderr only"
+	}
+	m.statusBar.ShowAlert(fmt.Sprintf("%s logs: %s", selected.Service, stream), 2*time.Second)
+}
+
 // IsFullscreen returns true if in fullscreen mode
 func (m *Model) IsFullscreen() bool {
 	return m.fullscreen
@@ -463,15 +1444,22 @@ func (m *Model) startSelected() tea.Cmd {
 	// Check for multi-select
 	if m.sidebar.HasMultiSelect() {
 		ids := m.sidebar.GetMultiSelected()
+		m.markUsed(ids...)
 		return func() tea.Msg {
 			for _, id := range ids {
 				m.logBuffer.Clear(id) // Clear old logs/errors
 				m.manager.Start(id)
 			}
 			m.sidebar.ClearMultiSelect()
-			return ProcessStatusChangedMsg{}
+			return nil
 		}
 	}
+	if m.sidebar.IsAllSelected() {
+		return m.startAll()
+	}
+	if m.sidebar.IsProjectSelected() {
+		return m.startProject(m.sidebar.SelectedProjectName())
+	}
 
 	selected := m.sidebar.Selected()
 	if selected.Service == "" {
@@ -485,10 +1473,11 @@ func (m *Model) startSelected() tea.Cmd {
 		return nil
 	}
 
+	m.markUsed(selected)
 	return func() tea.Msg {
 		m.logBuffer.Clear(selected) // Clear old logs/errors
 		m.manager.Start(selected)
-		return ProcessStatusChangedMsg{}
+		return nil
 	}
 }
 
@@ -502,9 +1491,17 @@ func (m *Model) stopSelected() tea.Cmd {
 				m.manager.Stop(id)
 			}
 			m.sidebar.ClearMultiSelect()
-			return ProcessStatusChangedMsg{}
+			return nil
 		}
 	}
+	if m.sidebar.IsAllSelected() {
+		m.ShowConfirmStopAll()
+		return nil
+	}
+	if m.sidebar.IsProjectSelected() {
+		m.ShowConfirmStopProject(m.sidebar.SelectedProjectName())
+		return nil
+	}
 
 	selected := m.sidebar.Selected()
 	if selected.Service == "" {
@@ -512,7 +1509,7 @@ func (m *Model) stopSelected() tea.Cmd {
 	}
 	return func() tea.Msg {
 		m.manager.Stop(selected)
-		return ProcessStatusChangedMsg{}
+		return nil
 	}
 }
 
@@ -521,40 +1518,148 @@ func (m *Model) restartSelected() tea.Cmd {
 	// Check for multi-select
 	if m.sidebar.HasMultiSelect() {
 		ids := m.sidebar.GetMultiSelected()
+		m.markUsed(ids...)
 		return func() tea.Msg {
 			for _, id := range ids {
 				m.logBuffer.Clear(id) // Clear old logs/errors
 				m.manager.Restart(id)
 			}
 			m.sidebar.ClearMultiSelect()
-			return ProcessStatusChangedMsg{}
+			return nil
 		}
 	}
+	if m.sidebar.IsAllSelected() {
+		return m.restartAll()
+	}
+	if m.sidebar.IsProjectSelected() {
+		return m.restartProject(m.sidebar.SelectedProjectName())
+	}
 
 	selected := m.sidebar.Selected()
 	if selected.Service == "" {
 		return nil
 	}
+	m.markUsed(selected)
 	return func() tea.Msg {
 		m.logBuffer.Clear(selected) // Clear old logs/errors
 		m.manager.Restart(selected)
-		return ProcessStatusChangedMsg{}
+		return nil
 	}
 }
 
-// startAll starts all services
+// togglePauseSelected pauses the selected service, or resumes it if it is
+// paused
+func (m *Model) togglePauseSelected() tea.Cmd {
+	selected := m.sidebar.Selected()
+	if selected.Service == "" {
+		return nil
+	}
+
+	var running, paused bool
+	for _, proc := range m.manager.Instances(selected) {
+		switch proc.Status() {
+		case process.StatusRunning:
+			running = true
+		case process.StatusPaused:
+			paused = true
+		}
+	}
+	if !running && !paused {
+		m.statusBar.ShowAlert(selected.Service+" is not running", 5*time.Second)
+		return nil
+	}
+
+	return func() tea.Msg {
+		var err error
+		if paused {
+			err = m.manager.Resume(selected)
+		} else {
+			err = m.manager.Pause(selected)
+		}
+		if err != nil {
+			return PauseErrorMsg{Error: err}
+		}
+		return nil
+	}
+}
+
+// startAll starts all services, showing their progress in dependency order
 func (m *Model) startAll() tea.Cmd {
+	var ids []config.ServiceID
+	for _, id := range m.manager.StartOrder() {
+		proc := m.manager.Get(id)
+		if proc == nil || proc.Status() == process.StatusRunning || proc.Status() == process.StatusSucceeded {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		m.statusBar.ShowAlert("All services are running", 3*time.Second)
+		return nil
+	}
+
+	m.startAllModal.Show(ids)
+	m.startAllModal.SetSize(m.width/2, m.height)
+	m.showStartAll = true
+
+	manager := m.manager
 	return func() tea.Msg {
-		m.manager.StartAll()
-		return ProcessStatusChangedMsg{}
+		manager.StartAll()
+		return startAllDoneMsg{}
 	}
 }
 
+// HideStartAll hides the start-all progress; services keep starting
+func (m *Model) HideStartAll() {
+	m.startAllModal.Hide()
+	m.showStartAll = false
+}
+
 // stopAll stops all services
 func (m *Model) stopAll() tea.Cmd {
 	return func() tea.Msg {
 		m.manager.StopAll()
-		return ProcessStatusChangedMsg{}
+		return nil
+	}
+}
+
+// restartAll restarts all services
+func (m *Model) restartAll() tea.Cmd {
+	ids := m.sidebar.ServiceIDs()
+	return func() tea.Msg {
+		for _, id := range ids {
+			m.logBuffer.Clear(id) // Clear old logs/errors
+		}
+		m.manager.RestartAll()
+		return nil
+	}
+}
+
+// startProject starts the services of a project
+func (m *Model) startProject(project string) tea.Cmd {
+	return func() tea.Msg {
+		m.manager.StartProject(project)
+		return nil
+	}
+}
+
+// stopProject stops the services of a project
+func (m *Model) stopProject(project string) tea.Cmd {
+	return func() tea.Msg {
+		m.manager.StopProject(project)
+		return nil
+	}
+}
+
+// restartProject restarts the services of a project
+func (m *Model) restartProject(project string) tea.Cmd {
+	procs := m.manager.GetByProject(project)
+	return func() tea.Msg {
+		for _, proc := range procs {
+			m.logBuffer.Clear(proc.ID) // Clear old logs/errors
+		}
+		m.manager.RestartProject(project)
+		return nil
 	}
 }
 
@@ -566,23 +1671,36 @@ func (m *Model) clearLogs() {
 	}
 }
 
+// clearAllLogs clears the logs of every service
+func (m *Model) clearAllLogs() {
+	m.logBuffer.ClearAll()
+	m.statusBar.ShowAlert("Cleared all logs", 2*time.Second)
+}
+
+// SetInline runs the UI in height rows below the prompt, with a narrower
+// sidebar, instead of filling the alternate screen
+func (m *Model) SetInline(height int) {
+	m.inlineHeight = height
+}
+
+// SetAccessible spells out states shown by a color or a symbol alone and
+// stops spinners, for screen readers and limited terminals
+func (m *Model) SetAccessible() {
+	components.SetAccessible()
+}
+
 // calculateLayout calculates panel sizes based on terminal dimensions
 func (m *Model) calculateLayout() {
 	// Status bar height
 	statusHeight := 1
-	if m.showHelp {
-		statusHeight = 10
-	}
 
 	// Panel heights (subtract status bar)
 	panelHeight := m.height - statusHeight - 1
 
-	if m.fullscreen {
-		// Fullscreen mode: logs take full width
-		m.logPanel.SetSize(m.width, panelHeight)
-	} else {
+	logArea := image.Rect(0, 0, m.width, panelHeight)
+	if !m.fullscreen {
 		// Normal mode: sidebar + logs
-		// Sidebar takes ~25% width, min 20, max 40
+		// Sidebar takes ~25% width, min 20, max 40 (max 28 inline)
 		sidebarWidth := m.width / 4
 		if sidebarWidth < 20 {
 			sidebarWidth = 20
@@ -590,15 +1708,38 @@ func (m *Model) calculateLayout() {
 		if sidebarWidth > 40 {
 			sidebarWidth = 40
 		}
+		if m.inlineHeight > 0 && sidebarWidth > 28 {
+			sidebarWidth = 28
+		}
 
-		// Log panel takes remaining width
-		logWidth := m.width - sidebarWidth - 1
+		// Log panels take remaining width
+		logArea.Min.X = sidebarWidth
+		logArea.Max.X = m.width - 1
 
 		m.sidebar.SetSize(sidebarWidth, panelHeight)
-		m.logPanel.SetSize(logWidth, panelHeight)
+		m.sidebarWidth = sidebarWidth
+	}
+
+	// Split log panels share the width, or the height when stacked
+	m.logBounds = m.logBounds[:0]
+	n := len(m.logPanels)
+	for i, panel := range m.logPanels {
+		bounds := logArea
+		if m.stackedLogs {
+			bounds.Min.Y = i * logArea.Dy() / n
+			bounds.Max.Y = (i + 1) * logArea.Dy() / n
+		} else {
+			bounds.Min.X = logArea.Min.X + i*logArea.Dx()/n
+			bounds.Max.X = logArea.Min.X + (i+1)*logArea.Dx()/n
+		}
+		panel.SetSize(bounds.Dx(), bounds.Dy())
+		m.logBounds = append(m.logBounds, bounds)
 	}
 
+	m.dashboard.SetSize(m.width, panelHeight)
+	m.helpScreen.SetSize(m.width, m.height)
 	m.statusBar.SetWidth(m.width)
+	m.toasts.SetWidth(max(m.width/2, 40))
 }
 
 // HotReload reloads the config file and updates the UI
@@ -611,15 +1752,18 @@ func (m *Model) HotReload() error {
 
 	// Stop all running processes
 	m.manager.StopAll()
+	m.manager.Close()
+	m.HideDetail()
 
 	// Update config
 	m.config = newConfig
+	m.configureLogPanels()
 
 	// Recreate manager with new config
-	m.manager = process.NewManager(m.config)
+	m.manager = newManager(m.config, m.configPath, m.logBuffer)
 
 	// Rebuild sidebar
-	m.sidebar = components.NewSidebar(m.config)
+	m.sidebar = newSidebar(m.config, m.configPath, m.manager)
 
 	// Recalculate layout
 	m.calculateLayout()
@@ -633,15 +1777,14 @@ func (m *Model) HotReload() error {
 	return nil
 }
 
-// ExportLogs exports logs for the selected service to a file
-func (m *Model) ExportLogs() (string, error) {
-	selected := m.sidebar.Selected()
-	if selected.Service == "" {
-		return "", fmt.Errorf("no service selected")
-	}
+// SetExportFormat sets the format logs are exported in
+func (m *Model) SetExportFormat(format log.ExportFormat) {
+	m.exportFormat = format
+}
 
-	// Get logs for service
-	entries := m.logBuffer.Get(selected)
+// ExportLogs exports entries to a file in the export format, naming it
+// after name
+func (m *Model) ExportLogs(entries []log.Entry, name string) (string, error) {
 	if len(entries) == 0 {
 		return "", fmt.Errorf("no logs to export")
 	}
@@ -657,8 +1800,9 @@ func (m *Model) ExportLogs() (string, error) {
 	}
 
 	// Generate filename
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	filename := fmt.Sprintf("%s_%s_%s.log", selected.Project, selected.Service, timestamp)
+	loc := m.config.Logs.Location()
+	timestamp := time.Now().In(loc).Format("2006-01-02_15-04-05")
+	filename := fmt.Sprintf("%s_%s%s", name, timestamp, m.exportFormat.Extension())
 	filepath := filepath.Join(logsDir, filename)
 
 	// Write logs
@@ -666,11 +1810,12 @@ func (m *Model) ExportLogs() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	defer file.Close()
-
-	for _, entry := range entries {
-		line := fmt.Sprintf("[%s] %s\n", entry.Timestamp.Format("15:04:05"), entry.Line)
-		file.WriteString(line)
+	if err := log.Export(file, entries, m.exportFormat, loc); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
 	}
 
 	return filepath, nil
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/paralerdev/paraler/internal/config"
+	"github.com/paralerdev/paraler/internal/process"
+	"github.com/paralerdev/paraler/internal/ui/components"
+)
+
+// uiState is the state of the UI kept between sessions
+type uiState struct {
+	Collapsed []string             `json:"collapsed,omitempty"` // Collapsed projects
+	Sort      string               `json:"sort,omitempty"`      // Sidebar sort mode
+	LastUsed  map[string]time.Time `json:"last_used,omitempty"` // When services were last started, by ID
+}
+
+// isEmpty returns true if there is no state to keep
+func (s uiState) isEmpty() bool {
+	return len(s.Collapsed) == 0 && s.Sort == "" && len(s.LastUsed) == 0
+}
+
+// uiStatePath returns the UI state file used for a config file
+func uiStatePath(configPath string) string {
+	return filepath.Join(config.StateDir(), "ui-"+config.StateKey(configPath)+".json")
+}
+
+// readUIState reads the UI state, returning an empty state if there is none
+func readUIState(path string) (uiState, error) {
+	var state uiState
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return state, err
+	}
+	err = json.Unmarshal(data, &state)
+	return state, err
+}
+
+// writeUIState writes the UI state, removing the file when there is
+// nothing to keep
+func writeUIState(path string, state uiState) error {
+	if state.isEmpty() {
+		err := os.Remove(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write atomically so a crash mid-write can't corrupt the file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// newSidebar creates the sidebar for a config, sorted and with projects
+// collapsed as in the last session
+func newSidebar(cfg *config.Config, configPath string, manager *process.Manager) *components.Sidebar {
+	sidebar := components.NewSidebar(cfg)
+	if state, err := readUIState(uiStatePath(configPath)); err == nil {
+		sidebar.SetLastUsed(state.LastUsed)
+		sidebar.SetSortMode(components.ParseSortMode(state.Sort), manager)
+		sidebar.SetCollapsed(state.Collapsed)
+	}
+	return sidebar
+}
+
+// saveUIState saves the state of the UI for the next session
+func (m *Model) saveUIState() {
+	state := uiState{
+		Collapsed: m.sidebar.Collapsed(),
+		LastUsed:  m.sidebar.LastUsed(),
+	}
+	if mode := m.sidebar.SortMode(); mode != components.SortAlphabetical {
+		state.Sort = mode.String()
+	}
+	if err := writeUIState(uiStatePath(m.configPath), state); err != nil {
+		m.statusBar.ShowAlert(fmt.Sprintf("Failed to save the UI state: %v", err), 3*time.Second)
+	}
+}
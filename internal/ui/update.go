@@ -1,13 +1,20 @@
 package ui
 
 import (
+	"fmt"
+	"image"
+	"os"
 	"os/exec"
 	"regexp"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/paralerdev/paraler/internal/config"
 	"github.com/paralerdev/paraler/internal/log"
+	"github.com/paralerdev/paraler/internal/notify"
 	"github.com/paralerdev/paraler/internal/process"
 	"github.com/paralerdev/paraler/internal/ui/components"
 	"github.com/charmbracelet/bubbles/key"
@@ -16,16 +23,22 @@ import (
 
 // Messages
 
-// OutputMsg is sent when process output is received
+// OutputMsg is sent when process output is received, with the lines
+// printed since the last one
 type OutputMsg struct {
-	Line process.OutputLine
+	Lines []process.OutputLine
 }
 
-// ProcessStatusChangedMsg is sent when a process status changes
-type ProcessStatusChangedMsg struct{}
+// EventMsg is sent when the process manager publishes an event
+type EventMsg struct {
+	Event process.Event
+}
 
-// HealthTickMsg is sent periodically to check health
-type HealthTickMsg struct{}
+// managerClosedMsg is sent when a listener's manager was closed, e.g.
+// because the config was reloaded
+type managerClosedMsg struct {
+	manager *process.Manager
+}
 
 // ProjectScannedMsg is sent when project scanning is complete
 type ProjectScannedMsg struct{}
@@ -58,24 +71,196 @@ type LogsExportErrorMsg struct {
 	Error error
 }
 
-// listenForOutput returns a command that listens for process output
+// LogsPipedMsg is sent when a command logs were piped to exits
+type LogsPipedMsg struct {
+	Output string
+	Error  error
+}
+
+// SignalErrorMsg is sent when sending a signal to a service fails
+type SignalErrorMsg struct {
+	Error error
+}
+
+// PauseErrorMsg is sent when pausing or resuming a service fails
+type PauseErrorMsg struct {
+	Error error
+}
+
+// shutdownTickMsg refreshes the shutdown screen
+type shutdownTickMsg struct{}
+
+// spinnerTickMsg moves the spinners of starting and stopping services
+type spinnerTickMsg struct{}
+
+// startAllDoneMsg is sent once starting all services is over
+type startAllDoneMsg struct{}
+
+// shutdownDoneMsg is sent once all services were stopped on quit
+type shutdownDoneMsg struct{}
+
+// toastExpiredMsg is sent when a toast's time is up
+type toastExpiredMsg struct{}
+
+// editorClosedMsg is sent when an editor run in the terminal exits
+type editorClosedMsg struct {
+	Error error
+}
+
+// OrphansFoundMsg is sent when processes from a previous session are found
+type OrphansFoundMsg struct {
+	Orphans []process.ProcessRecord
+}
+
+const (
+	// outputInterval is the shortest time between two updates with output,
+	// so services flooding output redraw the UI at most 30 times a second
+	outputInterval = time.Second / 30
+
+	// maxOutputBatch caps the lines handled in one update
+	maxOutputBatch = 5000
+)
+
+// listenForOutput returns a command that listens for process output. Lines
+// printed within outputInterval of the last batch come in the next one,
+// read as they're printed so the output channel doesn't fill up and drop
+// lines meanwhile.
 func (m *Model) listenForOutput() tea.Cmd {
+	manager := m.manager
+	next := m.lastOutput.Add(outputInterval)
 	return func() tea.Msg {
-		line, ok := <-m.manager.OutputChannel()
-		if !ok {
+		var lines []process.OutputLine
+		select {
+		case line := <-manager.OutputChannel():
+			lines = append(lines, line)
+		case <-manager.Done():
+			return managerClosedMsg{manager: manager}
+		}
+
+		timer := time.NewTimer(max(time.Until(next), 0))
+		defer timer.Stop()
+		for len(lines) < maxOutputBatch {
+			select {
+			case line := <-manager.OutputChannel():
+				lines = append(lines, line)
+			case <-timer.C:
+				return OutputMsg{Lines: lines}
+			}
+		}
+		return OutputMsg{Lines: lines}
+	}
+}
+
+// listenForEvents returns a command that listens for process manager events
+func (m *Model) listenForEvents() tea.Cmd {
+	manager := m.manager
+	return func() tea.Msg {
+		select {
+		case e := <-manager.Events():
+			return EventMsg{Event: e}
+		case <-manager.Done():
 			return nil
 		}
-		return OutputMsg{Line: line}
 	}
 }
 
-// tickHealth returns a command for periodic health checks
-func (m *Model) tickHealth() tea.Cmd {
-	return tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
-		return HealthTickMsg{}
+// findOrphans returns a command that looks for processes left running by a
+// previous session
+func (m *Model) findOrphans() tea.Cmd {
+	return func() tea.Msg {
+		return OrphansFoundMsg{Orphans: m.manager.FindOrphans()}
+	}
+}
+
+// shutdown stops all services before quitting, showing their progress
+func (m *Model) shutdown() tea.Cmd {
+	var active []*process.Process
+	for _, p := range m.manager.All() {
+		if !p.IsDone() {
+			active = append(active, p)
+		}
+	}
+	if len(active) == 0 {
+		m.manager.Shutdown()
+		return tea.Quit
+	}
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].ID.String() < active[j].ID.String()
+	})
+
+	m.shuttingDown = true
+	m.shutdownScreen.Start(active)
+
+	manager := m.manager
+	return tea.Batch(func() tea.Msg {
+		manager.Shutdown()
+		return shutdownDoneMsg{}
+	}, tickShutdown())
+}
+
+// tickShutdown refreshes the shutdown screen so countdowns keep moving
+func tickShutdown() tea.Cmd {
+	return tea.Tick(250*time.Millisecond, func(time.Time) tea.Msg {
+		return shutdownTickMsg{}
+	})
+}
+
+// tickSpinner moves the spinners to their next frame
+func tickSpinner() tea.Cmd {
+	return tea.Tick(components.SpinnerInterval, func(time.Time) tea.Msg {
+		return spinnerTickMsg{}
+	})
+}
+
+// spin keeps the spinners moving while services start or stop, and stops
+// ticking once none do
+func (m *Model) spin() tea.Cmd {
+	if m.spinning {
+		return nil
+	}
+	if m.startAllModal.IsRunning() {
+		m.spinning = true
+		return tickSpinner()
+	}
+	for _, p := range m.manager.All() {
+		if components.Spins(p.Status()) {
+			m.spinning = true
+			return tickSpinner()
+		}
+	}
+	return nil
+}
+
+// toast shows a toast and dismisses it once its time is up
+func (m *Model) toast(level components.ToastLevel, text string) tea.Cmd {
+	m.toasts.Push(level, text)
+	return tea.Tick(level.Duration(), func(time.Time) tea.Msg {
+		return toastExpiredMsg{}
 	})
 }
 
+// alertCrashLoop shows an alert with the last error lines of a service
+// that is crash-looping and raises a desktop notification
+func (m *Model) alertCrashLoop(id config.ServiceID) tea.Cmd {
+	title := fmt.Sprintf("%s is crash-looping", id.String())
+	lines := m.logBuffer.LastErrors(id, 3)
+
+	alert := title + ", auto-restart disabled"
+	if len(lines) > 0 {
+		alert += ": " + lines[len(lines)-1]
+	}
+	m.statusBar.ShowAlert(alert, 15*time.Second)
+
+	body := strings.Join(lines, "\n")
+	if body == "" {
+		body = "Auto-restart disabled"
+	}
+	return func() tea.Msg {
+		notify.Send(title, body)
+		return nil
+	}
+}
+
 // Update handles all messages
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
@@ -87,71 +272,280 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 
+	case tea.MouseMsg:
+		if cmd := m.handleMouseMsg(msg); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		if m.inlineHeight > 0 {
+			m.height = min(msg.Height, m.inlineHeight)
+		}
 		m.calculateLayout()
 		m.ready = true
 
 	case OutputMsg:
-		// Add to log buffer
-		entry := log.Entry{
-			ServiceID: msg.Line.ServiceID,
-			Line:      msg.Line.Line,
-			IsStderr:  msg.Line.IsStderr,
-			Timestamp: msg.Line.Timestamp,
-		}
-		m.logBuffer.Add(entry)
-
-		// Check for EADDRINUSE error (port already in use)
-		if port := parsePortFromEADDRINUSE(msg.Line.Line); port > 0 {
-			// Only show if this is the currently selected service
-			if msg.Line.ServiceID == m.sidebar.Selected() && !m.showPortConflict {
-				conflict := m.manager.CheckPortAvailability(msg.Line.ServiceID)
-				if conflict == nil {
-					// Port wasn't in config, create conflict info from detected port
-					status := process.GetPortStatus(port)
-					conflict = &process.PortConflictInfo{
-						Port:            port,
-						IsParalerService: false,
-						ExternalPID:     status.PID,
-						ExternalProcess: status.Process,
-						ExternalCommand: status.Command,
-					}
-				}
-				m.ShowPortConflict(msg.Line.ServiceID, conflict)
-			}
+		for _, line := range msg.Lines {
+			m.handleOutput(line)
 		}
 
 		// Continue listening
+		m.lastOutput = time.Now()
 		cmds = append(cmds, m.listenForOutput())
 
-	case ProcessStatusChangedMsg:
-		// Status changed, UI will update automatically
+	case EventMsg:
+		if cmd := m.handleEvent(msg.Event); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+		cmds = append(cmds, m.listenForEvents())
+
+	case managerClosedMsg:
+		// The manager was replaced, so listen to the new one
+		if msg.manager != m.manager {
+			cmds = append(cmds, m.listenForOutput(), m.listenForEvents())
+		}
+
+	case LogsPipedMsg:
+		if m.showPipe && m.pipeModal.IsRunning() {
+			m.pipeModal.SetResult(msg.Output, msg.Error)
+		}
+
+	case editorClosedMsg:
+		if msg.Error != nil {
+			cmds = append(cmds, m.toast(components.ToastError, fmt.Sprintf("Editor failed: %v", msg.Error)))
+		}
+
+	case SignalErrorMsg:
+		cmds = append(cmds, m.toast(components.ToastError, fmt.Sprintf("Failed to send signal: %v", msg.Error)))
+
+	case PauseErrorMsg:
+		cmds = append(cmds, m.toast(components.ToastError, fmt.Sprintf("Failed to pause/resume: %v", msg.Error)))
+
+	case LogsExportedMsg:
+		m.statusBar.SetActivity("")
+		cmds = append(cmds, m.toast(components.ToastSuccess, "Exported logs to "+msg.Path))
+
+	case LogsExportErrorMsg:
+		m.statusBar.SetActivity("")
+		m.ShowError("Failed to export logs", msg.Error)
+
+	case ConfigReloadedMsg:
+		m.statusBar.SetActivity("")
+		cmds = append(cmds, m.toast(components.ToastSuccess, "Config reloaded"))
+
+	case ConfigReloadErrorMsg:
+		m.statusBar.SetActivity("")
+		m.ShowError("Failed to reload config", msg.Error)
+
+	case ProjectAddedMsg:
+		cmds = append(cmds, m.toast(components.ToastSuccess, "Added project "+msg.Name))
+
+	case ProjectAddErrorMsg:
+		m.ShowError("Failed to add project", msg.Error)
+
+	case ProjectRenamedMsg:
+		cmds = append(cmds, m.toast(components.ToastSuccess, fmt.Sprintf("Renamed project %s to %s", msg.OldName, msg.NewName)))
 
-	case HealthTickMsg:
-		// Run health checks and auto-restart
-		m.manager.CheckHealth()
-		m.manager.CheckAutoRestart()
-		// Continue health ticks
-		cmds = append(cmds, m.tickHealth())
+	case ServiceRenamedMsg:
+		cmds = append(cmds, m.toast(components.ToastSuccess, fmt.Sprintf("Renamed service %s to %s", msg.OldName, msg.NewName)))
+
+	case RenameErrorMsg:
+		m.ShowError("Failed to rename", msg.Error)
+
+	case ServiceMovedMsg:
+		cmds = append(cmds, m.toast(components.ToastSuccess, fmt.Sprintf("Moved %s to %s", msg.Service, msg.ToProject)))
+
+	case ServiceMoveErrorMsg:
+		m.ShowError("Failed to move service", msg.Error)
+
+	case ServiceDeletedMsg:
+		cmds = append(cmds, m.toast(components.ToastInfo, fmt.Sprintf("Deleted service %s/%s", msg.Project, msg.Service)))
+
+	case ProjectDeletedMsg:
+		cmds = append(cmds, m.toast(components.ToastInfo, "Deleted project "+msg.Name))
+
+	case toastExpiredMsg:
+		m.toasts.Prune(time.Now())
+
+	case OrphansFoundMsg:
+		if len(msg.Orphans) > 0 {
+			m.ShowOrphans(msg.Orphans)
+		}
+
+	case shutdownTickMsg:
+		cmds = append(cmds, tickShutdown())
+
+	case spinnerTickMsg:
+		m.spinning = false
+		m.spinnerTick++
+		m.sidebar.SetSpinner(m.spinnerTick)
+		for _, panel := range m.logPanels {
+			panel.SetSpinner(m.spinnerTick)
+		}
+		m.startAllModal.SetSpinner(m.spinnerTick)
+		m.startAllModal.Refresh(m.manager)
+
+	case startAllDoneMsg:
+		m.startAllModal.Finish(m.manager)
+
+	case shutdownDoneMsg:
+		return m, tea.Quit
+
+	}
+
+	// Errors in the logs being looked at have been seen
+	if m.focus == FocusLogs {
+		m.acknowledgeErrors(m.logPanel.ShownIDs())
+	}
+
+	if cmd := m.spin(); cmd != nil {
+		cmds = append(cmds, cmd)
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
+// handleOutput adds a line of output to the logs
+func (m *Model) handleOutput(line process.OutputLine) {
+	entry := log.Entry{
+		ServiceID: line.ServiceID,
+		Line:      line.Line,
+		IsStderr:  line.IsStderr,
+		Timestamp: line.Timestamp,
+	}
+	m.logBuffer.Add(entry)
+	m.persistLog(entry)
+	m.forwardLog(entry)
+
+	// Check for EADDRINUSE error (port already in use)
+	if port := parsePortFromEADDRINUSE(line.Line); port > 0 {
+		// Only show if this is the currently selected service
+		if line.ServiceID == m.sidebar.Selected() && !m.showPortConflict {
+			conflict := m.manager.CheckPortAvailability(line.ServiceID)
+			if conflict == nil {
+				// Port wasn't in config, create conflict info from detected port
+				status := process.GetPortStatus(port)
+				conflict = &process.PortConflictInfo{
+					Port:            port,
+					IsParalerService: false,
+					ExternalPID:     status.PID,
+					ExternalProcess: status.Process,
+					ExternalCommand: status.Command,
+				}
+			}
+			m.ShowPortConflict(line.ServiceID, conflict)
+		}
+	}
+}
+
+// handleEvent reacts to a process manager event. Every event triggers a
+// re-render, so status and health changes show up immediately.
+func (m *Model) handleEvent(e process.Event) tea.Cmd {
+	switch e := e.(type) {
+	case process.CrashLoopDetected:
+		return m.alertCrashLoop(e.ID)
+
+	case process.StatusChanged:
+		m.sidebar.Resort(m.manager)
+		if e.New != process.StatusFailed {
+			return nil
+		}
+		proc := m.manager.Get(e.ID)
+		if proc == nil {
+			return nil
+		}
+		if proc.Config.IsTask() {
+			return m.toast(components.ToastError, fmt.Sprintf("%s failed (exit code %d)", e.ID.String(), proc.ExitCode()))
+		}
+		if e.Old == process.StatusRunning || e.Old == process.StatusPaused {
+			return m.toast(components.ToastError, fmt.Sprintf("%s crashed (exit code %d)", e.ID.String(), proc.ExitCode()))
+		}
+		return m.toast(components.ToastError, fmt.Sprintf("%s failed to start", e.ID.String()))
+
+	case process.PortReassigned:
+		m.statusBar.ShowAlert(fmt.Sprintf("%s: port %d in use, started on %d", e.ID.String(), e.From, e.To), 5*time.Second)
+
+	case process.RestartScheduled:
+		m.statusBar.ShowAlert(fmt.Sprintf("Restarting %s (attempt %d)", e.ID.String(), e.Attempt), 3*time.Second)
+
+	case process.OutputDropped:
+		if proc := m.manager.Get(e.ID); proc != nil {
+			if n := proc.TakeDroppedLines(); n > 0 {
+				m.logBuffer.Add(log.Entry{
+					ServiceID: e.ID,
+					Line:      fmt.Sprintf("⚠ %d output lines dropped", n),
+					Timestamp: time.Now(),
+				})
+			}
+		}
+	}
+	return nil
+}
+
 // handleKeyMsg handles keyboard input
 func (m *Model) handleKeyMsg(msg tea.KeyMsg) tea.Cmd {
+	// Exit waits for services to stop
+	if m.shuttingDown {
+		return nil
+	}
+
+	// If error modal is visible, it goes before anything else
+	if m.showError {
+		return m.handleErrorKeys(msg)
+	}
+
 	// If in copy mode, handle copy mode keys first
 	if m.logPanel.IsCopyMode() {
 		return m.handleCopyModeKeys(msg)
 	}
 
+	// If pipe modal is visible, handle its input
+	if m.showPipe {
+		return m.handlePipeKeys(msg)
+	}
+
+	// If orphaned processes modal is visible, handle its input
+	if m.showOrphans {
+		return m.handleOrphanKeys(msg)
+	}
+
 	// If port conflict modal is visible, handle its input
 	if m.showPortConflict {
 		return m.handlePortConflictKeys(msg)
 	}
 
+	// If signal modal is visible, handle its input
+	if m.showSignal {
+		return m.handleSignalKeys(msg)
+	}
+
+	// If search modal is visible, handle its input
+	if m.showSearch {
+		return m.handleSearchKeys(msg)
+	}
+
+	// If bookmarks modal is visible, handle its input
+	if m.showBookmarks {
+		return m.handleBookmarksKeys(msg)
+	}
+
+	// If detail modal is visible, handle its input
+	if m.showDetail {
+		return m.handleDetailKeys(msg)
+	}
+
+	// If environment modal is visible, handle its input
+	if m.showEnv {
+		return m.handleEnvKeys(msg)
+	}
+
+	// If start-all progress is visible, handle its input
+	if m.showStartAll {
+		return m.handleStartAllKeys(msg)
+	}
+
 	// If confirm modal is visible, handle its input
 	if m.showConfirm {
 		return m.handleConfirmKeys(msg)
@@ -167,32 +561,68 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) tea.Cmd {
 		return m.handleRenameKeys(msg)
 	}
 
+	// If service editor is visible, handle its input
+	if m.showEditor {
+		return m.handleEditorKeys(msg)
+	}
+
 	// If add project modal is visible, handle its input
 	if m.showAddProject {
 		return m.handleAddProjectKeys(msg)
 	}
 
+	// If finding a service, handle the find prompt's input
+	if m.sidebar.IsFinding() {
+		return m.handleFindInput(msg)
+	}
+
 	// If in filter mode, handle filter input
 	if m.logPanel.IsFiltering() {
 		return m.handleFilterInput(msg)
 	}
 
-	// If showing help, any key closes it
+	// If showing help, handle its keys
 	if m.showHelp {
-		m.showHelp = false
-		m.calculateLayout()
+		return m.handleHelpKeys(msg)
+	}
+
+	// If the dashboard is shown, handle its keys
+	if m.showDashboard {
+		return m.handleDashboardKeys(msg)
+	}
+
+	// Digits make a count for the next motion, as in vim's 5j; 0 only
+	// continues one, as it toggles the dashboard
+	if s := msg.String(); len(s) == 1 && s[0] >= '0' && s[0] <= '9' && (s != "0" || m.count > 0) {
+		m.count = min(m.count*10+int(s[0]-'0'), maxCount)
 		return nil
 	}
+	if count := m.count; count > 0 {
+		m.count = 0
+		if m.isMotion(msg) {
+			cmds := make([]tea.Cmd, 0, count)
+			for range count {
+				cmds = append(cmds, m.handleKeyMsg(msg))
+			}
+			return tea.Batch(cmds...)
+		}
+	}
 
 	// Global keys
 	switch {
 	case key.Matches(msg, m.keys.Quit):
-		m.manager.Shutdown()
+		return m.confirmQuit()
+
+	case key.Matches(msg, m.keys.Upgrade):
+		if !process.OutlivesParaler {
+			return m.toast(components.ToastWarning, "Upgrading in place isn't supported here: services stop with paraler")
+		}
+		// Services keep running; the app re-executes paraler once the UI exits
+		m.upgradeRequested = true
 		return tea.Quit
 
 	case key.Matches(msg, m.keys.Help):
-		m.showHelp = !m.showHelp
-		m.calculateLayout()
+		m.openHelp()
 		return nil
 
 	case key.Matches(msg, m.keys.Tab):
@@ -203,12 +633,25 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) tea.Cmd {
 		return m.startAll()
 
 	case key.Matches(msg, m.keys.StopAll):
-		return m.stopAll()
+		m.ShowConfirmStopAll()
+		return nil
 
 	case key.Matches(msg, m.keys.AddProject):
 		m.ShowAddProject()
 		return nil
 
+	case key.Matches(msg, m.keys.AddService):
+		m.ShowAddService()
+		return nil
+
+	case key.Matches(msg, m.keys.FindService):
+		m.startFind()
+		return nil
+
+	case key.Matches(msg, m.keys.Dashboard):
+		m.toggleDashboard()
+		return nil
+
 	case key.Matches(msg, m.keys.ReloadConfig):
 		return m.reloadConfig()
 
@@ -218,6 +661,75 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) tea.Cmd {
 	case key.Matches(msg, m.keys.Fullscreen):
 		m.toggleFullscreen()
 		return nil
+
+	case key.Matches(msg, m.keys.SplitLogs):
+		m.splitLogs()
+		return nil
+
+	case key.Matches(msg, m.keys.StackLogs):
+		m.toggleStackedLogs()
+		return nil
+
+	case key.Matches(msg, m.keys.CloseLogPanel):
+		m.closeLogPanel()
+		return nil
+
+	case key.Matches(msg, m.keys.ExpandJSON):
+		m.logPanel.ToggleJSON()
+		return nil
+
+	case key.Matches(msg, m.keys.WrapLines):
+		m.logPanel.ToggleWrap()
+		return nil
+
+	case key.Matches(msg, m.keys.Bookmark):
+		m.toggleBookmark()
+		return nil
+
+	case key.Matches(msg, m.keys.Bookmarks):
+		m.ShowBookmarks()
+		return nil
+
+	case key.Matches(msg, m.keys.NextBookmark):
+		m.nextBookmark(false)
+		return nil
+
+	case key.Matches(msg, m.keys.PrevBookmark):
+		m.nextBookmark(true)
+		return nil
+
+	case key.Matches(msg, m.keys.NextError):
+		m.nextError(false)
+		return nil
+
+	case key.Matches(msg, m.keys.PrevError):
+		m.nextError(true)
+		return nil
+
+	case key.Matches(msg, m.keys.AckErrors):
+		m.acknowledgeAllErrors()
+		return nil
+
+	case key.Matches(msg, m.keys.Timestamps):
+		format := m.logPanel.CycleTimestamps()
+		m.statusBar.ShowAlert("Timestamps: "+format, 2*time.Second)
+		return nil
+
+	case key.Matches(msg, m.keys.SearchLogs):
+		m.ShowSearch()
+		return nil
+
+	case key.Matches(msg, m.keys.MergedLogs):
+		m.toggleMergedLogs()
+		return nil
+
+	case key.Matches(msg, m.keys.LevelFilter):
+		m.cycleLevelFilter()
+		return nil
+
+	case key.Matches(msg, m.keys.StderrOnly):
+		m.toggleStderrOnly()
+		return nil
 	}
 
 	// Panel-specific keys
@@ -227,6 +739,97 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) tea.Cmd {
 	return m.handleLogKeys(msg)
 }
 
+// maxCount caps count prefixes, so a mistyped one doesn't keep the UI busy
+const maxCount = 999
+
+// isMotion returns true if a key moves the selection or the logs, so a
+// count prefix repeats it
+func (m *Model) isMotion(msg tea.KeyMsg) bool {
+	for _, binding := range []key.Binding{m.keys.Up, m.keys.Down, m.keys.PageUp, m.keys.PageDown,
+		m.keys.NextGroup, m.keys.PrevGroup, m.keys.NextError, m.keys.PrevError,
+		m.keys.NextBookmark, m.keys.PrevBookmark} {
+		if key.Matches(msg, binding) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleMouseMsg handles mouse input: clicking a service selects it,
+// clicking a panel title focuses the panel, clicking a key hint in the
+// status bar presses the key, and the wheel scrolls the logs (or moves
+// the selection over the sidebar)
+func (m *Model) handleMouseMsg(msg tea.MouseMsg) tea.Cmd {
+	// Modals, prompts and copy mode are only used with keys
+	if m.shuttingDown || m.showHelp || m.showDashboard || m.modalVisible() ||
+		m.logPanel.IsCopyMode() || m.logPanel.IsFiltering() {
+		return nil
+	}
+	if msg.Action != tea.MouseActionPress {
+		return nil
+	}
+
+	inSidebar := !m.fullscreen && msg.X < m.sidebarWidth
+	panel := -1
+	for i, bounds := range m.logBounds {
+		if image.Pt(msg.X, msg.Y).In(bounds) {
+			panel = i
+		}
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+		up := msg.Button == tea.MouseButtonWheelUp
+		if inSidebar {
+			if up {
+				m.sidebar.MoveUp()
+			} else {
+				m.sidebar.MoveDown()
+			}
+			m.updateLogPanelService()
+			return nil
+		}
+		if panel < 0 {
+			return nil
+		}
+		if m.logPanels[panel] != m.logPanel {
+			m.activateLogPanel(panel)
+		}
+		for range mouseWheelLines {
+			if up {
+				m.logPanel.ScrollUp()
+			} else {
+				m.logPanel.ScrollDown()
+			}
+		}
+		if up {
+			m.loadLogHistory()
+		} else {
+			m.dropLogHistory()
+		}
+
+	case tea.MouseButtonLeft:
+		// The status bar is the last row but one (see calculateLayout),
+		// and panel titles are below their top border
+		switch {
+		case msg.Y == m.height-2:
+			if hint := m.statusBar.HintAt(msg.X); hint != "" {
+				return m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(hint)})
+			}
+		case inSidebar:
+			if m.sidebar.SelectAt(msg.Y) {
+				m.updateLogPanelService()
+				m.setFocus(FocusSidebar)
+			} else if msg.Y == 1 {
+				m.setFocus(FocusSidebar)
+			}
+		case panel >= 0 && msg.Y == m.logBounds[panel].Min.Y+1:
+			m.activateLogPanel(panel)
+		}
+	}
+	return nil
+}
+
 // handleSidebarKeys handles keys when sidebar is focused
 func (m *Model) handleSidebarKeys(msg tea.KeyMsg) tea.Cmd {
 	switch {
@@ -238,6 +841,22 @@ func (m *Model) handleSidebarKeys(msg tea.KeyMsg) tea.Cmd {
 		m.sidebar.MoveDown()
 		m.updateLogPanelService()
 
+	case key.Matches(msg, m.keys.NextGroup):
+		if m.sidebar.NextProject() {
+			m.updateLogPanelService()
+		}
+
+	case key.Matches(msg, m.keys.PrevGroup):
+		if m.sidebar.PrevProject() {
+			m.updateLogPanelService()
+		}
+
+	case key.Matches(msg, m.keys.ToggleCollapse):
+		m.toggleCollapsed()
+
+	case key.Matches(msg, m.keys.SortServices):
+		m.cycleSortMode()
+
 	case key.Matches(msg, m.keys.Start):
 		return m.startSelected()
 
@@ -247,6 +866,24 @@ func (m *Model) handleSidebarKeys(msg tea.KeyMsg) tea.Cmd {
 	case key.Matches(msg, m.keys.Restart):
 		return m.restartSelected()
 
+	case key.Matches(msg, m.keys.SendSignal):
+		m.ShowSignal()
+
+	case key.Matches(msg, m.keys.Pause):
+		return m.togglePauseSelected()
+
+	case key.Matches(msg, m.keys.Info):
+		m.ShowDetail()
+
+	case key.Matches(msg, m.keys.Env):
+		m.ShowEnv()
+
+	case key.Matches(msg, m.keys.OpenURL):
+		return m.OpenURL()
+
+	case key.Matches(msg, m.keys.OpenEditor):
+		return m.OpenEditor()
+
 	case key.Matches(msg, m.keys.Filter):
 		m.setFocus(FocusLogs)
 		m.logPanel.StartFilter()
@@ -255,6 +892,9 @@ func (m *Model) handleSidebarKeys(msg tea.KeyMsg) tea.Cmd {
 	case key.Matches(msg, m.keys.ClearLogs):
 		m.clearLogs()
 
+	case key.Matches(msg, m.keys.ClearAllLogs):
+		m.ShowConfirmClearAllLogs()
+
 	case key.Matches(msg, m.keys.DeleteService):
 		m.ShowConfirmDeleteService()
 
@@ -272,6 +912,12 @@ func (m *Model) handleSidebarKeys(msg tea.KeyMsg) tea.Cmd {
 
 	case key.Matches(msg, m.keys.Rename):
 		m.ShowRename()
+
+	case key.Matches(msg, m.keys.RenameService):
+		m.ShowRenameService()
+
+	case key.Matches(msg, m.keys.EditService):
+		m.ShowEditor()
 	}
 
 	return nil
@@ -282,21 +928,33 @@ func (m *Model) handleLogKeys(msg tea.KeyMsg) tea.Cmd {
 	switch {
 	case key.Matches(msg, m.keys.Up):
 		m.logPanel.ScrollUp()
+		m.loadLogHistory()
 
 	case key.Matches(msg, m.keys.Down):
 		m.logPanel.ScrollDown()
+		m.dropLogHistory()
+
+	case key.Matches(msg, m.keys.NextGroup):
+		m.nextError(false)
+
+	case key.Matches(msg, m.keys.PrevGroup):
+		m.nextError(true)
 
 	case key.Matches(msg, m.keys.PageUp):
 		m.logPanel.PageUp()
+		m.loadLogHistory()
 
 	case key.Matches(msg, m.keys.PageDown):
 		m.logPanel.PageDown()
+		m.dropLogHistory()
 
 	case key.Matches(msg, m.keys.Home):
 		m.logPanel.GoToTop()
+		m.loadLogHistory()
 
 	case key.Matches(msg, m.keys.End):
 		m.logPanel.GoToBottom()
+		m.dropLogHistory()
 
 	case key.Matches(msg, m.keys.Filter):
 		m.logPanel.StartFilter()
@@ -305,6 +963,9 @@ func (m *Model) handleLogKeys(msg tea.KeyMsg) tea.Cmd {
 	case key.Matches(msg, m.keys.ClearLogs):
 		m.clearLogs()
 
+	case key.Matches(msg, m.keys.ClearAllLogs):
+		m.ShowConfirmClearAllLogs()
+
 	case key.Matches(msg, m.keys.Start):
 		return m.startSelected()
 
@@ -314,6 +975,24 @@ func (m *Model) handleLogKeys(msg tea.KeyMsg) tea.Cmd {
 	case key.Matches(msg, m.keys.Restart):
 		return m.restartSelected()
 
+	case key.Matches(msg, m.keys.SendSignal):
+		m.ShowSignal()
+
+	case key.Matches(msg, m.keys.Pause):
+		return m.togglePauseSelected()
+
+	case key.Matches(msg, m.keys.Info):
+		m.ShowDetail()
+
+	case key.Matches(msg, m.keys.Env):
+		m.ShowEnv()
+
+	case key.Matches(msg, m.keys.OpenURL):
+		return m.OpenURL()
+
+	case key.Matches(msg, m.keys.OpenEditor):
+		return m.OpenEditor()
+
 	case key.Matches(msg, m.keys.CopyMode):
 		m.logPanel.EnterCopyMode()
 	}
@@ -333,8 +1012,32 @@ func (m *Model) handleCopyModeKeys(msg tea.KeyMsg) tea.Cmd {
 	case key.Matches(msg, m.keys.Down):
 		m.logPanel.CopyModeCursorDown()
 
+	case key.Matches(msg, m.keys.CopyModeLeft):
+		m.logPanel.CopyModeCursorLeft()
+
+	case key.Matches(msg, m.keys.CopyModeRight):
+		m.logPanel.CopyModeCursorRight()
+
+	case key.Matches(msg, m.keys.CopyModeWord):
+		m.logPanel.CopyModeWordNext()
+
+	case key.Matches(msg, m.keys.CopyModeWordBack):
+		m.logPanel.CopyModeWordPrev()
+
+	case key.Matches(msg, m.keys.CopyModeLineStart):
+		m.logPanel.CopyModeLineStart()
+
+	case key.Matches(msg, m.keys.CopyModeLineEnd):
+		m.logPanel.CopyModeLineEnd()
+
 	case key.Matches(msg, m.keys.CopyModeSelect):
-		m.logPanel.CopyModeToggleSelect()
+		m.logPanel.CopyModeToggleSelect(components.CopyLines)
+
+	case key.Matches(msg, m.keys.CopyModeChars):
+		m.logPanel.CopyModeToggleSelect(components.CopyChars)
+
+	case key.Matches(msg, m.keys.CopyModeBlock):
+		m.logPanel.CopyModeToggleSelect(components.CopyBlock)
 
 	case key.Matches(msg, m.keys.CopyModeCopy):
 		text := m.logPanel.CopyModeGetSelectedText()
@@ -342,11 +1045,66 @@ func (m *Model) handleCopyModeKeys(msg tea.KeyMsg) tea.Cmd {
 			copyToClipboard(text)
 		}
 		m.logPanel.ExitCopyMode()
+
+	case key.Matches(msg, m.keys.ExportLogs):
+		cmd := m.exportLogs()
+		m.logPanel.ExitCopyMode()
+		return cmd
+
+	case key.Matches(msg, m.keys.PipeLogs):
+		m.ShowPipe()
+		m.logPanel.ExitCopyMode()
 	}
 
 	return nil
 }
 
+// handlePipeKeys handles keys when the pipe modal is visible
+func (m *Model) handlePipeKeys(msg tea.KeyMsg) tea.Cmd {
+	modal := m.pipeModal
+
+	if modal.IsRunning() || modal.HasResult() {
+		switch {
+		case key.Matches(msg, m.keys.Escape), key.Matches(msg, m.keys.Quit):
+			// The command is left to finish, its output is ignored
+			m.HidePipe()
+		case key.Matches(msg, m.keys.Up):
+			modal.ScrollUp(1)
+		case key.Matches(msg, m.keys.Down):
+			modal.ScrollDown(1)
+		case key.Matches(msg, m.keys.PageUp):
+			modal.ScrollUp(10)
+		case key.Matches(msg, m.keys.PageDown):
+			modal.ScrollDown(10)
+		}
+		return nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Enter):
+		command := modal.Command()
+		if command == "" {
+			return nil
+		}
+		entries := m.pipeEntries
+		modal.SetRunning()
+		return func() tea.Msg {
+			output, err := PipeLogs(entries, command)
+			return LogsPipedMsg{Output: output, Error: err}
+		}
+
+	case key.Matches(msg, m.keys.Escape):
+		m.HidePipe()
+		return nil
+	}
+
+	// Pass to text input
+	input := modal.Input()
+	newInput, cmd := input.Update(msg)
+	*input = newInput
+	return cmd
+}
+
 // copyToClipboard copies text to system clipboard using pbcopy (macOS)
 func copyToClipboard(text string) error {
 	cmd := exec.Command("pbcopy")
@@ -365,24 +1123,98 @@ func copyToClipboard(text string) error {
 	return cmd.Wait()
 }
 
+// browserCommand returns the command that opens a URL in the default browser
+func browserCommand(url string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url)
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		return exec.Command("xdg-open", url)
+	}
+}
+
+// editorCommand returns the command that opens a directory in $VISUAL or
+// $EDITOR, which run in the terminal, or else in VS Code
+func editorCommand(dir string) (cmd *exec.Cmd, inTerminal bool, err error) {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.Fields(os.Getenv(name)); len(editor) > 0 {
+			return exec.Command(editor[0], append(editor[1:], dir)...), true, nil
+		}
+	}
+	if code, err := exec.LookPath("code"); err == nil {
+		return exec.Command(code, dir), false, nil
+	}
+	return nil, false, fmt.Errorf("set $EDITOR or install VS Code's code command")
+}
+
+// startDetached starts a command without waiting for it to exit
+func startDetached(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
 // handleFilterInput handles input when filtering
 func (m *Model) handleFilterInput(msg tea.KeyMsg) tea.Cmd {
 	switch {
 	case key.Matches(msg, m.keys.Enter):
-		m.logPanel.ApplyFilter()
-		m.calculateLayout()
+		if m.logPanel.ApplyFilter() {
+			m.calculateLayout()
+		}
 		return nil
 
 	case key.Matches(msg, m.keys.Escape):
 		m.logPanel.ClearFilter()
 		m.calculateLayout()
 		return nil
+
+	case key.Matches(msg, m.keys.Tab):
+		m.logPanel.ToggleHighlight()
+		return nil
 	}
 
 	// Pass to text input
 	input := m.logPanel.FilterInput()
 	newInput, cmd := input.Update(msg)
 	*input = newInput
+	m.logPanel.ValidateFilter()
+	return cmd
+}
+
+// handleFindInput handles keys while finding a service in the sidebar
+func (m *Model) handleFindInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "enter":
+		m.sidebar.EndFind(true)
+		m.updateLogPanelService()
+		return nil
+
+	case "esc":
+		m.sidebar.EndFind(false)
+		m.updateLogPanelService()
+		return nil
+
+	case "down", "ctrl+n", "tab":
+		m.sidebar.NextMatch()
+		m.updateLogPanelService()
+		return nil
+
+	case "up", "ctrl+p", "shift+tab":
+		m.sidebar.PrevMatch()
+		m.updateLogPanelService()
+		return nil
+	}
+
+	// Pass to text input
+	input := m.sidebar.FindInput()
+	newInput, cmd := input.Update(msg)
+	*input = newInput
+	m.sidebar.UpdateFind()
+	m.updateLogPanelService()
 	return cmd
 }
 
@@ -409,6 +1241,10 @@ func (m *Model) handleAddProjectKeys(msg tea.KeyMsg) tea.Cmd {
 			modal.CompleteTab()
 			return nil
 
+		case msg.String() == "ctrl+t":
+			modal.Browse()
+			return nil
+
 		case key.Matches(msg, m.keys.Up):
 			modal.SuggestionUp()
 			return nil
@@ -418,15 +1254,45 @@ func (m *Model) handleAddProjectKeys(msg tea.KeyMsg) tea.Cmd {
 			return nil
 		}
 
-		// Pass to text input
-		input := modal.PathInput()
-		newInput, cmd := input.Update(msg)
-		*input = newInput
+		// Pass to text input
+		input := modal.PathInput()
+		newInput, cmd := input.Update(msg)
+		*input = newInput
+
+		// Update suggestions on input change
+		modal.UpdateSuggestions()
+
+		return cmd
+
+	case components.AddProjectStateBrowse:
+		tree := modal.Tree()
+		switch {
+		case key.Matches(msg, m.keys.Escape):
+			modal.UseBrowsed()
+			modal.BackToInput()
+
+		case key.Matches(msg, m.keys.Enter):
+			modal.UseBrowsed()
+			return m.scanProject()
+
+		case key.Matches(msg, m.keys.Up):
+			tree.MoveUp()
+
+		case key.Matches(msg, m.keys.Down):
+			tree.MoveDown()
+
+		case msg.String() == "right", msg.String() == "l":
+			tree.Expand()
 
-		// Update suggestions on input change
-		modal.UpdateSuggestions()
+		case msg.String() == "left", msg.String() == "h":
+			tree.Collapse()
 
-		return cmd
+		case key.Matches(msg, m.keys.Space):
+			tree.Toggle()
+
+		case msg.String() == "backspace":
+			tree.RootUp()
+		}
 
 	case components.AddProjectStatePreview:
 		switch {
@@ -445,8 +1311,37 @@ func (m *Model) handleAddProjectKeys(msg tea.KeyMsg) tea.Cmd {
 
 		case key.Matches(msg, m.keys.Space):
 			modal.ToggleSelected()
+
+		case msg.String() == "e":
+			modal.EditSelected()
+		}
+
+	case components.AddProjectStateEdit:
+		switch {
+		case key.Matches(msg, m.keys.Escape):
+			modal.CancelEdit()
+			return nil
+
+		case key.Matches(msg, m.keys.Enter):
+			modal.ApplyEdit()
+			return nil
+		}
+
+		switch msg.String() {
+		case "tab", "down":
+			modal.EditNext()
+			return nil
+		case "shift+tab", "up":
+			modal.EditPrev()
+			return nil
 		}
 
+		// Pass to the text input of the focused field
+		input := modal.EditInput()
+		newInput, cmd := input.Update(msg)
+		*input = newInput
+		return cmd
+
 	case components.AddProjectStateError:
 		switch {
 		case key.Matches(msg, m.keys.Escape):
@@ -534,7 +1429,18 @@ func (m *Model) handlePortConflictKeys(msg tea.KeyMsg) tea.Cmd {
 			// Start our service
 			m.logBuffer.Clear(serviceID)
 			m.manager.Start(serviceID)
-			return ProcessStatusChangedMsg{}
+			return nil
+		}
+
+	case msg.String() == "p" && m.portConflictModal.IsMovable():
+		// Leave the process alone and start on the next free port
+		serviceID := m.portConflictModal.ServiceID()
+		m.HidePortConflict()
+
+		return func() tea.Msg {
+			m.logBuffer.Clear(serviceID)
+			m.manager.StartOnFreePort(serviceID)
+			return nil
 		}
 
 	case key.Matches(msg, m.keys.Escape):
@@ -544,10 +1450,343 @@ func (m *Model) handlePortConflictKeys(msg tea.KeyMsg) tea.Cmd {
 	return nil
 }
 
+// handleSearchKeys handles keys when the search modal is visible
+func (m *Model) handleSearchKeys(msg tea.KeyMsg) tea.Cmd {
+	// Only arrow keys move the selection, k/j are part of the query
+	switch {
+	case key.Matches(msg, m.keys.Escape):
+		m.HideSearch()
+		return nil
+
+	case key.Matches(msg, m.keys.Enter):
+		if hit, ok := m.searchModal.Selected(); ok {
+			m.HideSearch()
+			m.jumpToSearchHit(hit)
+		}
+		return nil
+
+	case msg.Type == tea.KeyUp:
+		m.searchModal.MoveUp()
+		return nil
+
+	case msg.Type == tea.KeyDown:
+		m.searchModal.MoveDown()
+		return nil
+	}
+
+	// Pass to text input and search as the query changes
+	input := m.searchModal.Input()
+	query := input.Value()
+	newInput, cmd := input.Update(msg)
+	*input = newInput
+	if input.Value() != query {
+		m.searchModal.Search(m.logBuffer, m.sidebar.ServiceIDs())
+	}
+	return cmd
+}
+
+// handleBookmarksKeys handles keys when the bookmarks modal is visible
+func (m *Model) handleBookmarksKeys(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		m.bookmarksModal.MoveUp()
+
+	case key.Matches(msg, m.keys.Down):
+		m.bookmarksModal.MoveDown()
+
+	case key.Matches(msg, m.keys.Enter):
+		mark, ok := m.bookmarksModal.Selected()
+		m.HideBookmarks()
+		if !ok {
+			return nil
+		}
+		if !m.logPanel.ScrollToBookmark(mark) {
+			m.statusBar.ShowAlert("The bookmarked line is hidden by the filter", 3*time.Second)
+			return nil
+		}
+		m.setFocus(FocusLogs)
+
+	case key.Matches(msg, m.keys.Escape), key.Matches(msg, m.keys.Bookmarks):
+		m.HideBookmarks()
+	}
+
+	return nil
+}
+
+// handleSignalKeys handles keys when the signal modal is visible
+func (m *Model) handleSignalKeys(msg tea.KeyMsg) tea.Cmd {
+	modal := m.signalModal
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		modal.MoveUp()
+
+	case key.Matches(msg, m.keys.Down):
+		modal.MoveDown()
+
+	case key.Matches(msg, m.keys.Enter):
+		serviceID := modal.ServiceID()
+		sig := modal.Selected()
+		m.HideSignal()
+
+		if sig == "" {
+			return nil
+		}
+		return func() tea.Msg {
+			if err := m.manager.Signal(serviceID, sig); err != nil {
+				return SignalErrorMsg{Error: err}
+			}
+			return nil
+		}
+
+	case key.Matches(msg, m.keys.Escape):
+		m.HideSignal()
+	}
+
+	return nil
+}
+
+// handleDetailKeys handles keys when the detail modal is visible
+func (m *Model) handleDetailKeys(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Escape), key.Matches(msg, m.keys.Info):
+		m.HideDetail()
+	}
+	return nil
+}
+
+// handleEnvKeys handles keys when the environment modal is shown
+func (m *Model) handleEnvKeys(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Escape), key.Matches(msg, m.keys.Env):
+		m.HideEnv()
+
+	case key.Matches(msg, m.keys.Up):
+		m.envModal.MoveUp()
+
+	case key.Matches(msg, m.keys.Down):
+		m.envModal.MoveDown()
+
+	case msg.String() == "v":
+		m.envModal.ToggleReveal()
+
+	case msg.String() == "y":
+		if v, ok := m.envModal.Selected(); ok {
+			return m.copyEnv(v.Key, v.String())
+		}
+
+	case msg.String() == "Y":
+		vars := m.envModal.Vars()
+		if len(vars) == 0 {
+			return nil
+		}
+		lines := make([]string, len(vars))
+		for i, v := range vars {
+			lines[i] = v.String()
+		}
+		return m.copyEnv(fmt.Sprintf("%d variables", len(vars)), strings.Join(lines, "\n")+"\n")
+	}
+	return nil
+}
+
+// handleStartAllKeys handles keys when the start-all progress is shown
+func (m *Model) handleStartAllKeys(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Escape), key.Matches(msg, m.keys.Enter):
+		m.HideStartAll()
+	}
+	return nil
+}
+
+// copyEnv copies environment variables to the clipboard, unmasked
+func (m *Model) copyEnv(what, text string) tea.Cmd {
+	if err := copyToClipboard(text); err != nil {
+		return m.toast(components.ToastError, fmt.Sprintf("Failed to copy: %v", err))
+	}
+	return m.toast(components.ToastSuccess, "Copied "+what)
+}
+
+// handleErrorKeys handles keys when the error modal is visible
+func (m *Model) handleErrorKeys(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Escape), key.Matches(msg, m.keys.Enter), key.Matches(msg, m.keys.Quit):
+		m.HideError()
+
+	case msg.String() == "y":
+		if err := copyToClipboard(m.errorModal.Text()); err != nil {
+			return m.toast(components.ToastError, fmt.Sprintf("Failed to copy: %v", err))
+		}
+		return m.toast(components.ToastSuccess, "Copied error")
+	}
+	return nil
+}
+
+// openHelp shows the help screen from its top
+func (m *Model) openHelp() {
+	m.showHelp = true
+	m.helpScreen.ScrollTop()
+}
+
+// handleHelpKeys handles keys when the help screen is shown
+func (m *Model) handleHelpKeys(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Help), key.Matches(msg, m.keys.Escape), key.Matches(msg, m.keys.Quit):
+		m.showHelp = false
+
+	case key.Matches(msg, m.keys.Up):
+		m.helpScreen.ScrollUp(1)
+
+	case key.Matches(msg, m.keys.Down):
+		m.helpScreen.ScrollDown(1)
+
+	case key.Matches(msg, m.keys.PageUp):
+		m.helpScreen.ScrollUp(m.helpScreen.PageHeight())
+
+	case key.Matches(msg, m.keys.PageDown), msg.String() == " ":
+		m.helpScreen.ScrollDown(m.helpScreen.PageHeight())
+
+	case key.Matches(msg, m.keys.Home):
+		m.helpScreen.ScrollTop()
+
+	case key.Matches(msg, m.keys.End):
+		m.helpScreen.ScrollBottom()
+	}
+	return nil
+}
+
+// handleDashboardKeys handles keys when the dashboard is shown. Actions
+// apply to the highlighted service.
+func (m *Model) handleDashboardKeys(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m.confirmQuit()
+
+	case key.Matches(msg, m.keys.Help):
+		m.openHelp()
+
+	case key.Matches(msg, m.keys.Escape), key.Matches(msg, m.keys.Dashboard):
+		m.toggleDashboard()
+
+	case key.Matches(msg, m.keys.Up):
+		m.dashboard.MoveUp()
+
+	case key.Matches(msg, m.keys.Down):
+		m.dashboard.MoveDown()
+
+	case key.Matches(msg, m.keys.Enter):
+		if m.selectDashboardService() {
+			m.toggleDashboard()
+			m.setFocus(FocusLogs)
+		}
+
+	case key.Matches(msg, m.keys.Start):
+		if m.selectDashboardService() {
+			return m.startSelected()
+		}
+
+	case key.Matches(msg, m.keys.Stop):
+		if m.selectDashboardService() {
+			return m.stopSelected()
+		}
+
+	case key.Matches(msg, m.keys.Restart):
+		if m.selectDashboardService() {
+			return m.restartSelected()
+		}
+
+	case key.Matches(msg, m.keys.Info):
+		if m.selectDashboardService() {
+			m.ShowDetail()
+		}
+
+	case key.Matches(msg, m.keys.Env):
+		if m.selectDashboardService() {
+			m.ShowEnv()
+		}
+
+	case key.Matches(msg, m.keys.OpenURL):
+		if m.selectDashboardService() {
+			return m.OpenURL()
+		}
+
+	case key.Matches(msg, m.keys.OpenEditor):
+		if m.selectDashboardService() {
+			return m.OpenEditor()
+		}
+
+	case key.Matches(msg, m.keys.StartAll):
+		return m.startAll()
+
+	case key.Matches(msg, m.keys.StopAll):
+		m.ShowConfirmStopAll()
+		return nil
+	}
+	return nil
+}
+
+// handleOrphanKeys handles keys when orphaned processes modal is visible
+func (m *Model) handleOrphanKeys(msg tea.KeyMsg) tea.Cmd {
+	modal := m.orphanModal
+
+	var targets []process.ProcessRecord
+	var adopt bool
+
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		modal.MoveUp()
+		return nil
+
+	case key.Matches(msg, m.keys.Down):
+		modal.MoveDown()
+		return nil
+
+	case msg.String() == "a", msg.String() == "x":
+		orphan, ok := modal.Selected()
+		if !ok {
+			return nil
+		}
+		targets = []process.ProcessRecord{orphan}
+		adopt = msg.String() == "a"
+
+	case msg.String() == "A", msg.String() == "X":
+		targets = append(targets, modal.Orphans()...)
+		adopt = msg.String() == "A"
+
+	case key.Matches(msg, m.keys.Escape):
+		// Leave them running; they'll be offered again next launch
+		m.HideOrphans()
+		return nil
+
+	default:
+		return nil
+	}
+
+	for _, o := range targets {
+		modal.Remove(o.PID)
+	}
+	if !modal.IsVisible() {
+		m.HideOrphans()
+	}
+
+	return func() tea.Msg {
+		for _, o := range targets {
+			if adopt {
+				m.manager.AdoptOrphan(o)
+			} else {
+				m.manager.KillOrphan(o)
+			}
+		}
+		return nil
+	}
+}
+
 // handleConfirmKeys handles keys when confirm modal is visible
 func (m *Model) handleConfirmKeys(msg tea.KeyMsg) tea.Cmd {
+	// Pressing quit again quits
+	quitAgain := m.confirmModal.Action() == components.ConfirmQuit && key.Matches(msg, m.keys.Quit)
+
 	switch {
-	case key.Matches(msg, m.keys.Confirm):
+	case key.Matches(msg, m.keys.Confirm), quitAgain:
 		// Execute the confirmed action
 		modal := m.confirmModal
 		action := modal.Action()
@@ -567,12 +1806,22 @@ func (m *Model) handleConfirmKeys(msg tea.KeyMsg) tea.Cmd {
 				m.DeleteProject(projectName)
 				return ProjectDeletedMsg{Name: projectName}
 			}
+		case components.ConfirmClearAllLogs:
+			m.clearAllLogs()
+		case components.ConfirmRestartService:
+			return m.restartEdited(config.ServiceID{Project: projectName, Service: targetName})
+		case components.ConfirmStopProject:
+			return m.stopProject(projectName)
+		case components.ConfirmStopAll:
+			return m.stopAll()
+		case components.ConfirmQuit:
+			return m.shutdown()
 		}
 
-	case key.Matches(msg, m.keys.Escape):
-		m.HideConfirm()
-
-	case msg.String() == "n":
+	case key.Matches(msg, m.keys.Escape), msg.String() == "n":
+		if modal := m.confirmModal; modal.Action() == components.ConfirmRestartService {
+			m.statusBar.ShowAlert(fmt.Sprintf("Saved %s, the changes apply on its next restart", modal.TargetName()), 5*time.Second)
+		}
 		m.HideConfirm()
 	}
 
@@ -694,6 +1943,45 @@ func (m *Model) handleRenameKeys(msg tea.KeyMsg) tea.Cmd {
 	return cmd
 }
 
+// handleEditorKeys handles keys when the service editor is visible
+func (m *Model) handleEditorKeys(msg tea.KeyMsg) tea.Cmd {
+	editor := m.serviceEditor
+
+	switch {
+	case key.Matches(msg, m.keys.Enter):
+		if err := m.SaveServiceEdit(); err != nil {
+			editor.SetError(err.Error())
+		}
+		return nil
+	case key.Matches(msg, m.keys.Escape):
+		m.HideEditor()
+		return nil
+	}
+
+	switch msg.String() {
+	case "tab", "down":
+		editor.Next()
+		return nil
+	case "shift+tab", "up":
+		editor.Prev()
+		return nil
+	case " ":
+		if editor.Focused() == components.EditorAutoRestart {
+			editor.ToggleAutoRestart()
+			return nil
+		}
+	}
+
+	// Pass to the text input of the focused field
+	input := editor.Input()
+	if input == nil {
+		return nil
+	}
+	newInput, cmd := input.Update(msg)
+	*input = newInput
+	return cmd
+}
+
 // ProjectRenamedMsg is sent when a project is renamed
 type ProjectRenamedMsg struct {
 	OldName string
@@ -714,6 +2002,7 @@ type RenameErrorMsg struct {
 
 // reloadConfig reloads the config file
 func (m *Model) reloadConfig() tea.Cmd {
+	m.statusBar.SetActivity("reloading config…")
 	return func() tea.Msg {
 		if err := m.HotReload(); err != nil {
 			return ConfigReloadErrorMsg{Error: err}
@@ -722,10 +2011,27 @@ func (m *Model) reloadConfig() tea.Cmd {
 	}
 }
 
-// exportLogs exports logs for the selected service
+// exportLogs exports the logs shown in the log panel (or the lines
+// selected in copy mode)
 func (m *Model) exportLogs() tea.Cmd {
+	selected := m.sidebar.Selected()
+	name := selected.Project + "_" + selected.Service
+	switch {
+	case m.logPanel.IsMerged():
+		name = "all"
+	case selected.Service == "":
+		return func() tea.Msg {
+			return LogsExportErrorMsg{Error: fmt.Errorf("no service selected")}
+		}
+	case selected.Instance > 0:
+		name = fmt.Sprintf("%s-%d", name, selected.Instance)
+	}
+
+	// Collected here, the log panel isn't safe to read from the command
+	entries := m.logPanel.ExportEntries()
+	m.statusBar.SetActivity("exporting logs…")
 	return func() tea.Msg {
-		path, err := m.ExportLogs()
+		path, err := m.ExportLogs(entries, name)
 		if err != nil {
 			return LogsExportErrorMsg{Error: err}
 		}
@@ -3,6 +3,7 @@ package ui
 import (
 	"strings"
 
+	"github.com/paralerdev/paraler/internal/ui/components"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -12,28 +13,38 @@ func (m *Model) View() string {
 		return "Loading..."
 	}
 
+	if m.shuttingDown {
+		m.shutdownScreen.SetSize(m.width, m.height)
+		return m.shutdownScreen.View()
+	}
+
+	// The help takes the whole screen
+	if m.showHelp {
+		return m.helpScreen.View()
+	}
+
 	// Update log panel with current service status
 	m.updateLogPanelStatus()
 
 	// Main content area
 	var mainArea string
-	if m.fullscreen {
+	if m.showDashboard {
+		// Dashboard: an overview of every service
+		m.dashboard.SetServices(m.sidebar.ServiceIDs())
+		mainArea = m.dashboard.View(m.manager, m.logBuffer)
+	} else if m.fullscreen {
 		// Fullscreen mode: only logs
-		mainArea = m.logPanel.View(m.logBuffer)
+		mainArea = m.logsView()
 	} else {
 		// Normal mode: sidebar + logs
 		sidebar := m.sidebar.View(m.manager, m.logBuffer)
-		logs := m.logPanel.View(m.logBuffer)
+		logs := m.logsView()
 		mainArea = lipgloss.JoinHorizontal(lipgloss.Top, sidebar, logs)
 	}
 
 	// Status bar
-	var statusBar string
-	if m.showHelp {
-		statusBar = m.statusBar.View(m.manager, true)
-	} else {
-		statusBar = m.statusBar.View(m.manager, false)
-	}
+	m.statusBar.SetContext(m.sidebar.Selected(), m.logPanel.Filter(), m.sidebar.MultiSelectCount())
+	statusBar := m.statusBar.View(m.manager)
 
 	// Join vertically
 	var b strings.Builder
@@ -41,11 +52,53 @@ func (m *Model) View() string {
 	b.WriteString("\n")
 	b.WriteString(statusBar)
 
-	// Overlay modals if visible
+	if !m.toasts.IsEmpty() {
+		view := m.overlayToasts(b.String(), lipgloss.Height(mainArea))
+		b.Reset()
+		b.WriteString(view)
+	}
+
+	// Overlay modals if visible; errors go over the others
+	if m.showError {
+		return m.overlayErrorModal(b.String())
+	}
+
+	if m.showPipe {
+		return m.overlayPipeModal(b.String())
+	}
+
+	if m.showOrphans {
+		return m.overlayOrphanModal(b.String())
+	}
+
 	if m.showPortConflict {
 		return m.overlayPortConflictModal(b.String())
 	}
 
+	if m.showSignal {
+		return m.overlaySignalModal(b.String())
+	}
+
+	if m.showSearch {
+		return m.overlaySearchModal(b.String())
+	}
+
+	if m.showBookmarks {
+		return m.overlayBookmarksModal(b.String())
+	}
+
+	if m.showDetail {
+		return m.overlayDetailModal(b.String())
+	}
+
+	if m.showEnv {
+		return m.overlayEnvModal(b.String())
+	}
+
+	if m.showStartAll {
+		return m.overlayStartAllModal(b.String())
+	}
+
 	if m.showConfirm {
 		return m.overlayConfirmModal(b.String())
 	}
@@ -58,81 +111,132 @@ func (m *Model) View() string {
 		return m.overlayRenameModal(b.String())
 	}
 
+	if m.showEditor {
+		return m.overlayEditorModal(b.String())
+	}
+
 	if m.showAddProject {
-		return m.overlayModal(b.String(), m.addProjectModal.View())
+		return m.overlayAddProjectModal(b.String())
 	}
 
 	return b.String()
 }
 
-// overlayModal places a modal on top of the background
-func (m *Model) overlayModal(background, modal string) string {
-	// Calculate modal position (center of screen)
-	modalWidth := m.width / 2
-	modalHeight := m.height / 2
+// overlayToasts draws the toasts over the bottom right corner of the
+// panels, above their bottom border
+func (m *Model) overlayToasts(background string, panelHeight int) string {
+	toasts := m.toasts.View()
+	x := max(m.width-lipgloss.Width(toasts)-2, 0)
+	y := max(panelHeight-1-lipgloss.Height(toasts), 0)
+	return components.Overlay(background, toasts, x, y)
+}
 
-	if modalWidth < 50 {
-		modalWidth = 50
+// logsView renders the log panels side by side, or stacked
+func (m *Model) logsView() string {
+	views := make([]string, len(m.logPanels))
+	for i, panel := range m.logPanels {
+		views[i] = panel.View(m.logBuffer)
 	}
-	if modalHeight < 15 {
-		modalHeight = 15
+	if m.stackedLogs {
+		return lipgloss.JoinVertical(lipgloss.Left, views...)
 	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, views...)
+}
 
-	m.addProjectModal.SetSize(modalWidth, modalHeight)
-
-	// Simple overlay: just show the modal centered
-	modalStyle := lipgloss.NewStyle().
-		Width(m.width).
-		Height(m.height).
-		Align(lipgloss.Center, lipgloss.Center)
+// overlayModal centers a modal over the background, dimmed so the modal
+// stands out while the panels behind it stay in view
+func (m *Model) overlayModal(background, modal string) string {
+	x := max((m.width-lipgloss.Width(modal))/2, 0)
+	y := max((m.height-lipgloss.Height(modal))/2, 0)
+	return components.Overlay(components.Dim(background), modal, x, y)
+}
 
-	return modalStyle.Render(m.addProjectModal.View())
+// overlayAddProjectModal overlays the add project modal
+func (m *Model) overlayAddProjectModal(background string) string {
+	m.addProjectModal.SetSize(max(m.width/2, 50), max(m.height/2, 15))
+	return m.overlayModal(background, m.addProjectModal.View())
 }
 
 // overlayConfirmModal overlays the confirm modal
 func (m *Model) overlayConfirmModal(background string) string {
 	m.confirmModal.SetSize(m.width / 2)
-
-	modalStyle := lipgloss.NewStyle().
-		Width(m.width).
-		Height(m.height).
-		Align(lipgloss.Center, lipgloss.Center)
-
-	return modalStyle.Render(m.confirmModal.View())
+	return m.overlayModal(background, m.confirmModal.View())
 }
 
 // overlayMoveServiceModal overlays the move service modal
 func (m *Model) overlayMoveServiceModal(background string) string {
 	m.moveServiceModal.SetSize(m.width / 2)
-
-	modalStyle := lipgloss.NewStyle().
-		Width(m.width).
-		Height(m.height).
-		Align(lipgloss.Center, lipgloss.Center)
-
-	return modalStyle.Render(m.moveServiceModal.View())
+	return m.overlayModal(background, m.moveServiceModal.View())
 }
 
 // overlayRenameModal overlays the rename modal
 func (m *Model) overlayRenameModal(background string) string {
 	m.renameModal.SetSize(m.width / 2)
-
-	modalStyle := lipgloss.NewStyle().
-		Width(m.width).
-		Height(m.height).
-		Align(lipgloss.Center, lipgloss.Center)
-
-	return modalStyle.Render(m.renameModal.View())
+	return m.overlayModal(background, m.renameModal.View())
 }
 
 // overlayPortConflictModal overlays the port conflict modal
 func (m *Model) overlayPortConflictModal(background string) string {
 	m.portConflictModal.SetSize(m.width / 2)
+	return m.overlayModal(background, m.portConflictModal.View())
+}
+
+// overlayOrphanModal overlays the orphaned processes modal
+func (m *Model) overlayOrphanModal(background string) string {
+	m.orphanModal.SetSize(m.width / 2)
+	return m.overlayModal(background, m.orphanModal.View())
+}
 
-	modalStyle := lipgloss.NewStyle().
-		Width(m.width).
-		Height(m.height).
-		Align(lipgloss.Center, lipgloss.Center)
+// overlayDetailModal overlays the service detail modal
+func (m *Model) overlayDetailModal(background string) string {
+	m.detailModal.SetSize(m.width / 2)
+	return m.overlayModal(background, m.detailModal.View())
+}
+
+// overlayEnvModal overlays the service environment modal
+func (m *Model) overlayEnvModal(background string) string {
+	m.envModal.SetSize(m.width*3/4, m.height)
+	return m.overlayModal(background, m.envModal.View())
+}
+
+// overlayErrorModal overlays the error modal
+func (m *Model) overlayErrorModal(background string) string {
+	m.errorModal.SetSize(min(max(m.width/2, 50), m.width))
+	return m.overlayModal(background, m.errorModal.View())
+}
+
+// overlayStartAllModal overlays the start-all progress modal
+func (m *Model) overlayStartAllModal(background string) string {
+	m.startAllModal.SetSize(max(m.width/2, 50), m.height)
+	return m.overlayModal(background, m.startAllModal.View())
+}
+
+// overlaySearchModal overlays the log search modal
+func (m *Model) overlaySearchModal(background string) string {
+	m.searchModal.SetSize(m.width*3/4, m.height)
+	return m.overlayModal(background, m.searchModal.View())
+}
+
+// overlayBookmarksModal overlays the bookmarks modal
+func (m *Model) overlayBookmarksModal(background string) string {
+	m.bookmarksModal.SetSize(m.width*3/4, m.height)
+	return m.overlayModal(background, m.bookmarksModal.View())
+}
+
+// overlayPipeModal overlays the modal to pipe logs to a command
+func (m *Model) overlayPipeModal(background string) string {
+	m.pipeModal.SetSize(m.width*3/4, m.height)
+	return m.overlayModal(background, m.pipeModal.View())
+}
+
+// overlayEditorModal renders the service editor centered over the UI
+func (m *Model) overlayEditorModal(background string) string {
+	m.serviceEditor.SetSize(max(m.width/2, 60))
+	return m.overlayModal(background, m.serviceEditor.View())
+}
 
-	return modalStyle.Render(m.portConflictModal.View())
+// overlaySignalModal overlays the send signal modal
+func (m *Model) overlaySignalModal(background string) string {
+	m.signalModal.SetSize(m.width / 2)
+	return m.overlayModal(background, m.signalModal.View())
 }